@@ -1,6 +1,8 @@
 package adapter
 
 import (
+	"time"
+
 	"github.com/kodefluence/altair/core"
 	"github.com/kodefluence/altair/entity"
 )
@@ -20,4 +22,11 @@ func (a *appConfig) ProxyHost() string                   { return a.c.ProxyHost(
 func (a *appConfig) PluginExists(pluginName string) bool { return a.c.PluginExists(pluginName) }
 func (a *appConfig) Plugins() []string                   { return a.c.Plugins() }
 func (a *appConfig) AutoMigrate() bool                   { return a.c.AutoMigrate() }
+func (a *appConfig) TLS() entity.AppTLSConfig            { return a.c.TLS() }
+func (a *appConfig) HTTP2() bool                         { return a.c.HTTP2() }
+func (a *appConfig) H2C() bool                           { return a.c.H2C() }
+func (a *appConfig) ShutdownDelay() time.Duration        { return a.c.ShutdownDelay() }
+func (a *appConfig) ShutdownDrainPeriod() time.Duration  { return a.c.ShutdownDrainPeriod() }
+func (a *appConfig) CORS() *entity.RouteCORS             { return a.c.CORS() }
+func (a *appConfig) TrustedProxies() []string            { return a.c.TrustedProxies() }
 func (a *appConfig) Dump() string                        { return a.c.Dump() }
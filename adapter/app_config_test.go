@@ -77,11 +77,36 @@ func TestAppConfigAdapter(t *testing.T) {
 		})
 	})
 
+	t.Run("TLS", func(t *testing.T) {
+		appOption := appOption
+		appOption.TLS = entity.AppTLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}
+		appOption.HTTP2 = true
+
+		appConfig := adapter.AppConfig(entity.NewAppConfig(appOption))
+
+		assert.Equal(t, appOption.TLS, appConfig.TLS())
+		assert.True(t, appConfig.TLS().Enabled())
+		assert.True(t, appConfig.HTTP2())
+		assert.False(t, appConfig.H2C())
+	})
+
+	t.Run("CORS", func(t *testing.T) {
+		appOption := appOption
+		appOption.CORS = &entity.RouteCORS{AllowOrigins: []string{"https://app.example.com"}}
+
+		appConfig := adapter.AppConfig(entity.NewAppConfig(appOption))
+
+		assert.Equal(t, appOption.CORS, appConfig.CORS())
+	})
+
 	t.Run("Dump", func(t *testing.T) {
 		appConfig := adapter.AppConfig(entity.NewAppConfig(appOption))
 
-		content, _ := yaml.Marshal(appOption)
+		maskedOption := appOption
+		maskedOption.Authorization.Password = "********"
+		content, _ := yaml.Marshal(maskedOption)
 
 		assert.Equal(t, string(content), appConfig.Dump())
+		assert.NotContains(t, appConfig.Dump(), appOption.Authorization.Password)
 	})
 }
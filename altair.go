@@ -1,9 +1,9 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -27,10 +27,14 @@ import (
 	"github.com/kodefluence/altair/module/app"
 	"github.com/kodefluence/altair/module/controller"
 	"github.com/kodefluence/altair/module/healthcheck"
+	healthcheckHttp "github.com/kodefluence/altair/module/healthcheck/controller/http"
 	"github.com/kodefluence/altair/module/migration"
 	pluginlist "github.com/kodefluence/altair/module/plugin_list"
 	"github.com/kodefluence/altair/module/projectgenerator"
 	"github.com/kodefluence/altair/module/router"
+	routerUsecase "github.com/kodefluence/altair/module/router/usecase"
+	"github.com/kodefluence/altair/module/server"
+	serverUsecase "github.com/kodefluence/altair/module/server/usecase"
 	"github.com/kodefluence/altair/plugin"
 )
 
@@ -292,11 +296,20 @@ func runAPI() error {
 	gin.SetMode(gin.ReleaseMode)
 
 	apiEngine := gin.New()
+	if err := routerUsecase.TrustProxies(apiEngine, appConfig.TrustedProxies()); err != nil {
+		log.Error().
+			Err(err).
+			Stack().
+			Array("tags", zerolog.Arr().Str("altair").Str("main")).
+			Msg("Error setting the trusted proxies")
+		return err
+	}
+
 	apiError := apierror.Provide()
 
 	baseController := controller.Provide(apiEngine.Handle, apiError, nil)
 	baseModule := app.Provide(baseController)
-	healthcheck.Load(baseModule)
+	healthController := healthcheck.Load(baseModule)
 
 	pluginEngine := apiEngine.Group("/_plugins/", gin.BasicAuth(gin.Accounts{
 		appConfig.BasicAuthUsername(): appConfig.BasicAuthPassword(),
@@ -307,7 +320,7 @@ func runAPI() error {
 	appBearer := cfg.AppBearer(pluginEngine, appConfig)
 	dbBearer := cfg.DatabaseBearer(databases, dbConfigs)
 
-	if err := plugin.Load(appBearer, pluginBearer, dbBearer, apiError, pluginModule); err != nil {
+	if err := plugin.Load(appBearer, pluginBearer, dbBearer, apiError, pluginModule, baseModule); err != nil {
 		log.Error().
 			Err(err).
 			Stack().
@@ -318,52 +331,134 @@ func runAPI() error {
 
 	reportMigrationDrift(pluginBearer, dbBearer)
 
-	compiler, forwarder := router.Provide(pluginModule.Controller().ListDownstream(), pluginModule.Controller().ListMetric())
-	routeObjects, err := compiler.Compile("./routes")
-	if err != nil {
+	forwarder := router.Provide(appConfig, "./routes", pluginModule.Controller().ListDownstream(), pluginModule.Controller().ListMetric(), apiError)
+	defer forwarder.Close()
+
+	if err := forwarder.Reload(); err != nil {
 		log.Error().
 			Err(err).
 			Stack().
 			Array("tags", zerolog.Arr().Str("altair").Str("main")).
-			Msg("Error compiling routes")
+			Msg("Error generating routes")
 		return err
 	}
 
-	err = forwarder.Generate(apiEngine, routeObjects)
+	router.Load(pluginModule, forwarder)
+	apiEngine.NoRoute(forwarder.Handle)
+	forwarder.Watch(0)
+
+	srv, err := server.Provide(appConfig, apiEngine)
 	if err != nil {
 		log.Error().
 			Err(err).
 			Stack().
 			Array("tags", zerolog.Arr().Str("altair").Str("main")).
-			Msg("Error generating routes")
+			Msg("Error preparing api server")
 		return err
 	}
+	defer srv.Close()
+
+	srv.WatchCertificate(0)
 
 	gracefulSignal := make(chan os.Signal, 1)
 	signal.Notify(gracefulSignal, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
+	reloadSignal := make(chan os.Signal, 1)
+	signal.Notify(reloadSignal, syscall.SIGHUP)
+	defer signal.Stop(reloadSignal)
+
+	go func() {
+		for range reloadSignal {
+			if err := srv.ReloadCertificate(); err != nil {
+				log.Error().
+					Err(err).
+					Stack().
+					Array("tags", zerolog.Arr().Str("altair").Str("main").Str("reload")).
+					Msg("Error reloading tls certificate")
+			}
+
+			// Reload logs the compile error itself and keeps the current routes.
+			_ = forwarder.Reload()
+		}
+	}()
+
+	serverErr := make(chan error, 1)
 	go func() {
-		srv := &http.Server{
-			Addr:    fmt.Sprintf(":%d", appConfig.Port()),
-			Handler: apiEngine,
+		scheme := "http"
+		if srv.TLSEnabled() {
+			scheme = "https"
 		}
 
-		log.Info().Msg(fmt.Sprintf("Running Altair in: http://127.0.0.1:%d", appConfig.Port()))
+		log.Info().Msg(fmt.Sprintf("Running Altair in: %s://127.0.0.1:%d", scheme, appConfig.Port()))
+
+		serverErr <- srv.ListenAndServe()
+	}()
 
-		if err := srv.ListenAndServe(); err != nil {
+	var closeSignal os.Signal
+	select {
+	case err := <-serverErr:
+		if err != nil {
 			log.Error().
 				Err(err).
 				Stack().
 				Array("tags", zerolog.Arr().Str("altair").Str("main")).
 				Msg("Error running api engine")
 		}
-	}()
+		return err
+	case closeSignal = <-gracefulSignal:
+	}
 
-	closeSignal := <-gracefulSignal
 	log.Info().Array("tags", zerolog.Arr().Str("altair").Str("main")).Msg(fmt.Sprintf("Receiving %s signal.... Cleaning up processes.", closeSignal.String()))
+
+	drainAPI(healthController, srv, forwarder, gracefulSignal)
 	return nil
 }
 
+// drainAPI fails the health check, keeps serving for the configured delay so
+// load balancers stop routing to us, then stops accepting connections and waits
+// for in-flight requests up to the drain period. A second signal skips the
+// delay. Database connections are closed by the caller once this returns.
+func drainAPI(healthController *healthcheckHttp.HealthController, srv *serverUsecase.Server, forwarder *routerUsecase.Router, gracefulSignal <-chan os.Signal) {
+	healthController.Drain()
+
+	log.Info().
+		Dur("delay", appConfig.ShutdownDelay()).
+		Dur("drain_period", appConfig.ShutdownDrainPeriod()).
+		Array("tags", zerolog.Arr().Str("altair").Str("main").Str("shutdown")).
+		Msg("Health check is failing, draining connections.")
+
+	if delay := appConfig.ShutdownDelay(); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-gracefulSignal:
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), appConfig.ShutdownDrainPeriod())
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Warn().
+			Err(err).
+			Array("tags", zerolog.Arr().Str("altair").Str("main").Str("shutdown")).
+			Msg("Drain period elapsed before every connection went idle")
+	}
+
+	// Upgraded connections are hijacked from the server, so they are only
+	// visible through the routes.
+	if err := forwarder.Drain(ctx); err != nil {
+		log.Warn().
+			Err(err).
+			Array("tags", zerolog.Arr().Str("altair").Str("main").Str("shutdown")).
+			Msg("Drain period elapsed before every request completed")
+		return
+	}
+
+	log.Info().
+		Array("tags", zerolog.Arr().Str("altair").Str("main").Str("shutdown")).
+		Msg("Every in-flight request completed.")
+}
+
 // applyAutoMigrate runs UpAll through the migration runner, bounded by
 // timeout. If the timeout fires we abort boot rather than start an API server
 // against a partially-migrated schema; golang-migrate's MySQL advisory lock
@@ -1,11 +1,13 @@
 package cfg
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strconv"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
@@ -14,6 +16,8 @@ import (
 	"github.com/kodefluence/altair/entity"
 )
 
+const defaultShutdownDrainPeriod = time.Second * 30
+
 type app struct{}
 
 type baseAppConfig struct {
@@ -26,6 +30,22 @@ type baseAppConfig struct {
 		Username string `yaml:"username"`
 		Password string `yaml:"password"`
 	} `yaml:"authorization"`
+	TLS struct {
+		CertFile     string   `yaml:"cert_file"`
+		KeyFile      string   `yaml:"key_file"`
+		MinVersion   string   `yaml:"min_version"`
+		CipherSuites []string `yaml:"cipher_suites"`
+		ClientCAFile string   `yaml:"client_ca_file"`
+		ClientAuth   string   `yaml:"client_auth"`
+	} `yaml:"tls"`
+	HTTP2    *bool `yaml:"http2"`
+	H2C      bool  `yaml:"h2c"`
+	Shutdown struct {
+		Delay       string `yaml:"delay"`
+		DrainPeriod string `yaml:"drain_period"`
+	} `yaml:"shutdown"`
+	CORS           *entity.RouteCORS `yaml:"cors"`
+	TrustedProxies []string          `yaml:"trusted_proxies"`
 }
 
 func App() core.AppLoader {
@@ -89,8 +109,96 @@ func (a *app) Compile(configPath string) (core.AppConfig, error) {
 		appConfigOption.Authorization.Username = config.Authorization.Username
 		appConfigOption.Authorization.Password = config.Authorization.Password
 
+		appConfigOption.TLS = entity.AppTLSConfig{
+			CertFile:     config.TLS.CertFile,
+			KeyFile:      config.TLS.KeyFile,
+			MinVersion:   config.TLS.MinVersion,
+			CipherSuites: config.TLS.CipherSuites,
+			ClientCAFile: config.TLS.ClientCAFile,
+			ClientAuth:   config.TLS.ClientAuth,
+		}
+
+		if err := validateTLS(appConfigOption.TLS); err != nil {
+			return nil, err
+		}
+
+		appConfigOption.HTTP2 = config.HTTP2 == nil || *config.HTTP2
+		appConfigOption.H2C = config.H2C
+
+		if appConfigOption.H2C && appConfigOption.TLS.Enabled() {
+			return nil, errors.New("config `h2c` cannot be used together with `tls`, HTTP/2 is negotiated over TLS instead")
+		}
+
+		if config.Shutdown.Delay != "" {
+			delay, err := time.ParseDuration(config.Shutdown.Delay)
+			if err != nil {
+				return nil, fmt.Errorf("config shutdown `delay` is invalid: %v", err)
+			}
+
+			appConfigOption.Shutdown.Delay = delay
+		}
+
+		appConfigOption.Shutdown.DrainPeriod = defaultShutdownDrainPeriod
+		if config.Shutdown.DrainPeriod != "" {
+			drainPeriod, err := time.ParseDuration(config.Shutdown.DrainPeriod)
+			if err != nil {
+				return nil, fmt.Errorf("config shutdown `drain_period` is invalid: %v", err)
+			}
+
+			appConfigOption.Shutdown.DrainPeriod = drainPeriod
+		}
+
+		if config.CORS != nil {
+			if err := config.CORS.Validate(); err != nil {
+				return nil, fmt.Errorf("config %v", err)
+			}
+
+			appConfigOption.CORS = config.CORS
+		}
+
+		for _, proxy := range config.TrustedProxies {
+			if _, err := entity.ParseNetwork(proxy); err != nil {
+				return nil, fmt.Errorf("config trusted_proxies %v", err)
+			}
+		}
+
+		appConfigOption.TrustedProxies = config.TrustedProxies
+
 		return adapter.AppConfig(entity.NewAppConfig(appConfigOption)), nil
 	default:
 		return nil, fmt.Errorf("undefined template version: %s for app.yaml", v)
 	}
 }
+
+func validateTLS(tlsConfig entity.AppTLSConfig) error {
+	if tlsConfig.CertFile == "" && tlsConfig.KeyFile == "" {
+		if tlsConfig.MinVersion != "" || len(tlsConfig.CipherSuites) > 0 || tlsConfig.ClientCAFile != "" || tlsConfig.ClientAuth != "" {
+			return errors.New("config tls `cert_file` and `key_file` are required to enable tls")
+		}
+
+		return nil
+	}
+
+	if tlsConfig.CertFile == "" || tlsConfig.KeyFile == "" {
+		return errors.New("config tls `cert_file` and `key_file` must be set together")
+	}
+
+	if _, err := tlsConfig.Version(); err != nil {
+		return fmt.Errorf("config %v", err)
+	}
+
+	if _, err := tlsConfig.CipherSuiteIDs(); err != nil {
+		return fmt.Errorf("config %v", err)
+	}
+
+	clientAuth, err := tlsConfig.ClientAuthType()
+	if err != nil {
+		return fmt.Errorf("config %v", err)
+	}
+
+	if (clientAuth == tls.VerifyClientCertIfGiven || clientAuth == tls.RequireAndVerifyClientCert) && tlsConfig.ClientCAFile == "" {
+		return fmt.Errorf("config tls `client_ca_file` is required when `client_auth` is `%s`", tlsConfig.ClientAuth)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package cfg_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -106,6 +107,182 @@ func TestApp(t *testing.T) {
 				})
 			})
 
+			t.Run("With tls", func(t *testing.T) {
+				t.Run("Return app config", func(t *testing.T) {
+					configPath := "./app_with_tls/"
+					fileName := "app.yml"
+
+					testhelper.GenerateTempTestFiles(configPath, AppConfigWithTLS, fileName, 0666)
+
+					appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+					assert.Nil(t, err)
+
+					assert.Equal(t, entity.AppTLSConfig{
+						CertFile:     "./certs/altair.crt",
+						KeyFile:      "./certs/altair.key",
+						MinVersion:   "1.3",
+						CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+						ClientCAFile: "./certs/clients.crt",
+						ClientAuth:   "require_and_verify",
+					}, appConfig.TLS())
+					assert.False(t, appConfig.HTTP2())
+					assert.False(t, appConfig.H2C())
+
+					testhelper.RemoveTempTestFiles(configPath)
+				})
+
+				t.Run("Enable http2 by default", func(t *testing.T) {
+					configPath := "./app_http2_default/"
+					fileName := "app.yml"
+
+					testhelper.GenerateTempTestFiles(configPath, AppConfigNormal, fileName, 0666)
+
+					appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+					assert.Nil(t, err)
+					assert.True(t, appConfig.HTTP2())
+					assert.False(t, appConfig.TLS().Enabled())
+
+					testhelper.RemoveTempTestFiles(configPath)
+				})
+
+				for name, content := range map[string]string{
+					"invalid_min_version": AppConfigWithTLSInvalidMinVersion,
+					"missing_key":         AppConfigWithTLSMissingKey,
+					"missing_client_ca":   AppConfigWithTLSMissingClientCA,
+					"with_h2c":            AppConfigWithTLSAndH2C,
+				} {
+					t.Run(fmt.Sprintf("Return error when %s", name), func(t *testing.T) {
+						configPath := fmt.Sprintf("./app_tls_%s/", name)
+						fileName := "app.yml"
+
+						testhelper.GenerateTempTestFiles(configPath, content, fileName, 0666)
+
+						appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+						assert.NotNil(t, err)
+						assert.Nil(t, appConfig)
+
+						testhelper.RemoveTempTestFiles(configPath)
+					})
+				}
+			})
+
+			t.Run("With shutdown", func(t *testing.T) {
+				t.Run("Return app config", func(t *testing.T) {
+					configPath := "./app_with_shutdown/"
+					fileName := "app.yml"
+
+					testhelper.GenerateTempTestFiles(configPath, AppConfigWithShutdown, fileName, 0666)
+
+					appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+					assert.Nil(t, err)
+					assert.Equal(t, time.Second*5, appConfig.ShutdownDelay())
+					assert.Equal(t, time.Minute, appConfig.ShutdownDrainPeriod())
+
+					testhelper.RemoveTempTestFiles(configPath)
+				})
+
+				t.Run("Drain for 30 seconds by default", func(t *testing.T) {
+					configPath := "./app_shutdown_default/"
+					fileName := "app.yml"
+
+					testhelper.GenerateTempTestFiles(configPath, AppConfigNormal, fileName, 0666)
+
+					appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+					assert.Nil(t, err)
+					assert.Equal(t, time.Duration(0), appConfig.ShutdownDelay())
+					assert.Equal(t, time.Second*30, appConfig.ShutdownDrainPeriod())
+
+					testhelper.RemoveTempTestFiles(configPath)
+				})
+
+				t.Run("Return error when drain period is invalid", func(t *testing.T) {
+					configPath := "./app_shutdown_invalid/"
+					fileName := "app.yml"
+
+					testhelper.GenerateTempTestFiles(configPath, AppConfigWithShutdownInvalidDrainPeriod, fileName, 0666)
+
+					appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+					assert.NotNil(t, err)
+					assert.Nil(t, appConfig)
+
+					testhelper.RemoveTempTestFiles(configPath)
+				})
+			})
+
+			t.Run("With cors", func(t *testing.T) {
+				t.Run("Return app config", func(t *testing.T) {
+					configPath := "./app_with_cors/"
+					fileName := "app.yml"
+
+					testhelper.GenerateTempTestFiles(configPath, AppConfigWithCORS, fileName, 0666)
+
+					appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+					assert.Nil(t, err)
+					assert.Equal(t, &entity.RouteCORS{
+						AllowOrigins:     []string{"https://app.example.com", "https://*.example.com"},
+						ExposeHeaders:    []string{"X-Request-ID"},
+						AllowCredentials: true,
+						MaxAge:           "10m",
+					}, appConfig.CORS())
+
+					testhelper.RemoveTempTestFiles(configPath)
+				})
+
+				t.Run("Return nil cors when it is not configured", func(t *testing.T) {
+					configPath := "./app_without_cors/"
+					fileName := "app.yml"
+
+					testhelper.GenerateTempTestFiles(configPath, AppConfigNormal, fileName, 0666)
+
+					appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+					assert.Nil(t, err)
+					assert.Nil(t, appConfig.CORS())
+
+					testhelper.RemoveTempTestFiles(configPath)
+				})
+
+				t.Run("Return error when any origin allows credentials", func(t *testing.T) {
+					configPath := "./app_with_invalid_cors/"
+					fileName := "app.yml"
+
+					testhelper.GenerateTempTestFiles(configPath, AppConfigWithInvalidCORS, fileName, 0666)
+
+					appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+					assert.NotNil(t, err)
+					assert.Nil(t, appConfig)
+
+					testhelper.RemoveTempTestFiles(configPath)
+				})
+			})
+
+			t.Run("With trusted proxies", func(t *testing.T) {
+				t.Run("Return app config", func(t *testing.T) {
+					configPath := "./app_with_trusted_proxies/"
+					fileName := "app.yml"
+
+					testhelper.GenerateTempTestFiles(configPath, AppConfigWithTrustedProxies, fileName, 0666)
+
+					appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+					assert.Nil(t, err)
+					assert.Equal(t, []string{"10.0.0.0/8", "192.0.2.1"}, appConfig.TrustedProxies())
+
+					testhelper.RemoveTempTestFiles(configPath)
+				})
+
+				t.Run("Return error when a proxy is invalid", func(t *testing.T) {
+					configPath := "./app_with_invalid_trusted_proxies/"
+					fileName := "app.yml"
+
+					testhelper.GenerateTempTestFiles(configPath, AppConfigWithInvalidTrustedProxies, fileName, 0666)
+
+					appConfig, err := cfg.App().Compile(fmt.Sprintf("%s%s", configPath, fileName))
+					assert.NotNil(t, err)
+					assert.Nil(t, appConfig)
+
+					testhelper.RemoveTempTestFiles(configPath)
+				})
+			})
+
 			t.Run("Empty authorization username", func(t *testing.T) {
 				t.Run("Return error", func(t *testing.T) {
 					configPath := "./app_empty_username/"
@@ -266,6 +266,119 @@ authorization:
 plugins:
   - oauth`
 
+var AppConfigWithTLS = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+tls:
+  cert_file: ./certs/altair.crt
+  key_file: ./certs/altair.key
+  min_version: "1.3"
+  cipher_suites:
+    - TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256
+  client_ca_file: ./certs/clients.crt
+  client_auth: require_and_verify
+http2: false
+plugins:
+  - oauth`
+
+var AppConfigWithTLSInvalidMinVersion = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+tls:
+  cert_file: ./certs/altair.crt
+  key_file: ./certs/altair.key
+  min_version: "1.4"`
+
+var AppConfigWithTLSMissingKey = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+tls:
+  cert_file: ./certs/altair.crt`
+
+var AppConfigWithTLSMissingClientCA = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+tls:
+  cert_file: ./certs/altair.crt
+  key_file: ./certs/altair.key
+  client_auth: require_and_verify`
+
+var AppConfigWithTLSAndH2C = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+tls:
+  cert_file: ./certs/altair.crt
+  key_file: ./certs/altair.key
+h2c: true`
+
+var AppConfigWithShutdown = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+shutdown:
+  delay: 5s
+  drain_period: 1m`
+
+var AppConfigWithShutdownInvalidDrainPeriod = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+shutdown:
+  drain_period: forever`
+
+var AppConfigWithTrustedProxies = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+trusted_proxies:
+  - 10.0.0.0/8
+  - 192.0.2.1`
+
+var AppConfigWithInvalidTrustedProxies = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+trusted_proxies:
+  - load-balancer`
+
+var AppConfigWithCORS = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+cors:
+  allow_origins:
+    - https://app.example.com
+    - https://*.example.com
+  expose_headers:
+    - X-Request-ID
+  allow_credentials: true
+  max_age: 10m`
+
+var AppConfigWithInvalidCORS = `
+version: 1.0
+authorization:
+  username: altair
+  password: secret
+cors:
+  allow_origins:
+    - "*"
+  allow_credentials: true`
+
 var AppConfigUnmarshalError = `
 ASd:
 1231
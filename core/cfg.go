@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/kodefluence/monorepo/db"
+
+	"github.com/kodefluence/altair/entity"
 )
 
 type AppLoader interface {
@@ -51,6 +53,13 @@ type AppConfig interface {
 	PluginExists(pluginName string) bool
 	Plugins() []string
 	AutoMigrate() bool
+	TLS() entity.AppTLSConfig
+	HTTP2() bool
+	H2C() bool
+	ShutdownDelay() time.Duration
+	ShutdownDrainPeriod() time.Duration
+	CORS() *entity.RouteCORS
+	TrustedProxies() []string
 	Dump() string
 }
 
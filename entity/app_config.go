@@ -1,9 +1,16 @@
 package entity
 
 import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
 	"gopkg.in/yaml.v2"
 )
 
+const maskedSecret = "********"
+
 type AppConfigOption struct {
 	Port          int
 	ProxyHost     string
@@ -13,6 +20,99 @@ type AppConfigOption struct {
 		Username string
 		Password string
 	}
+	TLS      AppTLSConfig
+	HTTP2    bool
+	H2C      bool
+	Shutdown struct {
+		Delay       time.Duration
+		DrainPeriod time.Duration
+	}
+	CORS           *RouteCORS
+	TrustedProxies []string
+}
+
+// AppTLSConfig holds the TLS settings of the gateway listener. TLS is only
+// terminated by altair when CertFile is set.
+type AppTLSConfig struct {
+	CertFile     string
+	KeyFile      string
+	MinVersion   string
+	CipherSuites []string
+	ClientCAFile string
+	ClientAuth   string
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var tlsClientAuthTypes = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify_if_given":    tls.VerifyClientCertIfGiven,
+	"require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+func (t AppTLSConfig) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// Version returns the minimum TLS version, TLS 1.2 when it is not set.
+func (t AppTLSConfig) Version() (uint16, error) {
+	if t.MinVersion == "" {
+		return tls.VersionTLS12, nil
+	}
+
+	version, ok := tlsVersions[t.MinVersion]
+	if !ok {
+		return 0, fmt.Errorf("tls min_version `%s` is not supported, expected one of 1.0, 1.1, 1.2 or 1.3", t.MinVersion)
+	}
+
+	return version, nil
+}
+
+// CipherSuiteIDs resolves the configured cipher suite names. A nil slice keeps
+// the Go defaults.
+func (t AppTLSConfig) CipherSuiteIDs() ([]uint16, error) {
+	if len(t.CipherSuites) == 0 {
+		return nil, nil
+	}
+
+	available := map[string]uint16{}
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		available[suite.Name] = suite.ID
+	}
+
+	var ids []uint16
+	for _, name := range t.CipherSuites {
+		id, ok := available[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("tls cipher suite `%s` is not supported", name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// ClientAuthType returns how client certificates are verified, none when it is
+// not set.
+func (t AppTLSConfig) ClientAuthType() (tls.ClientAuthType, error) {
+	if t.ClientAuth == "" {
+		return tls.NoClientCert, nil
+	}
+
+	clientAuth, ok := tlsClientAuthTypes[t.ClientAuth]
+	if !ok {
+		return tls.NoClientCert, fmt.Errorf("tls client_auth `%s` is not supported, expected one of none, request, require, verify_if_given or require_and_verify", t.ClientAuth)
+	}
+
+	return clientAuth, nil
 }
 
 type AppConfig struct {
@@ -23,6 +123,13 @@ type AppConfig struct {
 	basicAuthUsername string
 	basicAuthPassword string
 	autoMigrate       bool
+	tls               AppTLSConfig
+	http2             bool
+	h2c               bool
+	shutdownDelay     time.Duration
+	drainPeriod       time.Duration
+	cors              *RouteCORS
+	trustedProxies    []string
 }
 
 func NewAppConfig(option AppConfigOption) AppConfig {
@@ -40,6 +147,13 @@ func NewAppConfig(option AppConfigOption) AppConfig {
 		basicAuthPassword: option.Authorization.Password,
 		basicAuthUsername: option.Authorization.Username,
 		autoMigrate:       option.AutoMigrate,
+		tls:               option.TLS,
+		http2:             option.HTTP2,
+		h2c:               option.H2C,
+		shutdownDelay:     option.Shutdown.Delay,
+		drainPeriod:       option.Shutdown.DrainPeriod,
+		cors:              option.CORS,
+		trustedProxies:    option.TrustedProxies,
 	}
 }
 
@@ -72,16 +186,64 @@ func (a AppConfig) AutoMigrate() bool {
 	return a.autoMigrate
 }
 
+func (a AppConfig) TLS() AppTLSConfig {
+	return a.tls
+}
+
+func (a AppConfig) HTTP2() bool {
+	return a.http2
+}
+
+func (a AppConfig) H2C() bool {
+	return a.h2c
+}
+
+// ShutdownDelay is how long altair keeps serving with a failing health check
+// before it stops accepting connections, giving load balancers time to notice.
+func (a AppConfig) ShutdownDelay() time.Duration {
+	return a.shutdownDelay
+}
+
+// ShutdownDrainPeriod bounds how long in-flight requests are waited for once
+// altair stops accepting connections.
+func (a AppConfig) ShutdownDrainPeriod() time.Duration {
+	return a.drainPeriod
+}
+
+// CORS is the cors applied to every route without its own, nil when app.yml
+// does not configure it.
+func (a AppConfig) CORS() *RouteCORS {
+	return a.cors
+}
+
+// TrustedProxies are the load balancers in front of altair, the client IP is
+// only read from X-Forwarded-For when the request comes from one of them.
+func (a AppConfig) TrustedProxies() []string {
+	return a.trustedProxies
+}
+
+// Dump encodes the config as yaml with secrets masked, it is printed by
+// `altair config app`.
 func (a AppConfig) Dump() string {
 	appConfigOption := AppConfigOption{
-		Port:        a.port,
-		Plugins:     a.plugins,
-		ProxyHost:   a.proxyHost,
-		AutoMigrate: a.autoMigrate,
+		Port:           a.port,
+		Plugins:        a.plugins,
+		ProxyHost:      a.proxyHost,
+		AutoMigrate:    a.autoMigrate,
+		TLS:            a.tls,
+		HTTP2:          a.http2,
+		H2C:            a.h2c,
+		CORS:           a.cors,
+		TrustedProxies: a.trustedProxies,
 	}
 
+	appConfigOption.Shutdown.Delay = a.shutdownDelay
+	appConfigOption.Shutdown.DrainPeriod = a.drainPeriod
+
 	appConfigOption.Authorization.Username = a.basicAuthUsername
-	appConfigOption.Authorization.Password = a.basicAuthPassword
+	if a.basicAuthPassword != "" {
+		appConfigOption.Authorization.Password = maskedSecret
+	}
 
 	encodedContent, _ := yaml.Marshal(appConfigOption)
 	return string(encodedContent)
@@ -76,11 +76,27 @@ func TestAppConfig(t *testing.T) {
 		})
 	})
 
+	t.Run("TLS", func(t *testing.T) {
+		appOption := appOption
+		appOption.TLS = entity.AppTLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}
+		appOption.HTTP2 = true
+
+		appConfig := entity.NewAppConfig(appOption)
+
+		assert.Equal(t, appOption.TLS, appConfig.TLS())
+		assert.True(t, appConfig.TLS().Enabled())
+		assert.True(t, appConfig.HTTP2())
+		assert.False(t, appConfig.H2C())
+	})
+
 	t.Run("Dump", func(t *testing.T) {
 		appConfig := entity.NewAppConfig(appOption)
 
-		content, _ := yaml.Marshal(appOption)
+		maskedOption := appOption
+		maskedOption.Authorization.Password = "********"
+		content, _ := yaml.Marshal(maskedOption)
 
 		assert.Equal(t, string(content), appConfig.Dump())
+		assert.NotContains(t, appConfig.Dump(), appOption.Authorization.Password)
 	})
 }
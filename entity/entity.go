@@ -1,18 +1,354 @@
 package entity
 
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
 type RouteObject struct {
-	Name   string                `yaml:"name"`
-	Auth   string                `yaml:"auth"`
-	Prefix string                `yaml:"prefix"`
-	Host   string                `yaml:"host"`
-	Path   map[string]RouterPath `yaml:"path"`
+	Name            string                `yaml:"name"`
+	Auth            string                `yaml:"auth"`
+	Prefix          string                `yaml:"prefix"`
+	Host            string                `yaml:"host"`
+	Scheme          string                `yaml:"scheme"`
+	Match           RouteMatch            `yaml:"match"`
+	TLS             RouteTLS              `yaml:"tls"`
+	Upstream        RouteUpstream         `yaml:"upstream"`
+	Timeout         string                `yaml:"timeout"`
+	ConnectTimeout  string                `yaml:"connect_timeout"`
+	MaxBodySize     string                `yaml:"max_body_size"`
+	Retry           RouteRetry            `yaml:"retry"`
+	CircuitBreaker  RouteCircuitBreaker   `yaml:"circuit_breaker"`
+	RequestHeaders  RouteHeaderRules      `yaml:"request_headers"`
+	ResponseHeaders RouteHeaderRules      `yaml:"response_headers"`
+	RequestBody     RouteBodyTransform    `yaml:"request_body"`
+	ResponseBody    RouteBodyTransform    `yaml:"response_body"`
+	StripPrefix     bool                  `yaml:"strip_prefix"`
+	AddPrefix       string                `yaml:"add_prefix"`
+	Rewrite         []RouteRewrite        `yaml:"rewrite"`
+	RateLimit       *RouteRateLimit       `yaml:"rate_limit"`
+	Cache           *RouteCache           `yaml:"cache"`
+	CORS            *RouteCORS            `yaml:"cors"`
+	IPFilter        *RouteIPFilter        `yaml:"ip_filter"`
+	Path            map[string]RouterPath `yaml:"path"`
+}
+
+// RouteHeaderRules transforms headers on their way through the route. Rules run
+// in the order remove, rename, set, append. Set and append values are
+// templates with .ClientIP, .RequestID, .Host, .Method, .Path and .RouteName.
+type RouteHeaderRules struct {
+	Remove []string          `yaml:"remove"`
+	Rename map[string]string `yaml:"rename"`
+	Set    map[string]string `yaml:"set"`
+	Append map[string]string `yaml:"append"`
+}
+
+// RouteBodyTransform rewrites JSON bodies on their way through the route, other
+// content types pass through untouched. Fields are addressed by dot separated
+// paths such as `data.user_name` or `items.0.id`, `#` stands for every element
+// of an array and `\.` escapes a dot inside a field name. Rules run in the order
+// remove, rename, set and wrap, wrap nests the whole body under its path.
+type RouteBodyTransform struct {
+	Remove []string               `yaml:"remove"`
+	Rename map[string]string      `yaml:"rename"`
+	Set    map[string]interface{} `yaml:"set"`
+	Wrap   string                 `yaml:"wrap"`
+}
+
+// RouteRateLimit overrides the ratelimit plugin config for a route or a path.
+// Key is one of client_ip, resource_owner_id, oauth_application_id or
+// header:<name>. Unset fields fall back to the plugin config.
+type RouteRateLimit struct {
+	Key      string `yaml:"key"`
+	Limit    int    `yaml:"limit"`
+	Period   string `yaml:"period"`
+	Burst    int    `yaml:"burst"`
+	Disabled bool   `yaml:"disabled"`
+}
+
+// RouteCache enables the cache plugin for GET and HEAD requests of a route or
+// a path. TTL is used when the upstream response sets no max-age. Private
+// responses are cached per oauth resource owner, only on `auth: oauth` routes.
+type RouteCache struct {
+	TTL      string `yaml:"ttl"`
+	Private  bool   `yaml:"private"`
+	Disabled bool   `yaml:"disabled"`
 }
 
+// RouteIPFilter overrides the ipfilter plugin config for a route or a path.
+// Allow replaces the plugin allow list when it is set, Deny is added to the
+// plugin deny list. Entries are IPs or CIDRs, a denied client is rejected even
+// when it is allowed.
+type RouteIPFilter struct {
+	Allow    []string `yaml:"allow"`
+	Deny     []string `yaml:"deny"`
+	Disabled bool     `yaml:"disabled"`
+
+	allowNetworks []*net.IPNet
+	denyNetworks  []*net.IPNet
+}
+
+// Validate reports the first entry that is not an IP or a CIDR. The entries are
+// parsed once here, when the routes are loaded, and kept for Networks.
+func (f *RouteIPFilter) Validate() error {
+	allowNetworks, err := parseNetworks(f.Allow)
+	if err != nil {
+		return err
+	}
+
+	denyNetworks, err := parseNetworks(f.Deny)
+	if err != nil {
+		return err
+	}
+
+	f.allowNetworks, f.denyNetworks = allowNetworks, denyNetworks
+	return nil
+}
+
+// Networks returns the parsed allow and deny lists. A filter that was not
+// validated has its entries parsed on every call, invalid ones are skipped.
+func (f *RouteIPFilter) Networks() (allow, deny []*net.IPNet) {
+	if f.allowNetworks != nil || f.denyNetworks != nil {
+		return f.allowNetworks, f.denyNetworks
+	}
+
+	allow, _ = parseNetworks(f.Allow)
+	deny, _ = parseNetworks(f.Deny)
+	return allow, deny
+}
+
+func parseNetworks(entries []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	var firstErr error
+	for _, entry := range entries {
+		network, err := ParseNetwork(entry)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, firstErr
+}
+
+// ParseNetwork parses an IP or a CIDR, an IP is a network of that single
+// address.
+func ParseNetwork(value string) (*net.IPNet, error) {
+	value = strings.TrimSpace(value)
+
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return nil, fmt.Errorf("`%s` is not an IP or a CIDR", value)
+		}
+
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, fmt.Errorf("`%s` is not an IP or a CIDR", value)
+	}
+
+	return network, nil
+}
+
+// RouteCORS answers CORS preflight requests at the gateway and adds the CORS
+// headers to the actual responses. AllowOrigins accepts `*` and a leading `*.`
+// wildcard subdomain such as `https://*.example.com`. Without AllowMethods the
+// methods of the path are allowed, without AllowHeaders the requested ones
+// are. The cors of a route replaces the app.yml cors as a whole.
+type RouteCORS struct {
+	AllowOrigins     []string `yaml:"allow_origins"`
+	AllowMethods     []string `yaml:"allow_methods"`
+	AllowHeaders     []string `yaml:"allow_headers"`
+	ExposeHeaders    []string `yaml:"expose_headers"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+	MaxAge           string   `yaml:"max_age"`
+	Disabled         bool     `yaml:"disabled"`
+}
+
+// Validate reports the first invalid field, a disabled cors is always valid.
+func (c RouteCORS) Validate() error {
+	if c.Disabled {
+		return nil
+	}
+
+	if len(c.AllowOrigins) == 0 {
+		return fmt.Errorf("cors allow_origins cannot be empty")
+	}
+
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" {
+			if c.AllowCredentials {
+				return fmt.Errorf("cors allow_origins `*` cannot be used together with allow_credentials")
+			}
+			continue
+		}
+
+		parsed, err := url.Parse(strings.Replace(origin, "://*.", "://", 1))
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" || parsed.Path != "" || strings.Contains(parsed.Host, "*") {
+			return fmt.Errorf("cors allow_origins `%s` must be `*` or a scheme and host such as `https://app.example.com` or `https://*.example.com`", origin)
+		}
+	}
+
+	if c.MaxAge != "" {
+		maxAge, err := time.ParseDuration(c.MaxAge)
+		if err != nil {
+			return fmt.Errorf("cors max_age is invalid: %v", err)
+		}
+
+		if maxAge < 0 {
+			return fmt.Errorf("cors max_age cannot be negative")
+		}
+	}
+
+	return nil
+}
+
+// RouteRewrite replaces the upstream path when it matches the Match regular
+// expression. Replace may reference capture groups as $1 or ${name} and gin
+// path params as :name.
+type RouteRewrite struct {
+	Match   string `yaml:"match"`
+	Replace string `yaml:"replace"`
+}
+
+// RouteMatch restricts a route to the requests matching every configured
+// field, so several routes can serve the same path for different tenants.
+// Hosts accept a leading `*.` wildcard, header and query values accept `*` to
+// only require presence.
+type RouteMatch struct {
+	Hosts       []string          `yaml:"hosts"`
+	Headers     map[string]string `yaml:"headers"`
+	Query       map[string]string `yaml:"query"`
+	ClientCIDRs []string          `yaml:"client_cidrs"`
+}
+
+// RouteTLS configures the TLS client used for https upstreams. CertFile and
+// KeyFile enable mutual TLS.
+type RouteTLS struct {
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// RouteRetry configures how a failed upstream call is retried. Only idempotent
+// methods are retried, and only when MaxAttempts is greater than one.
+type RouteRetry struct {
+	MaxAttempts       int    `yaml:"max_attempts"`
+	OnStatus          []int  `yaml:"on_status"`
+	OnConnectionError bool   `yaml:"on_connection_error"`
+	Backoff           string `yaml:"backoff"`
+	MaxBackoff        string `yaml:"max_backoff"`
+}
+
+// RouteCircuitBreaker trips the route open when the upstream error rate or slow
+// call rate within Window crosses its threshold. It is disabled while both
+// thresholds are zero.
+type RouteCircuitBreaker struct {
+	ErrorRateThreshold    float64 `yaml:"error_rate_threshold"`
+	SlowCallThreshold     string  `yaml:"slow_call_threshold"`
+	SlowCallRateThreshold float64 `yaml:"slow_call_rate_threshold"`
+	MinRequests           int     `yaml:"min_requests"`
+	Window                string  `yaml:"window"`
+	CoolDown              string  `yaml:"cool_down"`
+	HalfOpenRequests      int     `yaml:"half_open_requests"`
+}
+
+// RouteUpstream describes the pool of targets a route is balanced across.
+// When Targets is empty the route falls back to the single `host` field.
+type RouteUpstream struct {
+	Strategy     string           `yaml:"strategy"`
+	HashOn       string           `yaml:"hash_on"`
+	Targets      []RouteTarget    `yaml:"targets"`
+	FallbackHost string           `yaml:"fallback_host"`
+	HealthCheck  RouteHealthCheck `yaml:"health_check"`
+}
+
+type RouteTarget struct {
+	Host   string `yaml:"host"`
+	Weight int    `yaml:"weight"`
+}
+
+// RouteHealthCheck configures how upstream targets are ejected from and
+// restored to the balancing pool. Active probing is enabled when Active.Path is
+// set, passive observation when Passive.UnhealthyThreshold is positive.
+type RouteHealthCheck struct {
+	Active  RouteActiveHealthCheck  `yaml:"active"`
+	Passive RoutePassiveHealthCheck `yaml:"passive"`
+}
+
+type RouteActiveHealthCheck struct {
+	Path               string `yaml:"path"`
+	Interval           string `yaml:"interval"`
+	Timeout            string `yaml:"timeout"`
+	HealthyThreshold   int    `yaml:"healthy_threshold"`
+	UnhealthyThreshold int    `yaml:"unhealthy_threshold"`
+}
+
+type RoutePassiveHealthCheck struct {
+	UnhealthyThreshold int    `yaml:"unhealthy_threshold"`
+	EjectionDuration   string `yaml:"ejection_duration"`
+}
+
+// RouterPath configures one path of a route. When Methods is empty every method
+// is forwarded, otherwise the others are answered with 405. MethodOverrides
+// replaces the auth or scope of a single method. StripPrefix, AddPrefix and
+// Rewrite replace the route values when they are set, RateLimit, Cache and
+// IPFilter replace the route rate_limit, cache and ip_filter as a whole.
 type RouterPath struct {
+	Auth            string                      `yaml:"auth"`
+	Scope           string                      `yaml:"scope"`
+	Methods         []string                    `yaml:"methods"`
+	MethodOverrides map[string]RouterPathMethod `yaml:"method_overrides"`
+	StripPrefix     *bool                       `yaml:"strip_prefix"`
+	AddPrefix       *string                     `yaml:"add_prefix"`
+	Rewrite         []RouteRewrite              `yaml:"rewrite"`
+	RateLimit       *RouteRateLimit             `yaml:"rate_limit"`
+	Cache           *RouteCache                 `yaml:"cache"`
+	IPFilter        *RouteIPFilter              `yaml:"ip_filter"`
+}
+
+type RouterPathMethod struct {
 	Auth  string `yaml:"auth"`
 	Scope string `yaml:"scope"`
 }
 
+// ForMethod returns the router path with the overrides of the given method
+// applied. Empty override fields keep the path values.
+func (r RouterPath) ForMethod(method string) RouterPath {
+	override, ok := r.MethodOverrides[method]
+	if !ok {
+		return r
+	}
+
+	if override.Auth != "" {
+		r.Auth = override.Auth
+	}
+
+	if override.Scope != "" {
+		r.Scope = override.Scope
+	}
+
+	return r
+}
+
 func (r RouterPath) GetAuth() string {
 	return r.Auth
 }
@@ -20,3 +356,20 @@ func (r RouterPath) GetAuth() string {
 func (r RouterPath) GetScope() string {
 	return r.Scope
 }
+
+// GetPluginConfig returns the route config of the plugin with the given name,
+// nil when the path has none. Plugins assert it to their own config type, such
+// as *RouteRateLimit for ratelimit, *RouteCache for cache and *RouteIPFilter
+// for ipfilter.
+func (r RouterPath) GetPluginConfig(name string) interface{} {
+	switch {
+	case name == "ratelimit" && r.RateLimit != nil:
+		return r.RateLimit
+	case name == "cache" && r.Cache != nil:
+		return r.Cache
+	case name == "ipfilter" && r.IPFilter != nil:
+		return r.IPFilter
+	default:
+		return nil
+	}
+}
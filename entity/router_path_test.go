@@ -17,3 +17,90 @@ func TestRouterPath(t *testing.T) {
 	assert.Equal(t, routerPath.Auth, routerPath.GetAuth())
 	assert.Equal(t, routerPath.Scope, routerPath.GetScope())
 }
+
+func TestRouterPathForMethod(t *testing.T) {
+	routerPath := entity.RouterPath{
+		Auth:    "none",
+		Methods: []string{"GET", "PUT"},
+		MethodOverrides: map[string]entity.RouterPathMethod{
+			"PUT": {Auth: "oauth", Scope: "users:write"},
+		},
+	}
+
+	t.Run("Keep the path values when the method has no override", func(t *testing.T) {
+		assert.Equal(t, "none", routerPath.ForMethod("GET").GetAuth())
+		assert.Equal(t, "", routerPath.ForMethod("GET").GetScope())
+	})
+
+	t.Run("Apply the method override", func(t *testing.T) {
+		assert.Equal(t, "oauth", routerPath.ForMethod("PUT").GetAuth())
+		assert.Equal(t, "users:write", routerPath.ForMethod("PUT").GetScope())
+	})
+}
+
+func TestParseNetwork(t *testing.T) {
+	t.Run("Return the network of a CIDR", func(t *testing.T) {
+		network, err := entity.ParseNetwork("10.0.0.0/8")
+		assert.Nil(t, err)
+		assert.Equal(t, "10.0.0.0/8", network.String())
+	})
+
+	t.Run("Return a single address network of an IP", func(t *testing.T) {
+		network, err := entity.ParseNetwork("203.0.113.7")
+		assert.Nil(t, err)
+		assert.Equal(t, "203.0.113.7/32", network.String())
+
+		network, err = entity.ParseNetwork("2001:db8::1")
+		assert.Nil(t, err)
+		assert.Equal(t, "2001:db8::1/128", network.String())
+	})
+
+	t.Run("Return error when it is neither", func(t *testing.T) {
+		for _, value := range []string{"", "office", "10.0.0.0/33", "10.0.0.256"} {
+			_, err := entity.ParseNetwork(value)
+			assert.NotNil(t, err, value)
+		}
+	})
+}
+
+func TestRouteIPFilter(t *testing.T) {
+	t.Run("Parse the entries once when validated", func(t *testing.T) {
+		ipFilter := &entity.RouteIPFilter{Allow: []string{"10.0.0.0/8"}, Deny: []string{"10.1.2.3"}}
+		assert.Nil(t, ipFilter.Validate())
+
+		allow, deny := ipFilter.Networks()
+		ipFilter.Allow, ipFilter.Deny = nil, nil
+
+		cachedAllow, cachedDeny := ipFilter.Networks()
+		assert.Equal(t, "10.0.0.0/8", allow[0].String())
+		assert.Equal(t, "10.1.2.3/32", deny[0].String())
+		assert.Equal(t, allow, cachedAllow)
+		assert.Equal(t, deny, cachedDeny)
+	})
+
+	t.Run("Parse the entries of a filter that was not validated", func(t *testing.T) {
+		allow, deny := (&entity.RouteIPFilter{Allow: []string{"office", "10.0.0.0/8"}}).Networks()
+
+		assert.Len(t, allow, 1)
+		assert.Empty(t, deny)
+	})
+
+	t.Run("Return error on an invalid entry", func(t *testing.T) {
+		assert.NotNil(t, (&entity.RouteIPFilter{Deny: []string{"everyone"}}).Validate())
+	})
+}
+
+func TestRouterPathGetPluginConfig(t *testing.T) {
+	rateLimit := &entity.RouteRateLimit{Limit: 10}
+	routerPath := entity.RouterPath{RateLimit: rateLimit}
+
+	t.Run("Return the config of the plugin", func(t *testing.T) {
+		assert.Equal(t, rateLimit, routerPath.GetPluginConfig("ratelimit"))
+	})
+
+	t.Run("Return nil when the path has no config for the plugin", func(t *testing.T) {
+		assert.Nil(t, routerPath.GetPluginConfig("cache"))
+		assert.Nil(t, routerPath.GetPluginConfig("ipfilter"))
+		assert.Nil(t, routerPath.GetPluginConfig("unknown"))
+	})
+}
@@ -9,9 +9,9 @@ import (
 	time "time"
 
 	gomock "github.com/golang/mock/gomock"
-	db "github.com/kodefluence/monorepo/db"
-
 	core "github.com/kodefluence/altair/core"
+	entity "github.com/kodefluence/altair/entity"
+	db "github.com/kodefluence/monorepo/db"
 )
 
 // MockAppLoader is a mock of AppLoader interface.
@@ -151,20 +151,6 @@ func (m *MockPluginBearer) EXPECT() *MockPluginBearerMockRecorder {
 	return m.recorder
 }
 
-// DecodeConfig mocks base method.
-func (m *MockPluginBearer) DecodeConfig(pluginName string, injectedStruct interface{}) error {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DecodeConfig", pluginName, injectedStruct)
-	ret0, _ := ret[0].(error)
-	return ret0
-}
-
-// DecodeConfig indicates an expected call of DecodeConfig.
-func (mr *MockPluginBearerMockRecorder) DecodeConfig(pluginName, injectedStruct interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecodeConfig", reflect.TypeOf((*MockPluginBearer)(nil).DecodeConfig), pluginName, injectedStruct)
-}
-
 // ConfigExists mocks base method.
 func (m *MockPluginBearer) ConfigExists(pluginName string) bool {
 	m.ctrl.T.Helper()
@@ -179,6 +165,20 @@ func (mr *MockPluginBearerMockRecorder) ConfigExists(pluginName interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfigExists", reflect.TypeOf((*MockPluginBearer)(nil).ConfigExists), pluginName)
 }
 
+// DecodeConfig mocks base method.
+func (m *MockPluginBearer) DecodeConfig(pluginName string, target interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecodeConfig", pluginName, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DecodeConfig indicates an expected call of DecodeConfig.
+func (mr *MockPluginBearerMockRecorder) DecodeConfig(pluginName, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecodeConfig", reflect.TypeOf((*MockPluginBearer)(nil).DecodeConfig), pluginName, target)
+}
+
 // ForEach mocks base method.
 func (m *MockPluginBearer) ForEach(callbackFunc func(string) error) {
 	m.ctrl.T.Helper()
@@ -316,20 +316,6 @@ func (mr *MockDatabaseConfigMockRecorder) DBMaxOpenConn() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DBMaxOpenConn", reflect.TypeOf((*MockDatabaseConfig)(nil).DBMaxOpenConn))
 }
 
-// DBMigrationSource mocks base method.
-func (m *MockDatabaseConfig) DBMigrationSource() string {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DBMigrationSource")
-	ret0, _ := ret[0].(string)
-	return ret0
-}
-
-// DBMigrationSource indicates an expected call of DBMigrationSource.
-func (mr *MockDatabaseConfigMockRecorder) DBMigrationSource() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DBMigrationSource", reflect.TypeOf((*MockDatabaseConfig)(nil).DBMigrationSource))
-}
-
 // DBPassword mocks base method.
 func (m *MockDatabaseConfig) DBPassword() string {
 	m.ctrl.T.Helper()
@@ -463,6 +449,20 @@ func (m *MockAppConfig) EXPECT() *MockAppConfigMockRecorder {
 	return m.recorder
 }
 
+// AutoMigrate mocks base method.
+func (m *MockAppConfig) AutoMigrate() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AutoMigrate")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// AutoMigrate indicates an expected call of AutoMigrate.
+func (mr *MockAppConfigMockRecorder) AutoMigrate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AutoMigrate", reflect.TypeOf((*MockAppConfig)(nil).AutoMigrate))
+}
+
 // BasicAuthPassword mocks base method.
 func (m *MockAppConfig) BasicAuthPassword() string {
 	m.ctrl.T.Helper()
@@ -491,6 +491,20 @@ func (mr *MockAppConfigMockRecorder) BasicAuthUsername() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BasicAuthUsername", reflect.TypeOf((*MockAppConfig)(nil).BasicAuthUsername))
 }
 
+// CORS mocks base method.
+func (m *MockAppConfig) CORS() *entity.RouteCORS {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CORS")
+	ret0, _ := ret[0].(*entity.RouteCORS)
+	return ret0
+}
+
+// CORS indicates an expected call of CORS.
+func (mr *MockAppConfigMockRecorder) CORS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CORS", reflect.TypeOf((*MockAppConfig)(nil).CORS))
+}
+
 // Dump mocks base method.
 func (m *MockAppConfig) Dump() string {
 	m.ctrl.T.Helper()
@@ -505,6 +519,34 @@ func (mr *MockAppConfigMockRecorder) Dump() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dump", reflect.TypeOf((*MockAppConfig)(nil).Dump))
 }
 
+// H2C mocks base method.
+func (m *MockAppConfig) H2C() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "H2C")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// H2C indicates an expected call of H2C.
+func (mr *MockAppConfigMockRecorder) H2C() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "H2C", reflect.TypeOf((*MockAppConfig)(nil).H2C))
+}
+
+// HTTP2 mocks base method.
+func (m *MockAppConfig) HTTP2() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HTTP2")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// HTTP2 indicates an expected call of HTTP2.
+func (mr *MockAppConfigMockRecorder) HTTP2() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HTTP2", reflect.TypeOf((*MockAppConfig)(nil).HTTP2))
+}
+
 // PluginExists mocks base method.
 func (m *MockAppConfig) PluginExists(pluginName string) bool {
 	m.ctrl.T.Helper()
@@ -561,6 +603,62 @@ func (mr *MockAppConfigMockRecorder) ProxyHost() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProxyHost", reflect.TypeOf((*MockAppConfig)(nil).ProxyHost))
 }
 
+// ShutdownDelay mocks base method.
+func (m *MockAppConfig) ShutdownDelay() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShutdownDelay")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// ShutdownDelay indicates an expected call of ShutdownDelay.
+func (mr *MockAppConfigMockRecorder) ShutdownDelay() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShutdownDelay", reflect.TypeOf((*MockAppConfig)(nil).ShutdownDelay))
+}
+
+// ShutdownDrainPeriod mocks base method.
+func (m *MockAppConfig) ShutdownDrainPeriod() time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShutdownDrainPeriod")
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// ShutdownDrainPeriod indicates an expected call of ShutdownDrainPeriod.
+func (mr *MockAppConfigMockRecorder) ShutdownDrainPeriod() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShutdownDrainPeriod", reflect.TypeOf((*MockAppConfig)(nil).ShutdownDrainPeriod))
+}
+
+// TLS mocks base method.
+func (m *MockAppConfig) TLS() entity.AppTLSConfig {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TLS")
+	ret0, _ := ret[0].(entity.AppTLSConfig)
+	return ret0
+}
+
+// TLS indicates an expected call of TLS.
+func (mr *MockAppConfigMockRecorder) TLS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TLS", reflect.TypeOf((*MockAppConfig)(nil).TLS))
+}
+
+// TrustedProxies mocks base method.
+func (m *MockAppConfig) TrustedProxies() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrustedProxies")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// TrustedProxies indicates an expected call of TrustedProxies.
+func (mr *MockAppConfigMockRecorder) TrustedProxies() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrustedProxies", reflect.TypeOf((*MockAppConfig)(nil).TrustedProxies))
+}
+
 // MockMetricConfig is a mock of MetricConfig interface.
 type MockMetricConfig struct {
 	ctrl     *gomock.Controller
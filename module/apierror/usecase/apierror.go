@@ -98,3 +98,31 @@ func (*ApiError) ValidationError(msg string) jsonapi.Option {
 		),
 	)
 }
+
+func (*ApiError) ServiceUnavailableError(ktx kontext.Context, serviceName string) jsonapi.Option {
+	err := fmt.Errorf("Service `%s` is temporarily unavailable, please try again later. Tracing code: `%v`", serviceName, ktx.GetWithoutCheck("request_id"))
+	return jsonapi.WithException(
+		"ERR0503",
+		http.StatusServiceUnavailable,
+		exception.Throw(
+			err,
+			exception.WithTitle("Service unavailable error"),
+			exception.WithDetail(err.Error()),
+			exception.WithType(exception.Unexpected),
+		),
+	)
+}
+
+func (*ApiError) TooManyRequestsError(ktx kontext.Context) jsonapi.Option {
+	err := fmt.Errorf("You have sent too many requests, please slow down and try again later. Tracing code: `%v`", ktx.GetWithoutCheck("request_id"))
+	return jsonapi.WithException(
+		"ERR0429",
+		http.StatusTooManyRequests,
+		exception.Throw(
+			err,
+			exception.WithTitle("Too many requests error"),
+			exception.WithDetail(err.Error()),
+			exception.WithType(exception.Forbidden),
+		),
+	)
+}
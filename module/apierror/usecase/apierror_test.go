@@ -95,4 +95,33 @@ func TestApiError(t *testing.T) {
 			response.Errors.Error(),
 		)
 	})
+
+	t.Run("Service unavailable error", func(t *testing.T) {
+		ktx := kontext.Fabricate()
+		uuid := uuid.New()
+		ktx.Set("request_id", uuid)
+
+		response := jsonapi.BuildResponse(usecase.NewApiError().ServiceUnavailableError(ktx, "users"))
+
+		assert.Equal(t, http.StatusServiceUnavailable, response.HTTPStatus())
+		assert.Equal(
+			t,
+			fmt.Sprintf("JSONAPI Error:\n[Service unavailable error] Detail: Service `users` is temporarily unavailable, please try again later. Tracing code: `%v`, Code: ERR0503\n", uuid),
+			response.Errors.Error(),
+		)
+	})
+	t.Run("Too many requests error", func(t *testing.T) {
+		ktx := kontext.Fabricate()
+		uuid := uuid.New()
+		ktx.Set("request_id", uuid)
+
+		response := jsonapi.BuildResponse(usecase.NewApiError().TooManyRequestsError(ktx))
+
+		assert.Equal(t, http.StatusTooManyRequests, response.HTTPStatus())
+		assert.Equal(
+			t,
+			fmt.Sprintf("JSONAPI Error:\n[Too many requests error] Detail: You have sent too many requests, please slow down and try again later. Tracing code: `%v`, Code: ERR0429\n", uuid),
+			response.Errors.Error(),
+		)
+	})
 }
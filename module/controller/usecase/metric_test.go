@@ -14,10 +14,14 @@ type fakeMetric struct{}
 
 func (*fakeMetric) InjectCounter(metricName string, labels ...string)     {}
 func (*fakeMetric) InjectHistogram(metricName string, labels ...string)   {}
+func (*fakeMetric) InjectGauge(metricName string, labels ...string)       {}
 func (*fakeMetric) Inc(metricName string, labels map[string]string) error { return nil }
 func (*fakeMetric) Observe(metricName string, value float64, labels map[string]string) error {
 	return nil
 }
+func (*fakeMetric) Set(metricName string, value float64, labels map[string]string) error {
+	return nil
+}
 
 func TestMetric(t *testing.T) {
 	suite.Run(t, &MetricSuiteTest{
@@ -2,19 +2,28 @@ package http
 
 import (
 	"net/http"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kodefluence/monorepo/kontext"
-
-	"github.com/kodefluence/altair/module"
 )
 
-type HealthController struct{}
+// HealthController answers the load balancer health check. Once Drain is
+// called it starts failing so the instance is taken out of rotation before
+// altair stops accepting connections.
+type HealthController struct {
+	draining atomic.Bool
+}
 
-func NewHealthController() module.HttpController {
+func NewHealthController() *HealthController {
 	return &HealthController{}
 }
 
+// Drain flips the health check to failing.
+func (h *HealthController) Drain() {
+	h.draining.Store(true)
+}
+
 func (*HealthController) Path() string {
 	return "/health"
 }
@@ -23,7 +32,14 @@ func (*HealthController) Method() string {
 	return "GET"
 }
 
-func (*HealthController) Control(ktx kontext.Context, c *gin.Context) {
+func (h *HealthController) Control(ktx kontext.Context, c *gin.Context) {
+	if h.draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"message": "Draining",
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "OK",
 	})
@@ -24,5 +24,17 @@ func TestHealth(t *testing.T) {
 			w := testhelper.PerformRequest(engine, "GET", "/health", nil)
 			assert.Equal(t, http.StatusOK, w.Code)
 		})
+
+		t.Run("Return service unavailable while draining", func(t *testing.T) {
+			gin.SetMode(gin.ReleaseMode)
+			engine := gin.New()
+
+			healthController := healthcheckHttp.NewHealthController()
+			controller.Provide(engine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(healthController)
+			healthController.Drain()
+
+			w := testhelper.PerformRequest(engine, "GET", "/health", nil)
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		})
 	})
 }
@@ -5,6 +5,10 @@ import (
 	"github.com/kodefluence/altair/module/healthcheck/controller/http"
 )
 
-func Load(app module.App) {
-	app.Controller().InjectHTTP(http.NewHealthController())
+// Load registers the health check endpoint and returns its controller so the
+// caller can fail it while shutting down.
+func Load(app module.App) *http.HealthController {
+	healthController := http.NewHealthController()
+	app.Controller().InjectHTTP(healthController)
+	return healthController
 }
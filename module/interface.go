@@ -29,8 +29,10 @@ type Controller interface {
 type MetricController interface {
 	InjectCounter(metricName string, labels ...string)
 	InjectHistogram(metricName string, labels ...string)
+	InjectGauge(metricName string, labels ...string)
 	Inc(metricName string, labels map[string]string) error
 	Observe(metricName string, value float64, labels map[string]string) error
+	Set(metricName string, value float64, labels map[string]string) error
 }
 
 type HttpController interface {
@@ -57,6 +59,25 @@ type DownstreamController interface {
 	Intervene(c *gin.Context, proxyReq *http.Request, r RouterPath) error
 }
 
+// BodyReaderDownstreamController is implemented by downstream plugins that read
+// the request body through proxyReq.GetBody. The body is buffered only for
+// router paths where one of them returns true, every other request is streamed
+// to the upstream.
+type BodyReaderDownstreamController interface {
+	DownstreamController
+	ReadsBody(r RouterPath) bool
+}
+
+// ResponseDownstreamController is implemented by downstream plugins that look
+// at the response once it is written to the client, AfterResponse is called for
+// every request the plugins let through. A plugin answering the request itself
+// aborts the gin context in Intervene and returns nil, the request is then not
+// forwarded.
+type ResponseDownstreamController interface {
+	DownstreamController
+	AfterResponse(c *gin.Context, proxyReq *http.Request, r RouterPath)
+}
+
 type ApiError interface {
 	InternalServerError(ktx kontext.Context) jsonapi.Option
 	BadRequestError(in string) jsonapi.Option
@@ -64,11 +85,14 @@ type ApiError interface {
 	UnauthorizedError() jsonapi.Option
 	ForbiddenError(ktx kontext.Context, entityType, reason string) jsonapi.Option
 	ValidationError(msg string) jsonapi.Option
+	ServiceUnavailableError(ktx kontext.Context, serviceName string) jsonapi.Option
+	TooManyRequestsError(ktx kontext.Context) jsonapi.Option
 }
 
 type RouterPath interface {
 	GetAuth() string
 	GetScope() string
+	GetPluginConfig(name string) interface{}
 }
 
 // type RouterCompiler interface {
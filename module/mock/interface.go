@@ -223,6 +223,23 @@ func (mr *MockMetricControllerMockRecorder) InjectCounter(metricName interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InjectCounter", reflect.TypeOf((*MockMetricController)(nil).InjectCounter), varargs...)
 }
 
+// InjectGauge mocks base method.
+func (m *MockMetricController) InjectGauge(metricName string, labels ...string) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{metricName}
+	for _, a := range labels {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "InjectGauge", varargs...)
+}
+
+// InjectGauge indicates an expected call of InjectGauge.
+func (mr *MockMetricControllerMockRecorder) InjectGauge(metricName interface{}, labels ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{metricName}, labels...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InjectGauge", reflect.TypeOf((*MockMetricController)(nil).InjectGauge), varargs...)
+}
+
 // InjectHistogram mocks base method.
 func (m *MockMetricController) InjectHistogram(metricName string, labels ...string) {
 	m.ctrl.T.Helper()
@@ -254,6 +271,20 @@ func (mr *MockMetricControllerMockRecorder) Observe(metricName, value, labels in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Observe", reflect.TypeOf((*MockMetricController)(nil).Observe), metricName, value, labels)
 }
 
+// Set mocks base method.
+func (m *MockMetricController) Set(metricName string, value float64, labels map[string]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", metricName, value, labels)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockMetricControllerMockRecorder) Set(metricName, value, labels interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockMetricController)(nil).Set), metricName, value, labels)
+}
+
 // MockHttpController is a mock of HttpController interface.
 type MockHttpController struct {
 	ctrl     *gomock.Controller
@@ -457,6 +488,134 @@ func (mr *MockDownstreamControllerMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockDownstreamController)(nil).Name))
 }
 
+// MockBodyReaderDownstreamController is a mock of BodyReaderDownstreamController interface.
+type MockBodyReaderDownstreamController struct {
+	ctrl     *gomock.Controller
+	recorder *MockBodyReaderDownstreamControllerMockRecorder
+}
+
+// MockBodyReaderDownstreamControllerMockRecorder is the mock recorder for MockBodyReaderDownstreamController.
+type MockBodyReaderDownstreamControllerMockRecorder struct {
+	mock *MockBodyReaderDownstreamController
+}
+
+// NewMockBodyReaderDownstreamController creates a new mock instance.
+func NewMockBodyReaderDownstreamController(ctrl *gomock.Controller) *MockBodyReaderDownstreamController {
+	mock := &MockBodyReaderDownstreamController{ctrl: ctrl}
+	mock.recorder = &MockBodyReaderDownstreamControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBodyReaderDownstreamController) EXPECT() *MockBodyReaderDownstreamControllerMockRecorder {
+	return m.recorder
+}
+
+// Intervene mocks base method.
+func (m *MockBodyReaderDownstreamController) Intervene(c *gin.Context, proxyReq *http.Request, r module.RouterPath) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Intervene", c, proxyReq, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Intervene indicates an expected call of Intervene.
+func (mr *MockBodyReaderDownstreamControllerMockRecorder) Intervene(c, proxyReq, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Intervene", reflect.TypeOf((*MockBodyReaderDownstreamController)(nil).Intervene), c, proxyReq, r)
+}
+
+// Name mocks base method.
+func (m *MockBodyReaderDownstreamController) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockBodyReaderDownstreamControllerMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockBodyReaderDownstreamController)(nil).Name))
+}
+
+// ReadsBody mocks base method.
+func (m *MockBodyReaderDownstreamController) ReadsBody(r module.RouterPath) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadsBody", r)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ReadsBody indicates an expected call of ReadsBody.
+func (mr *MockBodyReaderDownstreamControllerMockRecorder) ReadsBody(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadsBody", reflect.TypeOf((*MockBodyReaderDownstreamController)(nil).ReadsBody), r)
+}
+
+// MockResponseDownstreamController is a mock of ResponseDownstreamController interface.
+type MockResponseDownstreamController struct {
+	ctrl     *gomock.Controller
+	recorder *MockResponseDownstreamControllerMockRecorder
+}
+
+// MockResponseDownstreamControllerMockRecorder is the mock recorder for MockResponseDownstreamController.
+type MockResponseDownstreamControllerMockRecorder struct {
+	mock *MockResponseDownstreamController
+}
+
+// NewMockResponseDownstreamController creates a new mock instance.
+func NewMockResponseDownstreamController(ctrl *gomock.Controller) *MockResponseDownstreamController {
+	mock := &MockResponseDownstreamController{ctrl: ctrl}
+	mock.recorder = &MockResponseDownstreamControllerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResponseDownstreamController) EXPECT() *MockResponseDownstreamControllerMockRecorder {
+	return m.recorder
+}
+
+// AfterResponse mocks base method.
+func (m *MockResponseDownstreamController) AfterResponse(c *gin.Context, proxyReq *http.Request, r module.RouterPath) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterResponse", c, proxyReq, r)
+}
+
+// AfterResponse indicates an expected call of AfterResponse.
+func (mr *MockResponseDownstreamControllerMockRecorder) AfterResponse(c, proxyReq, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterResponse", reflect.TypeOf((*MockResponseDownstreamController)(nil).AfterResponse), c, proxyReq, r)
+}
+
+// Intervene mocks base method.
+func (m *MockResponseDownstreamController) Intervene(c *gin.Context, proxyReq *http.Request, r module.RouterPath) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Intervene", c, proxyReq, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Intervene indicates an expected call of Intervene.
+func (mr *MockResponseDownstreamControllerMockRecorder) Intervene(c, proxyReq, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Intervene", reflect.TypeOf((*MockResponseDownstreamController)(nil).Intervene), c, proxyReq, r)
+}
+
+// Name mocks base method.
+func (m *MockResponseDownstreamController) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockResponseDownstreamControllerMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockResponseDownstreamController)(nil).Name))
+}
+
 // MockApiError is a mock of ApiError interface.
 type MockApiError struct {
 	ctrl     *gomock.Controller
@@ -536,6 +695,34 @@ func (mr *MockApiErrorMockRecorder) NotFoundError(ktx, entityType interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotFoundError", reflect.TypeOf((*MockApiError)(nil).NotFoundError), ktx, entityType)
 }
 
+// ServiceUnavailableError mocks base method.
+func (m *MockApiError) ServiceUnavailableError(ktx kontext.Context, serviceName string) jsonapi.Option {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServiceUnavailableError", ktx, serviceName)
+	ret0, _ := ret[0].(jsonapi.Option)
+	return ret0
+}
+
+// ServiceUnavailableError indicates an expected call of ServiceUnavailableError.
+func (mr *MockApiErrorMockRecorder) ServiceUnavailableError(ktx, serviceName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServiceUnavailableError", reflect.TypeOf((*MockApiError)(nil).ServiceUnavailableError), ktx, serviceName)
+}
+
+// TooManyRequestsError mocks base method.
+func (m *MockApiError) TooManyRequestsError(ktx kontext.Context) jsonapi.Option {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TooManyRequestsError", ktx)
+	ret0, _ := ret[0].(jsonapi.Option)
+	return ret0
+}
+
+// TooManyRequestsError indicates an expected call of TooManyRequestsError.
+func (mr *MockApiErrorMockRecorder) TooManyRequestsError(ktx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TooManyRequestsError", reflect.TypeOf((*MockApiError)(nil).TooManyRequestsError), ktx)
+}
+
 // UnauthorizedError mocks base method.
 func (m *MockApiError) UnauthorizedError() jsonapi.Option {
 	m.ctrl.T.Helper()
@@ -601,6 +788,20 @@ func (mr *MockRouterPathMockRecorder) GetAuth() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuth", reflect.TypeOf((*MockRouterPath)(nil).GetAuth))
 }
 
+// GetPluginConfig mocks base method.
+func (m *MockRouterPath) GetPluginConfig(name string) interface{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPluginConfig", name)
+	ret0, _ := ret[0].(interface{})
+	return ret0
+}
+
+// GetPluginConfig indicates an expected call of GetPluginConfig.
+func (mr *MockRouterPathMockRecorder) GetPluginConfig(name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPluginConfig", reflect.TypeOf((*MockRouterPath)(nil).GetPluginConfig), name)
+}
+
 // GetScope mocks base method.
 func (m *MockRouterPath) GetScope() string {
 	m.ctrl.T.Helper()
@@ -64,6 +64,11 @@ type PluginContext struct {
 	AppBearer core.AppBearer
 	AppModule App
 	ApiError  ApiError
+
+	// PublicModule registers HTTP controllers on the root of the api engine,
+	// outside of the basic auth guarding AppModule under /_plugins. It is nil
+	// for LoadCommand.
+	PublicModule App
 }
 
 // MigrationSet describes one embedded migration source belonging to a plugin.
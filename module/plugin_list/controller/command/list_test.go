@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 	"testing/fstest"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
@@ -11,6 +12,7 @@ import (
 	"github.com/kodefluence/monorepo/db"
 
 	"github.com/kodefluence/altair/core"
+	"github.com/kodefluence/altair/entity"
 	"github.com/kodefluence/altair/module"
 	"github.com/kodefluence/altair/module/migration/usecase"
 	"github.com/kodefluence/altair/module/plugin_list/controller/command"
@@ -22,14 +24,21 @@ type stubAppConfig struct {
 	enabled map[string]bool
 }
 
-func (s *stubAppConfig) Port() int                     { return 0 }
-func (s *stubAppConfig) BasicAuthUsername() string     { return "" }
-func (s *stubAppConfig) BasicAuthPassword() string     { return "" }
-func (s *stubAppConfig) ProxyHost() string             { return "" }
-func (s *stubAppConfig) PluginExists(name string) bool { return s.enabled[name] }
-func (s *stubAppConfig) Plugins() []string             { return nil }
-func (s *stubAppConfig) AutoMigrate() bool             { return false }
-func (s *stubAppConfig) Dump() string                  { return "" }
+func (s *stubAppConfig) Port() int                          { return 0 }
+func (s *stubAppConfig) BasicAuthUsername() string          { return "" }
+func (s *stubAppConfig) BasicAuthPassword() string          { return "" }
+func (s *stubAppConfig) ProxyHost() string                  { return "" }
+func (s *stubAppConfig) PluginExists(name string) bool      { return s.enabled[name] }
+func (s *stubAppConfig) Plugins() []string                  { return nil }
+func (s *stubAppConfig) AutoMigrate() bool                  { return false }
+func (s *stubAppConfig) TLS() entity.AppTLSConfig           { return entity.AppTLSConfig{} }
+func (s *stubAppConfig) HTTP2() bool                        { return false }
+func (s *stubAppConfig) H2C() bool                          { return false }
+func (s *stubAppConfig) ShutdownDelay() time.Duration       { return 0 }
+func (s *stubAppConfig) ShutdownDrainPeriod() time.Duration { return 0 }
+func (s *stubAppConfig) CORS() *entity.RouteCORS            { return nil }
+func (s *stubAppConfig) TrustedProxies() []string           { return nil }
+func (s *stubAppConfig) Dump() string                       { return "" }
 
 type stubAppBearer struct {
 	cfg core.AppConfig
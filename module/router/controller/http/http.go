@@ -0,0 +1,12 @@
+package http
+
+import (
+	"github.com/kodefluence/altair/entity"
+)
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./http.go
+
+type RouteReloader interface {
+	Reload() error
+	RouteObjects() []entity.RouteObject
+}
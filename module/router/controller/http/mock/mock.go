@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./http.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/kodefluence/altair/entity"
+)
+
+// MockRouteReloader is a mock of RouteReloader interface.
+type MockRouteReloader struct {
+	ctrl     *gomock.Controller
+	recorder *MockRouteReloaderMockRecorder
+}
+
+// MockRouteReloaderMockRecorder is the mock recorder for MockRouteReloader.
+type MockRouteReloaderMockRecorder struct {
+	mock *MockRouteReloader
+}
+
+// NewMockRouteReloader creates a new mock instance.
+func NewMockRouteReloader(ctrl *gomock.Controller) *MockRouteReloader {
+	mock := &MockRouteReloader{ctrl: ctrl}
+	mock.recorder = &MockRouteReloaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRouteReloader) EXPECT() *MockRouteReloaderMockRecorder {
+	return m.recorder
+}
+
+// Reload mocks base method.
+func (m *MockRouteReloader) Reload() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockRouteReloaderMockRecorder) Reload() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockRouteReloader)(nil).Reload))
+}
+
+// RouteObjects mocks base method.
+func (m *MockRouteReloader) RouteObjects() []entity.RouteObject {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RouteObjects")
+	ret0, _ := ret[0].([]entity.RouteObject)
+	return ret0
+}
+
+// RouteObjects indicates an expected call of RouteObjects.
+func (mr *MockRouteReloaderMockRecorder) RouteObjects() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RouteObjects", reflect.TypeOf((*MockRouteReloader)(nil).RouteObjects))
+}
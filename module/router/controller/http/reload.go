@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+
+	"github.com/kodefluence/altair/module"
+)
+
+// ReloadController recompiles the routes directory and swaps the routing table
+type ReloadController struct {
+	routeReloader RouteReloader
+	apiError      module.ApiError
+}
+
+// NewReload return struct of ReloadController
+func NewReload(routeReloader RouteReloader, apiError module.ApiError) *ReloadController {
+	return &ReloadController{
+		routeReloader: routeReloader,
+		apiError:      apiError,
+	}
+}
+
+// Method POST
+func (r *ReloadController) Method() string {
+	return "POST"
+}
+
+// Path /routes/reload
+func (r *ReloadController) Path() string {
+	return "/routes/reload"
+}
+
+// Control reload the routes, the current routes keep serving when it fails
+func (r *ReloadController) Control(ktx kontext.Context, c *gin.Context) {
+	if err := r.routeReloader.Reload(); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, jsonapi.BuildResponse(r.apiError.ValidationError(err.Error())))
+		return
+	}
+
+	routes := []string{}
+	for _, routeObject := range r.routeReloader.RouteObjects() {
+		routes = append(routes, routeObject.Name)
+	}
+
+	c.JSON(http.StatusOK, jsonapi.BuildResponse(
+		jsonapi.WithData(gin.H{"routes": routes}),
+	))
+}
@@ -0,0 +1,84 @@
+package http_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/controller"
+	routerHttp "github.com/kodefluence/altair/module/router/controller/http"
+	"github.com/kodefluence/altair/module/router/controller/http/mock"
+	"github.com/kodefluence/altair/testhelper"
+)
+
+type responseReload struct {
+	Data struct {
+		Routes []string `json:"routes"`
+	} `json:"data"`
+}
+
+func TestReload(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	apierror := apierror.Provide()
+
+	t.Run("Method", func(t *testing.T) {
+		routeReloader := mock.NewMockRouteReloader(mockCtrl)
+		assert.Equal(t, "POST", routerHttp.NewReload(routeReloader, apierror).Method())
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		routeReloader := mock.NewMockRouteReloader(mockCtrl)
+		assert.Equal(t, "/routes/reload", routerHttp.NewReload(routeReloader, apierror).Path())
+	})
+
+	t.Run("Control", func(t *testing.T) {
+		t.Run("Routes compiled", func(t *testing.T) {
+			t.Run("Return reloaded route names", func(t *testing.T) {
+				apiEngine := gin.New()
+
+				routeReloader := mock.NewMockRouteReloader(mockCtrl)
+				routeReloader.EXPECT().Reload().Return(nil)
+				routeReloader.EXPECT().RouteObjects().Return([]entity.RouteObject{{Name: "users"}, {Name: "orders"}})
+
+				ctrl := routerHttp.NewReload(routeReloader, apierror)
+				controller.Provide(apiEngine.Handle, apierror, &cobra.Command{}).InjectHTTP(ctrl)
+
+				var response responseReload
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), nil)
+
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Nil(t, err)
+
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, []string{"users", "orders"}, response.Data.Routes)
+			})
+		})
+
+		t.Run("Routes failed to compile", func(t *testing.T) {
+			t.Run("Return unprocessable entity", func(t *testing.T) {
+				apiEngine := gin.New()
+
+				routeReloader := mock.NewMockRouteReloader(mockCtrl)
+				routeReloader.EXPECT().Reload().Return(errors.New("route `users`: retry.max_attempts cannot be negative"))
+
+				ctrl := routerHttp.NewReload(routeReloader, apierror)
+				controller.Provide(apiEngine.Handle, apierror, &cobra.Command{}).InjectHTTP(ctrl)
+
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), nil)
+
+				assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+				assert.Contains(t, w.Body.String(), "retry.max_attempts cannot be negative")
+			})
+		})
+	})
+}
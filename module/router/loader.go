@@ -0,0 +1,14 @@
+package router
+
+import (
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/router/controller/http"
+	"github.com/kodefluence/altair/module/router/usecase"
+)
+
+// Load registers the endpoint reloading the routes, it is meant for the
+// authenticated plugin module.
+func Load(app module.App, router *usecase.Router) {
+	app.Controller().InjectHTTP(http.NewReload(router, apierror.Provide()))
+}
@@ -1,10 +1,11 @@
 package router
 
 import (
+	"github.com/kodefluence/altair/core"
 	"github.com/kodefluence/altair/module"
 	"github.com/kodefluence/altair/module/router/usecase"
 )
 
-func Provide(downStreamPlugin []module.DownstreamController, metric []module.MetricController) (*usecase.Compiler, *usecase.Generator) {
-	return usecase.NewCompiler(), usecase.NewGenerator(downStreamPlugin, metric)
+func Provide(appConfig core.AppConfig, routesPath string, downStreamPlugin []module.DownstreamController, metric []module.MetricController, apiError module.ApiError) *usecase.Router {
+	return usecase.NewRouter(usecase.NewCompiler(), routesPath, appConfig.ProxyHost(), appConfig.CORS(), appConfig.TrustedProxies(), downStreamPlugin, metric, apiError)
 }
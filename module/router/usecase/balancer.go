@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"hash/crc32"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module"
+)
+
+const (
+	strategyRoundRobin     = "round_robin"
+	strategyWeighted       = "weighted"
+	strategyLeastInFlight  = "least_in_flight"
+	strategyConsistentHash = "consistent_hash"
+
+	hashOnClientIP     = "client_ip"
+	hashOnHeaderPrefix = "header:"
+
+	// consistentHashReplicas is the number of virtual nodes placed on the ring
+	// for every unit of target weight.
+	consistentHashReplicas = 64
+)
+
+type upstreamTarget struct {
+	host     string
+	weight   int
+	inFlight int64
+
+	// healthy is the combined verdict reported to the routes_upstream_healthy
+	// gauge. Passive and active checks keep their own state below so neither
+	// resets or overrides the other.
+	healthy int32
+
+	passiveFailures int32
+	ejectedUntil    int64
+
+	activeHealthy  int32
+	probeFailures  int32
+	probeSuccesses int32
+}
+
+func (t *upstreamTarget) acquire() {
+	atomic.AddInt64(&t.inFlight, 1)
+}
+
+func (t *upstreamTarget) release() {
+	atomic.AddInt64(&t.inFlight, -1)
+}
+
+// upstreamPool picks one healthy target per request following the route's
+// balancing strategy. A pool is built once per route object and shared by every
+// path registered under it.
+type upstreamPool struct {
+	routeName string
+	strategy  string
+	hashOn    string
+	targets   []*upstreamTarget
+	fallback  *upstreamTarget
+
+	scheme      string
+	health      healthPolicy
+	probeClient *http.Client
+	metrics     []module.MetricController
+	stop        chan struct{}
+	stopOnce    *sync.Once
+
+	counter uint64
+
+	weightLock    *sync.Mutex
+	currentWeight []int
+
+	ring       []uint32
+	ringTarget map[uint32]*upstreamTarget
+}
+
+func newUpstreamPool(routeObject entity.RouteObject, metrics []module.MetricController) *upstreamPool {
+	pool := &upstreamPool{
+		routeName:  routeObject.Name,
+		strategy:   routeObject.Upstream.Strategy,
+		hashOn:     routeObject.Upstream.HashOn,
+		scheme:     routeScheme(routeObject),
+		health:     newHealthPolicy(routeObject.Upstream.HealthCheck),
+		metrics:    metrics,
+		stop:       make(chan struct{}),
+		stopOnce:   &sync.Once{},
+		weightLock: &sync.Mutex{},
+		ringTarget: map[uint32]*upstreamTarget{},
+	}
+
+	pool.probeClient = &http.Client{Timeout: pool.health.timeout}
+
+	if pool.strategy == "" {
+		pool.strategy = strategyRoundRobin
+	}
+
+	if pool.hashOn == "" {
+		pool.hashOn = hashOnClientIP
+	}
+
+	for _, target := range routeObject.Upstream.Targets {
+		weight := target.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+
+		pool.targets = append(pool.targets, &upstreamTarget{host: target.Host, weight: weight, healthy: 1, activeHealthy: 1})
+	}
+
+	if len(pool.targets) == 0 {
+		pool.targets = append(pool.targets, &upstreamTarget{host: routeObject.Host, weight: 1, healthy: 1, activeHealthy: 1})
+	}
+
+	if routeObject.Upstream.FallbackHost != "" {
+		pool.fallback = &upstreamTarget{host: routeObject.Upstream.FallbackHost, weight: 1, healthy: 1, activeHealthy: 1}
+	}
+
+	for _, target := range pool.targets {
+		pool.reportHealth(target)
+	}
+
+	pool.currentWeight = make([]int, len(pool.targets))
+
+	if pool.strategy == strategyConsistentHash {
+		pool.buildRing()
+	}
+
+	return pool
+}
+
+// pick returns the target for the current request. When every target is
+// ejected it returns the fallback target, or nil if none is configured.
+func (p *upstreamPool) pick(c *gin.Context) *upstreamTarget {
+	var target *upstreamTarget
+
+	switch p.strategy {
+	case strategyWeighted:
+		target = p.pickWeighted()
+	case strategyLeastInFlight:
+		target = p.pickLeastInFlight()
+	case strategyConsistentHash:
+		target = p.pickConsistentHash(c)
+	default:
+		target = p.pickRoundRobin()
+	}
+
+	if target == nil {
+		return p.fallback
+	}
+
+	return target
+}
+
+func (p *upstreamPool) pickRoundRobin() *upstreamTarget {
+	n := atomic.AddUint64(&p.counter, 1) - 1
+
+	for i := range p.targets {
+		target := p.targets[(n+uint64(i))%uint64(len(p.targets))]
+		if p.available(target) {
+			return target
+		}
+	}
+
+	return nil
+}
+
+// pickWeighted implements the smooth weighted round robin used by nginx, which
+// interleaves heavier targets instead of sending them bursts of requests.
+func (p *upstreamPool) pickWeighted() *upstreamTarget {
+	p.weightLock.Lock()
+	defer p.weightLock.Unlock()
+
+	total := 0
+	best := -1
+
+	for i, target := range p.targets {
+		if !p.available(target) {
+			continue
+		}
+
+		p.currentWeight[i] += target.weight
+		total += target.weight
+
+		if best == -1 || p.currentWeight[i] > p.currentWeight[best] {
+			best = i
+		}
+	}
+
+	if best == -1 {
+		return nil
+	}
+
+	p.currentWeight[best] -= total
+	return p.targets[best]
+}
+
+func (p *upstreamPool) pickLeastInFlight() *upstreamTarget {
+	offset := int(atomic.AddUint64(&p.counter, 1) % uint64(len(p.targets)))
+
+	var best *upstreamTarget
+	var bestLoad float64
+
+	// Start from a rotating offset so ties are spread across targets instead of
+	// always landing on the first one.
+	for i := range p.targets {
+		target := p.targets[(offset+i)%len(p.targets)]
+		if !p.available(target) {
+			continue
+		}
+
+		load := float64(atomic.LoadInt64(&target.inFlight)) / float64(target.weight)
+
+		if best == nil || load < bestLoad {
+			best = target
+			bestLoad = load
+		}
+	}
+
+	return best
+}
+
+// pickConsistentHash walks clockwise from the key's position on the ring, so
+// keys owned by an ejected target move to its neighbour and come back once it
+// recovers.
+func (p *upstreamPool) pickConsistentHash(c *gin.Context) *upstreamTarget {
+	hash := crc32.ChecksumIEEE([]byte(p.hashKey(c)))
+	start := sort.Search(len(p.ring), func(i int) bool { return p.ring[i] >= hash })
+
+	for i := range p.ring {
+		target := p.ringTarget[p.ring[(start+i)%len(p.ring)]]
+		if p.available(target) {
+			return target
+		}
+	}
+
+	return nil
+}
+
+func (p *upstreamPool) hashKey(c *gin.Context) string {
+	if strings.HasPrefix(p.hashOn, hashOnHeaderPrefix) {
+		if value := c.GetHeader(strings.TrimPrefix(p.hashOn, hashOnHeaderPrefix)); value != "" {
+			return value
+		}
+	}
+
+	return c.ClientIP()
+}
+
+func (p *upstreamPool) buildRing() {
+	for _, target := range p.targets {
+		for i := 0; i < consistentHashReplicas*target.weight; i++ {
+			hash := crc32.ChecksumIEEE([]byte(target.host + "#" + strconv.Itoa(i)))
+			if _, collide := p.ringTarget[hash]; collide {
+				continue
+			}
+
+			p.ring = append(p.ring, hash)
+			p.ringTarget[hash] = target
+		}
+	}
+
+	sort.Slice(p.ring, func(i, j int) bool { return p.ring[i] < p.ring[j] })
+}
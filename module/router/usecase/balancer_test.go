@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+func TestUpstreamPool(t *testing.T) {
+	newContext := func(modifier func(req *http.Request)) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request, _ = http.NewRequest("GET", "/users/me", nil)
+		c.Request.RemoteAddr = "10.0.0.1:5000"
+		if modifier != nil {
+			modifier(c.Request)
+		}
+		return c
+	}
+
+	t.Run("Fallback to single host", func(t *testing.T) {
+		pool := newUpstreamPool(entity.RouteObject{Host: "localhost:3000"}, nil)
+
+		assert.Equal(t, "localhost:3000", pool.pick(newContext(nil)).host)
+		assert.Equal(t, strategyRoundRobin, pool.strategy)
+	})
+
+	t.Run("Round robin", func(t *testing.T) {
+		pool := newUpstreamPool(entity.RouteObject{
+			Upstream: entity.RouteUpstream{
+				Targets: []entity.RouteTarget{{Host: "a"}, {Host: "b"}, {Host: "c"}},
+			},
+		}, nil)
+
+		var picked []string
+		for i := 0; i < 6; i++ {
+			picked = append(picked, pool.pick(newContext(nil)).host)
+		}
+
+		assert.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, picked)
+	})
+
+	t.Run("Weighted", func(t *testing.T) {
+		pool := newUpstreamPool(entity.RouteObject{
+			Upstream: entity.RouteUpstream{
+				Strategy: strategyWeighted,
+				Targets:  []entity.RouteTarget{{Host: "a", Weight: 3}, {Host: "b", Weight: 1}},
+			},
+		}, nil)
+
+		hits := map[string]int{}
+		for i := 0; i < 8; i++ {
+			hits[pool.pick(newContext(nil)).host]++
+		}
+
+		assert.Equal(t, 6, hits["a"])
+		assert.Equal(t, 2, hits["b"])
+	})
+
+	t.Run("Least in flight", func(t *testing.T) {
+		pool := newUpstreamPool(entity.RouteObject{
+			Upstream: entity.RouteUpstream{
+				Strategy: strategyLeastInFlight,
+				Targets:  []entity.RouteTarget{{Host: "a"}, {Host: "b"}},
+			},
+		}, nil)
+
+		busy := pool.targets[0]
+		busy.acquire()
+		busy.acquire()
+
+		for i := 0; i < 4; i++ {
+			assert.Equal(t, "b", pool.pick(newContext(nil)).host)
+		}
+
+		busy.release()
+		busy.release()
+	})
+
+	t.Run("Consistent hash", func(t *testing.T) {
+		t.Run("Hash on client ip", func(t *testing.T) {
+			pool := newUpstreamPool(entity.RouteObject{
+				Upstream: entity.RouteUpstream{
+					Strategy: strategyConsistentHash,
+					Targets:  []entity.RouteTarget{{Host: "a"}, {Host: "b"}, {Host: "c"}},
+				},
+			}, nil)
+
+			first := pool.pick(newContext(nil)).host
+			for i := 0; i < 10; i++ {
+				assert.Equal(t, first, pool.pick(newContext(nil)).host)
+			}
+		})
+
+		t.Run("Hash on header", func(t *testing.T) {
+			pool := newUpstreamPool(entity.RouteObject{
+				Upstream: entity.RouteUpstream{
+					Strategy: strategyConsistentHash,
+					HashOn:   "header:X-User-ID",
+					Targets:  []entity.RouteTarget{{Host: "a"}, {Host: "b"}, {Host: "c"}},
+				},
+			}, nil)
+
+			hits := map[string]bool{}
+			for i := 0; i < 50; i++ {
+				userID := string(rune('a' + i%26))
+				c := newContext(func(req *http.Request) { req.Header.Set("X-User-ID", userID) })
+
+				host := pool.pick(c).host
+				assert.Equal(t, host, pool.pick(c).host)
+				hits[host] = true
+			}
+
+			assert.Greater(t, len(hits), 1)
+		})
+	})
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module"
+)
+
+const (
+	breakerStateClosed   = "closed"
+	breakerStateOpen     = "open"
+	breakerStateHalfOpen = "half_open"
+
+	defaultBreakerMinRequests      = 10
+	defaultBreakerWindow           = time.Second * 10
+	defaultBreakerCoolDown         = time.Second * 30
+	defaultBreakerHalfOpenRequests = 1
+)
+
+// circuitBreaker guards one route. While closed it counts outcomes in a fixed
+// window, once open it rejects every call until the cool down passes, then lets
+// a few probe calls through in half open to decide whether to close again.
+type circuitBreaker struct {
+	routeName string
+	enabled   bool
+	metrics   []module.MetricController
+
+	errorRateThreshold    float64
+	slowCallThreshold     time.Duration
+	slowCallRateThreshold float64
+	minRequests           int
+	window                time.Duration
+	coolDown              time.Duration
+	halfOpenRequests      int
+
+	mutex       sync.Mutex
+	state       string
+	windowStart time.Time
+	openedAt    time.Time
+	total       int
+	failures    int
+	slowCalls   int
+	probes      int
+	probeOK     int
+	// generation changes on every transition, a call only counts toward the
+	// state it was admitted under.
+	generation uint64
+}
+
+func newCircuitBreaker(routeObject entity.RouteObject, metrics []module.MetricController) *circuitBreaker {
+	config := routeObject.CircuitBreaker
+
+	breaker := &circuitBreaker{
+		routeName:             routeObject.Name,
+		metrics:               metrics,
+		errorRateThreshold:    config.ErrorRateThreshold,
+		slowCallThreshold:     parseDurationOr(config.SlowCallThreshold, 0),
+		slowCallRateThreshold: config.SlowCallRateThreshold,
+		minRequests:           config.MinRequests,
+		window:                parseDurationOr(config.Window, defaultBreakerWindow),
+		coolDown:              parseDurationOr(config.CoolDown, defaultBreakerCoolDown),
+		halfOpenRequests:      config.HalfOpenRequests,
+		state:                 breakerStateClosed,
+		windowStart:           time.Now(),
+	}
+
+	if breaker.slowCallThreshold == 0 {
+		breaker.slowCallRateThreshold = 0
+	}
+
+	if breaker.minRequests <= 0 {
+		breaker.minRequests = defaultBreakerMinRequests
+	}
+
+	if breaker.halfOpenRequests <= 0 {
+		breaker.halfOpenRequests = defaultBreakerHalfOpenRequests
+	}
+
+	breaker.enabled = breaker.errorRateThreshold > 0 || breaker.slowCallRateThreshold > 0
+
+	return breaker
+}
+
+// allow reports whether a call may reach the upstream, along with the
+// generation it is admitted under. Every allowed call must be followed by
+// record with that generation.
+func (b *circuitBreaker) allow() (uint64, bool) {
+	if !b.enabled {
+		return 0, true
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch b.state {
+	case breakerStateOpen:
+		if time.Since(b.openedAt) < b.coolDown {
+			return b.generation, false
+		}
+
+		b.transition(breakerStateHalfOpen)
+		fallthrough
+	case breakerStateHalfOpen:
+		if b.probes >= b.halfOpenRequests {
+			return b.generation, false
+		}
+
+		b.probes++
+	}
+
+	return b.generation, true
+}
+
+// record feeds the outcome of an allowed call back into the breaker. Outcomes
+// of calls admitted before the last transition are ignored, so a slow call
+// admitted while closed can't be taken for a half open probe.
+func (b *circuitBreaker) record(generation uint64, success bool, latency time.Duration) {
+	if !b.enabled {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	slow := b.slowCallThreshold > 0 && latency >= b.slowCallThreshold
+
+	switch b.state {
+	case breakerStateHalfOpen:
+		if !success || slow {
+			b.transition(breakerStateOpen)
+			return
+		}
+
+		b.probeOK++
+		if b.probeOK >= b.halfOpenRequests {
+			b.transition(breakerStateClosed)
+		}
+	case breakerStateClosed:
+		if time.Since(b.windowStart) >= b.window {
+			b.resetWindow()
+		}
+
+		b.total++
+		if !success {
+			b.failures++
+		}
+		if slow {
+			b.slowCalls++
+		}
+
+		if b.total < b.minRequests {
+			return
+		}
+
+		if b.tripped(b.failures, b.errorRateThreshold) || b.tripped(b.slowCalls, b.slowCallRateThreshold) {
+			b.transition(breakerStateOpen)
+		}
+	}
+}
+
+func (b *circuitBreaker) tripped(count int, threshold float64) bool {
+	return threshold > 0 && float64(count)/float64(b.total) >= threshold
+}
+
+func (b *circuitBreaker) resetWindow() {
+	b.windowStart = time.Now()
+	b.total, b.failures, b.slowCalls = 0, 0, 0
+}
+
+// transition must be called with the mutex held.
+func (b *circuitBreaker) transition(to string) {
+	from := b.state
+	b.state = to
+	b.probes, b.probeOK = 0, 0
+	b.generation++
+
+	switch to {
+	case breakerStateOpen:
+		b.openedAt = time.Now()
+		log.Warn().Str("name", b.routeName).Str("from", from).Str("to", to).Int("requests", b.total).Int("failures", b.failures).Int("slow_calls", b.slowCalls).Array("tags", zerolog.Arr().Str("route").Str("circuit_breaker")).Msg("Circuit breaker is open")
+	default:
+		log.Info().Str("name", b.routeName).Str("from", from).Str("to", to).Array("tags", zerolog.Arr().Str("route").Str("circuit_breaker")).Msg("Circuit breaker changed state")
+	}
+
+	b.resetWindow()
+
+	for _, m := range b.metrics {
+		_ = m.Inc("routes_circuit_breaker_transitions", map[string]string{"route_name": b.routeName, "from": from, "to": to})
+	}
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Run("Disabled by default", func(t *testing.T) {
+		breaker := newCircuitBreaker(entity.RouteObject{Name: "users"}, nil)
+
+		for i := 0; i < 20; i++ {
+			generation, allowed := breaker.allow()
+			assert.True(t, allowed)
+			breaker.record(generation, false, 0)
+		}
+	})
+
+	t.Run("Open on error rate then close after successful probe", func(t *testing.T) {
+		breaker := newCircuitBreaker(entity.RouteObject{
+			Name: "users",
+			CircuitBreaker: entity.RouteCircuitBreaker{
+				ErrorRateThreshold: 0.5,
+				MinRequests:        4,
+				CoolDown:           "20ms",
+			},
+		}, nil)
+
+		breaker.record(0, true, 0)
+		breaker.record(0, false, 0)
+		breaker.record(0, true, 0)
+		assert.Equal(t, breakerStateClosed, breaker.state)
+
+		breaker.record(0, false, 0)
+		assert.Equal(t, breakerStateOpen, breaker.state)
+		_, allowed := breaker.allow()
+		assert.False(t, allowed)
+
+		time.Sleep(time.Millisecond * 30)
+
+		generation, allowed := breaker.allow()
+		assert.True(t, allowed)
+		assert.Equal(t, breakerStateHalfOpen, breaker.state)
+		_, allowed = breaker.allow()
+		assert.False(t, allowed)
+
+		breaker.record(generation, true, 0)
+		assert.Equal(t, breakerStateClosed, breaker.state)
+		_, allowed = breaker.allow()
+		assert.True(t, allowed)
+	})
+
+	t.Run("Reopen when half open probe fails", func(t *testing.T) {
+		breaker := newCircuitBreaker(entity.RouteObject{
+			Name: "users",
+			CircuitBreaker: entity.RouteCircuitBreaker{
+				ErrorRateThreshold: 1,
+				MinRequests:        1,
+				CoolDown:           "10ms",
+			},
+		}, nil)
+
+		breaker.record(0, false, 0)
+		time.Sleep(time.Millisecond * 20)
+
+		generation, allowed := breaker.allow()
+		assert.True(t, allowed)
+		breaker.record(generation, false, 0)
+		assert.Equal(t, breakerStateOpen, breaker.state)
+		_, allowed = breaker.allow()
+		assert.False(t, allowed)
+	})
+
+	t.Run("Open on slow call rate", func(t *testing.T) {
+		breaker := newCircuitBreaker(entity.RouteObject{
+			Name: "users",
+			CircuitBreaker: entity.RouteCircuitBreaker{
+				SlowCallThreshold:     "100ms",
+				SlowCallRateThreshold: 0.5,
+				MinRequests:           2,
+			},
+		}, nil)
+
+		breaker.record(0, true, time.Millisecond*10)
+		breaker.record(0, true, time.Millisecond*200)
+		assert.Equal(t, breakerStateOpen, breaker.state)
+	})
+
+	t.Run("Forget outcomes outside of the window", func(t *testing.T) {
+		breaker := newCircuitBreaker(entity.RouteObject{
+			Name: "users",
+			CircuitBreaker: entity.RouteCircuitBreaker{
+				ErrorRateThreshold: 0.5,
+				MinRequests:        2,
+				Window:             "10ms",
+			},
+		}, nil)
+
+		breaker.record(0, false, 0)
+		time.Sleep(time.Millisecond * 20)
+		breaker.record(0, true, 0)
+		breaker.record(0, true, 0)
+		assert.Equal(t, breakerStateClosed, breaker.state)
+	})
+
+	t.Run("Ignore outcomes of calls admitted before the breaker opened", func(t *testing.T) {
+		newBreaker := func() *circuitBreaker {
+			return newCircuitBreaker(entity.RouteObject{
+				Name: "users",
+				CircuitBreaker: entity.RouteCircuitBreaker{
+					ErrorRateThreshold: 1,
+					MinRequests:        1,
+					CoolDown:           "10ms",
+				},
+			}, nil)
+		}
+
+		t.Run("Slow failure admitted while closed does not reopen the half open breaker", func(t *testing.T) {
+			breaker := newBreaker()
+
+			staleGeneration, allowed := breaker.allow()
+			assert.True(t, allowed)
+
+			breaker.record(0, false, 0)
+			assert.Equal(t, breakerStateOpen, breaker.state)
+			time.Sleep(time.Millisecond * 20)
+
+			probeGeneration, allowed := breaker.allow()
+			assert.True(t, allowed)
+			assert.Equal(t, breakerStateHalfOpen, breaker.state)
+
+			breaker.record(staleGeneration, false, 0)
+			assert.Equal(t, breakerStateHalfOpen, breaker.state)
+
+			breaker.record(probeGeneration, true, 0)
+			assert.Equal(t, breakerStateClosed, breaker.state)
+		})
+
+		t.Run("Success admitted while closed does not close the half open breaker", func(t *testing.T) {
+			breaker := newBreaker()
+
+			staleGeneration, allowed := breaker.allow()
+			assert.True(t, allowed)
+
+			breaker.record(0, false, 0)
+			time.Sleep(time.Millisecond * 20)
+
+			probeGeneration, allowed := breaker.allow()
+			assert.True(t, allowed)
+
+			breaker.record(staleGeneration, true, 0)
+			assert.Equal(t, breakerStateHalfOpen, breaker.state)
+
+			breaker.record(probeGeneration, false, 0)
+			assert.Equal(t, breakerStateOpen, breaker.state)
+		})
+	})
+}
@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrustProxies makes c.ClientIP() of the engine resolve the client behind the
+// trusted proxies. X-Forwarded-For is only believed when the peer is one of
+// them and it is walked from the right past every trusted proxy, otherwise the
+// peer address is the client IP. gin trusts every peer until this is called.
+func TrustProxies(engine *gin.Engine, trustedProxies []string) error {
+	if err := engine.SetTrustedProxies(trustedProxies); err != nil {
+		return err
+	}
+
+	engine.RemoteIPHeaders = []string{"X-Forwarded-For"}
+	engine.Use(joinForwardedFor)
+
+	return nil
+}
+
+// joinForwardedFor merges repeated X-Forwarded-For headers into one. gin only
+// reads the first of them, while proxies such as HAProxy append their own
+// header after the one sent by the client.
+func joinForwardedFor(c *gin.Context) {
+	if values := c.Request.Header.Values("X-Forwarded-For"); len(values) > 1 {
+		c.Request.Header.Set("X-Forwarded-For", strings.Join(values, ", "))
+	}
+}
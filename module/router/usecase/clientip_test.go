@@ -0,0 +1,55 @@
+package usecase_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/router/usecase"
+)
+
+func TestTrustProxies(t *testing.T) {
+	clientIP := func(trustedProxies []string, remoteAddr string, header http.Header) string {
+		engine := gin.New()
+		assert.Nil(t, usecase.TrustProxies(engine, trustedProxies))
+
+		var resolved string
+		engine.GET("/ip", func(c *gin.Context) {
+			resolved = c.ClientIP()
+		})
+
+		req := httptest.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header = header
+
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+		return resolved
+	}
+
+	t.Run("Use the peer address without trusted proxies", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", clientIP(nil, "203.0.113.7:41000", http.Header{"X-Forwarded-For": {"198.51.100.2"}}))
+	})
+
+	t.Run("Ignore X-Forwarded-For sent by a client that is not a trusted proxy", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", clientIP([]string{"10.0.0.0/8"}, "203.0.113.7:41000", http.Header{"X-Forwarded-For": {"198.51.100.2"}}))
+	})
+
+	t.Run("Walk X-Forwarded-For past the trusted proxies", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.2", clientIP([]string{"10.0.0.0/8"}, "10.0.0.1:41000", http.Header{"X-Forwarded-For": {"192.0.2.9, 198.51.100.2, 10.0.0.2"}}))
+	})
+
+	t.Run("Read the header appended by the trusted proxy after the client one", func(t *testing.T) {
+		assert.Equal(t, "198.51.100.2", clientIP([]string{"10.0.0.0/8"}, "10.0.0.1:41000", http.Header{"X-Forwarded-For": {"192.0.2.9", "198.51.100.2"}}))
+	})
+
+	t.Run("Ignore X-Real-IP", func(t *testing.T) {
+		assert.Equal(t, "10.0.0.1", clientIP([]string{"10.0.0.0/8"}, "10.0.0.1:41000", http.Header{"X-Real-Ip": {"192.0.2.9"}}))
+	})
+
+	t.Run("Return error on an invalid proxy", func(t *testing.T) {
+		assert.NotNil(t, usecase.TrustProxies(gin.New(), []string{"load-balancer"}))
+	})
+}
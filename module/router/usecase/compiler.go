@@ -2,9 +2,12 @@ package usecase
 
 import (
 	"bytes"
+	"fmt"
 	"html/template"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gopkg.in/yaml.v2"
@@ -43,16 +46,405 @@ func (c *Compiler) Compile(routesPath string) ([]entity.RouteObject, error) {
 			routeObject.Auth = "none"
 		}
 
+		c.normalizeMethods(routeObject)
+
+		if err := c.validateMethods(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if _, err := newRouteMatcher(routeObject.Match); err != nil {
+			return routeObjects, fmt.Errorf("route `%s`: %v", routeObject.Name, err)
+		}
+
+		if err := c.validateHeaders(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if err := c.validateBody(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if err := c.validateRewrite(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if err := c.validateUpstream(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if err := c.validateRetry(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if err := c.validateCircuitBreaker(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if err := c.validateTLS(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if err := c.validateRateLimits(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if err := c.validateCaches(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if err := c.validateIPFilters(routeObject); err != nil {
+			return routeObjects, err
+		}
+
+		if routeObject.CORS != nil {
+			if err := routeObject.CORS.Validate(); err != nil {
+				return routeObjects, fmt.Errorf("route `%s`: %v", routeObject.Name, err)
+			}
+		}
+
+		if routeObject.MaxBodySize != "" {
+			if _, err := parseByteSize(routeObject.MaxBodySize); err != nil {
+				return routeObjects, fmt.Errorf("route `%s`: max_body_size is invalid: %v", routeObject.Name, err)
+			}
+		}
+
 		routeObjects = append(routeObjects, routeObject)
 	}
 
 	return routeObjects, nil
 }
 
+// normalizeMethods upper cases the method names so `get` and `GET` are the same.
+func (c *Compiler) normalizeMethods(routeObject entity.RouteObject) {
+	for name, routePath := range routeObject.Path {
+		for i, method := range routePath.Methods {
+			routePath.Methods[i] = strings.ToUpper(method)
+		}
+
+		if len(routePath.MethodOverrides) > 0 {
+			overrides := map[string]entity.RouterPathMethod{}
+			for method, override := range routePath.MethodOverrides {
+				overrides[strings.ToUpper(method)] = override
+			}
+			routePath.MethodOverrides = overrides
+		}
+
+		routeObject.Path[name] = routePath
+	}
+}
+
+func (c *Compiler) validateMethods(routeObject entity.RouteObject) error {
+	for name, routePath := range routeObject.Path {
+		seen := map[string]bool{}
+
+		for _, method := range routePath.Methods {
+			if !containsMethod(routeMethods, method) {
+				return fmt.Errorf("route `%s`: path `%s` method `%s` is not supported, expected one of %s", routeObject.Name, name, method, strings.Join(routeMethods, ", "))
+			}
+
+			if seen[method] {
+				return fmt.Errorf("route `%s`: path `%s` method `%s` is listed more than once", routeObject.Name, name, method)
+			}
+			seen[method] = true
+		}
+
+		for method := range routePath.MethodOverrides {
+			if !containsMethod(allowedMethods(routePath), method) {
+				return fmt.Errorf("route `%s`: path `%s` method_overrides `%s` is not an allowed method of the path", routeObject.Name, name, method)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateHeaders(routeObject entity.RouteObject) error {
+	if _, err := newHeaderRules(routeObject.RequestHeaders); err != nil {
+		return fmt.Errorf("route `%s`: request_headers: %v", routeObject.Name, err)
+	}
+
+	if _, err := newHeaderRules(routeObject.ResponseHeaders); err != nil {
+		return fmt.Errorf("route `%s`: response_headers: %v", routeObject.Name, err)
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateBody(routeObject entity.RouteObject) error {
+	if _, err := newBodyTransform(routeObject.RequestBody); err != nil {
+		return fmt.Errorf("route `%s`: request_body: %v", routeObject.Name, err)
+	}
+
+	if _, err := newBodyTransform(routeObject.ResponseBody); err != nil {
+		return fmt.Errorf("route `%s`: response_body: %v", routeObject.Name, err)
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateRewrite(routeObject entity.RouteObject) error {
+	if routeObject.AddPrefix != "" && !strings.HasPrefix(routeObject.AddPrefix, "/") {
+		return fmt.Errorf("route `%s`: add_prefix must start with `/`", routeObject.Name)
+	}
+
+	if _, err := newPathRewrite(routeObject, entity.RouterPath{}); err != nil {
+		return fmt.Errorf("route `%s`: %v", routeObject.Name, err)
+	}
+
+	for name, routePath := range routeObject.Path {
+		if routePath.AddPrefix != nil && *routePath.AddPrefix != "" && !strings.HasPrefix(*routePath.AddPrefix, "/") {
+			return fmt.Errorf("route `%s`: path `%s` add_prefix must start with `/`", routeObject.Name, name)
+		}
+
+		if _, err := newPathRewrite(routeObject, routePath); err != nil {
+			return fmt.Errorf("route `%s`: path `%s` %v", routeObject.Name, name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateUpstream(routeObject entity.RouteObject) error {
+	upstream := routeObject.Upstream
+
+	switch upstream.Strategy {
+	case "", strategyRoundRobin, strategyWeighted, strategyLeastInFlight:
+	case strategyConsistentHash:
+		if upstream.HashOn != "" && upstream.HashOn != hashOnClientIP && !strings.HasPrefix(upstream.HashOn, hashOnHeaderPrefix) {
+			return fmt.Errorf("route `%s`: upstream hash_on `%s` is invalid, expected `%s` or `%s<name>`", routeObject.Name, upstream.HashOn, hashOnClientIP, hashOnHeaderPrefix)
+		}
+
+		if upstream.HashOn == hashOnHeaderPrefix {
+			return fmt.Errorf("route `%s`: upstream hash_on header name cannot be empty", routeObject.Name)
+		}
+	default:
+		return fmt.Errorf("route `%s`: upstream strategy `%s` is not supported", routeObject.Name, upstream.Strategy)
+	}
+
+	for _, target := range upstream.Targets {
+		if target.Host == "" {
+			return fmt.Errorf("route `%s`: upstream target host cannot be empty", routeObject.Name)
+		}
+
+		if target.Weight < 0 {
+			return fmt.Errorf("route `%s`: upstream target `%s` weight cannot be negative", routeObject.Name, target.Host)
+		}
+	}
+
+	healthCheck := upstream.HealthCheck
+
+	for _, duration := range []struct{ field, raw string }{
+		{"health_check.active.interval", healthCheck.Active.Interval},
+		{"health_check.active.timeout", healthCheck.Active.Timeout},
+		{"health_check.passive.ejection_duration", healthCheck.Passive.EjectionDuration},
+	} {
+		if duration.raw == "" {
+			continue
+		}
+
+		if _, err := time.ParseDuration(duration.raw); err != nil {
+			return fmt.Errorf("route `%s`: upstream %s is invalid: %v", routeObject.Name, duration.field, err)
+		}
+	}
+
+	if healthCheck.Active.Path != "" && !strings.HasPrefix(healthCheck.Active.Path, "/") {
+		return fmt.Errorf("route `%s`: upstream health_check.active.path must start with `/`", routeObject.Name)
+	}
+
+	if healthCheck.Active.HealthyThreshold < 0 || healthCheck.Active.UnhealthyThreshold < 0 || healthCheck.Passive.UnhealthyThreshold < 0 {
+		return fmt.Errorf("route `%s`: upstream health_check thresholds cannot be negative", routeObject.Name)
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateRetry(routeObject entity.RouteObject) error {
+	for _, duration := range []struct{ field, raw string }{
+		{"timeout", routeObject.Timeout},
+		{"connect_timeout", routeObject.ConnectTimeout},
+		{"retry.backoff", routeObject.Retry.Backoff},
+		{"retry.max_backoff", routeObject.Retry.MaxBackoff},
+	} {
+		if duration.raw == "" {
+			continue
+		}
+
+		if _, err := time.ParseDuration(duration.raw); err != nil {
+			return fmt.Errorf("route `%s`: %s is invalid: %v", routeObject.Name, duration.field, err)
+		}
+	}
+
+	if routeObject.Retry.MaxAttempts < 0 {
+		return fmt.Errorf("route `%s`: retry.max_attempts cannot be negative", routeObject.Name)
+	}
+
+	for _, status := range routeObject.Retry.OnStatus {
+		if status < 100 || status > 599 {
+			return fmt.Errorf("route `%s`: retry.on_status `%d` is not a valid http status code", routeObject.Name, status)
+		}
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateRateLimits(routeObject entity.RouteObject) error {
+	if err := c.validateRateLimit(routeObject.RateLimit); err != nil {
+		return fmt.Errorf("route `%s`: rate_limit %v", routeObject.Name, err)
+	}
+
+	for name, routePath := range routeObject.Path {
+		if err := c.validateRateLimit(routePath.RateLimit); err != nil {
+			return fmt.Errorf("route `%s`: path `%s` rate_limit %v", routeObject.Name, name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateRateLimit(rateLimit *entity.RouteRateLimit) error {
+	if rateLimit == nil {
+		return nil
+	}
+
+	switch key := rateLimit.Key; {
+	case key == "", key == "client_ip", key == "resource_owner_id", key == "oauth_application_id":
+	case strings.HasPrefix(key, "header:") && strings.TrimSpace(strings.TrimPrefix(key, "header:")) != "":
+	default:
+		return fmt.Errorf("key `%s` is not supported, use client_ip, resource_owner_id, oauth_application_id or header:<name>", key)
+	}
+
+	if rateLimit.Limit < 0 || rateLimit.Burst < 0 {
+		return fmt.Errorf("limit and burst cannot be negative")
+	}
+
+	if rateLimit.Period != "" {
+		period, err := time.ParseDuration(rateLimit.Period)
+		if err != nil {
+			return fmt.Errorf("period is invalid: %v", err)
+		}
+
+		if period <= 0 {
+			return fmt.Errorf("period must be positive")
+		}
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateCaches(routeObject entity.RouteObject) error {
+	if err := c.validateCache(routeObject.Cache); err != nil {
+		return fmt.Errorf("route `%s`: cache %v", routeObject.Name, err)
+	}
+
+	for name, routePath := range routeObject.Path {
+		if err := c.validateCache(routePath.Cache); err != nil {
+			return fmt.Errorf("route `%s`: path `%s` cache %v", routeObject.Name, name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateCache(cache *entity.RouteCache) error {
+	if cache == nil || cache.TTL == "" {
+		return nil
+	}
+
+	ttl, err := time.ParseDuration(cache.TTL)
+	if err != nil {
+		return fmt.Errorf("ttl is invalid: %v", err)
+	}
+
+	if ttl <= 0 {
+		return fmt.Errorf("ttl must be positive")
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateIPFilters(routeObject entity.RouteObject) error {
+	if routeObject.IPFilter != nil {
+		if err := routeObject.IPFilter.Validate(); err != nil {
+			return fmt.Errorf("route `%s`: ip_filter %v", routeObject.Name, err)
+		}
+	}
+
+	for name, routePath := range routeObject.Path {
+		if routePath.IPFilter == nil {
+			continue
+		}
+
+		if err := routePath.IPFilter.Validate(); err != nil {
+			return fmt.Errorf("route `%s`: path `%s` ip_filter %v", routeObject.Name, name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Compiler) validateCircuitBreaker(routeObject entity.RouteObject) error {
+	breaker := routeObject.CircuitBreaker
+
+	for _, duration := range []struct{ field, raw string }{
+		{"circuit_breaker.slow_call_threshold", breaker.SlowCallThreshold},
+		{"circuit_breaker.window", breaker.Window},
+		{"circuit_breaker.cool_down", breaker.CoolDown},
+	} {
+		if duration.raw == "" {
+			continue
+		}
+
+		if _, err := time.ParseDuration(duration.raw); err != nil {
+			return fmt.Errorf("route `%s`: %s is invalid: %v", routeObject.Name, duration.field, err)
+		}
+	}
+
+	for _, rate := range []struct {
+		field string
+		value float64
+	}{
+		{"circuit_breaker.error_rate_threshold", breaker.ErrorRateThreshold},
+		{"circuit_breaker.slow_call_rate_threshold", breaker.SlowCallRateThreshold},
+	} {
+		if rate.value < 0 || rate.value > 1 {
+			return fmt.Errorf("route `%s`: %s must be between 0 and 1", routeObject.Name, rate.field)
+		}
+	}
+
+	if breaker.SlowCallRateThreshold > 0 && breaker.SlowCallThreshold == "" {
+		return fmt.Errorf("route `%s`: circuit_breaker.slow_call_rate_threshold requires circuit_breaker.slow_call_threshold", routeObject.Name)
+	}
+
+	if breaker.MinRequests < 0 || breaker.HalfOpenRequests < 0 {
+		return fmt.Errorf("route `%s`: circuit_breaker request counts cannot be negative", routeObject.Name)
+	}
+
+	return nil
+}
+
+// validateTLS loads the certificates up front so a missing or broken file fails
+// the boot instead of the first proxied request.
+func (c *Compiler) validateTLS(routeObject entity.RouteObject) error {
+	switch routeObject.Scheme {
+	case "", schemeHTTP:
+		if routeObject.TLS != (entity.RouteTLS{}) {
+			return fmt.Errorf("route `%s`: tls is only supported with scheme `%s`", routeObject.Name, schemeHTTPS)
+		}
+	case schemeHTTPS:
+		if _, err := newUpstreamTLSConfig(routeObject); err != nil {
+			return fmt.Errorf("route `%s`: %v", routeObject.Name, err)
+		}
+	default:
+		return fmt.Errorf("route `%s`: scheme `%s` is not supported, expected `%s` or `%s`", routeObject.Name, routeObject.Scheme, schemeHTTP, schemeHTTPS)
+	}
+
+	return nil
+}
+
 func (c *Compiler) compileTemplate(b []byte) ([]byte, error) {
 	tpl, err := template.New(uuid.New().String()).Funcs(template.FuncMap{
 		"env": os.Getenv,
-	}).Parse(string(b))
+	}).Parse(string(escapeHeaderTemplates(b)))
 	if err != nil {
 		return nil, err
 	}
@@ -2,6 +2,7 @@ package usecase_test
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"testing"
 	"text/template"
@@ -94,6 +95,328 @@ func TestCompiler(t *testing.T) {
 				testhelper.RemoveTempTestFiles(routesPath)
 			})
 
+			t.Run("Upstream targets", func(t *testing.T) {
+				routesPath := "./routes_upstream_targets/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithUpstream)
+
+				t.Run("Return route object with upstream targets", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, 1, len(routeObjects))
+					assert.Equal(t, "weighted", routeObjects[0].Upstream.Strategy)
+					assert.Equal(t, []entity.RouteTarget{{Host: "localhost:3001", Weight: 3}, {Host: "localhost:3002", Weight: 1}}, routeObjects[0].Upstream.Targets)
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Upstream strategy is invalid", func(t *testing.T) {
+				routesPath := "./routes_upstream_invalid_strategy/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithInvalidUpstreamStrategy)
+
+				t.Run("Return error", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, 0, len(routeObjects))
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Upstream hash on is invalid", func(t *testing.T) {
+				routesPath := "./routes_upstream_invalid_hash_on/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithInvalidUpstreamHashOn)
+
+				t.Run("Return error", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, 0, len(routeObjects))
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Retry status code is invalid", func(t *testing.T) {
+				routesPath := "./routes_invalid_retry/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithInvalidRetry)
+
+				t.Run("Return error", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, 0, len(routeObjects))
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Circuit breaker error rate threshold is invalid", func(t *testing.T) {
+				routesPath := "./routes_invalid_circuit_breaker/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithInvalidCircuitBreaker)
+
+				t.Run("Return error", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, 0, len(routeObjects))
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Max body size is invalid", func(t *testing.T) {
+				routesPath := "./routes_invalid_max_body_size/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithInvalidMaxBodySize)
+
+				t.Run("Return error", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, 0, len(routeObjects))
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Upstream tls certificate is missing", func(t *testing.T) {
+				routesPath := "./routes_missing_tls_certificate/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithMissingTLSCertificate)
+
+				t.Run("Return error", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, 0, len(routeObjects))
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Path with methods", func(t *testing.T) {
+				routesPath := "./routes_with_methods/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithMethods)
+
+				t.Run("Return upper cased methods and overrides", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, []string{"GET", "PUT"}, routeObjects[0].Path["/me"].Methods)
+					assert.Equal(t, entity.RouterPathMethod{Auth: "oauth", Scope: "users:write"}, routeObjects[0].Path["/me"].MethodOverrides["PUT"])
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Route with header rules", func(t *testing.T) {
+				routesPath := "./routes_with_header_rules/"
+
+				os.Setenv("ALTAIR_TEST_ENVIRONMENT", "staging")
+				defer os.Unsetenv("ALTAIR_TEST_ENVIRONMENT")
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithHeaderRules)
+
+				t.Run("Keep request templates for the runtime and render env", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, map[string]string{"X-Client-IP": "{{ .ClientIP }}", "X-Environment": "staging"}, routeObjects[0].RequestHeaders.Set)
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Route file templating other fields like before header rules", func(t *testing.T) {
+				routesPath := "./routes_with_template_fields/"
+
+				os.Setenv("ALTAIR_TEST_ENVIRONMENT", "staging")
+				defer os.Unsetenv("ALTAIR_TEST_ENVIRONMENT")
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithTemplateFields)
+
+				t.Run("Compile it and keep the header rule fields", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, 1, len(routeObjects))
+					assert.Equal(t, map[string]string{"X-Client-IP": "{{ .ClientIP }}", "X-Request-ID": "{{ .RequestID }}"}, routeObjects[0].RequestHeaders.Set)
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Route with body transform", func(t *testing.T) {
+				routesPath := "./routes_with_body_transform/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithBodyTransform)
+
+				t.Run("Return route objects", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, map[string]string{"user_name": "userName"}, routeObjects[0].ResponseBody.Rename)
+					assert.Equal(t, map[string]interface{}{"meta.version": 2}, routeObjects[0].ResponseBody.Set)
+					assert.Equal(t, "data", routeObjects[0].ResponseBody.Wrap)
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Route with rate limit", func(t *testing.T) {
+				routesPath := "./routes_with_rate_limit/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithRateLimit)
+
+				t.Run("Return route objects", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, &entity.RouteRateLimit{Limit: 100, Period: "1m"}, routeObjects[0].RateLimit)
+					assert.Equal(t, &entity.RouteRateLimit{Key: "header:X-Api-Key", Limit: 5, Burst: 10}, routeObjects[0].Path["/login"].RateLimit)
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Route with cache", func(t *testing.T) {
+				routesPath := "./routes_with_cache/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithCache)
+
+				t.Run("Return route objects", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, &entity.RouteCache{TTL: "5m"}, routeObjects[0].Cache)
+					assert.Equal(t, &entity.RouteCache{TTL: "30s", Private: true}, routeObjects[0].Path["/wishlist"].Cache)
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Route with ip filter", func(t *testing.T) {
+				routesPath := "./routes_with_ip_filter/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithIPFilter)
+
+				t.Run("Return route objects", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, []string{"10.0.0.0/8", "203.0.113.7"}, routeObjects[0].IPFilter.Allow)
+					assert.True(t, routeObjects[0].Path["/status"].IPFilter.Disabled)
+
+					allow, deny := routeObjects[0].IPFilter.Networks()
+					assert.Equal(t, []string{"10.0.0.0/8", "203.0.113.7/32"}, []string{allow[0].String(), allow[1].String()})
+					assert.Empty(t, deny)
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Route with cors", func(t *testing.T) {
+				routesPath := "./routes_with_cors/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithCORS)
+
+				t.Run("Return route objects", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, &entity.RouteCORS{AllowOrigins: []string{"https://*.example.com"}, AllowMethods: []string{"GET", "POST"}, MaxAge: "10m"}, routeObjects[0].CORS)
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			for name, content := range map[string]string{
+				"unknown_method":                ExampleRoutesWithUnknownMethod,
+				"override_of_disallowed_method": ExampleRoutesWithOverrideOfDisallowedMethod,
+				"invalid_rewrite":               ExampleRoutesWithInvalidRewrite,
+				"invalid_add_prefix":            ExampleRoutesWithInvalidAddPrefix,
+				"invalid_match_host":            ExampleRoutesWithInvalidMatchHost,
+				"invalid_match_client_cidr":     ExampleRoutesWithInvalidMatchClientCIDR,
+				"invalid_header_rules":          ExampleRoutesWithInvalidHeaderRules,
+				"invalid_body_transform":        ExampleRoutesWithInvalidBodyTransform,
+				"invalid_rate_limit":            ExampleRoutesWithInvalidRateLimit,
+				"invalid_cache":                 ExampleRoutesWithInvalidCache,
+				"invalid_cors":                  ExampleRoutesWithInvalidCORS,
+				"invalid_ip_filter":             ExampleRoutesWithInvalidIPFilter,
+			} {
+				t.Run(fmt.Sprintf("Path with %s", name), func(t *testing.T) {
+					routesPath := fmt.Sprintf("./routes_path_%s/", name)
+
+					generateAllTempTestFiles(routesPath, content)
+
+					t.Run("Return error", func(t *testing.T) {
+						c := usecase.NewCompiler()
+						routeObjects, err := c.Compile(routesPath)
+
+						assert.NotNil(t, err)
+						assert.Equal(t, 0, len(routeObjects))
+					})
+
+					testhelper.RemoveTempTestFiles(routesPath)
+				})
+			}
+
+			t.Run("Upstream scheme is not supported", func(t *testing.T) {
+				routesPath := "./routes_unsupported_scheme/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithUnsupportedScheme)
+
+				t.Run("Return error", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, 0, len(routeObjects))
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
+			t.Run("Upstream health check interval is invalid", func(t *testing.T) {
+				routesPath := "./routes_upstream_invalid_health_check/"
+
+				generateAllTempTestFiles(routesPath, ExampleRoutesWithInvalidHealthCheckInterval)
+
+				t.Run("Return error", func(t *testing.T) {
+					c := usecase.NewCompiler()
+					routeObjects, err := c.Compile(routesPath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, 0, len(routeObjects))
+				})
+
+				testhelper.RemoveTempTestFiles(routesPath)
+			})
+
 			t.Run("Template parsing error", func(t *testing.T) {
 				routesPath := "./routes_template_parsing_error/"
 
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+const corsHeaderPrefix = "Access-Control-"
+
+// corsPolicy is the cors of a route resolved once. A route without a policy
+// leaves CORS to its upstream.
+type corsPolicy struct {
+	anyOrigin        bool
+	origins          map[string]bool
+	wildcardOrigins  []corsWildcardOrigin
+	allowMethods     []string
+	allowHeaders     string
+	allowedHeaders   map[string]bool
+	anyHeader        bool
+	exposeHeaders    string
+	allowCredentials bool
+	maxAge           string
+}
+
+// corsWildcardOrigin matches `https://*.example.com` as the scheme
+// `https://` followed by any host ending with `.example.com`.
+type corsWildcardOrigin struct {
+	scheme string
+	suffix string
+}
+
+// newCORSPolicy resolves the cors of a route, the route cors replaces the
+// app.yml one as a whole. It returns nil when neither is set or the cors in
+// effect is disabled.
+func newCORSPolicy(global, route *entity.RouteCORS) (*corsPolicy, error) {
+	cors := route
+	if cors == nil {
+		cors = global
+	}
+
+	if cors == nil || cors.Disabled {
+		return nil, nil
+	}
+
+	if err := cors.Validate(); err != nil {
+		return nil, err
+	}
+
+	policy := &corsPolicy{
+		origins:          map[string]bool{},
+		allowHeaders:     strings.Join(cors.AllowHeaders, ", "),
+		allowedHeaders:   map[string]bool{},
+		exposeHeaders:    strings.Join(cors.ExposeHeaders, ", "),
+		allowCredentials: cors.AllowCredentials,
+	}
+
+	for _, origin := range cors.AllowOrigins {
+		origin = strings.ToLower(origin)
+
+		switch {
+		case origin == matchAnyValue:
+			policy.anyOrigin = true
+		case strings.Contains(origin, "://*."):
+			scheme, suffix, _ := strings.Cut(origin, "://*")
+			policy.wildcardOrigins = append(policy.wildcardOrigins, corsWildcardOrigin{scheme: scheme + "://", suffix: suffix})
+		default:
+			policy.origins[origin] = true
+		}
+	}
+
+	for _, method := range cors.AllowMethods {
+		policy.allowMethods = append(policy.allowMethods, strings.ToUpper(strings.TrimSpace(method)))
+	}
+
+	for _, header := range cors.AllowHeaders {
+		header = strings.ToLower(strings.TrimSpace(header))
+		if header == matchAnyValue {
+			policy.anyHeader = true
+		}
+		policy.allowedHeaders[header] = true
+	}
+
+	if cors.MaxAge != "" {
+		maxAge, _ := time.ParseDuration(cors.MaxAge)
+		policy.maxAge = strconv.Itoa(int(maxAge.Seconds()))
+	}
+
+	return policy, nil
+}
+
+func (p *corsPolicy) allowOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+
+	if p.anyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if p.origins[origin] {
+		return true
+	}
+
+	for _, wildcard := range p.wildcardOrigins {
+		if strings.HasPrefix(origin, wildcard.scheme) && strings.HasSuffix(origin, wildcard.suffix) && len(origin) > len(wildcard.scheme)+len(wildcard.suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowRequestHeaders reports whether every header listed by the
+// Access-Control-Request-Headers of a preflight is allowed. Without
+// allow_headers any requested header is.
+func (p *corsPolicy) allowRequestHeaders(requested string) bool {
+	if len(p.allowedHeaders) == 0 || p.anyHeader {
+		return true
+	}
+
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.ToLower(strings.TrimSpace(header))
+		if header != "" && !p.allowedHeaders[header] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// decorate adds the CORS headers of an actual response. The response varies by
+// origin unless every origin is allowed.
+func (p *corsPolicy) decorate(header http.Header, origin string) {
+	if !p.anyOrigin {
+		header.Add("Vary", "Origin")
+	}
+
+	if !p.allowOrigin(origin) {
+		return
+	}
+
+	p.setAllowOrigin(header, origin)
+
+	if p.exposeHeaders != "" {
+		header.Set("Access-Control-Expose-Headers", p.exposeHeaders)
+	}
+}
+
+func (p *corsPolicy) setAllowOrigin(header http.Header, origin string) {
+	if p.anyOrigin {
+		header.Set("Access-Control-Allow-Origin", matchAnyValue)
+	} else {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+
+	if p.allowCredentials {
+		header.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+// handlePreflight answers the preflight requests of a path at the gateway, any
+// other OPTIONS request is handled by next. Without allow_methods the methods
+// of the path are allowed.
+func (p *corsPolicy) handlePreflight(next gin.HandlerFunc, allowed []string) gin.HandlerFunc {
+	methods := p.allowMethods
+	if len(methods) == 0 {
+		methods = allowed
+	}
+	allowMethods := strings.Join(methods, ", ")
+
+	return func(c *gin.Context) {
+		origin := c.Request.Header.Get("Origin")
+		requestMethod := c.Request.Header.Get("Access-Control-Request-Method")
+
+		if origin == "" || requestMethod == "" {
+			next(c)
+			return
+		}
+
+		header := c.Writer.Header()
+		if !p.anyOrigin {
+			header.Add("Vary", "Origin")
+		}
+		header.Add("Vary", "Access-Control-Request-Method")
+		header.Add("Vary", "Access-Control-Request-Headers")
+
+		requestHeaders := c.Request.Header.Get("Access-Control-Request-Headers")
+
+		if !p.allowOrigin(origin) || !containsMethod(methods, strings.ToUpper(requestMethod)) || !p.allowRequestHeaders(requestHeaders) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"status":  http.StatusForbidden,
+				"message": "CORS preflight request is not allowed",
+			})
+			return
+		}
+
+		p.setAllowOrigin(header, origin)
+		header.Set("Access-Control-Allow-Methods", allowMethods)
+
+		if len(p.allowedHeaders) == 0 || p.anyHeader {
+			if requestHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", requestHeaders)
+			}
+		} else {
+			header.Set("Access-Control-Allow-Headers", p.allowHeaders)
+		}
+
+		if p.maxAge != "" {
+			header.Set("Access-Control-Max-Age", p.maxAge)
+		}
+
+		c.Status(http.StatusNoContent)
+		c.Writer.WriteHeaderNow()
+	}
+}
+
+// isCORSHeader reports whether an upstream header would clash with the CORS
+// headers set by the gateway.
+func isCORSHeader(name string) bool {
+	return strings.HasPrefix(http.CanonicalHeaderKey(name), corsHeaderPrefix)
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const drainPollInterval = time.Millisecond * 50
+
+// Drain waits until no generated route has a request in flight, upgraded
+// connections included. It gives up when ctx is done and reports the routes
+// that still had requests running.
+func (g *Generator) Drain(ctx context.Context) error {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		pending := map[string]int64{}
+		for _, runtime := range g.routes {
+			if inFlight := atomic.LoadInt64(&runtime.inFlight); inFlight > 0 {
+				pending[runtime.name] += inFlight
+			}
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			for name, inFlight := range pending {
+				log.Warn().Str("name", name).Int64("in_flight", inFlight).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("drain")).Msg("Route still has requests in flight")
+			}
+
+			return fmt.Errorf("drain: %d route(s) still have requests in flight: %w", len(pending), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
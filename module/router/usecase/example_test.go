@@ -48,3 +48,345 @@ path:
   /me: {}
   /:id: {}
 `
+
+var ExampleRoutesWithUpstream = `
+name: users
+prefix: /users
+upstream:
+  strategy: weighted
+  targets:
+    - host: localhost:3001
+      weight: 3
+    - host: localhost:3002
+      weight: 1
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithInvalidUpstreamStrategy = `
+name: users
+prefix: /users
+upstream:
+  strategy: random
+  targets:
+    - host: localhost:3001
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithInvalidUpstreamHashOn = `
+name: users
+prefix: /users
+upstream:
+  strategy: consistent_hash
+  hash_on: cookie
+  targets:
+    - host: localhost:3001
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithInvalidHealthCheckInterval = `
+name: users
+prefix: /users
+host: localhost:3001
+upstream:
+  health_check:
+    active:
+      path: /health
+      interval: every minute
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithInvalidRetry = `
+name: users
+prefix: /users
+host: localhost:3001
+timeout: 5s
+retry:
+  max_attempts: 3
+  on_status: [503, 1000]
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithInvalidCircuitBreaker = `
+name: users
+prefix: /users
+host: localhost:3001
+circuit_breaker:
+  error_rate_threshold: 50
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithInvalidMaxBodySize = `
+name: users
+prefix: /users
+host: localhost:3001
+max_body_size: ten megabytes
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithMissingTLSCertificate = `
+name: users
+prefix: /users
+host: localhost:3001
+scheme: https
+tls:
+  cert_file: ./missing-cert.pem
+  key_file: ./missing-key.pem
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithUnsupportedScheme = `
+name: users
+prefix: /users
+host: localhost:3001
+scheme: ftp
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithMethods = `
+name: users
+prefix: /users
+host: localhost:3001
+path:
+  /me:
+    methods: [get, put]
+    method_overrides:
+      put:
+        auth: oauth
+        scope: users:write
+`
+
+var ExampleRoutesWithUnknownMethod = `
+name: users
+prefix: /users
+host: localhost:3001
+path:
+  /me:
+    methods: [GET, FETCH]
+`
+
+var ExampleRoutesWithOverrideOfDisallowedMethod = `
+name: users
+prefix: /users
+host: localhost:3001
+path:
+  /me:
+    methods: [GET]
+    method_overrides:
+      DELETE:
+        auth: oauth
+`
+
+var ExampleRoutesWithInvalidRewrite = `
+name: users
+prefix: /users
+host: localhost:3001
+strip_prefix: true
+path:
+  /:id:
+    rewrite:
+      - match: ^/(\d+
+        replace: /accounts/$1
+`
+
+var ExampleRoutesWithInvalidAddPrefix = `
+name: users
+prefix: /users
+host: localhost:3001
+add_prefix: api/v1
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithInvalidMatchHost = `
+name: users
+prefix: /users
+host: localhost:3001
+match:
+  hosts: [api.*.com]
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithInvalidMatchClientCIDR = `
+name: users
+prefix: /users
+host: localhost:3001
+match:
+  client_cidrs: [10.0.0.0/33]
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithHeaderRules = `
+name: users
+prefix: /users
+host: localhost:3001
+request_headers:
+  set:
+    X-Client-IP: "{{ .ClientIP }}"
+    X-Environment: {{ env "ALTAIR_TEST_ENVIRONMENT" }}
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithInvalidHeaderRules = `
+name: users
+prefix: /users
+host: localhost:3001
+response_headers:
+  rename:
+    X-Upstream: ""
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithTemplateFields = `
+# generated by {{ .Generator }} for {{ env "ALTAIR_TEST_ENVIRONMENT" }}
+name: users
+prefix: /users
+host: localhost:3001
+request_headers:
+  set:
+    X-Client-IP: "{{.ClientIP}}"
+    X-Request-ID: "{{ .RequestID }}"
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithBodyTransform = `
+name: users
+prefix: /users
+host: localhost:3001
+response_body:
+  rename:
+    user_name: userName
+  set:
+    meta.version: 2
+  wrap: data
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithInvalidBodyTransform = `
+name: users
+prefix: /users
+host: localhost:3001
+request_body:
+  rename:
+    items.#.id: id
+path:
+  /me: {}
+`
+
+var ExampleRoutesWithRateLimit = `
+name: users
+prefix: /users
+host: localhost:3001
+rate_limit:
+  limit: 100
+  period: 1m
+path:
+  /me: {}
+  /login:
+    rate_limit:
+      key: header:X-Api-Key
+      limit: 5
+      burst: 10
+`
+
+var ExampleRoutesWithInvalidRateLimit = `
+name: users
+prefix: /users
+host: localhost:3001
+path:
+  /me:
+    rate_limit:
+      key: cookie
+      limit: 5
+`
+
+var ExampleRoutesWithCache = `
+name: catalog
+prefix: /catalog
+host: localhost:3001
+cache:
+  ttl: 5m
+path:
+  /products: {}
+  /wishlist:
+    auth: oauth
+    cache:
+      ttl: 30s
+      private: true
+`
+
+var ExampleRoutesWithInvalidCache = `
+name: catalog
+prefix: /catalog
+host: localhost:3001
+path:
+  /products:
+    cache:
+      ttl: -1m
+`
+
+var ExampleRoutesWithIPFilter = `
+name: admin
+prefix: /admin
+host: localhost:3001
+ip_filter:
+  allow:
+    - 10.0.0.0/8
+    - 203.0.113.7
+path:
+  /dashboard: {}
+  /status:
+    ip_filter:
+      disabled: true
+`
+
+var ExampleRoutesWithInvalidIPFilter = `
+name: admin
+prefix: /admin
+host: localhost:3001
+path:
+  /dashboard:
+    ip_filter:
+      deny:
+        - 10.0.0.0/33
+`
+
+var ExampleRoutesWithCORS = `
+name: storefront
+prefix: /storefront
+host: localhost:3001
+cors:
+  allow_origins:
+    - https://*.example.com
+  allow_methods:
+    - GET
+    - POST
+  max_age: 10m
+path:
+  /cart: {}
+`
+
+var ExampleRoutesWithInvalidCORS = `
+name: storefront
+prefix: /storefront
+host: localhost:3001
+cors:
+  allow_origins:
+    - https://*.example.*
+path:
+  /cart: {}
+`
@@ -2,15 +2,19 @@ package usecase
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"net"
 	"net/http"
-	"os"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
@@ -18,25 +22,40 @@ import (
 	"github.com/kodefluence/altair/module"
 )
 
+const streamBufferSize = 32 * 1024
+
+// maxTransformedResponseSize caps how much of a response body is buffered for
+// the response_body rules. Larger bodies are streamed to the client untouched.
+const maxTransformedResponseSize = 10 * 1024 * 1024
+
 type Generator struct {
-	routerPath       map[string]module.RouterPath
+	proxyHost        string
+	cors             *entity.RouteCORS
+	routes           []*routeRuntime
 	downStreamPlugin []module.DownstreamController
 	metrics          []module.MetricController
+	apiError         module.ApiError
 }
 
-func NewGenerator(downStreamPlugin []module.DownstreamController, metric []module.MetricController) *Generator {
+func NewGenerator(proxyHost string, cors *entity.RouteCORS, downStreamPlugin []module.DownstreamController, metric []module.MetricController, apiError module.ApiError) *Generator {
 	return &Generator{
-		routerPath:       map[string]module.RouterPath{},
+		proxyHost:        proxyHost,
+		cors:             cors,
 		downStreamPlugin: downStreamPlugin,
 		metrics:          metric,
+		apiError:         apiError,
 	}
 }
 
 func (g *Generator) Generate(engine *gin.Engine, routeObjects []entity.RouteObject) (errVariable error) {
 	for _, m := range g.metrics {
-		m.InjectCounter("routes_downstream_hits", "route_name", "method", "path", "status_code", "status_code_group")
-		m.InjectHistogram("routes_downstream_latency_seconds", "route_name", "method", "path", "status_code", "status_code_group")
+		m.InjectCounter("routes_downstream_hits", "route_name", "method", "path", "status_code", "status_code_group", "attempts")
+		m.InjectHistogram("routes_downstream_latency_seconds", "route_name", "method", "path", "status_code", "status_code_group", "attempts")
 		m.InjectHistogram("routes_downstream_plugin_latency_seconds", "route_name", "plugin_name", "method", "path", "status_code", "status_code_group")
+		m.InjectGauge("routes_upstream_healthy", "route_name", "upstream")
+		m.InjectCounter("routes_upstream_ejections", "route_name", "upstream", "reason")
+		m.InjectCounter("routes_circuit_breaker_transitions", "route_name", "from", "to")
+		m.InjectGauge("routes_in_flight_requests", "route_name")
 	}
 
 	defer func() {
@@ -46,110 +65,343 @@ func (g *Generator) Generate(engine *gin.Engine, routeObjects []entity.RouteObje
 		}
 	}()
 
+	dispatcher := newRouteDispatcher()
+
 	for _, routeObject := range routeObjects {
+		matcher, err := newRouteMatcher(routeObject.Match)
+		if err != nil {
+			return fmt.Errorf("route `%s`: %v", routeObject.Name, err)
+		}
+
+		runtime, err := newRouteRuntime(routeObject, g.cors, g.metrics)
+		if err != nil {
+			log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("name", routeObject.Name).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("route_runtime")).Msg("Error preparing the route upstream")
+			return fmt.Errorf("route `%s`: %v", routeObject.Name, err)
+		}
+		runtime.start()
+		g.routes = append(g.routes, runtime)
+
 		for r, routePath := range routeObject.Path {
 			g.inheritRouterObject(routeObject, &routePath)
 
 			urlPath := fmt.Sprintf("%s%s", routeObject.Prefix, r)
 
-			g.routerPath[urlPath] = routePath
+			log.Info().Str("host", routeObject.Host).Str("name", routeObject.Name).Str("path", urlPath).Strs("methods", routePath.Methods).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("url_path")).Msg("Generating routes")
 
-			log.Info().Str("host", routeObject.Host).Str("name", routeObject.Name).Str("path", urlPath).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("url_path")).Msg("Generating routes")
+			allowed := allowedMethods(routePath)
+			notAllowed := methodNotAllowed(allowed)
 
-			engine.Any(urlPath, func(c *gin.Context) {
-				requestID := uuid.New().String()
-				startTime := time.Now()
+			for _, method := range routeMethods {
+				if !containsMethod(allowed, method) {
+					handler := notAllowed
+					if method == http.MethodOptions && runtime.cors != nil {
+						handler = runtime.cors.handlePreflight(handler, allowed)
+					}
 
-				g.do(c, urlPath, requestID, routeObject)
+					if err := dispatcher.add(urlPath, method, routeCandidate{routeName: routeObject.Name, matcher: matcher, handler: handler}); err != nil {
+						return err
+					}
+					continue
+				}
 
-				log.Info().Str("request_id", requestID).Str("host", routeObject.Host).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Float64("duration_seconds", time.Since(startTime).Seconds()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate")).Msg("Complete forwarding the request")
-			})
+				methodPath := routePath.ForMethod(method)
+
+				rewrite, err := newPathRewrite(routeObject, methodPath)
+				if err != nil {
+					return fmt.Errorf("route `%s`: path `%s`: %v", routeObject.Name, r, err)
+				}
+
+				path := pathRuntime{routePath: methodPath, bufferBody: g.readsBody(methodPath) || runtime.requestBody.enabled(), rewrite: rewrite}
+
+				handler := func(c *gin.Context) {
+					defer runtime.begin()()
+
+					requestID := uuid.New().String()
+					startTime := time.Now()
+
+					attempts := g.do(c, urlPath, requestID, routeObject, runtime, path)
+
+					log.Info().Str("request_id", requestID).Str("host", routeObject.Host).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Int("attempts", attempts).Float64("duration_seconds", time.Since(startTime).Seconds()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate")).Msg("Complete forwarding the request")
+				}
+
+				// Preflights are answered before any downstream plugin, they
+				// carry no credentials for the oauth plugin to check.
+				if method == http.MethodOptions && runtime.cors != nil {
+					handler = runtime.cors.handlePreflight(handler, allowed)
+				}
+
+				if err := dispatcher.add(urlPath, method, routeCandidate{routeName: routeObject.Name, matcher: matcher, handler: handler}); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
+	dispatcher.register(engine)
+
 	return errVariable
 }
 
-func (g *Generator) do(c *gin.Context, urlPath, requestID string, routeObject entity.RouteObject) {
-	proxyReq, err := g.decorateProxyRequest(c, urlPath, requestID, routeObject)
+// do forwards the request and returns how many upstream attempts were made.
+func (g *Generator) do(c *gin.Context, urlPath, requestID string, routeObject entity.RouteObject, runtime *routeRuntime, path pathRuntime) int {
+	// Set before anything is written so the errors of altair and its plugins
+	// are readable by the browser too.
+	if runtime.cors != nil {
+		runtime.cors.decorate(c.Writer.Header(), c.Request.Header.Get("Origin"))
+	}
+
+	if isUpgradeRequest(c.Request) && !upgradable(c.Request) {
+		log.Warn().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("proto", c.Request.Proto).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("upgrade")).Msg("Rejecting upgrade request not sent over HTTP/1.1")
+
+		response := jsonapi.BuildResponse(g.apiError.BadRequestError("Upgrade header, it requires HTTP/1.1"))
+		c.JSON(response.HTTPStatus(), response)
+		g.downStreamMetric(c, routeObject.Name, urlPath, 0, time.Now())
+		return 0
+	}
+
+	proxyReq, err := g.decorateProxyRequest(c, urlPath, requestID, routeObject, runtime, path)
 	if err != nil {
-		return
+		return 0
+	}
+
+	g.decorateHeader(c, requestID, routeObject, runtime, proxyReq)
+
+	if err := g.downStreamPluginCallback(c, proxyReq, urlPath, requestID, routeObject, path.routePath); err != nil {
+		return 0
+	}
+
+	// A plugin answered the request itself, such as a cache hit.
+	if c.IsAborted() {
+		return 0
+	}
+
+	defer g.afterResponse(c, proxyReq, path.routePath)
+
+	generation, allowed := runtime.breaker.allow()
+	if !allowed {
+		log.Warn().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("circuit_breaker")).Msg("Circuit breaker is open, rejecting the request")
+
+		ktx := kontext.Fabricate()
+		ktx.Set("request_id", requestID)
+
+		response := jsonapi.BuildResponse(g.apiError.ServiceUnavailableError(ktx, routeObject.Name))
+		c.JSON(response.HTTPStatus(), response)
+		g.downStreamMetric(c, routeObject.Name, urlPath, 0, time.Now())
+		return 0
 	}
 
-	g.decorateHeader(c, requestID, proxyReq)
+	startTime := time.Now()
+
+	target := runtime.upstream.pick(c)
+	if target == nil {
+		runtime.breaker.record(generation, false, time.Since(startTime))
+
+		log.Error().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("upstream_unavailable")).Msg("No healthy upstream target")
 
-	if err := g.downStreamPluginCallback(c, proxyReq, urlPath, requestID, routeObject); err != nil {
-		return
+		ktx := kontext.Fabricate()
+		ktx.Set("request_id", requestID)
+
+		response := jsonapi.BuildResponse(g.apiError.ServiceUnavailableError(ktx, routeObject.Name))
+		c.JSON(response.HTTPStatus(), response)
+		g.downStreamMetric(c, routeObject.Name, urlPath, 0, startTime)
+		return 0
 	}
 
-	if err := g.callDownStreamService(c, proxyReq, urlPath, requestID, routeObject); err != nil {
-		return
+	if isUpgradeRequest(c.Request) {
+		err := g.callUpgradeService(c, proxyReq, urlPath, requestID, routeObject, runtime, target)
+		// The lifetime of an upgraded connection says nothing about upstream latency.
+		runtime.breaker.record(generation, err == nil && c.Writer.Status() < http.StatusInternalServerError, 0)
+		return 1
 	}
 
+	attempts, err := g.callDownStreamService(c, proxyReq, urlPath, requestID, routeObject, runtime, target)
+	runtime.breaker.record(generation, err == nil && c.Writer.Status() < http.StatusInternalServerError, time.Since(startTime))
+
+	return attempts
 }
 
-func (g *Generator) decorateProxyRequest(c *gin.Context, urlPath, requestID string, routeObject entity.RouteObject) (*http.Request, error) {
-	var proxyReq *http.Request
+// Close stops the background health checkers and idle upstream connections
+// owned by the generated routes.
+func (g *Generator) Close() {
+	for _, runtime := range g.routes {
+		runtime.close()
+	}
+}
 
-	if c.Request.Body != nil {
-		body, err := io.ReadAll(c.Request.Body)
-		if err != nil {
-			log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("read_all_request")).Msg("Error reading incoming request body")
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  http.StatusBadRequest,
-				"message": "Malformed request body given by the client",
-			})
-			return nil, err
-		}
+// decorateProxyRequest builds the upstream request with the rewritten path. The
+// incoming body is streamed as is unless the path buffers it, or the route
+// retries and the body is small enough, in which case it is read into memory,
+// transformed by the route request_body rules when it is JSON, so downstream
+// plugins and retries can replay it through GetBody.
+func (g *Generator) decorateProxyRequest(c *gin.Context, urlPath, requestID string, routeObject entity.RouteObject, runtime *routeRuntime, path pathRuntime) (*http.Request, error) {
+	if runtime.maxBodySize > 0 && c.Request.ContentLength > runtime.maxBodySize {
+		g.requestEntityTooLarge(c, urlPath, requestID, routeObject)
+		return nil, fmt.Errorf("request body of %d bytes exceeds max_body_size", c.Request.ContentLength)
+	}
 
-		proxyReq, err = http.NewRequest(c.Request.Method, "", bytes.NewReader(body))
-		if err != nil {
-			log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("new_request")).Msg("Error creating proxy request")
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  http.StatusBadRequest,
-				"message": "Malformed request body given by the client",
-			})
-			return nil, err
+	var body io.Reader
+	var err error
+
+	bufferBody := path.bufferBody || runtime.retry.buffers(c.Request.ContentLength)
+
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		requestBody := c.Request.Body
+		if runtime.maxBodySize > 0 {
+			requestBody = http.MaxBytesReader(c.Writer, requestBody, runtime.maxBodySize)
 		}
-	} else {
-		var err error
 
-		proxyReq, err = http.NewRequest(c.Request.Method, "", nil)
-		if err != nil {
-			log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("new_request")).Msg("Error creating proxy request")
-			c.JSON(http.StatusBadRequest, gin.H{
-				"status":  http.StatusBadRequest,
-				"message": "Malformed request body given by the client",
-			})
-			return nil, err
+		body = requestBody
+
+		if bufferBody {
+			var buffered []byte
+
+			buffered, err = io.ReadAll(requestBody)
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					g.requestEntityTooLarge(c, urlPath, requestID, routeObject)
+					return nil, err
+				}
+
+				log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("read_all_request")).Msg("Error reading incoming request body")
+				c.JSON(http.StatusBadRequest, gin.H{
+					"status":  http.StatusBadRequest,
+					"message": "Malformed request body given by the client",
+				})
+				return nil, err
+			}
+
+			if runtime.requestBody.transformable(c.Request.Header) {
+				if transformed, err := runtime.requestBody.apply(buffered); err != nil {
+					log.Warn().Err(err).Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("request_body")).Msg("Request body is not valid JSON, forwarding it untouched")
+				} else {
+					buffered = transformed
+				}
+			}
+
+			body = bytes.NewReader(buffered)
 		}
 	}
 
-	proxyReq.URL.Scheme = "http"
-	proxyReq.URL.Host = routeObject.Host
-	proxyReq.URL.Path = c.Request.URL.Path
+	proxyReq, err := http.NewRequestWithContext(c.Request.Context(), c.Request.Method, "", body)
+	if err != nil {
+		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("new_request")).Msg("Error creating proxy request")
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Malformed request body given by the client",
+		})
+		return nil, err
+	}
+
+	if body != nil && !bufferBody {
+		proxyReq.ContentLength = c.Request.ContentLength
+	}
+
+	proxyReq.URL.Scheme = routeScheme(routeObject)
+	proxyReq.URL.Path = path.rewrite.apply(c, c.Request.URL.Path)
 	proxyReq.URL.RawQuery = c.Request.URL.RawQuery
 
 	return proxyReq, nil
 }
 
-func (g *Generator) decorateHeader(c *gin.Context, requestID string, proxyReq *http.Request) {
+func (g *Generator) requestEntityTooLarge(c *gin.Context, urlPath, requestID string, routeObject entity.RouteObject) {
+	log.Warn().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Int64("content_length", c.Request.ContentLength).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("max_body_size")).Msg("Request body is too large")
+	c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+		"status":  http.StatusRequestEntityTooLarge,
+		"message": "Request entity too large",
+	})
+}
+
+// decorateHeader copies the client headers without the hop-by-hop ones, adds
+// the forwarding headers and then applies the route request_headers rules, so
+// the rules can override anything altair sets.
+func (g *Generator) decorateHeader(c *gin.Context, requestID string, routeObject entity.RouteObject, runtime *routeRuntime, proxyReq *http.Request) {
 	for header, values := range c.Request.Header {
 		for _, value := range values {
 			proxyReq.Header.Add(header, value)
 		}
 	}
 
-	proxyReq.Host = os.Getenv("PROXY_HOST")
+	removeHopByHopHeaders(proxyReq.Header)
+
+	if headerContainsToken(c.Request.Header, "Te", "trailers") {
+		proxyReq.Header.Set("Te", "trailers")
+	}
+
+	if isUpgradeRequest(c.Request) {
+		proxyReq.Header.Set("Connection", "Upgrade")
+		proxyReq.Header.Set("Upgrade", c.Request.Header.Get("Upgrade"))
+	}
+
+	proxyReq.Host = g.proxyHost
 	proxyReq.Header.Add("X-Request-ID", requestID)
 	proxyReq.Header.Set("X-Real-Ip-Address", c.ClientIP())
-	proxyReq.Header.Set("X-Forwarded-For", c.Request.RemoteAddr)
+	proxyReq.Header.Set("X-Forwarded-For", forwardedFor(c.Request))
+
+	// The response_body transform cannot read a compressed body, the transport
+	// negotiates gzip itself and hands the response back decompressed.
+	if runtime.responseBody.enabled() {
+		proxyReq.Header.Del("Accept-Encoding")
+	}
+
+	runtime.requestHeaders.apply(proxyReq.Header, headerData(c, requestID, routeObject))
+
+	// Go sends proxyReq.Host instead of a Host header, honour a rule setting it.
+	if host := proxyReq.Header.Get("Host"); host != "" {
+		proxyReq.Host = host
+		proxyReq.Header.Del("Host")
+	}
+}
+
+// copyResponseHeader hands the upstream headers to the client, without the
+// hop-by-hop ones unless the connection switches protocols, and applies the
+// route response_headers rules. The upstream CORS headers are dropped when the
+// route has a cors policy, the gateway already set its own.
+func (g *Generator) copyResponseHeader(c *gin.Context, requestID string, routeObject entity.RouteObject, runtime *routeRuntime, proxyRes *http.Response) {
+	if proxyRes.StatusCode != http.StatusSwitchingProtocols {
+		removeHopByHopHeaders(proxyRes.Header)
+	}
+
+	for header, values := range proxyRes.Header {
+		if runtime.cors != nil && isCORSHeader(header) {
+			continue
+		}
+
+		for _, value := range values {
+			c.Writer.Header().Add(header, value)
+		}
+	}
+
+	runtime.responseHeaders.apply(c.Writer.Header(), headerData(c, requestID, routeObject))
+}
+
+func headerData(c *gin.Context, requestID string, routeObject entity.RouteObject) headerTemplateData {
+	return headerTemplateData{
+		ClientIP:  c.ClientIP(),
+		RequestID: requestID,
+		Host:      c.Request.Host,
+		Method:    c.Request.Method,
+		Path:      c.Request.URL.Path,
+		RouteName: routeObject.Name,
+	}
+}
+
+// readsBody reports whether any downstream plugin needs to read the request
+// body of the given path.
+func (g *Generator) readsBody(routePath module.RouterPath) bool {
+	for _, plugin := range g.downStreamPlugin {
+		if reader, ok := plugin.(module.BodyReaderDownstreamController); ok && reader.ReadsBody(routePath) {
+			return true
+		}
+	}
+
+	return false
 }
 
-func (g *Generator) downStreamPluginCallback(c *gin.Context, proxyReq *http.Request, urlPath, requestID string, routeObject entity.RouteObject) error {
+func (g *Generator) downStreamPluginCallback(c *gin.Context, proxyReq *http.Request, urlPath, requestID string, routeObject entity.RouteObject, routePath module.RouterPath) error {
 	for _, plugin := range g.downStreamPlugin {
 		startTimePlugin := time.Now()
-		if err := plugin.Intervene(c, proxyReq, g.routerPath[urlPath]); err != nil {
+		if err := plugin.Intervene(c, proxyReq, routePath); err != nil {
 			log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("plugin").Str(plugin.Name())).Msg("Plugin error")
 			g.downStreamPluginMetric(c, routeObject.Name, plugin.Name(), urlPath, startTimePlugin)
 			return err
@@ -167,51 +419,230 @@ func (g *Generator) downStreamPluginCallback(c *gin.Context, proxyReq *http.Requ
 	return nil
 }
 
-func (g *Generator) callDownStreamService(c *gin.Context, proxyReq *http.Request, urlPath, requestID string, routeObject entity.RouteObject) error {
-	defer g.downStreamMetric(c, routeObject.Name, urlPath, time.Now())
+// afterResponse hands the response written to the client to the plugins
+// looking at it.
+func (g *Generator) afterResponse(c *gin.Context, proxyReq *http.Request, routePath module.RouterPath) {
+	for _, plugin := range g.downStreamPlugin {
+		if responder, ok := plugin.(module.ResponseDownstreamController); ok {
+			responder.AfterResponse(c, proxyReq, routePath)
+		}
+	}
+}
+
+func (g *Generator) callDownStreamService(c *gin.Context, proxyReq *http.Request, urlPath, requestID string, routeObject entity.RouteObject, runtime *routeRuntime, target *upstreamTarget) (attempts int, errVariable error) {
+	defer func(startTime time.Time) {
+		g.downStreamMetric(c, routeObject.Name, urlPath, attempts, startTime)
+	}(time.Now())
+
+	var proxyRes *http.Response
+	var err error
+
+	target.acquire()
+	defer func() { target.release() }()
+
+	for {
+		attempts++
+
+		proxyRes, err = g.send(runtime, proxyReq, target)
+
+		if !runtime.retry.shouldRetry(proxyReq.Method, attempts, proxyRes, err) {
+			break
+		}
+
+		if !replayable(proxyReq) {
+			log.Warn().Str("host", routeObject.Host).Str("upstream", target.host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Int64("content_length", c.Request.ContentLength).Int("attempt", attempts).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("retry")).Msg("Request body was streamed, not retrying the request")
+			break
+		}
+
+		next := runtime.upstream.pick(c)
+		if next == nil {
+			break
+		}
+
+		if proxyRes != nil {
+			_, _ = io.Copy(io.Discard, proxyRes.Body)
+			proxyRes.Body.Close()
+		}
+		target.release()
+
+		log.Warn().Err(err).Str("host", routeObject.Host).Str("upstream", target.host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Int("attempt", attempts).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("retry")).Msg("Retrying the request")
+
+		target = next
+		target.acquire()
+
+		select {
+		case <-proxyReq.Context().Done():
+			proxyRes, err = nil, proxyReq.Context().Err()
+		case <-time.After(runtime.retry.wait(attempts)):
+			if proxyReq.GetBody != nil {
+				proxyReq.Body, err = proxyReq.GetBody()
+			}
+		}
+
+		if err != nil {
+			break
+		}
+	}
 
-	client := http.Client{}
-	proxyRes, err := client.Do(proxyReq)
 	if err != nil {
-		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("client_do")).Msg("Error fowarding the request")
+		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("upstream", target.host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Int("attempts", attempts).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("client_do")).Msg("Error fowarding the request")
+
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			g.requestEntityTooLarge(c, urlPath, requestID, routeObject)
+			return attempts, err
+		}
+
+		if isTimeout(err) {
+			c.JSON(http.StatusGatewayTimeout, gin.H{
+				"status":  http.StatusGatewayTimeout,
+				"message": "Gateway timeout",
+			})
+			return attempts, err
+		}
+
 		c.JSON(http.StatusBadGateway, gin.H{
 			"status":  http.StatusBadGateway,
 			"message": "Bad gateway",
 		})
-		return err
+		return attempts, err
 	}
 	defer proxyRes.Body.Close()
 
-	resp, err := io.ReadAll(proxyRes.Body)
+	g.copyResponseHeader(c, requestID, routeObject, runtime, proxyRes)
+
+	if runtime.responseBody.transformable(proxyRes.Header) && responseHasBody(c.Request.Method, proxyRes.StatusCode) {
+		return attempts, g.transformResponse(c, proxyRes, urlPath, requestID, routeObject, runtime)
+	}
+
+	c.Status(proxyRes.StatusCode)
+	c.Writer.WriteHeaderNow()
+
+	if err := g.streamResponse(c.Writer, proxyRes); err != nil {
+		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("stream_response")).Msg("Error streaming the response")
+		c.Abort()
+		return attempts, err
+	}
+
+	return attempts, nil
+}
+
+// transformResponse buffers the upstream JSON body and writes it back through
+// the route response_body rules. A body that is not valid JSON or is larger than
+// maxTransformedResponseSize is written as is.
+func (g *Generator) transformResponse(c *gin.Context, proxyRes *http.Response, urlPath, requestID string, routeObject entity.RouteObject, runtime *routeRuntime) error {
+	body, err := io.ReadAll(io.LimitReader(proxyRes.Body, maxTransformedResponseSize+1))
 	if err != nil {
-		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("read_all_response")).Msg("Error reading the response")
+		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("response_body")).Msg("Error reading the response body")
 		c.JSON(http.StatusBadGateway, gin.H{
 			"status":  http.StatusBadGateway,
-			"message": "Bad gateway.",
+			"message": "Bad gateway",
 		})
 		return err
 	}
 
-	for header, values := range proxyRes.Header {
-		for _, value := range values {
-			c.Writer.Header().Add(header, value)
+	if len(body) > maxTransformedResponseSize {
+		log.Warn().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("response_body")).Msg("Response body is too large to transform, streaming it untouched")
+
+		c.Status(proxyRes.StatusCode)
+		c.Writer.WriteHeaderNow()
+
+		proxyRes.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), proxyRes.Body), proxyRes.Body}
+
+		if err := g.streamResponse(c.Writer, proxyRes); err != nil {
+			log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("stream_response")).Msg("Error streaming the response")
+			c.Abort()
+			return err
 		}
+
+		return nil
+	}
+
+	if transformed, err := runtime.responseBody.apply(body); err != nil {
+		log.Warn().Err(err).Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("response_body")).Msg("Response body is not valid JSON, writing it untouched")
+	} else {
+		body = transformed
 	}
 
+	c.Writer.Header().Set("Content-Length", strconv.Itoa(len(body)))
 	c.Status(proxyRes.StatusCode)
-	_, err = c.Writer.Write(resp)
-	if err != nil {
-		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("writer_write")).Msg("Error reading the response")
-		c.JSON(http.StatusBadGateway, gin.H{
-			"status":  http.StatusBadGateway,
-			"message": "Bad gateway.",
-		})
+	c.Writer.WriteHeaderNow()
+
+	if _, err := c.Writer.Write(body); err != nil {
+		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("response_body")).Msg("Error writing the response")
+		c.Abort()
 		return err
 	}
 
 	return nil
 }
 
+// responseHasBody reports whether a response to method with status carries a
+// body, see RFC 7230 section 3.3.3.
+func responseHasBody(method string, status int) bool {
+	if method == http.MethodHead {
+		return false
+	}
+
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// send performs a single attempt against target and feeds the outcome to the
+// passive health checker.
+func (g *Generator) send(runtime *routeRuntime, proxyReq *http.Request, target *upstreamTarget) (*http.Response, error) {
+	proxyReq.URL.Host = target.host
+
+	proxyRes, err := runtime.client.Do(proxyReq)
+	runtime.upstream.observe(target, err == nil && proxyRes.StatusCode < http.StatusInternalServerError)
+
+	return proxyRes, err
+}
+
+// streamResponse copies the upstream body to the client as it arrives. Responses
+// of unknown length, such as server-sent events, are flushed after every read so
+// the client does not wait on gin's buffer.
+func (g *Generator) streamResponse(w gin.ResponseWriter, proxyRes *http.Response) error {
+	flushImmediately := proxyRes.ContentLength == -1
+	if mediaType, _, _ := mime.ParseMediaType(proxyRes.Header.Get("Content-Type")); mediaType == "text/event-stream" {
+		flushImmediately = true
+	}
+
+	buf := make([]byte, streamBufferSize)
+	for {
+		n, readErr := proxyRes.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+
+			if flushImmediately {
+				w.Flush()
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// replayable reports whether the request body can be sent again on retry.
+func replayable(proxyReq *http.Request) bool {
+	return proxyReq.Body == nil || proxyReq.Body == http.NoBody || proxyReq.GetBody != nil
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
 func (g *Generator) downStreamPluginMetric(c *gin.Context, routeName, pluginName, path string, startTime time.Time) {
 	labels := map[string]string{
 		"route_name":        routeName,
@@ -227,8 +658,9 @@ func (g *Generator) downStreamPluginMetric(c *gin.Context, routeName, pluginName
 	}
 }
 
-func (g *Generator) downStreamMetric(c *gin.Context, routeName, path string, startTime time.Time) {
+func (g *Generator) downStreamMetric(c *gin.Context, routeName, path string, attempts int, startTime time.Time) {
 	labels := map[string]string{
+		"attempts":          strconv.Itoa(attempts),
 		"route_name":        routeName,
 		"method":            c.Request.Method,
 		"path":              path,
@@ -246,4 +678,16 @@ func (g *Generator) inheritRouterObject(routeObject entity.RouteObject, routePat
 	if routePath.Auth == "" {
 		routePath.Auth = routeObject.Auth
 	}
+
+	if routePath.RateLimit == nil {
+		routePath.RateLimit = routeObject.RateLimit
+	}
+
+	if routePath.Cache == nil {
+		routePath.Cache = routeObject.Cache
+	}
+
+	if routePath.IPFilter == nil {
+		routePath.IPFilter = routeObject.IPFilter
+	}
 }
@@ -10,6 +10,7 @@ import (
 
 	"github.com/kodefluence/altair/entity"
 	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/module/apierror"
 	"github.com/kodefluence/altair/module/router/usecase"
 	"github.com/kodefluence/altair/testhelper"
 )
@@ -39,7 +40,7 @@ func BenchmarkRoute(b *testing.B) {
 	}
 
 	var downStreamController []module.DownstreamController
-	err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+	err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 	assert.Nil(b, err)
 
 	srvTarget := &http.Server{
@@ -1,10 +1,29 @@
 package usecase_test
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,6 +33,7 @@ import (
 
 	"github.com/kodefluence/altair/entity"
 	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/module/apierror"
 	"github.com/kodefluence/altair/module/mock"
 	"github.com/kodefluence/altair/module/router/usecase"
 	"github.com/kodefluence/altair/testhelper"
@@ -53,7 +73,7 @@ func TestGenerator(t *testing.T) {
 
 				var downStreamController []module.DownstreamController
 
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 
 				srvTarget := &http.Server{
@@ -102,7 +122,7 @@ func TestGenerator(t *testing.T) {
 
 				var downStreamController []module.DownstreamController
 
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 
 				srvTarget := &http.Server{
@@ -153,7 +173,7 @@ func TestGenerator(t *testing.T) {
 
 				var downStreamController []module.DownstreamController
 
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 
 				srvTarget := &http.Server{
@@ -177,6 +197,1338 @@ func TestGenerator(t *testing.T) {
 			})
 		})
 
+		t.Run("Call target services routes with multiple upstream targets", func(t *testing.T) {
+			t.Run("Spread requests across targets", func(t *testing.T) {
+				gatewayEngine := gin.New()
+
+				var routeObjects []entity.RouteObject
+				routeObjects = append(
+					routeObjects,
+					entity.RouteObject{
+						Auth:   "none",
+						Name:   "users",
+						Prefix: "/users",
+						Upstream: entity.RouteUpstream{
+							Strategy: "round_robin",
+							Targets: []entity.RouteTarget{
+								{Host: "localhost:5021"},
+								{Host: "localhost:5022"},
+							},
+						},
+						Path: map[string]entity.RouterPath{
+							"/me": {Auth: "none"},
+						},
+					},
+				)
+
+				hits := map[string]int{}
+				hitsLock := &sync.Mutex{}
+
+				var servers []*http.Server
+				for _, target := range routeObjects[0].Upstream.Targets {
+					host := target.Host
+
+					targetEngine := gin.New()
+					targetEngine.GET("/users/me", func(c *gin.Context) {
+						hitsLock.Lock()
+						hits[host]++
+						hitsLock.Unlock()
+						c.Status(http.StatusOK)
+					})
+
+					srvTarget := &http.Server{
+						Addr:    host,
+						Handler: targetEngine,
+					}
+
+					go func() {
+						_ = srvTarget.ListenAndServe()
+					}()
+
+					servers = append(servers, srvTarget)
+				}
+
+				var downStreamController []module.DownstreamController
+
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
+				assert.Nil(t, err)
+
+				// Given sleep time so the server can boot first
+				time.Sleep(time.Millisecond * 100)
+
+				for i := 0; i < 4; i++ {
+					rec := testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+					assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				}
+
+				assert.Equal(t, 2, hits["localhost:5021"])
+				assert.Equal(t, 2, hits["localhost:5022"])
+
+				for _, srv := range servers {
+					_ = srv.Close()
+				}
+			})
+		})
+
+		t.Run("Call target services routes with every upstream target ejected", func(t *testing.T) {
+			t.Run("Fail fast with 503 status", func(t *testing.T) {
+				gatewayEngine := gin.New()
+
+				var routeObjects []entity.RouteObject
+				routeObjects = append(
+					routeObjects,
+					entity.RouteObject{
+						Auth:   "none",
+						Host:   "localhost:5023",
+						Name:   "users",
+						Prefix: "/users",
+						Upstream: entity.RouteUpstream{
+							HealthCheck: entity.RouteHealthCheck{
+								Passive: entity.RoutePassiveHealthCheck{UnhealthyThreshold: 1, EjectionDuration: "1m"},
+							},
+						},
+						Path: map[string]entity.RouterPath{
+							"/me": {Auth: "none"},
+						},
+					},
+				)
+
+				var downStreamController []module.DownstreamController
+
+				generator := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+				defer generator.Close()
+
+				err := generator.Generate(gatewayEngine, routeObjects)
+				assert.Nil(t, err)
+
+				rec := testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+				assert.Equal(t, http.StatusBadGateway, rec.Result().StatusCode)
+
+				rec = testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+				assert.Equal(t, http.StatusServiceUnavailable, rec.Result().StatusCode)
+				assert.Contains(t, rec.Body.String(), "ERR0503")
+			})
+		})
+
+		t.Run("Call target services routes with retry policy", func(t *testing.T) {
+			t.Run("Retry idempotent request on configured status", func(t *testing.T) {
+				gatewayEngine := gin.New()
+
+				var routeObjects []entity.RouteObject
+				routeObjects = append(
+					routeObjects,
+					entity.RouteObject{
+						Auth:   "none",
+						Host:   "localhost:5024",
+						Name:   "users",
+						Prefix: "/users",
+						Retry: entity.RouteRetry{
+							MaxAttempts: 3,
+							OnStatus:    []int{http.StatusServiceUnavailable},
+							Backoff:     "1ms",
+						},
+						Path: map[string]entity.RouterPath{
+							"/me": {Auth: "none"},
+						},
+					},
+				)
+
+				hits := 0
+				mutex := &sync.Mutex{}
+				srvTarget := &http.Server{
+					Addr: ":5024",
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						mutex.Lock()
+						defer mutex.Unlock()
+
+						hits++
+						if hits < 3 {
+							w.WriteHeader(http.StatusServiceUnavailable)
+							return
+						}
+						w.WriteHeader(http.StatusOK)
+					}),
+				}
+
+				go func() {
+					_ = srvTarget.ListenAndServe()
+				}()
+
+				var downStreamController []module.DownstreamController
+
+				generator := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+				defer generator.Close()
+
+				err := generator.Generate(gatewayEngine, routeObjects)
+				assert.Nil(t, err)
+
+				// Given sleep time so the server can boot first
+				time.Sleep(time.Millisecond * 100)
+
+				rec := testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				assert.Equal(t, 3, hits)
+
+				hits = 0
+				rec = testhelper.PerformRequest(gatewayEngine, "POST", "/users/me", nil)
+				assert.Equal(t, http.StatusServiceUnavailable, rec.Result().StatusCode)
+				assert.Equal(t, 1, hits)
+
+				_ = srvTarget.Close()
+			})
+
+			t.Run("Retry request bodies only when they are buffered", func(t *testing.T) {
+				gatewayEngine := gin.New()
+
+				var routeObjects []entity.RouteObject
+				routeObjects = append(
+					routeObjects,
+					entity.RouteObject{
+						Auth:   "none",
+						Host:   "localhost:5037",
+						Name:   "users",
+						Prefix: "/users",
+						Retry: entity.RouteRetry{
+							MaxAttempts: 3,
+							OnStatus:    []int{http.StatusServiceUnavailable},
+							Backoff:     "1ms",
+						},
+						Path: map[string]entity.RouterPath{
+							"/me": {Auth: "none"},
+						},
+					},
+				)
+
+				var bodies []int
+				mutex := &sync.Mutex{}
+				srvTarget := &http.Server{
+					Addr: ":5037",
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						body, _ := io.ReadAll(r.Body)
+
+						mutex.Lock()
+						defer mutex.Unlock()
+
+						bodies = append(bodies, len(body))
+						w.WriteHeader(http.StatusServiceUnavailable)
+					}),
+				}
+
+				go func() {
+					_ = srvTarget.ListenAndServe()
+				}()
+
+				var downStreamController []module.DownstreamController
+
+				generator := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+				defer generator.Close()
+
+				err := generator.Generate(gatewayEngine, routeObjects)
+				assert.Nil(t, err)
+
+				// Given sleep time so the server can boot first
+				time.Sleep(time.Millisecond * 100)
+
+				t.Run("Replay small bodies on every attempt", func(t *testing.T) {
+					bodies = nil
+
+					rec := testhelper.PerformRequest(gatewayEngine, "PUT", "/users/me", strings.NewReader(`{"name":"altair"}`))
+					assert.Equal(t, http.StatusServiceUnavailable, rec.Result().StatusCode)
+					assert.Equal(t, []int{17, 17, 17}, bodies)
+				})
+
+				t.Run("Do not retry bodies larger than the retry buffer", func(t *testing.T) {
+					bodies = nil
+
+					rec := testhelper.PerformRequest(gatewayEngine, "PUT", "/users/me", bytes.NewReader(make([]byte, 1<<20+1)))
+					assert.Equal(t, http.StatusServiceUnavailable, rec.Result().StatusCode)
+					assert.Equal(t, []int{1<<20 + 1}, bodies)
+				})
+
+				t.Run("Do not retry chunked bodies", func(t *testing.T) {
+					bodies = nil
+
+					rec := testhelper.PerformRequest(gatewayEngine, "PUT", "/users/me", io.NopCloser(strings.NewReader(`{"name":"altair"}`)), func(req *http.Request) {
+						req.ContentLength = -1
+					})
+					assert.Equal(t, http.StatusServiceUnavailable, rec.Result().StatusCode)
+					assert.Equal(t, []int{17}, bodies)
+				})
+
+				_ = srvTarget.Close()
+			})
+
+			t.Run("Return 504 when upstream exceeds the route timeout", func(t *testing.T) {
+				gatewayEngine := gin.New()
+
+				var routeObjects []entity.RouteObject
+				routeObjects = append(
+					routeObjects,
+					entity.RouteObject{
+						Auth:    "none",
+						Host:    "localhost:5025",
+						Name:    "users",
+						Prefix:  "/users",
+						Timeout: "50ms",
+						Path: map[string]entity.RouterPath{
+							"/me": {Auth: "none"},
+						},
+					},
+				)
+
+				srvTarget := &http.Server{
+					Addr: ":5025",
+					Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						time.Sleep(time.Millisecond * 200)
+						w.WriteHeader(http.StatusOK)
+					}),
+				}
+
+				go func() {
+					_ = srvTarget.ListenAndServe()
+				}()
+
+				var downStreamController []module.DownstreamController
+
+				generator := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+				defer generator.Close()
+
+				err := generator.Generate(gatewayEngine, routeObjects)
+				assert.Nil(t, err)
+
+				// Given sleep time so the server can boot first
+				time.Sleep(time.Millisecond * 100)
+
+				rec := testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+				assert.Equal(t, http.StatusGatewayTimeout, rec.Result().StatusCode)
+
+				_ = srvTarget.Close()
+			})
+		})
+
+		t.Run("Call target services routes with circuit breaker", func(t *testing.T) {
+			t.Run("Fail fast with json api 503 while the breaker is open", func(t *testing.T) {
+				gatewayEngine := gin.New()
+
+				var routeObjects []entity.RouteObject
+				routeObjects = append(
+					routeObjects,
+					entity.RouteObject{
+						Auth:   "none",
+						Host:   "localhost:5026",
+						Name:   "users",
+						Prefix: "/users",
+						CircuitBreaker: entity.RouteCircuitBreaker{
+							ErrorRateThreshold: 1,
+							MinRequests:        1,
+							CoolDown:           "1m",
+						},
+						Path: map[string]entity.RouterPath{
+							"/me": {Auth: "none"},
+						},
+					},
+				)
+
+				var downStreamController []module.DownstreamController
+
+				generator := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+				defer generator.Close()
+
+				err := generator.Generate(gatewayEngine, routeObjects)
+				assert.Nil(t, err)
+
+				rec := testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+				assert.Equal(t, http.StatusBadGateway, rec.Result().StatusCode)
+
+				rec = testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+				assert.Equal(t, http.StatusServiceUnavailable, rec.Result().StatusCode)
+				assert.Contains(t, rec.Body.String(), "ERR0503")
+			})
+		})
+
+		t.Run("Call target services routes with request and response bodies", func(t *testing.T) {
+			gatewayEngine := gin.New()
+
+			var routeObjects []entity.RouteObject
+			routeObjects = append(
+				routeObjects,
+				entity.RouteObject{
+					Auth:        "none",
+					Host:        "localhost:5027",
+					Name:        "files",
+					Prefix:      "/files",
+					MaxBodySize: "1KB",
+					Path: map[string]entity.RouterPath{
+						"/upload":  {Auth: "none"},
+						"/events":  {Auth: "none"},
+						"/private": {Auth: "oauth_application"},
+					},
+				},
+			)
+
+			releaseEvents := make(chan struct{})
+			srvTarget := &http.Server{
+				Addr: ":5027",
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path == "/files/events" {
+						w.Header().Set("Content-Type", "text/event-stream")
+						_, _ = w.Write([]byte("data: first\n\n"))
+						w.(http.Flusher).Flush()
+						<-releaseEvents
+						_, _ = w.Write([]byte("data: second\n\n"))
+						return
+					}
+
+					body, _ := io.ReadAll(r.Body)
+					_, _ = w.Write(body)
+				}),
+			}
+
+			go func() {
+				_ = srvTarget.ListenAndServe()
+			}()
+
+			bodyReader := mock.NewMockBodyReaderDownstreamController(mockCtrl)
+			bodyReader.EXPECT().Name().AnyTimes().Return("body-reader-plugin")
+			bodyReader.EXPECT().ReadsBody(gomock.Any()).AnyTimes().DoAndReturn(func(r module.RouterPath) bool {
+				return r.GetAuth() == "oauth_application"
+			})
+			bodyReader.EXPECT().Intervene(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(c *gin.Context, proxyReq *http.Request, r module.RouterPath) error {
+				if r.GetAuth() != "oauth_application" {
+					assert.Nil(t, proxyReq.GetBody)
+					return nil
+				}
+
+				body, err := proxyReq.GetBody()
+				assert.Nil(t, err)
+
+				content, _ := io.ReadAll(body)
+				assert.Equal(t, `{"client_uid": "uid"}`, string(content))
+				return nil
+			})
+
+			generator := usecase.NewGenerator("", nil, []module.DownstreamController{bodyReader}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+			defer generator.Close()
+
+			err := generator.Generate(gatewayEngine, routeObjects)
+			assert.Nil(t, err)
+
+			// Given sleep time so the server can boot first
+			time.Sleep(time.Millisecond * 100)
+
+			t.Run("Stream request body to the upstream", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "POST", "/files/upload", strings.NewReader("hello world"))
+				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				assert.Equal(t, "hello world", rec.Body.String())
+			})
+
+			t.Run("Buffer request body when a plugin reads it", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "POST", "/files/private", strings.NewReader(`{"client_uid": "uid"}`))
+				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+				assert.Equal(t, `{"client_uid": "uid"}`, rec.Body.String())
+			})
+
+			t.Run("Reject request body larger than max body size", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "POST", "/files/upload", strings.NewReader(strings.Repeat("a", 2048)))
+				assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Result().StatusCode)
+			})
+
+			t.Run("Reject chunked request body larger than max body size", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "POST", "/files/upload", io.MultiReader(strings.NewReader(strings.Repeat("a", 2048))), func(req *http.Request) {
+					req.ContentLength = -1
+				})
+				assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Result().StatusCode)
+			})
+
+			t.Run("Flush server-sent events as they arrive", func(t *testing.T) {
+				gateway := httptest.NewServer(gatewayEngine)
+				defer gateway.Close()
+
+				res, err := http.Get(gateway.URL + "/files/events")
+				assert.Nil(t, err)
+				defer res.Body.Close()
+
+				reader := bufio.NewReader(res.Body)
+				line, err := reader.ReadString('\n')
+				assert.Nil(t, err)
+				assert.Equal(t, "data: first\n", line)
+
+				close(releaseEvents)
+
+				rest, _ := io.ReadAll(reader)
+				assert.Equal(t, "\ndata: second\n\n", string(rest))
+			})
+
+			_ = srvTarget.Close()
+		})
+
+		t.Run("Call target services routes with connection upgrade", func(t *testing.T) {
+			gatewayEngine := gin.New()
+
+			var routeObjects []entity.RouteObject
+			routeObjects = append(
+				routeObjects,
+				entity.RouteObject{
+					Auth:    "none",
+					Host:    "localhost:5028",
+					Name:    "notifications",
+					Prefix:  "/notifications",
+					Timeout: "50ms",
+					Path: map[string]entity.RouterPath{
+						"/ws":      {Auth: "none"},
+						"/private": {Auth: "oauth"},
+					},
+				},
+			)
+
+			upgrades := &atomic.Int32{}
+			srvTarget := &http.Server{
+				Addr: ":5028",
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					upgrades.Add(1)
+					assert.Equal(t, "echo", r.Header.Get("Upgrade"))
+
+					conn, buf, err := w.(http.Hijacker).Hijack()
+					assert.Nil(t, err)
+					defer conn.Close()
+
+					_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: echo\r\nConnection: Upgrade\r\n\r\n")
+					_ = buf.Flush()
+
+					_, _ = io.Copy(conn, buf)
+				}),
+			}
+
+			go func() {
+				_ = srvTarget.ListenAndServe()
+			}()
+
+			oauthPlugin := mock.NewMockDownstreamController(mockCtrl)
+			oauthPlugin.EXPECT().Name().AnyTimes().Return("oauth-plugin")
+			oauthPlugin.EXPECT().Intervene(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(c *gin.Context, proxyReq *http.Request, r module.RouterPath) error {
+				if r.GetAuth() == "oauth" {
+					c.AbortWithStatus(http.StatusUnauthorized)
+					return errors.New("unauthorized")
+				}
+				return nil
+			})
+
+			generator := usecase.NewGenerator("", nil, []module.DownstreamController{oauthPlugin}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+			defer generator.Close()
+
+			err := generator.Generate(gatewayEngine, routeObjects)
+			assert.Nil(t, err)
+
+			gateway := httptest.NewServer(gatewayEngine)
+			defer gateway.Close()
+
+			// Given sleep time so the server can boot first
+			time.Sleep(time.Millisecond * 100)
+
+			handshake := func(path string) (net.Conn, *bufio.Reader, *http.Response) {
+				conn, err := net.Dial("tcp", strings.TrimPrefix(gateway.URL, "http://"))
+				assert.Nil(t, err)
+
+				_, err = conn.Write([]byte("GET " + path + " HTTP/1.1\r\nHost: altair\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n"))
+				assert.Nil(t, err)
+
+				reader := bufio.NewReader(conn)
+				res, err := http.ReadResponse(reader, nil)
+				assert.Nil(t, err)
+
+				return conn, reader, res
+			}
+
+			t.Run("Pipe bytes both ways after switching protocols", func(t *testing.T) {
+				conn, reader, res := handshake("/notifications/ws")
+				defer conn.Close()
+
+				assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+				assert.Equal(t, "echo", res.Header.Get("Upgrade"))
+
+				// Outlive the route timeout to make sure it does not apply to upgraded connections.
+				time.Sleep(time.Millisecond * 100)
+
+				for _, message := range []string{"ping", "pong"} {
+					_, err := conn.Write([]byte(message))
+					assert.Nil(t, err)
+
+					received := make([]byte, len(message))
+					_, err = io.ReadFull(reader, received)
+					assert.Nil(t, err)
+					assert.Equal(t, message, string(received))
+				}
+			})
+
+			t.Run("Run downstream plugins before accepting the upgrade", func(t *testing.T) {
+				conn, _, res := handshake("/notifications/private")
+				defer conn.Close()
+
+				assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+			})
+
+			t.Run("Reject upgrade requests that are not HTTP/1.1 without calling the upstream", func(t *testing.T) {
+				upgrades.Store(0)
+
+				for _, proto := range []struct {
+					major, minor int
+				}{{1, 0}, {2, 0}} {
+					req := httptest.NewRequest("GET", "/notifications/ws", nil)
+					req.ProtoMajor, req.ProtoMinor = proto.major, proto.minor
+					req.Proto = fmt.Sprintf("HTTP/%d.%d", proto.major, proto.minor)
+					req.Header.Set("Connection", "Upgrade")
+					req.Header.Set("Upgrade", "echo")
+
+					w := httptest.NewRecorder()
+					gatewayEngine.ServeHTTP(w, req)
+
+					assert.Equal(t, http.StatusBadRequest, w.Code, req.Proto)
+				}
+
+				assert.Equal(t, int32(0), upgrades.Load())
+			})
+
+			_ = srvTarget.Close()
+		})
+
+		t.Run("Call target services routes with restricted methods", func(t *testing.T) {
+			gatewayEngine := gin.New()
+
+			var routeObjects []entity.RouteObject
+			routeObjects = append(
+				routeObjects,
+				entity.RouteObject{
+					Auth:   "none",
+					Host:   "localhost:5031",
+					Name:   "users",
+					Prefix: "/users",
+					Path: map[string]entity.RouterPath{
+						"/me": {
+							Methods: []string{"GET", "PUT"},
+							MethodOverrides: map[string]entity.RouterPathMethod{
+								"PUT": {Auth: "oauth", Scope: "users:write"},
+							},
+						},
+						"/any": {},
+					},
+				},
+			)
+
+			srvTarget := &http.Server{
+				Addr: ":5031",
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}),
+			}
+
+			go func() {
+				_ = srvTarget.ListenAndServe()
+			}()
+
+			var lock sync.Mutex
+			interveneAuth := map[string]module.RouterPath{}
+
+			oauthPlugin := mock.NewMockDownstreamController(mockCtrl)
+			oauthPlugin.EXPECT().Name().AnyTimes().Return("oauth-plugin")
+			oauthPlugin.EXPECT().Intervene(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().DoAndReturn(func(c *gin.Context, proxyReq *http.Request, r module.RouterPath) error {
+				lock.Lock()
+				defer lock.Unlock()
+				interveneAuth[c.Request.Method] = r
+				return nil
+			})
+
+			generator := usecase.NewGenerator("", nil, []module.DownstreamController{oauthPlugin}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+			defer generator.Close()
+
+			err := generator.Generate(gatewayEngine, routeObjects)
+			assert.Nil(t, err)
+
+			// Given sleep time so the server can boot first
+			time.Sleep(time.Millisecond * 100)
+
+			t.Run("Forward allowed methods with their overrides", func(t *testing.T) {
+				w := testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+				assert.Equal(t, http.StatusOK, w.Code)
+
+				w = testhelper.PerformRequest(gatewayEngine, "PUT", "/users/me", nil)
+				assert.Equal(t, http.StatusOK, w.Code)
+
+				lock.Lock()
+				defer lock.Unlock()
+
+				assert.Equal(t, "none", interveneAuth["GET"].GetAuth())
+				assert.Equal(t, "oauth", interveneAuth["PUT"].GetAuth())
+				assert.Equal(t, "users:write", interveneAuth["PUT"].GetScope())
+			})
+
+			t.Run("Answer 405 with allow header for other methods", func(t *testing.T) {
+				w := testhelper.PerformRequest(gatewayEngine, "DELETE", "/users/me", nil)
+				assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+				assert.Equal(t, "GET, PUT", w.Header().Get("Allow"))
+
+				lock.Lock()
+				defer lock.Unlock()
+
+				_, intervened := interveneAuth["DELETE"]
+				assert.False(t, intervened)
+			})
+
+			t.Run("Forward every method when the path has no methods", func(t *testing.T) {
+				w := testhelper.PerformRequest(gatewayEngine, "DELETE", "/users/any", nil)
+				assert.Equal(t, http.StatusOK, w.Code)
+			})
+
+			_ = srvTarget.Close()
+		})
+
+		t.Run("Call target services routes with rewritten paths", func(t *testing.T) {
+			gatewayEngine := gin.New()
+
+			var routeObjects []entity.RouteObject
+			routeObjects = append(
+				routeObjects,
+				entity.RouteObject{
+					Auth:        "none",
+					Host:        "localhost:5032",
+					Name:        "users",
+					Prefix:      "/users",
+					StripPrefix: true,
+					AddPrefix:   "/api",
+					Path: map[string]entity.RouterPath{
+						"/me": {},
+						"/:id/avatar": {
+							Rewrite: []entity.RouteRewrite{{Match: `^/\d+/avatar$`, Replace: "/avatars/:id"}},
+						},
+					},
+				},
+			)
+
+			srvTarget := &http.Server{
+				Addr: ":5032",
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, _ = w.Write([]byte(r.URL.RequestURI()))
+				}),
+			}
+
+			go func() {
+				_ = srvTarget.ListenAndServe()
+			}()
+
+			generator := usecase.NewGenerator("", nil, []module.DownstreamController{}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+			defer generator.Close()
+
+			err := generator.Generate(gatewayEngine, routeObjects)
+			assert.Nil(t, err)
+
+			// Given sleep time so the server can boot first
+			time.Sleep(time.Millisecond * 100)
+
+			t.Run("Strip the route prefix and add the upstream prefix", func(t *testing.T) {
+				w := testhelper.PerformRequest(gatewayEngine, "GET", "/users/me?fields=name", nil)
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, "/api/me?fields=name", w.Body.String())
+			})
+
+			t.Run("Rewrite with path params", func(t *testing.T) {
+				w := testhelper.PerformRequest(gatewayEngine, "GET", "/users/42/avatar", nil)
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, "/api/avatars/42", w.Body.String())
+			})
+
+			_ = srvTarget.Close()
+		})
+
+		t.Run("Call target services routes matched by host", func(t *testing.T) {
+			gatewayEngine := gin.New()
+
+			tenantA := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("tenant-a"))
+			}))
+			defer tenantA.Close()
+
+			tenantB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("tenant-b"))
+			}))
+			defer tenantB.Close()
+
+			routeObjects := []entity.RouteObject{
+				{
+					Auth:   "none",
+					Host:   strings.TrimPrefix(tenantA.URL, "http://"),
+					Name:   "users-tenant-a",
+					Prefix: "/users",
+					Match:  entity.RouteMatch{Hosts: []string{"api.tenant-a.com"}},
+					Path:   map[string]entity.RouterPath{"/me": {}},
+				},
+				{
+					Auth:   "none",
+					Host:   strings.TrimPrefix(tenantB.URL, "http://"),
+					Name:   "users-tenant-b",
+					Prefix: "/users",
+					Match:  entity.RouteMatch{Hosts: []string{"*.tenant-b.com"}},
+					Path:   map[string]entity.RouterPath{"/me": {}},
+				},
+			}
+
+			generator := usecase.NewGenerator("", nil, []module.DownstreamController{}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+			defer generator.Close()
+
+			err := generator.Generate(gatewayEngine, routeObjects)
+			assert.Nil(t, err)
+
+			for host, expected := range map[string]string{
+				"api.tenant-a.com":    "tenant-a",
+				"eu.api.tenant-b.com": "tenant-b",
+			} {
+				req := httptest.NewRequest("GET", "/users/me", nil)
+				req.Host = host
+
+				w := httptest.NewRecorder()
+				gatewayEngine.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusOK, w.Code, host)
+				assert.Equal(t, expected, w.Body.String(), host)
+			}
+
+			req := httptest.NewRequest("GET", "/users/me", nil)
+			req.Host = "api.tenant-c.com"
+
+			w := httptest.NewRecorder()
+			gatewayEngine.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusNotFound, w.Code)
+		})
+
+		t.Run("Call target services routes with header rules", func(t *testing.T) {
+			gatewayEngine := gin.New()
+
+			routeObjects := []entity.RouteObject{
+				{
+					Auth:   "none",
+					Host:   "localhost:5033",
+					Name:   "users",
+					Prefix: "/users",
+					RequestHeaders: entity.RouteHeaderRules{
+						Remove: []string{"Cookie"},
+						Rename: map[string]string{"X-Tenant": "X-Upstream-Tenant"},
+						Set:    map[string]string{"X-Route": "{{ .RouteName }}"},
+					},
+					ResponseHeaders: entity.RouteHeaderRules{
+						Remove: []string{"Server"},
+						Append: map[string]string{"X-Served-By": "altair {{ .Method }}"},
+					},
+					Path: map[string]entity.RouterPath{"/me": {}},
+				},
+			}
+
+			var received *http.Request
+
+			srvTarget := &http.Server{
+				Addr: ":5033",
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					received = r
+					w.Header().Set("Server", "users-service")
+					w.Header().Set("Connection", "X-Upstream-Only")
+					w.Header().Set("X-Upstream-Only", "1")
+					w.WriteHeader(http.StatusOK)
+				}),
+			}
+
+			go func() {
+				_ = srvTarget.ListenAndServe()
+			}()
+
+			generator := usecase.NewGenerator("users.internal", nil, []module.DownstreamController{}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+			defer generator.Close()
+
+			err := generator.Generate(gatewayEngine, routeObjects)
+			assert.Nil(t, err)
+
+			// Given sleep time so the server can boot first
+			time.Sleep(time.Millisecond * 100)
+
+			req := httptest.NewRequest("GET", "/users/me", nil)
+			req.Header.Set("Cookie", "session=1")
+			req.Header.Set("X-Tenant", "a")
+			req.Header.Set("Connection", "X-Client-Only")
+			req.Header.Set("X-Client-Only", "1")
+			req.Header.Set("Keep-Alive", "timeout=5")
+
+			w := httptest.NewRecorder()
+			gatewayEngine.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			t.Run("Transform request headers", func(t *testing.T) {
+				assert.Equal(t, "users.internal", received.Host)
+				assert.Equal(t, "", received.Header.Get("Cookie"))
+				assert.Equal(t, "a", received.Header.Get("X-Upstream-Tenant"))
+				assert.Equal(t, "users", received.Header.Get("X-Route"))
+				assert.NotEqual(t, "", received.Header.Get("X-Request-ID"))
+			})
+
+			t.Run("Strip hop-by-hop request headers", func(t *testing.T) {
+				assert.Equal(t, "", received.Header.Get("X-Client-Only"))
+				assert.Equal(t, "", received.Header.Get("Keep-Alive"))
+			})
+
+			t.Run("Transform response headers", func(t *testing.T) {
+				assert.Equal(t, "", w.Header().Get("Server"))
+				assert.Equal(t, "altair GET", w.Header().Get("X-Served-By"))
+				assert.Equal(t, "", w.Header().Get("X-Upstream-Only"))
+			})
+
+			_ = srvTarget.Close()
+		})
+
+		t.Run("Transform JSON bodies", func(t *testing.T) {
+			gatewayEngine := gin.New()
+
+			routeObjects := []entity.RouteObject{
+				{
+					Auth:   "none",
+					Host:   "localhost:5034",
+					Name:   "users",
+					Prefix: "/users",
+					RequestBody: entity.RouteBodyTransform{
+						Rename: map[string]string{"userName": "user_name"},
+					},
+					ResponseBody: entity.RouteBodyTransform{
+						Remove: []string{"password"},
+						Wrap:   "data",
+					},
+					Path: map[string]entity.RouterPath{"/me": {}, "/avatar": {}, "/large": {}},
+				},
+			}
+
+			var receivedBody []byte
+			var receivedAcceptEncoding string
+
+			largeBody := []byte(`{"password":"secret","padding":"` + strings.Repeat("a", 10*1024*1024) + `"}`)
+
+			srvTarget := &http.Server{
+				Addr: ":5034",
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					receivedBody, _ = io.ReadAll(r.Body)
+					receivedAcceptEncoding = r.Header.Get("Accept-Encoding")
+
+					if r.URL.Path == "/users/avatar" {
+						w.Header().Set("Content-Type", "text/plain")
+						_, _ = w.Write([]byte(`{"password":"plain"}`))
+						return
+					}
+
+					if r.URL.Path == "/users/large" {
+						w.Header().Set("Content-Type", "application/json")
+						_, _ = w.Write(largeBody)
+						return
+					}
+
+					w.Header().Set("Content-Type", "application/json")
+					_, _ = w.Write([]byte(`{"name":"john","password":"secret"}`))
+				}),
+			}
+
+			go func() {
+				_ = srvTarget.ListenAndServe()
+			}()
+
+			generator := usecase.NewGenerator("", nil, []module.DownstreamController{}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+			defer generator.Close()
+
+			err := generator.Generate(gatewayEngine, routeObjects)
+			assert.Nil(t, err)
+
+			// Given sleep time so the server can boot first
+			time.Sleep(time.Millisecond * 100)
+
+			t.Run("Transform JSON request and response bodies", func(t *testing.T) {
+				req := httptest.NewRequest("POST", "/users/me", bytes.NewBufferString(`{"userName":"john"}`))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Accept-Encoding", "br")
+
+				w := httptest.NewRecorder()
+				gatewayEngine.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.JSONEq(t, `{"user_name":"john"}`, string(receivedBody))
+				assert.NotEqual(t, "br", receivedAcceptEncoding)
+				assert.JSONEq(t, `{"data":{"name":"john"}}`, w.Body.String())
+				assert.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get("Content-Length"))
+			})
+
+			t.Run("Pass non JSON bodies through untouched", func(t *testing.T) {
+				req := httptest.NewRequest("POST", "/users/avatar", bytes.NewBufferString(`{"userName":"john"}`))
+				req.Header.Set("Content-Type", "text/plain")
+
+				w := httptest.NewRecorder()
+				gatewayEngine.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, `{"userName":"john"}`, string(receivedBody))
+				assert.Equal(t, `{"password":"plain"}`, w.Body.String())
+			})
+
+			t.Run("Stream bodies too large to transform untouched", func(t *testing.T) {
+				req := httptest.NewRequest("GET", "/users/large", nil)
+
+				w := httptest.NewRecorder()
+				gatewayEngine.ServeHTTP(w, req)
+
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.True(t, bytes.Equal(largeBody, w.Body.Bytes()))
+			})
+
+			_ = srvTarget.Close()
+		})
+
+		t.Run("Answer requests from plugins", func(t *testing.T) {
+			gatewayEngine := gin.New()
+
+			routeObjects := []entity.RouteObject{
+				{
+					Auth:   "none",
+					Host:   "localhost:5035",
+					Name:   "catalog",
+					Prefix: "/catalog",
+					Cache:  &entity.RouteCache{TTL: "1m"},
+					Path:   map[string]entity.RouterPath{"/products": {}},
+				},
+			}
+
+			upstreamHits := 0
+			srvTarget := &http.Server{
+				Addr: ":5035",
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					upstreamHits++
+					_, _ = w.Write([]byte("products"))
+				}),
+			}
+
+			go func() {
+				_ = srvTarget.ListenAndServe()
+			}()
+
+			var cached []byte
+			cachePlugin := mock.NewMockResponseDownstreamController(mockCtrl)
+			cachePlugin.EXPECT().Name().AnyTimes().Return("cache-plugin")
+			cachePlugin.EXPECT().Intervene(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).DoAndReturn(func(c *gin.Context, proxyReq *http.Request, r module.RouterPath) error {
+				assert.Equal(t, &entity.RouteCache{TTL: "1m"}, r.GetPluginConfig("cache"))
+
+				if cached != nil {
+					c.Data(http.StatusOK, "text/plain", cached)
+					c.Abort()
+				}
+				return nil
+			})
+			cachePlugin.EXPECT().AfterResponse(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Do(func(c *gin.Context, proxyReq *http.Request, r module.RouterPath) {
+				assert.Equal(t, http.StatusOK, c.Writer.Status())
+				cached = []byte("cached products")
+			})
+
+			generator := usecase.NewGenerator("", nil, []module.DownstreamController{cachePlugin}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+			defer generator.Close()
+
+			err := generator.Generate(gatewayEngine, routeObjects)
+			assert.Nil(t, err)
+
+			// Given sleep time so the server can boot first
+			time.Sleep(time.Millisecond * 100)
+
+			t.Run("Hand the response to the plugin once written", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "GET", "/catalog/products", nil)
+				assert.Equal(t, "products", rec.Body.String())
+			})
+
+			t.Run("Do not forward a request the plugin answered", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "GET", "/catalog/products", nil)
+				assert.Equal(t, "cached products", rec.Body.String())
+				assert.Equal(t, 1, upstreamHits)
+			})
+
+			_ = srvTarget.Close()
+		})
+
+		t.Run("Answer cors preflights at the gateway", func(t *testing.T) {
+			gatewayEngine := gin.New()
+
+			routeObjects := []entity.RouteObject{
+				{
+					Auth:   "none",
+					Host:   "localhost:5036",
+					Name:   "storefront",
+					Prefix: "/storefront",
+					Path:   map[string]entity.RouterPath{"/cart": {Methods: []string{"GET", "POST"}}},
+				},
+				{
+					Auth:   "none",
+					Host:   "localhost:5036",
+					Name:   "legacy",
+					Prefix: "/legacy",
+					CORS:   &entity.RouteCORS{Disabled: true},
+					Path:   map[string]entity.RouterPath{"/cart": {}},
+				},
+			}
+
+			upstreamHits := 0
+			srvTarget := &http.Server{
+				Addr: ":5036",
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					upstreamHits++
+					w.Header().Set("Access-Control-Allow-Origin", "*")
+					_, _ = w.Write([]byte("cart"))
+				}),
+			}
+
+			go func() {
+				_ = srvTarget.ListenAndServe()
+			}()
+
+			downStreamPlugin := mock.NewMockDownstreamController(mockCtrl)
+			downStreamPlugin.EXPECT().Name().AnyTimes().Return("oauth-plugin")
+			downStreamPlugin.EXPECT().Intervene(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(nil)
+
+			cors := &entity.RouteCORS{
+				AllowOrigins:     []string{"https://*.example.com"},
+				ExposeHeaders:    []string{"X-Request-ID"},
+				AllowCredentials: true,
+				MaxAge:           "10m",
+			}
+
+			generator := usecase.NewGenerator("", cors, []module.DownstreamController{downStreamPlugin}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+			defer generator.Close()
+
+			err := generator.Generate(gatewayEngine, routeObjects)
+			assert.Nil(t, err)
+
+			// Given sleep time so the server can boot first
+			time.Sleep(time.Millisecond * 100)
+
+			preflight := func(origin, method string) func(req *http.Request) {
+				return func(req *http.Request) {
+					req.Header.Set("Origin", origin)
+					req.Header.Set("Access-Control-Request-Method", method)
+					req.Header.Set("Access-Control-Request-Headers", "Authorization, Content-Type")
+				}
+			}
+
+			t.Run("Answer an allowed preflight without forwarding it", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "OPTIONS", "/storefront/cart", nil, preflight("https://shop.example.com", "POST"))
+
+				assert.Equal(t, http.StatusNoContent, rec.Code)
+				assert.Equal(t, "https://shop.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+				assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+				assert.Equal(t, "GET, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+				assert.Equal(t, "Authorization, Content-Type", rec.Header().Get("Access-Control-Allow-Headers"))
+				assert.Equal(t, "600", rec.Header().Get("Access-Control-Max-Age"))
+				assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+				assert.Equal(t, 0, upstreamHits)
+			})
+
+			t.Run("Reject a preflight from an unknown origin", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "OPTIONS", "/storefront/cart", nil, preflight("https://example.org", "POST"))
+
+				assert.Equal(t, http.StatusForbidden, rec.Code)
+				assert.Equal(t, "", rec.Header().Get("Access-Control-Allow-Origin"))
+			})
+
+			t.Run("Reject a preflight for a method the path does not allow", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "OPTIONS", "/storefront/cart", nil, preflight("https://shop.example.com", "DELETE"))
+
+				assert.Equal(t, http.StatusForbidden, rec.Code)
+			})
+
+			t.Run("Answer other OPTIONS requests as before", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "OPTIONS", "/storefront/cart", nil)
+
+				assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+			})
+
+			t.Run("Replace the upstream cors headers of an actual response", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "GET", "/storefront/cart", nil, func(req *http.Request) {
+					req.Header.Set("Origin", "https://shop.example.com")
+				})
+
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, []string{"https://shop.example.com"}, rec.Header().Values("Access-Control-Allow-Origin"))
+				assert.Equal(t, "X-Request-ID", rec.Header().Get("Access-Control-Expose-Headers"))
+				assert.Contains(t, rec.Header().Values("Vary"), "Origin")
+			})
+
+			t.Run("Forward preflights of a route with cors disabled", func(t *testing.T) {
+				rec := testhelper.PerformRequest(gatewayEngine, "OPTIONS", "/legacy/cart", nil, preflight("https://shop.example.com", "POST"))
+
+				assert.Equal(t, http.StatusOK, rec.Code)
+				assert.Equal(t, "*", rec.Header().Get("Access-Control-Allow-Origin"))
+				assert.Equal(t, 2, upstreamHits)
+			})
+
+			_ = srvTarget.Close()
+		})
+
+		t.Run("Drain in-flight requests", func(t *testing.T) {
+			gatewayEngine := gin.New()
+
+			var routeObjects []entity.RouteObject
+			routeObjects = append(
+				routeObjects,
+				entity.RouteObject{
+					Auth:   "none",
+					Host:   "localhost:5029",
+					Name:   "reports",
+					Prefix: "/reports",
+					Path: map[string]entity.RouterPath{
+						"/slow": {},
+					},
+				},
+			)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+
+			srvTarget := &http.Server{
+				Addr: ":5029",
+				Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					close(started)
+					<-release
+					w.WriteHeader(http.StatusOK)
+				}),
+			}
+
+			go func() {
+				_ = srvTarget.ListenAndServe()
+			}()
+
+			generator := usecase.NewGenerator("", nil, []module.DownstreamController{}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+			defer generator.Close()
+
+			err := generator.Generate(gatewayEngine, routeObjects)
+			assert.Nil(t, err)
+
+			gateway := httptest.NewServer(gatewayEngine)
+			defer gateway.Close()
+
+			// Given sleep time so the server can boot first
+			time.Sleep(time.Millisecond * 100)
+
+			t.Run("Return immediately when nothing is in flight", func(t *testing.T) {
+				assert.Nil(t, generator.Drain(context.Background()))
+			})
+
+			done := make(chan int)
+			go func() {
+				res, err := http.Get(gateway.URL + "/reports/slow")
+				assert.Nil(t, err)
+				defer res.Body.Close()
+				done <- res.StatusCode
+			}()
+
+			<-started
+
+			t.Run("Return error when the drain period elapses", func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+				defer cancel()
+
+				assert.NotNil(t, generator.Drain(ctx))
+			})
+
+			t.Run("Wait for the request to complete", func(t *testing.T) {
+				drained := make(chan error)
+				go func() {
+					drained <- generator.Drain(context.Background())
+				}()
+
+				select {
+				case <-drained:
+					t.Fatal("drain returned while a request is in flight")
+				case <-time.After(time.Millisecond * 100):
+				}
+
+				close(release)
+
+				assert.Equal(t, http.StatusOK, <-done)
+				assert.Nil(t, <-drained)
+			})
+
+			_ = srvTarget.Close()
+		})
+
+		t.Run("Call target services routes over https", func(t *testing.T) {
+			certDir := t.TempDir()
+			clientCertFile, clientKeyFile, clientCert := writeTestCertificate(t, certDir, "client")
+
+			clientCAs := x509.NewCertPool()
+			clientCAs.AddCert(clientCert)
+
+			srvTarget := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "client", r.TLS.PeerCertificates[0].Subject.CommonName)
+				w.WriteHeader(http.StatusOK)
+			}))
+			srvTarget.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+			srvTarget.StartTLS()
+			defer srvTarget.Close()
+
+			caFile := filepath.Join(certDir, "ca.pem")
+			assert.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srvTarget.Certificate().Raw}), 0600))
+
+			generate := func(routeTLS entity.RouteTLS) *gin.Engine {
+				gatewayEngine := gin.New()
+
+				generator := usecase.NewGenerator("", nil, nil, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+				t.Cleanup(generator.Close)
+
+				err := generator.Generate(gatewayEngine, []entity.RouteObject{
+					{
+						Auth:   "none",
+						Host:   srvTarget.Listener.Addr().String(),
+						Scheme: "https",
+						TLS:    routeTLS,
+						Name:   "users",
+						Prefix: "/users",
+						Path: map[string]entity.RouterPath{
+							"/me": {Auth: "none"},
+						},
+					},
+				})
+				assert.Nil(t, err)
+
+				return gatewayEngine
+			}
+
+			t.Run("Forward with mutual tls", func(t *testing.T) {
+				gatewayEngine := generate(entity.RouteTLS{CAFile: caFile, CertFile: clientCertFile, KeyFile: clientKeyFile, ServerName: "example.com"})
+
+				rec := testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+				assert.Equal(t, http.StatusOK, rec.Result().StatusCode)
+			})
+
+			t.Run("Return 502 without client certificate", func(t *testing.T) {
+				gatewayEngine := generate(entity.RouteTLS{CAFile: caFile})
+
+				rec := testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+				assert.Equal(t, http.StatusBadGateway, rec.Result().StatusCode)
+			})
+
+			t.Run("Return 502 when upstream certificate is not trusted", func(t *testing.T) {
+				gatewayEngine := generate(entity.RouteTLS{CertFile: clientCertFile, KeyFile: clientKeyFile})
+
+				rec := testhelper.PerformRequest(gatewayEngine, "GET", "/users/me", nil)
+				assert.Equal(t, http.StatusBadGateway, rec.Result().StatusCode)
+			})
+
+			t.Run("Return error when certificate files are gone", func(t *testing.T) {
+				err := usecase.NewGenerator("", nil, nil, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gin.New(), []entity.RouteObject{
+					{
+						Host:   srvTarget.Listener.Addr().String(),
+						Scheme: "https",
+						TLS:    entity.RouteTLS{CAFile: filepath.Join(certDir, "missing.pem")},
+						Name:   "users",
+						Prefix: "/users",
+					},
+				})
+				assert.NotNil(t, err)
+			})
+		})
+
 		t.Run("Call target services routes with downstream plugins", func(t *testing.T) {
 			t.Run("Run gracefully", func(t *testing.T) {
 				targetEngine := gin.Default()
@@ -209,7 +1561,7 @@ func TestGenerator(t *testing.T) {
 				var downStreamController []module.DownstreamController
 				downStreamController = append(downStreamController, oauthPlugin)
 
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 
 				srvTarget := &http.Server{
@@ -264,7 +1616,7 @@ func TestGenerator(t *testing.T) {
 				var downStreamController []module.DownstreamController
 				downStreamController = append(downStreamController, oauthPlugin)
 
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 
 				srvTarget := &http.Server{
@@ -317,7 +1669,7 @@ func TestGenerator(t *testing.T) {
 				var downStreamController []module.DownstreamController
 				downStreamController = append(downStreamController, oauthPlugin)
 
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 
 				srvTarget := &http.Server{
@@ -371,7 +1723,7 @@ func TestGenerator(t *testing.T) {
 				var downStreamController []module.DownstreamController
 				downStreamController = append(downStreamController, oauthPlugin)
 
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 
 				srvTarget := &http.Server{
@@ -421,7 +1773,7 @@ func TestGenerator(t *testing.T) {
 				}
 
 				var downStreamController []module.DownstreamController
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 
 				srvTarget := &http.Server{
@@ -472,7 +1824,7 @@ func TestGenerator(t *testing.T) {
 
 				var downStreamController []module.DownstreamController
 
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 
 				srvTarget := &http.Server{
@@ -517,7 +1869,7 @@ func TestGenerator(t *testing.T) {
 
 				var downStreamController []module.DownstreamController
 
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 			})
 		})
@@ -549,7 +1901,7 @@ func TestGenerator(t *testing.T) {
 
 				var downStreamController []module.DownstreamController
 
-				err := usecase.NewGenerator(downStreamController, []module.MetricController{testhelper.NewDummyMetric()}).Generate(gatewayEngine, routeObjects)
+				err := usecase.NewGenerator("", nil, downStreamController, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide()).Generate(gatewayEngine, routeObjects)
 				assert.Nil(t, err)
 
 				srvTarget := &http.Server{
@@ -609,3 +1961,34 @@ func buildTargetEngine(targetEngine *gin.Engine, method string, routeObject enti
 		})
 	}
 }
+
+func writeTestCertificate(t *testing.T, dir, commonName string) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	certFile := filepath.Join(dir, commonName+"-cert.pem")
+	keyFile := filepath.Join(dir, commonName+"-key.pem")
+
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certFile, keyFile, cert
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+// hopByHopHeaders only apply to a single connection and must not be forwarded,
+// see RFC 7230 section 6.1.
+var hopByHopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// headerTemplateFields are the values available to header rule templates.
+var headerTemplateFields = []string{"ClientIP", "RequestID", "Host", "Method", "Path", "RouteName"}
+
+type headerTemplateData struct {
+	ClientIP  string
+	RequestID string
+	Host      string
+	Method    string
+	Path      string
+	RouteName string
+}
+
+// headerTemplateField matches a header rule field such as {{ .ClientIP }} in a
+// route file.
+var headerTemplateField = regexp.MustCompile(`{{\s*\.(` + strings.Join(headerTemplateFields, "|") + `)\s*}}`)
+
+// escapeHeaderTemplates turns the header rule fields of a route file into
+// literals of the route file template, so they survive the compile pass and are
+// rendered per request. Any other field is left to the route file template.
+func escapeHeaderTemplates(b []byte) []byte {
+	return headerTemplateField.ReplaceAll(b, []byte(`{{"{{"}} .$1 }}`))
+}
+
+// removeHopByHopHeaders deletes the hop-by-hop headers and every header the
+// Connection header nominates.
+func removeHopByHopHeaders(header http.Header) {
+	for _, value := range header.Values("Connection") {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				header.Del(name)
+			}
+		}
+	}
+
+	for _, name := range hopByHopHeaders {
+		header.Del(name)
+	}
+}
+
+// forwardedFor appends the address of the peer to the X-Forwarded-For chain of
+// the request, so the upstream can tell the client from the proxies in front
+// of altair.
+func forwardedFor(r *http.Request) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+
+	if prior := r.Header.Values("X-Forwarded-For"); len(prior) > 0 {
+		return strings.Join(prior, ", ") + ", " + peer
+	}
+
+	return peer
+}
+
+type headerValue struct {
+	name  string
+	value *template.Template
+}
+
+type headerRename struct {
+	from string
+	to   string
+}
+
+type headerRules struct {
+	remove []string
+	rename []headerRename
+	set    []headerValue
+	append []headerValue
+}
+
+func newHeaderRules(rules entity.RouteHeaderRules) (headerRules, error) {
+	var compiled headerRules
+	var err error
+
+	for _, name := range rules.Remove {
+		compiled.remove = append(compiled.remove, http.CanonicalHeaderKey(name))
+	}
+
+	for from, to := range rules.Rename {
+		if from == "" || to == "" {
+			return headerRules{}, fmt.Errorf("header rename cannot use an empty header name")
+		}
+		compiled.rename = append(compiled.rename, headerRename{from: http.CanonicalHeaderKey(from), to: http.CanonicalHeaderKey(to)})
+	}
+	sort.Slice(compiled.rename, func(i, j int) bool { return compiled.rename[i].from < compiled.rename[j].from })
+
+	if compiled.set, err = newHeaderValues(rules.Set); err != nil {
+		return headerRules{}, err
+	}
+
+	if compiled.append, err = newHeaderValues(rules.Append); err != nil {
+		return headerRules{}, err
+	}
+
+	return compiled, nil
+}
+
+func newHeaderValues(values map[string]string) ([]headerValue, error) {
+	var compiled []headerValue
+
+	for name, value := range values {
+		if name == "" {
+			return nil, fmt.Errorf("header name cannot be empty")
+		}
+
+		tpl, err := template.New(name).Option("missingkey=error").Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("header `%s` value is invalid: %v", name, err)
+		}
+
+		// Render once so references to unknown fields are reported up front.
+		if err := tpl.Execute(&bytes.Buffer{}, headerTemplateData{}); err != nil {
+			return nil, fmt.Errorf("header `%s` value is invalid: %v", name, err)
+		}
+
+		compiled = append(compiled, headerValue{name: http.CanonicalHeaderKey(name), value: tpl})
+	}
+
+	sort.Slice(compiled, func(i, j int) bool { return compiled[i].name < compiled[j].name })
+
+	return compiled, nil
+}
+
+func (r headerRules) apply(header http.Header, data headerTemplateData) {
+	for _, name := range r.remove {
+		header.Del(name)
+	}
+
+	for _, rename := range r.rename {
+		values := header.Values(rename.from)
+		if len(values) == 0 {
+			continue
+		}
+
+		header.Del(rename.from)
+		header[rename.to] = append([]string{}, values...)
+	}
+
+	for _, value := range r.set {
+		header.Set(value.name, value.render(data))
+	}
+
+	for _, value := range r.append {
+		header.Add(value.name, value.render(data))
+	}
+}
+
+func (v headerValue) render(data headerTemplateData) string {
+	buf := &bytes.Buffer{}
+	if err := v.value.Execute(buf, data); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
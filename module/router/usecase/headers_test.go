@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+func TestRemoveHopByHopHeaders(t *testing.T) {
+	header := http.Header{
+		"Connection":        {"keep-alive, X-Internal"},
+		"Keep-Alive":        {"timeout=5"},
+		"X-Internal":        {"secret"},
+		"Transfer-Encoding": {"chunked"},
+		"Upgrade":           {"websocket"},
+		"Proxy-Connection":  {"keep-alive"},
+		"Content-Type":      {"application/json"},
+	}
+
+	removeHopByHopHeaders(header)
+
+	assert.Equal(t, http.Header{"Content-Type": {"application/json"}}, header)
+}
+
+func TestForwardedFor(t *testing.T) {
+	t.Run("Return the peer address without port", func(t *testing.T) {
+		r := &http.Request{RemoteAddr: "10.0.0.1:1234", Header: http.Header{}}
+		assert.Equal(t, "10.0.0.1", forwardedFor(r))
+	})
+
+	t.Run("Append the peer address to the chain", func(t *testing.T) {
+		r := &http.Request{RemoteAddr: "10.0.0.1:1234", Header: http.Header{"X-Forwarded-For": {"203.0.113.7, 198.51.100.2"}}}
+		assert.Equal(t, "203.0.113.7, 198.51.100.2, 10.0.0.1", forwardedFor(r))
+	})
+}
+
+func TestHeaderRules(t *testing.T) {
+	data := headerTemplateData{ClientIP: "10.0.0.1", RequestID: "request-1", RouteName: "users"}
+
+	t.Run("Apply remove, rename, set and append in order", func(t *testing.T) {
+		rules, err := newHeaderRules(entity.RouteHeaderRules{
+			Remove: []string{"x-debug"},
+			Rename: map[string]string{"X-Tenant": "X-Upstream-Tenant"},
+			Set:    map[string]string{"X-Client": "{{ .ClientIP }}", "X-Upstream-Tenant": "{{ .RouteName }}"},
+			Append: map[string]string{"Via": "altair {{ .RequestID }}"},
+		})
+		assert.Nil(t, err)
+
+		header := http.Header{
+			"X-Debug":  {"1"},
+			"X-Tenant": {"a"},
+			"Via":      {"1.1 edge"},
+		}
+
+		rules.apply(header, data)
+
+		assert.Equal(t, http.Header{
+			"X-Client":          {"10.0.0.1"},
+			"X-Upstream-Tenant": {"users"},
+			"Via":               {"1.1 edge", "altair request-1"},
+		}, header)
+	})
+
+	t.Run("Keep the header when the rename source is missing", func(t *testing.T) {
+		rules, err := newHeaderRules(entity.RouteHeaderRules{Rename: map[string]string{"X-Tenant": "X-Upstream-Tenant"}})
+		assert.Nil(t, err)
+
+		header := http.Header{"X-Upstream-Tenant": {"b"}}
+		rules.apply(header, data)
+
+		assert.Equal(t, http.Header{"X-Upstream-Tenant": {"b"}}, header)
+	})
+
+	t.Run("Return error on invalid templates", func(t *testing.T) {
+		for _, rules := range []entity.RouteHeaderRules{
+			{Set: map[string]string{"X-Client": "{{ .ClientIP"}},
+			{Append: map[string]string{"X-Client": "{{ .UnknownField }}"}},
+			{Set: map[string]string{"": "value"}},
+			{Rename: map[string]string{"X-Tenant": ""}},
+		} {
+			_, err := newHeaderRules(rules)
+			assert.NotNil(t, err, rules)
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+const (
+	defaultHealthCheckInterval     = time.Second * 10
+	defaultHealthCheckTimeout      = time.Second * 2
+	defaultHealthyThreshold        = 2
+	defaultUnhealthyThreshold      = 3
+	defaultPassiveEjectionDuration = time.Second * 30
+
+	healthCheckReasonActive  = "active"
+	healthCheckReasonPassive = "passive"
+)
+
+// healthPolicy is the parsed form of entity.RouteHealthCheck. Durations are
+// validated by the compiler, so a parse failure here falls back to defaults.
+type healthPolicy struct {
+	activePath         string
+	interval           time.Duration
+	timeout            time.Duration
+	healthyThreshold   int32
+	unhealthyThreshold int32
+
+	passiveThreshold int32
+	ejectionDuration time.Duration
+}
+
+func newHealthPolicy(healthCheck entity.RouteHealthCheck) healthPolicy {
+	policy := healthPolicy{
+		activePath:         healthCheck.Active.Path,
+		interval:           parseDurationOr(healthCheck.Active.Interval, defaultHealthCheckInterval),
+		timeout:            parseDurationOr(healthCheck.Active.Timeout, defaultHealthCheckTimeout),
+		healthyThreshold:   int32(healthCheck.Active.HealthyThreshold),
+		unhealthyThreshold: int32(healthCheck.Active.UnhealthyThreshold),
+		passiveThreshold:   int32(healthCheck.Passive.UnhealthyThreshold),
+		ejectionDuration:   parseDurationOr(healthCheck.Passive.EjectionDuration, defaultPassiveEjectionDuration),
+	}
+
+	if policy.healthyThreshold <= 0 {
+		policy.healthyThreshold = defaultHealthyThreshold
+	}
+
+	if policy.unhealthyThreshold <= 0 {
+		policy.unhealthyThreshold = defaultUnhealthyThreshold
+	}
+
+	return policy
+}
+
+func parseDurationOr(raw string, fallback time.Duration) time.Duration {
+	if raw == "" {
+		return fallback
+	}
+
+	duration, err := time.ParseDuration(raw)
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+
+	return duration
+}
+
+// available reports whether the target may receive traffic. A target is
+// available only while neither check ejects it: a passive ejection lasts for
+// its ejection duration, an active ejection lasts until enough probes succeed.
+func (p *upstreamPool) available(target *upstreamTarget) bool {
+	if until := atomic.LoadInt64(&target.ejectedUntil); until > 0 {
+		if time.Now().UnixNano() < until {
+			return false
+		}
+
+		if atomic.CompareAndSwapInt64(&target.ejectedUntil, until, 0) {
+			p.refreshHealth(target, healthCheckReasonPassive)
+		}
+	}
+
+	return atomic.LoadInt32(&target.activeHealthy) == 1
+}
+
+// observe records the outcome of a proxied request for passive health checking.
+func (p *upstreamPool) observe(target *upstreamTarget, success bool) {
+	if p.health.passiveThreshold <= 0 || target == p.fallback {
+		return
+	}
+
+	if success {
+		atomic.StoreInt32(&target.passiveFailures, 0)
+		return
+	}
+
+	if atomic.AddInt32(&target.passiveFailures, 1) < p.health.passiveThreshold {
+		return
+	}
+
+	atomic.StoreInt32(&target.passiveFailures, 0)
+	if atomic.CompareAndSwapInt64(&target.ejectedUntil, 0, time.Now().Add(p.health.ejectionDuration).UnixNano()) {
+		p.eject(target, healthCheckReasonPassive)
+	}
+}
+
+// probed records the outcome of an active health check probe.
+func (p *upstreamPool) probed(target *upstreamTarget, success bool) {
+	if success {
+		atomic.StoreInt32(&target.probeFailures, 0)
+		if atomic.LoadInt32(&target.activeHealthy) == 1 {
+			return
+		}
+
+		if atomic.AddInt32(&target.probeSuccesses, 1) >= p.health.healthyThreshold && atomic.CompareAndSwapInt32(&target.activeHealthy, 0, 1) {
+			atomic.StoreInt32(&target.probeSuccesses, 0)
+			p.refreshHealth(target, healthCheckReasonActive)
+		}
+		return
+	}
+
+	atomic.StoreInt32(&target.probeSuccesses, 0)
+	if atomic.LoadInt32(&target.activeHealthy) == 0 {
+		return
+	}
+
+	if atomic.AddInt32(&target.probeFailures, 1) >= p.health.unhealthyThreshold && atomic.CompareAndSwapInt32(&target.activeHealthy, 1, 0) {
+		atomic.StoreInt32(&target.probeFailures, 0)
+		p.eject(target, healthCheckReasonActive)
+	}
+}
+
+func (p *upstreamPool) eject(target *upstreamTarget, reason string) {
+	log.Warn().Str("name", p.routeName).Str("upstream", target.host).Str("reason", reason).Array("tags", zerolog.Arr().Str("route").Str("upstream").Str("health_check")).Msg("Upstream target is ejected")
+
+	for _, m := range p.metrics {
+		_ = m.Inc("routes_upstream_ejections", map[string]string{"route_name": p.routeName, "upstream": target.host, "reason": reason})
+	}
+
+	p.refreshHealth(target, reason)
+}
+
+// refreshHealth recomputes the combined verdict of both checks and reports it
+// when it changed.
+func (p *upstreamPool) refreshHealth(target *upstreamTarget, reason string) {
+	var healthy int32
+	if atomic.LoadInt32(&target.activeHealthy) == 1 && atomic.LoadInt64(&target.ejectedUntil) == 0 {
+		healthy = 1
+	}
+
+	if atomic.SwapInt32(&target.healthy, healthy) == healthy {
+		return
+	}
+
+	if healthy == 1 {
+		log.Info().Str("name", p.routeName).Str("upstream", target.host).Str("reason", reason).Array("tags", zerolog.Arr().Str("route").Str("upstream").Str("health_check")).Msg("Upstream target is healthy again")
+	}
+
+	p.reportHealth(target)
+}
+
+func (p *upstreamPool) reportHealth(target *upstreamTarget) {
+	for _, m := range p.metrics {
+		_ = m.Set("routes_upstream_healthy", float64(atomic.LoadInt32(&target.healthy)), map[string]string{"route_name": p.routeName, "upstream": target.host})
+	}
+}
+
+func (p *upstreamPool) startHealthCheck() {
+	if p.health.activePath == "" {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(p.health.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.probeAll()
+			}
+		}
+	}()
+}
+
+func (p *upstreamPool) probeAll() {
+	wg := &sync.WaitGroup{}
+
+	for _, target := range p.targets {
+		wg.Add(1)
+		go func(target *upstreamTarget) {
+			defer wg.Done()
+			p.probed(target, p.probe(target))
+		}(target)
+	}
+
+	wg.Wait()
+}
+
+func (p *upstreamPool) probe(target *upstreamTarget) bool {
+	res, err := p.probeClient.Get(p.scheme + "://" + target.host + p.health.activePath)
+	if err != nil {
+		return false
+	}
+	defer res.Body.Close()
+
+	return res.StatusCode < http.StatusBadRequest
+}
+
+func (p *upstreamPool) close() {
+	p.stopOnce.Do(func() { close(p.stop) })
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+func TestUpstreamHealthCheck(t *testing.T) {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request, _ = http.NewRequest("GET", "/users/me", nil)
+
+	t.Run("Passive health check", func(t *testing.T) {
+		t.Run("Eject target after consecutive failures", func(t *testing.T) {
+			pool := newUpstreamPool(entity.RouteObject{
+				Upstream: entity.RouteUpstream{
+					Targets: []entity.RouteTarget{{Host: "a"}, {Host: "b"}},
+					HealthCheck: entity.RouteHealthCheck{
+						Passive: entity.RoutePassiveHealthCheck{UnhealthyThreshold: 2},
+					},
+				},
+			}, nil)
+
+			a := pool.targets[0]
+
+			pool.observe(a, false)
+			pool.observe(a, true)
+			pool.observe(a, false)
+			assert.True(t, pool.available(a))
+
+			pool.observe(a, false)
+			assert.False(t, pool.available(a))
+
+			for i := 0; i < 4; i++ {
+				assert.Equal(t, "b", pool.pick(c).host)
+			}
+		})
+
+		t.Run("Restore target after ejection duration", func(t *testing.T) {
+			pool := newUpstreamPool(entity.RouteObject{
+				Upstream: entity.RouteUpstream{
+					Targets: []entity.RouteTarget{{Host: "a"}},
+					HealthCheck: entity.RouteHealthCheck{
+						Passive: entity.RoutePassiveHealthCheck{UnhealthyThreshold: 1, EjectionDuration: "10ms"},
+					},
+				},
+			}, nil)
+
+			a := pool.targets[0]
+
+			pool.observe(a, false)
+			assert.Nil(t, pool.pick(c))
+
+			time.Sleep(time.Millisecond * 20)
+			assert.Equal(t, a, pool.pick(c))
+		})
+
+		t.Run("Use fallback host when every target is ejected", func(t *testing.T) {
+			pool := newUpstreamPool(entity.RouteObject{
+				Upstream: entity.RouteUpstream{
+					Targets:      []entity.RouteTarget{{Host: "a"}},
+					FallbackHost: "fallback",
+					HealthCheck: entity.RouteHealthCheck{
+						Passive: entity.RoutePassiveHealthCheck{UnhealthyThreshold: 1},
+					},
+				},
+			}, nil)
+
+			pool.observe(pool.targets[0], false)
+			assert.Equal(t, "fallback", pool.pick(c).host)
+
+			pool.observe(pool.fallback, false)
+			assert.Equal(t, "fallback", pool.pick(c).host)
+		})
+
+		t.Run("Disabled when threshold is not set", func(t *testing.T) {
+			pool := newUpstreamPool(entity.RouteObject{Host: "a"}, nil)
+
+			for i := 0; i < 10; i++ {
+				pool.observe(pool.targets[0], false)
+			}
+
+			assert.Equal(t, "a", pool.pick(c).host)
+		})
+	})
+
+	t.Run("Active health check", func(t *testing.T) {
+		healthy := &atomic.Bool{}
+		healthy.Store(true)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/health", r.URL.Path)
+			if healthy.Load() {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer srv.Close()
+
+		srvURL, _ := url.Parse(srv.URL)
+
+		pool := newUpstreamPool(entity.RouteObject{
+			Upstream: entity.RouteUpstream{
+				Targets: []entity.RouteTarget{{Host: srvURL.Host}},
+				HealthCheck: entity.RouteHealthCheck{
+					Active: entity.RouteActiveHealthCheck{Path: "/health", HealthyThreshold: 2, UnhealthyThreshold: 2},
+				},
+			},
+		}, nil)
+
+		target := pool.targets[0]
+
+		healthy.Store(false)
+		pool.probeAll()
+		assert.True(t, pool.available(target))
+		pool.probeAll()
+		assert.False(t, pool.available(target))
+
+		healthy.Store(true)
+		pool.probeAll()
+		assert.False(t, pool.available(target))
+		pool.probeAll()
+		assert.True(t, pool.available(target))
+	})
+
+	t.Run("Passive and active health check", func(t *testing.T) {
+		newPool := func() *upstreamPool {
+			return newUpstreamPool(entity.RouteObject{
+				Upstream: entity.RouteUpstream{
+					Targets: []entity.RouteTarget{{Host: "a"}},
+					HealthCheck: entity.RouteHealthCheck{
+						Active:  entity.RouteActiveHealthCheck{Path: "/health", HealthyThreshold: 1, UnhealthyThreshold: 1},
+						Passive: entity.RoutePassiveHealthCheck{UnhealthyThreshold: 2, EjectionDuration: "10ms"},
+					},
+				},
+			}, nil)
+		}
+
+		t.Run("Passive ejection expires without a successful probe", func(t *testing.T) {
+			pool := newPool()
+			a := pool.targets[0]
+
+			pool.observe(a, false)
+			pool.observe(a, false)
+			assert.False(t, pool.available(a))
+
+			time.Sleep(time.Millisecond * 20)
+			assert.True(t, pool.available(a))
+			assert.Equal(t, int32(1), a.healthy)
+		})
+
+		t.Run("Successful probe does not reset passive failures", func(t *testing.T) {
+			pool := newPool()
+			a := pool.targets[0]
+
+			pool.observe(a, false)
+			pool.probed(a, true)
+			pool.observe(a, false)
+			assert.False(t, pool.available(a))
+		})
+
+		t.Run("Successful probe does not end passive ejection", func(t *testing.T) {
+			pool := newPool()
+			a := pool.targets[0]
+
+			pool.observe(a, false)
+			pool.observe(a, false)
+			pool.probed(a, true)
+			assert.False(t, pool.available(a))
+		})
+
+		t.Run("Active ejection outlasts passive ejection", func(t *testing.T) {
+			pool := newPool()
+			a := pool.targets[0]
+
+			pool.observe(a, false)
+			pool.observe(a, false)
+			pool.probed(a, false)
+
+			time.Sleep(time.Millisecond * 20)
+			assert.False(t, pool.available(a))
+			assert.Equal(t, int32(0), a.healthy)
+
+			pool.probed(a, true)
+			assert.True(t, pool.available(a))
+			assert.Equal(t, int32(1), a.healthy)
+		})
+	})
+
+	t.Run("Close is idempotent", func(t *testing.T) {
+		pool := newUpstreamPool(entity.RouteObject{
+			Host: "a",
+			Upstream: entity.RouteUpstream{
+				HealthCheck: entity.RouteHealthCheck{
+					Active: entity.RouteActiveHealthCheck{Path: "/health", Interval: "10ms"},
+				},
+			},
+		}, nil)
+
+		pool.startHealthCheck()
+
+		assert.NotPanics(t, func() {
+			pool.close()
+			pool.close()
+		})
+	})
+}
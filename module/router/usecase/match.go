@@ -0,0 +1,293 @@
+package usecase
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+const (
+	hostRankAny = iota
+	hostRankWildcard
+	hostRankExact
+
+	matchAnyValue = "*"
+)
+
+// routeMatcher decides whether a request belongs to a route besides its path.
+// An empty matcher accepts every request.
+type routeMatcher struct {
+	exactHosts    []string
+	wildcardHosts []string
+	headers       map[string]string
+	query         map[string]string
+	networks      []*net.IPNet
+}
+
+func newRouteMatcher(match entity.RouteMatch) (routeMatcher, error) {
+	matcher := routeMatcher{headers: map[string]string{}, query: map[string]string{}}
+
+	for _, host := range match.Hosts {
+		host = strings.ToLower(strings.TrimSpace(host))
+
+		switch {
+		case host == "":
+			return routeMatcher{}, fmt.Errorf("match hosts cannot contain an empty host")
+		case strings.HasPrefix(host, "*."):
+			if strings.Contains(host[2:], "*") || host == "*." {
+				return routeMatcher{}, fmt.Errorf("match host `%s` is invalid, only a leading `*.` wildcard is supported", host)
+			}
+			matcher.wildcardHosts = append(matcher.wildcardHosts, host[1:])
+		case strings.Contains(host, "*"):
+			return routeMatcher{}, fmt.Errorf("match host `%s` is invalid, only a leading `*.` wildcard is supported", host)
+		default:
+			matcher.exactHosts = append(matcher.exactHosts, host)
+		}
+	}
+
+	for name, value := range match.Headers {
+		if name == "" {
+			return routeMatcher{}, fmt.Errorf("match header name cannot be empty")
+		}
+		matcher.headers[http.CanonicalHeaderKey(name)] = value
+	}
+
+	for name, value := range match.Query {
+		if name == "" {
+			return routeMatcher{}, fmt.Errorf("match query name cannot be empty")
+		}
+		matcher.query[name] = value
+	}
+
+	for _, cidr := range match.ClientCIDRs {
+		network, err := entity.ParseNetwork(cidr)
+		if err != nil {
+			return routeMatcher{}, fmt.Errorf("match client_cidrs %v", err)
+		}
+		matcher.networks = append(matcher.networks, network)
+	}
+
+	return matcher, nil
+}
+
+// matches reports whether the request belongs to the route. The client IP is
+// resolved with the trusted proxies of the engine, see TrustProxies.
+func (m routeMatcher) matches(c *gin.Context) bool {
+	if len(m.exactHosts) > 0 || len(m.wildcardHosts) > 0 {
+		if !m.matchesHost(requestHost(c.Request)) {
+			return false
+		}
+	}
+
+	for name, expected := range m.headers {
+		values, ok := c.Request.Header[name]
+		if !ok || !matchesValue(values, expected) {
+			return false
+		}
+	}
+
+	if len(m.query) > 0 {
+		query := c.Request.URL.Query()
+		for name, expected := range m.query {
+			values, ok := query[name]
+			if !ok || !matchesValue(values, expected) {
+				return false
+			}
+		}
+	}
+
+	if len(m.networks) > 0 {
+		ip := net.ParseIP(c.ClientIP())
+		if ip == nil {
+			return false
+		}
+
+		for _, network := range m.networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return true
+}
+
+func (m routeMatcher) matchesHost(host string) bool {
+	for _, exact := range m.exactHosts {
+		if host == exact {
+			return true
+		}
+	}
+
+	for _, suffix := range m.wildcardHosts {
+		if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchesValue(values []string, expected string) bool {
+	if expected == matchAnyValue {
+		return true
+	}
+
+	for _, value := range values {
+		if value == expected {
+			return true
+		}
+	}
+
+	return false
+}
+
+func requestHost(req *http.Request) string {
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	return strings.ToLower(host)
+}
+
+// precedence ranks matchers from the most to the least specific: exact hosts
+// first, then wildcard hosts with the longest suffix, then the number of
+// header and query matchers, then client CIDRs with the narrowest network.
+func (m routeMatcher) precedence() []int {
+	hostRank, hostLength := hostRankAny, 0
+
+	switch {
+	case len(m.exactHosts) > 0:
+		hostRank = hostRankExact
+	case len(m.wildcardHosts) > 0:
+		hostRank = hostRankWildcard
+		for _, suffix := range m.wildcardHosts {
+			if len(suffix) > hostLength {
+				hostLength = len(suffix)
+			}
+		}
+	}
+
+	networkBits := -1
+	for _, network := range m.networks {
+		if ones, _ := network.Mask.Size(); ones > networkBits {
+			networkBits = ones
+		}
+	}
+
+	return []int{hostRank, hostLength, len(m.headers), len(m.query), networkBits}
+}
+
+// key identifies matchers accepting the same requests, two routes serving the
+// same path with the same key would shadow each other.
+func (m routeMatcher) key() string {
+	hosts := append(append([]string{}, m.exactHosts...), m.wildcardHosts...)
+	sort.Strings(hosts)
+
+	var headers, query, networks []string
+	for name, value := range m.headers {
+		headers = append(headers, name+"="+value)
+	}
+	for name, value := range m.query {
+		query = append(query, name+"="+value)
+	}
+	for _, network := range m.networks {
+		networks = append(networks, network.String())
+	}
+
+	sort.Strings(headers)
+	sort.Strings(query)
+	sort.Strings(networks)
+
+	return fmt.Sprintf("hosts=%v headers=%v query=%v client_cidrs=%v", hosts, headers, query, networks)
+}
+
+type routeCandidate struct {
+	routeName string
+	matcher   routeMatcher
+	handler   gin.HandlerFunc
+}
+
+// routeDispatcher collects the handlers of every route registering the same
+// path and method, gin only accepts one handler per path.
+type routeDispatcher struct {
+	paths      []string
+	candidates map[string]map[string][]routeCandidate
+}
+
+func newRouteDispatcher() *routeDispatcher {
+	return &routeDispatcher{candidates: map[string]map[string][]routeCandidate{}}
+}
+
+func (d *routeDispatcher) add(urlPath, method string, candidate routeCandidate) error {
+	methods, ok := d.candidates[urlPath]
+	if !ok {
+		methods = map[string][]routeCandidate{}
+		d.candidates[urlPath] = methods
+		d.paths = append(d.paths, urlPath)
+	}
+
+	for _, existing := range methods[method] {
+		if existing.matcher.key() == candidate.matcher.key() {
+			return fmt.Errorf("route `%s` and route `%s` both serve %s %s with the same match", existing.routeName, candidate.routeName, method, urlPath)
+		}
+	}
+
+	methods[method] = append(methods[method], candidate)
+	return nil
+}
+
+// register hands every path to gin. A path served by a single route without
+// matcher keeps its handler as is, otherwise the first matching route in
+// precedence order handles the request.
+func (d *routeDispatcher) register(engine *gin.Engine) {
+	for _, urlPath := range d.paths {
+		for method, candidates := range d.candidates[urlPath] {
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return lessPrecedence(candidates[j].matcher.precedence(), candidates[i].matcher.precedence())
+			})
+
+			if len(candidates) == 1 && candidates[0].matcher.key() == (routeMatcher{}).key() {
+				engine.Handle(method, urlPath, candidates[0].handler)
+				continue
+			}
+
+			engine.Handle(method, urlPath, dispatch(candidates))
+		}
+	}
+}
+
+func dispatch(candidates []routeCandidate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, candidate := range candidates {
+			if candidate.matcher.matches(c) {
+				candidate.handler(c)
+				return
+			}
+		}
+
+		c.JSON(http.StatusNotFound, gin.H{
+			"status":  http.StatusNotFound,
+			"message": "No route matches the request",
+		})
+	}
+}
+
+func lessPrecedence(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return false
+}
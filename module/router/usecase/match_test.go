@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+func TestRouteMatcher(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	newContext := func(host, target, remoteAddr string, header http.Header) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", target, nil)
+		c.Request.Host = host
+		c.Request.RemoteAddr = remoteAddr
+		for name, values := range header {
+			c.Request.Header[name] = values
+		}
+		return c
+	}
+
+	t.Run("Match hosts", func(t *testing.T) {
+		matcher, err := newRouteMatcher(entity.RouteMatch{Hosts: []string{"api.tenant-a.com", "*.tenant-b.com"}})
+		assert.Nil(t, err)
+
+		assert.True(t, matcher.matches(newContext("API.tenant-a.com:8080", "/users", "10.0.0.1:1", nil)))
+		assert.True(t, matcher.matches(newContext("eu.api.tenant-b.com", "/users", "10.0.0.1:1", nil)))
+		assert.False(t, matcher.matches(newContext("tenant-b.com", "/users", "10.0.0.1:1", nil)))
+		assert.False(t, matcher.matches(newContext("api.tenant-c.com", "/users", "10.0.0.1:1", nil)))
+	})
+
+	t.Run("Match headers and query", func(t *testing.T) {
+		matcher, err := newRouteMatcher(entity.RouteMatch{
+			Headers: map[string]string{"x-tenant": "a", "X-Debug": "*"},
+			Query:   map[string]string{"version": "2"},
+		})
+		assert.Nil(t, err)
+
+		assert.True(t, matcher.matches(newContext("altair", "/users?version=2", "10.0.0.1:1", http.Header{"X-Tenant": {"a"}, "X-Debug": {"1"}})))
+		assert.False(t, matcher.matches(newContext("altair", "/users?version=1", "10.0.0.1:1", http.Header{"X-Tenant": {"a"}, "X-Debug": {"1"}})))
+		assert.False(t, matcher.matches(newContext("altair", "/users?version=2", "10.0.0.1:1", http.Header{"X-Tenant": {"a"}})))
+		assert.False(t, matcher.matches(newContext("altair", "/users?version=2", "10.0.0.1:1", http.Header{"X-Tenant": {"b"}, "X-Debug": {"1"}})))
+	})
+
+	t.Run("Match client cidrs", func(t *testing.T) {
+		matcher, err := newRouteMatcher(entity.RouteMatch{ClientCIDRs: []string{"10.0.0.0/8", "192.168.1.10"}})
+		assert.Nil(t, err)
+
+		assert.True(t, matcher.matches(newContext("altair", "/users", "10.20.30.40:1", nil)))
+		assert.True(t, matcher.matches(newContext("altair", "/users", "192.168.1.10:1", nil)))
+		assert.False(t, matcher.matches(newContext("altair", "/users", "192.168.1.11:1", nil)))
+	})
+
+	t.Run("Match client cidrs behind trusted proxies", func(t *testing.T) {
+		matcher, err := newRouteMatcher(entity.RouteMatch{ClientCIDRs: []string{"10.0.0.0/8"}})
+		assert.Nil(t, err)
+
+		newProxiedContext := func(remoteAddr, forwardedFor string) *gin.Context {
+			c, engine := gin.CreateTestContext(httptest.NewRecorder())
+			assert.Nil(t, TrustProxies(engine, []string{"172.16.0.1"}))
+
+			c.Request = httptest.NewRequest("GET", "/users", nil)
+			c.Request.RemoteAddr = remoteAddr
+			c.Request.Header.Set("X-Forwarded-For", forwardedFor)
+			return c
+		}
+
+		assert.True(t, matcher.matches(newProxiedContext("172.16.0.1:1", "10.20.30.40")))
+		assert.False(t, matcher.matches(newProxiedContext("172.16.0.1:1", "10.20.30.40, 192.168.1.11")))
+		assert.False(t, matcher.matches(newProxiedContext("192.168.1.11:1", "10.20.30.40")))
+	})
+
+	t.Run("Match every request when empty", func(t *testing.T) {
+		matcher, err := newRouteMatcher(entity.RouteMatch{})
+		assert.Nil(t, err)
+		assert.True(t, matcher.matches(newContext("altair", "/users", "10.0.0.1:1", nil)))
+	})
+
+	t.Run("Return error on invalid matchers", func(t *testing.T) {
+		for _, match := range []entity.RouteMatch{
+			{Hosts: []string{""}},
+			{Hosts: []string{"api.*.com"}},
+			{Hosts: []string{"*."}},
+			{Headers: map[string]string{"": "a"}},
+			{ClientCIDRs: []string{"10.0.0.0/33"}},
+			{ClientCIDRs: []string{"localhost"}},
+		} {
+			_, err := newRouteMatcher(match)
+			assert.NotNil(t, err, match)
+		}
+	})
+}
+
+func TestRouteDispatcher(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	respond := func(name string) gin.HandlerFunc {
+		return func(c *gin.Context) { c.String(http.StatusOK, name) }
+	}
+
+	candidate := func(name string, match entity.RouteMatch) routeCandidate {
+		matcher, err := newRouteMatcher(match)
+		assert.Nil(t, err)
+		return routeCandidate{routeName: name, matcher: matcher, handler: respond(name)}
+	}
+
+	dispatcher := newRouteDispatcher()
+	assert.Nil(t, dispatcher.add("/users", "GET", candidate("default", entity.RouteMatch{})))
+	assert.Nil(t, dispatcher.add("/users", "GET", candidate("wildcard", entity.RouteMatch{Hosts: []string{"*.tenant-a.com"}})))
+	assert.Nil(t, dispatcher.add("/users", "GET", candidate("exact", entity.RouteMatch{Hosts: []string{"api.tenant-a.com"}})))
+	assert.Nil(t, dispatcher.add("/users", "GET", candidate("beta", entity.RouteMatch{Hosts: []string{"*.tenant-a.com"}, Headers: map[string]string{"X-Beta": "1"}})))
+
+	t.Run("Return error when two routes share the same match", func(t *testing.T) {
+		assert.NotNil(t, dispatcher.add("/users", "GET", candidate("duplicate", entity.RouteMatch{Hosts: []string{"*.TENANT-A.com"}})))
+	})
+
+	engine := gin.New()
+	dispatcher.register(engine)
+
+	serve := func(host string, header http.Header) string {
+		req := httptest.NewRequest("GET", "/users", nil)
+		req.Host = host
+		for name, values := range header {
+			req.Header[name] = values
+		}
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	assert.Equal(t, "exact", serve("api.tenant-a.com", http.Header{"X-Beta": {"1"}}))
+	assert.Equal(t, "beta", serve("eu.tenant-a.com", http.Header{"X-Beta": {"1"}}))
+	assert.Equal(t, "wildcard", serve("eu.tenant-a.com", nil))
+	assert.Equal(t, "default", serve("api.tenant-b.com", nil))
+
+	t.Run("Answer not found when no route matches", func(t *testing.T) {
+		engine := gin.New()
+		dispatcher := newRouteDispatcher()
+		assert.Nil(t, dispatcher.add("/users", "GET", candidate("exact", entity.RouteMatch{Hosts: []string{"api.tenant-a.com"}})))
+		dispatcher.register(engine)
+
+		req := httptest.NewRequest("GET", "/users", nil)
+		req.Host = "api.tenant-b.com"
+
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+// routeMethods are the methods a path can be registered with, the same set gin
+// registers for engine.Any.
+var routeMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodTrace,
+}
+
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowedMethods returns the methods forwarded for the path, every method when
+// the path does not restrict them.
+func allowedMethods(routePath entity.RouterPath) []string {
+	if len(routePath.Methods) == 0 {
+		return routeMethods
+	}
+
+	return routePath.Methods
+}
+
+func methodNotAllowed(allowed []string) gin.HandlerFunc {
+	allow := strings.Join(allowed, ", ")
+
+	return func(c *gin.Context) {
+		c.Header("Allow", allow)
+		c.JSON(http.StatusMethodNotAllowed, gin.H{
+			"status":  http.StatusMethodNotAllowed,
+			"message": "Method not allowed",
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+const (
+	defaultRetryBackoff    = time.Millisecond * 100
+	defaultRetryMaxBackoff = time.Second * 2
+
+	// retryBodyBufferSize is the largest request body buffered so it can be
+	// sent again on retry, larger and chunked bodies are streamed once.
+	retryBodyBufferSize = 1 << 20
+)
+
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+}
+
+type retryPolicy struct {
+	maxAttempts       int
+	onStatus          map[int]bool
+	onConnectionError bool
+	backoff           time.Duration
+	maxBackoff        time.Duration
+}
+
+func newRetryPolicy(retry entity.RouteRetry) retryPolicy {
+	policy := retryPolicy{
+		maxAttempts:       retry.MaxAttempts,
+		onStatus:          map[int]bool{},
+		onConnectionError: retry.OnConnectionError,
+		backoff:           parseDurationOr(retry.Backoff, defaultRetryBackoff),
+		maxBackoff:        parseDurationOr(retry.MaxBackoff, defaultRetryMaxBackoff),
+	}
+
+	if policy.maxAttempts < 1 {
+		policy.maxAttempts = 1
+	}
+
+	for _, status := range retry.OnStatus {
+		policy.onStatus[status] = true
+	}
+
+	return policy
+}
+
+// shouldRetry decides whether another attempt is allowed after the given
+// attempt finished with res or err.
+func (r retryPolicy) shouldRetry(method string, attempt int, res *http.Response, err error) bool {
+	if attempt >= r.maxAttempts || !idempotentMethods[method] {
+		return false
+	}
+
+	if err != nil {
+		return r.onConnectionError
+	}
+
+	return r.onStatus[res.StatusCode]
+}
+
+// buffers reports whether a request body of the given length is buffered to
+// be replayed on retry.
+func (r retryPolicy) buffers(contentLength int64) bool {
+	return r.maxAttempts > 1 && contentLength > 0 && contentLength <= retryBodyBufferSize
+}
+
+// wait returns the delay before the next attempt: exponential backoff capped at
+// maxBackoff, with jitter so that replicas retrying the same failure do not
+// stampede the upstream in lockstep.
+func (r retryPolicy) wait(attempt int) time.Duration {
+	delay := r.backoff
+	for i := 1; i < attempt && delay < r.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > r.maxBackoff {
+		delay = r.maxBackoff
+	}
+
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int64N(half+1))
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("Disabled by default", func(t *testing.T) {
+		policy := newRetryPolicy(entity.RouteRetry{})
+
+		assert.Equal(t, 1, policy.maxAttempts)
+		assert.False(t, policy.shouldRetry(http.MethodGet, 1, nil, errors.New("connection refused")))
+	})
+
+	t.Run("Should retry", func(t *testing.T) {
+		policy := newRetryPolicy(entity.RouteRetry{
+			MaxAttempts:       3,
+			OnStatus:          []int{http.StatusBadGateway},
+			OnConnectionError: true,
+		})
+
+		badGateway := &http.Response{StatusCode: http.StatusBadGateway}
+		ok := &http.Response{StatusCode: http.StatusOK}
+
+		assert.True(t, policy.shouldRetry(http.MethodGet, 1, badGateway, nil))
+		assert.True(t, policy.shouldRetry(http.MethodPut, 2, nil, errors.New("connection refused")))
+		assert.False(t, policy.shouldRetry(http.MethodGet, 3, badGateway, nil))
+		assert.False(t, policy.shouldRetry(http.MethodGet, 1, ok, nil))
+		assert.False(t, policy.shouldRetry(http.MethodPost, 1, badGateway, nil))
+		assert.False(t, policy.shouldRetry(http.MethodPatch, 1, nil, errors.New("connection refused")))
+	})
+
+	t.Run("Buffer bodies small enough to replay", func(t *testing.T) {
+		policy := newRetryPolicy(entity.RouteRetry{MaxAttempts: 2})
+
+		assert.True(t, policy.buffers(retryBodyBufferSize))
+		assert.False(t, policy.buffers(retryBodyBufferSize+1))
+		assert.False(t, policy.buffers(-1))
+		assert.False(t, policy.buffers(0))
+		assert.False(t, newRetryPolicy(entity.RouteRetry{}).buffers(1))
+	})
+
+	t.Run("Wait with capped exponential backoff", func(t *testing.T) {
+		policy := newRetryPolicy(entity.RouteRetry{Backoff: "100ms", MaxBackoff: "300ms"})
+
+		for attempt, max := range map[int]time.Duration{1: 100, 2: 200, 3: 300, 10: 300, 64: 300} {
+			wait := policy.wait(attempt)
+			assert.GreaterOrEqual(t, wait, max*time.Millisecond/2)
+			assert.LessOrEqual(t, wait, max*time.Millisecond)
+		}
+	})
+}
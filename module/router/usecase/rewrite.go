@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+var pathParamPattern = regexp.MustCompile(`:([A-Za-z_][A-Za-z0-9_]*)`)
+
+type rewriteRule struct {
+	match   *regexp.Regexp
+	replace string
+}
+
+// pathRewrite turns the incoming path into the upstream path: the route prefix
+// is stripped first, then the rewrite rules run in order on the result of the
+// previous one, and add_prefix is prepended last.
+type pathRewrite struct {
+	stripPrefix string
+	addPrefix   string
+	rules       []rewriteRule
+}
+
+func newPathRewrite(routeObject entity.RouteObject, routePath entity.RouterPath) (pathRewrite, error) {
+	rewrite := pathRewrite{addPrefix: routeObject.AddPrefix}
+
+	stripPrefix := routeObject.StripPrefix
+	if routePath.StripPrefix != nil {
+		stripPrefix = *routePath.StripPrefix
+	}
+
+	if stripPrefix {
+		rewrite.stripPrefix = routeObject.Prefix
+	}
+
+	if routePath.AddPrefix != nil {
+		rewrite.addPrefix = *routePath.AddPrefix
+	}
+
+	rules := routeObject.Rewrite
+	if routePath.Rewrite != nil {
+		rules = routePath.Rewrite
+	}
+
+	for _, rule := range rules {
+		match, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return pathRewrite{}, fmt.Errorf("rewrite match `%s` is invalid: %v", rule.Match, err)
+		}
+
+		rewrite.rules = append(rewrite.rules, rewriteRule{match: match, replace: rule.Replace})
+	}
+
+	return rewrite, nil
+}
+
+func (p pathRewrite) apply(c *gin.Context, path string) string {
+	if p.stripPrefix != "" {
+		path = strings.TrimPrefix(path, p.stripPrefix)
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+	}
+
+	for _, rule := range p.rules {
+		if !rule.match.MatchString(path) {
+			continue
+		}
+
+		path = rule.match.ReplaceAllString(path, expandPathParams(c, rule.replace))
+	}
+
+	if p.addPrefix != "" {
+		path = strings.TrimSuffix(p.addPrefix, "/") + path
+	}
+
+	return path
+}
+
+// expandPathParams substitutes :name with the gin path param. The value is
+// escaped so a `$` in it is not read as a capture group reference.
+func expandPathParams(c *gin.Context, replace string) string {
+	return pathParamPattern.ReplaceAllStringFunc(replace, func(token string) string {
+		value, ok := c.Params.Get(token[1:])
+		if !ok {
+			return token
+		}
+
+		return strings.ReplaceAll(strings.TrimPrefix(value, "/"), "$", "$$")
+	})
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+func TestPathRewrite(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Params = gin.Params{{Key: "id", Value: "42"}, {Key: "file", Value: "/docs/a$1.pdf"}}
+
+	routeObject := entity.RouteObject{Prefix: "/users", StripPrefix: true}
+
+	keep := false
+	apiPrefix := "/api/v1/"
+
+	for name, test := range map[string]struct {
+		routeObject entity.RouteObject
+		routePath   entity.RouterPath
+		path        string
+		expected    string
+	}{
+		"forward verbatim by default":     {entity.RouteObject{Prefix: "/users"}, entity.RouterPath{}, "/users/me", "/users/me"},
+		"strip the prefix":                {routeObject, entity.RouterPath{}, "/users/me", "/me"},
+		"strip the whole path":            {routeObject, entity.RouterPath{}, "/users", "/"},
+		"keep the prefix for the path":    {routeObject, entity.RouterPath{StripPrefix: &keep}, "/users/me", "/users/me"},
+		"add a prefix after stripping":    {entity.RouteObject{Prefix: "/users", StripPrefix: true, AddPrefix: "/internal"}, entity.RouterPath{}, "/users/me", "/internal/me"},
+		"add the path prefix":             {entity.RouteObject{Prefix: "/users", StripPrefix: true, AddPrefix: "/internal"}, entity.RouterPath{AddPrefix: &apiPrefix}, "/users/me", "/api/v1/me"},
+		"rewrite with capture groups":     {routeObject, entity.RouterPath{Rewrite: []entity.RouteRewrite{{Match: `^/(?P<id>\d+)/orders$`, Replace: "/orders/by-user/${id}"}}}, "/users/42/orders", "/orders/by-user/42"},
+		"rewrite with gin path params":    {routeObject, entity.RouterPath{Rewrite: []entity.RouteRewrite{{Match: `^/.*$`, Replace: "/accounts/:id/profile"}}}, "/users/42", "/accounts/42/profile"},
+		"escape dollar in path params":    {routeObject, entity.RouterPath{Rewrite: []entity.RouteRewrite{{Match: `^/files/.*$`, Replace: "/storage/:file"}}}, "/users/files/docs/a$1.pdf", "/storage/docs/a$1.pdf"},
+		"run rules in order":              {entity.RouteObject{Prefix: "/users", Rewrite: []entity.RouteRewrite{{Match: `^/users`, Replace: "/accounts"}, {Match: `/me$`, Replace: "/self"}}}, entity.RouterPath{}, "/users/me", "/accounts/self"},
+		"skip rules that do not match":    {routeObject, entity.RouterPath{Rewrite: []entity.RouteRewrite{{Match: `^/orders`, Replace: "/purchases"}}}, "/users/me", "/me"},
+		"replace the route rules by path": {entity.RouteObject{Prefix: "/users", Rewrite: []entity.RouteRewrite{{Match: `^/users`, Replace: "/accounts"}}}, entity.RouterPath{Rewrite: []entity.RouteRewrite{}}, "/users/me", "/users/me"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rewrite, err := newPathRewrite(test.routeObject, test.routePath)
+			assert.Nil(t, err)
+			assert.Equal(t, test.expected, rewrite.apply(c, test.path))
+		})
+	}
+
+	t.Run("Return error on invalid regular expression", func(t *testing.T) {
+		_, err := newPathRewrite(routeObject, entity.RouterPath{Rewrite: []entity.RouteRewrite{{Match: "^/(\\d+"}}})
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module"
+)
+
+const defaultConnectTimeout = time.Second * 30
+
+// routeRuntime holds the state shared by every path registered from one route
+// object: the balancing pool, the http clients tuned with the route timeouts,
+// the retry policy, the circuit breaker, the cors policy and the in-flight
+// request count.
+type routeRuntime struct {
+	name          string
+	upstream      *upstreamPool
+	client        *http.Client
+	upgradeClient *http.Client
+	retry         retryPolicy
+	breaker       *circuitBreaker
+
+	requestHeaders  headerRules
+	responseHeaders headerRules
+	requestBody     bodyTransform
+	responseBody    bodyTransform
+
+	maxBodySize int64
+	cors        *corsPolicy
+
+	inFlight int64
+	metrics  []module.MetricController
+}
+
+// pathRuntime holds what is resolved once per registered path and method: the
+// router path handed to downstream plugins, whether the body has to be buffered
+// for them or for the request_body transform and how the upstream path is
+// rewritten.
+type pathRuntime struct {
+	routePath  module.RouterPath
+	bufferBody bool
+	rewrite    pathRewrite
+}
+
+func newRouteRuntime(routeObject entity.RouteObject, globalCORS *entity.RouteCORS, metrics []module.MetricController) (*routeRuntime, error) {
+	requestHeaders, err := newHeaderRules(routeObject.RequestHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("request_headers: %v", err)
+	}
+
+	responseHeaders, err := newHeaderRules(routeObject.ResponseHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("response_headers: %v", err)
+	}
+
+	requestBody, err := newBodyTransform(routeObject.RequestBody)
+	if err != nil {
+		return nil, fmt.Errorf("request_body: %v", err)
+	}
+
+	responseBody, err := newBodyTransform(routeObject.ResponseBody)
+	if err != nil {
+		return nil, fmt.Errorf("response_body: %v", err)
+	}
+
+	cors, err := newCORSPolicy(globalCORS, routeObject.CORS)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newRouteClient(routeObject)
+	if err != nil {
+		return nil, err
+	}
+
+	upstream := newUpstreamPool(routeObject, metrics)
+	upstream.probeClient.Transport = client.Transport
+
+	// Upgraded connections live as long as both peers keep them open, so the
+	// route timeout must not cut them.
+	upgradeClient := &http.Client{Transport: client.Transport}
+
+	return &routeRuntime{
+		name:          routeObject.Name,
+		upstream:      upstream,
+		client:        client,
+		upgradeClient: upgradeClient,
+		retry:         newRetryPolicy(routeObject.Retry),
+		breaker:       newCircuitBreaker(routeObject, metrics),
+
+		requestHeaders:  requestHeaders,
+		responseHeaders: responseHeaders,
+		requestBody:     requestBody,
+		responseBody:    responseBody,
+
+		maxBodySize: parseByteSizeOr(routeObject.MaxBodySize, 0),
+		cors:        cors,
+		metrics:     metrics,
+	}, nil
+}
+
+func (r *routeRuntime) start() {
+	r.upstream.startHealthCheck()
+}
+
+// begin marks a request as in flight and returns the function ending it.
+func (r *routeRuntime) begin() func() {
+	r.reportInFlight(atomic.AddInt64(&r.inFlight, 1))
+
+	return func() {
+		r.reportInFlight(atomic.AddInt64(&r.inFlight, -1))
+	}
+}
+
+func (r *routeRuntime) reportInFlight(inFlight int64) {
+	for _, m := range r.metrics {
+		_ = m.Set("routes_in_flight_requests", float64(inFlight), map[string]string{"route_name": r.name})
+	}
+}
+
+func (r *routeRuntime) close() {
+	r.upstream.close()
+	r.client.CloseIdleConnections()
+}
+
+// newRouteClient builds one client per route so connections to the upstream
+// are pooled across requests. A zero timeout keeps the previous behaviour of
+// waiting on the upstream indefinitely.
+func newRouteClient(routeObject entity.RouteObject) (*http.Client, error) {
+	tlsConfig, err := newUpstreamTLSConfig(routeObject)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   parseDurationOr(routeObject.ConnectTimeout, defaultConnectTimeout),
+		KeepAlive: time.Second * 30,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   parseDurationOr(routeObject.Timeout, 0),
+	}, nil
+}
+
+var byteSizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// parseByteSize parses sizes such as `512KB`, `10MB` or a plain number of bytes.
+func parseByteSize(raw string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(raw))
+	multiplier := int64(1)
+
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("`%s` is not a valid size, expected a number optionally followed by B, KB, MB or GB", raw)
+	}
+
+	if size < 0 {
+		return 0, fmt.Errorf("`%s` cannot be negative", raw)
+	}
+
+	return size * multiplier, nil
+}
+
+func parseByteSizeOr(raw string, fallback int64) int64 {
+	if raw == "" {
+		return fallback
+	}
+
+	size, err := parseByteSize(raw)
+	if err != nil {
+		return fallback
+	}
+
+	return size
+}
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseByteSize(t *testing.T) {
+	for raw, expected := range map[string]int64{
+		"1024":   1024,
+		"10B":    10,
+		"512KB":  512 << 10,
+		"10 mb":  10 << 20,
+		"2GB":    2 << 30,
+		"0":      0,
+		" 1MB  ": 1 << 20,
+	} {
+		size, err := parseByteSize(raw)
+		assert.Nil(t, err, raw)
+		assert.Equal(t, expected, size, raw)
+	}
+
+	for _, raw := range []string{"", "MB", "ten megabytes", "-1KB", "1.5MB"} {
+		_, err := parseByteSize(raw)
+		assert.NotNil(t, err, raw)
+	}
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module"
+)
+
+const defaultRoutesWatchInterval = time.Second * 2
+
+// routingTable is one generation of compiled routes. Each generation owns its
+// own gin engine because gin cannot unregister a route.
+type routingTable struct {
+	engine       *gin.Engine
+	generator    *Generator
+	routeObjects []entity.RouteObject
+}
+
+// Router serves the routes compiled from the routes directory and swaps them
+// atomically on Reload. Requests already running on the previous table keep
+// going until they complete, only then is that table closed.
+type Router struct {
+	compiler   *Compiler
+	routesPath string
+	proxyHost  string
+	cors       *entity.RouteCORS
+
+	trustedProxies []string
+
+	downStreamPlugin []module.DownstreamController
+	metrics          []module.MetricController
+	apiError         module.ApiError
+
+	table      atomic.Pointer[routingTable]
+	reloadLock *sync.Mutex
+	fileState  string
+
+	tablesLock *sync.Mutex
+	tables     map[*routingTable]struct{}
+
+	stop     chan struct{}
+	stopOnce *sync.Once
+}
+
+func NewRouter(compiler *Compiler, routesPath, proxyHost string, cors *entity.RouteCORS, trustedProxies []string, downStreamPlugin []module.DownstreamController, metric []module.MetricController, apiError module.ApiError) *Router {
+	return &Router{
+		compiler:         compiler,
+		routesPath:       routesPath,
+		proxyHost:        proxyHost,
+		cors:             cors,
+		trustedProxies:   trustedProxies,
+		downStreamPlugin: downStreamPlugin,
+		metrics:          metric,
+		apiError:         apiError,
+		reloadLock:       &sync.Mutex{},
+		tablesLock:       &sync.Mutex{},
+		tables:           map[*routingTable]struct{}{},
+		stop:             make(chan struct{}),
+		stopOnce:         &sync.Once{},
+	}
+}
+
+// Reload compiles the routes directory and swaps the routing table. When the
+// routes fail to compile or generate the current table keeps serving and the
+// error says why.
+func (r *Router) Reload() error {
+	r.reloadLock.Lock()
+	defer r.reloadLock.Unlock()
+
+	fileState, _ := r.routesFileState()
+
+	routeObjects, err := r.compiler.Compile(r.routesPath)
+	if err != nil {
+		log.Error().Err(err).Stack().Str("routes_path", r.routesPath).Array("tags", zerolog.Arr().Str("route").Str("router").Str("reload")).Msg("Error compiling routes, keeping the current routes")
+		return err
+	}
+
+	engine := gin.New()
+	if err := TrustProxies(engine, r.trustedProxies); err != nil {
+		log.Error().Err(err).Stack().Str("routes_path", r.routesPath).Array("tags", zerolog.Arr().Str("route").Str("router").Str("reload")).Msg("Error setting the trusted proxies, keeping the current routes")
+		return err
+	}
+
+	table := &routingTable{
+		engine:       engine,
+		generator:    NewGenerator(r.proxyHost, r.cors, r.downStreamPlugin, r.metrics, r.apiError),
+		routeObjects: routeObjects,
+	}
+
+	if err := table.generator.Generate(table.engine, routeObjects); err != nil {
+		table.generator.Close()
+		log.Error().Err(err).Stack().Str("routes_path", r.routesPath).Array("tags", zerolog.Arr().Str("route").Str("router").Str("reload")).Msg("Error generating routes, keeping the current routes")
+		return err
+	}
+
+	r.tablesLock.Lock()
+	r.tables[table] = struct{}{}
+	r.tablesLock.Unlock()
+
+	r.fileState = fileState
+
+	if previous := r.table.Swap(table); previous != nil {
+		go r.retire(previous)
+	}
+
+	log.Info().Str("routes_path", r.routesPath).Int("routes", len(routeObjects)).Array("tags", zerolog.Arr().Str("route").Str("router").Str("reload")).Msg("Routes loaded")
+
+	return nil
+}
+
+// retire waits for the requests still running on a replaced table before
+// stopping its health checkers and upstream connections.
+func (r *Router) retire(table *routingTable) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	_ = table.generator.Drain(ctx)
+	table.generator.Close()
+
+	r.tablesLock.Lock()
+	delete(r.tables, table)
+	r.tablesLock.Unlock()
+}
+
+// RouteObjects returns the routes currently being served.
+func (r *Router) RouteObjects() []entity.RouteObject {
+	table := r.table.Load()
+	if table == nil {
+		return nil
+	}
+
+	return table.routeObjects
+}
+
+// Handle forwards the request to the current routing table. It is meant to be
+// registered as the gateway engine NoRoute handler so altair's own endpoints
+// keep precedence.
+func (r *Router) Handle(c *gin.Context) {
+	r.ServeHTTP(c.Writer, c.Request)
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	table := r.table.Load()
+	if table == nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	table.engine.ServeHTTP(w, req)
+}
+
+// Watch polls the routes directory and reloads when a route file is added,
+// removed or modified.
+func (r *Router) Watch(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultRoutesWatchInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				fileState, err := r.routesFileState()
+				if err != nil || fileState == r.loadedFileState() {
+					continue
+				}
+
+				log.Info().Str("routes_path", r.routesPath).Array("tags", zerolog.Arr().Str("route").Str("router").Str("watch")).Msg("Routes changed, reloading")
+
+				if err := r.Reload(); err != nil {
+					// Remember the broken state so it is not recompiled on every tick.
+					r.reloadLock.Lock()
+					r.fileState = fileState
+					r.reloadLock.Unlock()
+				}
+			}
+		}
+	}()
+}
+
+func (r *Router) loadedFileState() string {
+	r.reloadLock.Lock()
+	defer r.reloadLock.Unlock()
+
+	return r.fileState
+}
+
+// routesFileState describes every route file by path, size and modification
+// time, any change to it means the routes have to be compiled again.
+func (r *Router) routesFileState() (string, error) {
+	var files []string
+
+	err := filepath.Walk(r.routesPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || (filepath.Ext(path) != ".yaml" && filepath.Ext(path) != ".yml") {
+			return nil
+		}
+
+		files = append(files, fmt.Sprintf("%s:%d:%d", path, info.Size(), info.ModTime().UnixNano()))
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(files)
+
+	return strings.Join(files, "\n"), nil
+}
+
+// Drain waits for the in-flight requests of the current and the replaced
+// routing tables, bounded by ctx.
+func (r *Router) Drain(ctx context.Context) error {
+	r.tablesLock.Lock()
+	tables := make([]*routingTable, 0, len(r.tables))
+	for table := range r.tables {
+		tables = append(tables, table)
+	}
+	r.tablesLock.Unlock()
+
+	for _, table := range tables {
+		if err := table.generator.Drain(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close stops the watcher and every routing table.
+func (r *Router) Close() {
+	r.stopOnce.Do(func() { close(r.stop) })
+
+	if table := r.table.Load(); table != nil {
+		table.generator.Close()
+	}
+}
@@ -0,0 +1,168 @@
+package usecase_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/router/usecase"
+	"github.com/kodefluence/altair/testhelper"
+)
+
+func TestRouter(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	release := make(chan struct{})
+
+	srvTarget := &http.Server{
+		Addr: ":5030",
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/users/slow" {
+				<-release
+			}
+			w.WriteHeader(http.StatusOK)
+		}),
+	}
+
+	go func() {
+		_ = srvTarget.ListenAndServe()
+	}()
+	defer srvTarget.Close()
+
+	routesPath := t.TempDir()
+	writeRoute := func(fileName, content string) {
+		assert.Nil(t, os.WriteFile(filepath.Join(routesPath, fileName), []byte(content), 0666))
+	}
+
+	writeRoute("app.yml", `
+name: users
+auth: none
+prefix: /users
+host: localhost:5030
+path:
+  /slow: {}
+  /me: {}
+`)
+
+	router := usecase.NewRouter(usecase.NewCompiler(), routesPath, "", nil, nil, []module.DownstreamController{}, []module.MetricController{testhelper.NewDummyMetric()}, apierror.Provide())
+	defer router.Close()
+
+	gatewayEngine := gin.New()
+	gatewayEngine.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+	gatewayEngine.NoRoute(router.Handle)
+
+	gateway := httptest.NewServer(gatewayEngine)
+	defer gateway.Close()
+
+	statusOf := func(path string) int {
+		res, err := http.Get(gateway.URL + path)
+		assert.Nil(t, err)
+		defer res.Body.Close()
+
+		return res.StatusCode
+	}
+
+	t.Run("Return not found before routes are loaded", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, statusOf("/users/me"))
+	})
+
+	t.Run("Serve the loaded routes", func(t *testing.T) {
+		assert.Nil(t, router.Reload())
+
+		// Given sleep time so the server can boot first
+		time.Sleep(time.Millisecond * 100)
+
+		assert.Equal(t, http.StatusOK, statusOf("/users/me"))
+		assert.Equal(t, http.StatusOK, statusOf("/health"))
+		assert.Equal(t, "users", router.RouteObjects()[0].Name)
+	})
+
+	t.Run("Keep the current routes when compiling fails", func(t *testing.T) {
+		writeRoute("broken.yml", "name: [broken")
+
+		assert.NotNil(t, router.Reload())
+		assert.Equal(t, http.StatusOK, statusOf("/users/me"))
+
+		assert.Nil(t, os.Remove(filepath.Join(routesPath, "broken.yml")))
+	})
+
+	t.Run("Keep the current routes when generating fails", func(t *testing.T) {
+		writeRoute("duplicate.yml", `
+name: duplicate
+auth: none
+prefix: /users
+host: localhost:5030
+path:
+  /me: {}
+`)
+
+		assert.NotNil(t, router.Reload())
+		assert.Equal(t, http.StatusOK, statusOf("/users/me"))
+
+		assert.Nil(t, os.Remove(filepath.Join(routesPath, "duplicate.yml")))
+	})
+
+	t.Run("Swap routes without dropping in-flight requests", func(t *testing.T) {
+		done := make(chan int)
+		go func() {
+			done <- statusOf("/users/slow")
+		}()
+
+		assert.Eventually(t, func() bool {
+			return router.Drain(expiredContext()) != nil
+		}, time.Second, time.Millisecond*10)
+
+		writeRoute("app.yml", `
+name: accounts
+auth: none
+prefix: /accounts
+host: localhost:5030
+path:
+  /me: {}
+`)
+
+		assert.Nil(t, router.Reload())
+		assert.Equal(t, http.StatusOK, statusOf("/accounts/me"))
+		assert.Equal(t, http.StatusNotFound, statusOf("/users/me"))
+
+		close(release)
+
+		assert.Equal(t, http.StatusOK, <-done)
+		assert.Nil(t, router.Drain(context.Background()))
+	})
+
+	t.Run("Reload when route files change", func(t *testing.T) {
+		router.Watch(time.Millisecond * 10)
+
+		writeRoute("orders.yml", `
+name: orders
+auth: none
+prefix: /orders
+host: localhost:5030
+path:
+  /me: {}
+`)
+
+		assert.Eventually(t, func() bool {
+			return len(router.RouteObjects()) == 2
+		}, time.Second, time.Millisecond*10)
+
+		assert.Equal(t, http.StatusOK, statusOf("/orders/me"))
+	})
+}
+
+func expiredContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	return ctx
+}
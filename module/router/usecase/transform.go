@@ -0,0 +1,406 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+const jsonPathEveryElement = "#"
+
+// jsonPath addresses a field of a JSON document, one segment per object key or
+// array index.
+type jsonPath []string
+
+func parseJSONPath(raw string) (jsonPath, error) {
+	var path jsonPath
+	var segment strings.Builder
+
+	escaped := false
+	for _, r := range raw {
+		switch {
+		case escaped:
+			segment.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '.':
+			path = append(path, segment.String())
+			segment.Reset()
+		default:
+			segment.WriteRune(r)
+		}
+	}
+	path = append(path, segment.String())
+
+	if escaped {
+		return nil, fmt.Errorf("path `%s` cannot end with `\\`", raw)
+	}
+
+	for _, s := range path {
+		if s == "" {
+			return nil, fmt.Errorf("path `%s` cannot contain an empty field", raw)
+		}
+	}
+
+	if path[len(path)-1] == jsonPathEveryElement {
+		return nil, fmt.Errorf("path `%s` cannot end with `#`", raw)
+	}
+
+	return path, nil
+}
+
+// split separates the part of the path going through arrays with `#` from the
+// part applied to every element.
+func (p jsonPath) split() (jsonPath, jsonPath) {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i] == jsonPathEveryElement {
+			return p[:i+1], p[i+1:]
+		}
+	}
+
+	return nil, p
+}
+
+func (p jsonPath) equal(other jsonPath) bool {
+	if len(p) != len(other) {
+		return false
+	}
+
+	for i := range p {
+		if p[i] != other[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+type bodyRemove struct {
+	prefix jsonPath
+	path   jsonPath
+}
+
+type bodyRename struct {
+	prefix jsonPath
+	from   jsonPath
+	to     jsonPath
+}
+
+type bodySet struct {
+	prefix jsonPath
+	path   jsonPath
+	value  []byte
+}
+
+// bodyTransform is the compiled form of a route request_body or response_body.
+type bodyTransform struct {
+	remove []bodyRemove
+	rename []bodyRename
+	set    []bodySet
+	wrap   jsonPath
+}
+
+func newBodyTransform(transform entity.RouteBodyTransform) (bodyTransform, error) {
+	var compiled bodyTransform
+
+	removes := append([]string{}, transform.Remove...)
+	sort.Strings(removes)
+
+	for _, raw := range removes {
+		path, err := parseJSONPath(raw)
+		if err != nil {
+			return bodyTransform{}, fmt.Errorf("remove %v", err)
+		}
+
+		prefix, rest := path.split()
+		compiled.remove = append(compiled.remove, bodyRemove{prefix: prefix, path: rest})
+	}
+
+	for _, rawFrom := range sortedKeys(transform.Rename) {
+		from, err := parseJSONPath(rawFrom)
+		if err != nil {
+			return bodyTransform{}, fmt.Errorf("rename %v", err)
+		}
+
+		to, err := parseJSONPath(transform.Rename[rawFrom])
+		if err != nil {
+			return bodyTransform{}, fmt.Errorf("rename %v", err)
+		}
+
+		fromPrefix, fromRest := from.split()
+		toPrefix, toRest := to.split()
+		if !fromPrefix.equal(toPrefix) {
+			return bodyTransform{}, fmt.Errorf("rename `%s` to `%s` must keep the same `#` segments", rawFrom, transform.Rename[rawFrom])
+		}
+
+		compiled.rename = append(compiled.rename, bodyRename{prefix: fromPrefix, from: fromRest, to: toRest})
+	}
+
+	for _, raw := range sortedKeys(transform.Set) {
+		path, err := parseJSONPath(raw)
+		if err != nil {
+			return bodyTransform{}, fmt.Errorf("set %v", err)
+		}
+
+		value, err := json.Marshal(normalizeYAMLValue(transform.Set[raw]))
+		if err != nil {
+			return bodyTransform{}, fmt.Errorf("set `%s` value cannot be encoded as JSON: %v", raw, err)
+		}
+
+		prefix, rest := path.split()
+		compiled.set = append(compiled.set, bodySet{prefix: prefix, path: rest, value: value})
+	}
+
+	if transform.Wrap != "" {
+		path, err := parseJSONPath(transform.Wrap)
+		if err != nil {
+			return bodyTransform{}, fmt.Errorf("wrap %v", err)
+		}
+
+		if prefix, _ := path.split(); prefix != nil {
+			return bodyTransform{}, fmt.Errorf("wrap `%s` cannot contain `#`", transform.Wrap)
+		}
+
+		compiled.wrap = path
+	}
+
+	return compiled, nil
+}
+
+func (t bodyTransform) enabled() bool {
+	return len(t.remove) > 0 || len(t.rename) > 0 || len(t.set) > 0 || t.wrap != nil
+}
+
+// transformable reports whether a body with the given headers is JSON the
+// transform can read, compressed bodies are left alone.
+func (t bodyTransform) transformable(header http.Header) bool {
+	if !t.enabled() || !isJSONContentType(header.Get("Content-Type")) {
+		return false
+	}
+
+	encoding := header.Get("Content-Encoding")
+	return encoding == "" || strings.EqualFold(encoding, "identity")
+}
+
+// apply returns the transformed body. Key order is not preserved since objects
+// are encoded with sorted keys.
+func (t bodyTransform) apply(body []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, fmt.Errorf("body contains more than one JSON value")
+	}
+
+	for _, remove := range t.remove {
+		document = walkJSON(document, remove.prefix, func(node interface{}) interface{} {
+			return removeJSON(node, remove.path)
+		})
+	}
+
+	for _, rename := range t.rename {
+		document = walkJSON(document, rename.prefix, func(node interface{}) interface{} {
+			value, ok := getJSON(node, rename.from)
+			if !ok {
+				return node
+			}
+
+			return setJSON(removeJSON(node, rename.from), rename.to, value)
+		})
+	}
+
+	for _, set := range t.set {
+		document = walkJSON(document, set.prefix, func(node interface{}) interface{} {
+			// Decode per element so elements never share the same value.
+			value, _ := decodeJSON(set.value)
+			return setJSON(node, set.path, value)
+		})
+	}
+
+	if t.wrap != nil {
+		document = setJSON(nil, t.wrap, document)
+	}
+
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// walkJSON calls fn on every node the path leads to, `#` standing for every
+// element of an array. Missing nodes are skipped.
+func walkJSON(node interface{}, path jsonPath, fn func(interface{}) interface{}) interface{} {
+	if len(path) == 0 {
+		return fn(node)
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if child, ok := n[path[0]]; ok {
+			n[path[0]] = walkJSON(child, path[1:], fn)
+		}
+	case []interface{}:
+		if path[0] == jsonPathEveryElement {
+			for i := range n {
+				n[i] = walkJSON(n[i], path[1:], fn)
+			}
+			return n
+		}
+
+		if i, ok := jsonArrayIndex(n, path[0]); ok {
+			n[i] = walkJSON(n[i], path[1:], fn)
+		}
+	}
+
+	return node
+}
+
+func getJSON(node interface{}, path jsonPath) (interface{}, bool) {
+	for _, segment := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			child, ok := n[segment]
+			if !ok {
+				return nil, false
+			}
+			node = child
+		case []interface{}:
+			i, ok := jsonArrayIndex(n, segment)
+			if !ok {
+				return nil, false
+			}
+			node = n[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return node, true
+}
+
+func removeJSON(node interface{}, path jsonPath) interface{} {
+	last := path[len(path)-1]
+
+	return walkJSON(node, path[:len(path)-1], func(parent interface{}) interface{} {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			delete(p, last)
+		case []interface{}:
+			if i, ok := jsonArrayIndex(p, last); ok {
+				return append(p[:i], p[i+1:]...)
+			}
+		}
+
+		return parent
+	})
+}
+
+// setJSON stores value at path, creating the missing objects on the way. An
+// array index may address an existing element or append right after the last
+// one, a path going through a scalar is left untouched.
+func setJSON(node interface{}, path jsonPath, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+
+	switch n := node.(type) {
+	case map[string]interface{}:
+		n[path[0]] = setJSON(n[path[0]], path[1:], value)
+		return n
+	case []interface{}:
+		i, err := strconv.Atoi(path[0])
+		switch {
+		case err != nil || i < 0 || i > len(n):
+			return n
+		case i == len(n):
+			return append(n, setJSON(nil, path[1:], value))
+		default:
+			n[i] = setJSON(n[i], path[1:], value)
+			return n
+		}
+	case nil:
+		return map[string]interface{}{path[0]: setJSON(nil, path[1:], value)}
+	default:
+		return node
+	}
+}
+
+func jsonArrayIndex(array []interface{}, segment string) (int, bool) {
+	i, err := strconv.Atoi(segment)
+	if err != nil || i < 0 || i >= len(array) {
+		return 0, false
+	}
+
+	return i, true
+}
+
+func decodeJSON(raw []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var value interface{}
+	err := decoder.Decode(&value)
+	return value, err
+}
+
+// normalizeYAMLValue turns the maps decoded by yaml into maps JSON can encode.
+func normalizeYAMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[fmt.Sprintf("%v", key)] = normalizeYAMLValue(item)
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[key] = normalizeYAMLValue(item)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeYAMLValue(item)
+		}
+		return normalized
+	default:
+		return value
+	}
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
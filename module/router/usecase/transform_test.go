@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	path, err := parseJSONPath(`data.items.#.file\.name`)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonPath{"data", "items", "#", "file.name"}, path)
+
+	for _, raw := range []string{"", "data..name", "data.", "items.#", `data\`} {
+		_, err := parseJSONPath(raw)
+		assert.NotNil(t, err, raw)
+	}
+}
+
+func TestBodyTransform(t *testing.T) {
+	t.Run("Apply remove, rename, set and wrap in order", func(t *testing.T) {
+		transform, err := newBodyTransform(entity.RouteBodyTransform{
+			Remove: []string{"debug", "items.#.internal"},
+			Rename: map[string]string{"user_name": "userName", "items.#.item_id": "items.#.id"},
+			Set:    map[string]interface{}{"meta.version": 2, "items.#.tags": []interface{}{"legacy"}, "source": map[interface{}]interface{}{"name": "altair"}},
+			Wrap:   "data",
+		})
+		assert.Nil(t, err)
+
+		body, err := transform.apply([]byte(`{"user_name":"john","debug":true,"amount":10.50,"items":[{"item_id":1,"internal":"x"},{"item_id":2}]}`))
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"data":{"userName":"john","amount":10.50,"meta":{"version":2},"source":{"name":"altair"},"items":[{"id":1,"tags":["legacy"]},{"id":2,"tags":["legacy"]}]}}`, string(body))
+		assert.Contains(t, string(body), "10.50")
+	})
+
+	t.Run("Address array elements by index", func(t *testing.T) {
+		transform, err := newBodyTransform(entity.RouteBodyTransform{
+			Remove: []string{"0.secret"},
+			Set:    map[string]interface{}{"2": "appended", "5": "out of range"},
+		})
+		assert.Nil(t, err)
+
+		body, err := transform.apply([]byte(`[{"secret":1,"id":1},"b"]`))
+		assert.Nil(t, err)
+		assert.JSONEq(t, `[{"id":1},"b","appended"]`, string(body))
+	})
+
+	t.Run("Skip missing fields", func(t *testing.T) {
+		transform, err := newBodyTransform(entity.RouteBodyTransform{
+			Remove: []string{"missing.field"},
+			Rename: map[string]string{"missing": "found"},
+		})
+		assert.Nil(t, err)
+
+		body, err := transform.apply([]byte(`{"name":"john"}`))
+		assert.Nil(t, err)
+		assert.JSONEq(t, `{"name":"john"}`, string(body))
+	})
+
+	t.Run("Reject invalid JSON", func(t *testing.T) {
+		transform, err := newBodyTransform(entity.RouteBodyTransform{Wrap: "data"})
+		assert.Nil(t, err)
+
+		_, err = transform.apply([]byte(`{"name":`))
+		assert.NotNil(t, err)
+
+		_, err = transform.apply([]byte(`{} {}`))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Reject invalid rules", func(t *testing.T) {
+		for name, rules := range map[string]entity.RouteBodyTransform{
+			"rename across arrays": {Rename: map[string]string{"items.#.id": "id"}},
+			"wrap in array":        {Wrap: "items.#.data"},
+			"empty remove path":    {Remove: []string{""}},
+		} {
+			_, err := newBodyTransform(rules)
+			assert.NotNil(t, err, name)
+		}
+	})
+
+	t.Run("Only transform uncompressed JSON", func(t *testing.T) {
+		transform, err := newBodyTransform(entity.RouteBodyTransform{Wrap: "data"})
+		assert.Nil(t, err)
+
+		assert.True(t, transform.transformable(http.Header{"Content-Type": {"application/json; charset=utf-8"}}))
+		assert.True(t, transform.transformable(http.Header{"Content-Type": {"application/vnd.api+json"}}))
+		assert.False(t, transform.transformable(http.Header{"Content-Type": {"text/plain"}}))
+		assert.False(t, transform.transformable(http.Header{"Content-Type": {"application/json"}, "Content-Encoding": {"gzip"}}))
+		assert.False(t, bodyTransform{}.transformable(http.Header{"Content-Type": {"application/json"}}))
+	})
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/entity"
+)
+
+// isUpgradeRequest reports whether the client asks to switch protocols, e.g.
+// a WebSocket handshake.
+func isUpgradeRequest(req *http.Request) bool {
+	return headerContainsToken(req.Header, "Connection", "upgrade") && req.Header.Get("Upgrade") != ""
+}
+
+// upgradable reports whether the protocol of the request can switch. Upgrade
+// only exists in HTTP/1.1, HTTP/2 has no connection to hijack.
+func upgradable(req *http.Request) bool {
+	return req.ProtoMajor == 1 && req.ProtoMinor == 1
+}
+
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// callUpgradeService performs the handshake against the upstream and, once it
+// switches protocols, hijacks the client connection and pipes bytes both ways
+// until one of the peers closes. Any other upstream answer is forwarded as a
+// plain response.
+func (g *Generator) callUpgradeService(c *gin.Context, proxyReq *http.Request, urlPath, requestID string, routeObject entity.RouteObject, runtime *routeRuntime, target *upstreamTarget) (errVariable error) {
+	defer func(startTime time.Time) {
+		g.downStreamMetric(c, routeObject.Name, urlPath, 1, startTime)
+	}(time.Now())
+
+	target.acquire()
+	defer target.release()
+
+	proxyReq.URL.Host = target.host
+
+	proxyRes, err := runtime.upgradeClient.Do(proxyReq)
+	runtime.upstream.observe(target, err == nil && proxyRes.StatusCode < http.StatusInternalServerError)
+	if err != nil {
+		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("upstream", target.host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("upgrade")).Msg("Error forwarding the upgrade request")
+		c.JSON(http.StatusBadGateway, gin.H{
+			"status":  http.StatusBadGateway,
+			"message": "Bad gateway",
+		})
+		return err
+	}
+	defer proxyRes.Body.Close()
+
+	g.copyResponseHeader(c, requestID, routeObject, runtime, proxyRes)
+
+	if proxyRes.StatusCode != http.StatusSwitchingProtocols {
+		c.Status(proxyRes.StatusCode)
+		c.Writer.WriteHeaderNow()
+		return g.streamResponse(c.Writer, proxyRes)
+	}
+
+	backConn, ok := proxyRes.Body.(io.ReadWriteCloser)
+	if !ok || !strings.EqualFold(proxyRes.Header.Get("Upgrade"), proxyReq.Header.Get("Upgrade")) {
+		err := fmt.Errorf("upstream switched to protocol `%s`, requested `%s`", proxyRes.Header.Get("Upgrade"), proxyReq.Header.Get("Upgrade"))
+		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("upstream", target.host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("upgrade")).Msg("Invalid upgrade response from upstream")
+		c.Writer.Header().Del("Upgrade")
+		c.Writer.Header().Del("Connection")
+		c.JSON(http.StatusBadGateway, gin.H{
+			"status":  http.StatusBadGateway,
+			"message": "Bad gateway",
+		})
+		return err
+	}
+	defer backConn.Close()
+
+	// Record the status for the metrics and log lines before gin loses track of
+	// the connection.
+	c.Status(http.StatusSwitchingProtocols)
+
+	clientConn, clientBuf, err := c.Writer.Hijack()
+	if err != nil {
+		log.Error().Err(err).Stack().Str("host", routeObject.Host).Str("upstream", target.host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("method", c.Request.Method).Str("full_path", c.Request.URL.String()).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("hijack")).Msg("Error hijacking the client connection")
+		return err
+	}
+	defer clientConn.Close()
+
+	proxyRes.Body = nil
+	if err := proxyRes.Write(clientBuf); err != nil {
+		return err
+	}
+
+	if err := clientBuf.Flush(); err != nil {
+		return err
+	}
+
+	log.Info().Str("host", routeObject.Host).Str("upstream", target.host).Str("request_id", requestID).Str("prefix", routeObject.Prefix).Str("name", routeObject.Name).Str("path", urlPath).Str("protocol", proxyReq.Header.Get("Upgrade")).Str("client_ip", c.ClientIP()).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("upgrade")).Msg("Connection upgraded")
+
+	errc := make(chan error, 2)
+	go pipe(backConn, clientBuf, errc)
+	go pipe(clientConn, backConn, errc)
+
+	if err := <-errc; err != nil && !errors.Is(err, io.EOF) {
+		log.Debug().Err(err).Str("request_id", requestID).Str("name", routeObject.Name).Str("path", urlPath).Array("tags", zerolog.Arr().Str("route").Str("generator").Str("generate").Str("upgrade")).Msg("Upgraded connection closed")
+	}
+
+	return nil
+}
+
+func pipe(dst io.Writer, src io.Reader, errc chan<- error) {
+	_, err := io.Copy(dst, src)
+	errc <- err
+}
@@ -0,0 +1,13 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/kodefluence/altair/core"
+	"github.com/kodefluence/altair/module/server/usecase"
+)
+
+// Provide builds the gateway listener described by app.yml.
+func Provide(appConfig core.AppConfig, handler http.Handler) (*usecase.Server, error) {
+	return usecase.NewServer(appConfig, handler)
+}
@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/core"
+	"github.com/kodefluence/altair/entity"
+)
+
+const defaultCertificateWatchInterval = time.Second * 10
+
+// Server is the gateway listener. It terminates TLS when app.yml has a tls
+// block and keeps the certificate swappable so rotated files are picked up
+// without a restart.
+type Server struct {
+	srv       *http.Server
+	tlsConfig entity.AppTLSConfig
+
+	certificate atomic.Pointer[tls.Certificate]
+	modTime     time.Time
+	reloadLock  *sync.Mutex
+
+	stop     chan struct{}
+	stopOnce *sync.Once
+}
+
+func NewServer(appConfig core.AppConfig, handler http.Handler) (*Server, error) {
+	s := &Server{
+		srv: &http.Server{
+			Addr:    fmt.Sprintf(":%d", appConfig.Port()),
+			Handler: handler,
+		},
+		tlsConfig:  appConfig.TLS(),
+		reloadLock: &sync.Mutex{},
+		stop:       make(chan struct{}),
+		stopOnce:   &sync.Once{},
+	}
+
+	protocols := &http.Protocols{}
+	protocols.SetHTTP1(true)
+
+	if s.tlsConfig.Enabled() {
+		tlsConfig, err := s.buildTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		s.srv.TLSConfig = tlsConfig
+		protocols.SetHTTP2(appConfig.HTTP2())
+	} else {
+		protocols.SetUnencryptedHTTP2(appConfig.H2C())
+	}
+
+	s.srv.Protocols = protocols
+
+	return s, nil
+}
+
+func (s *Server) buildTLSConfig() (*tls.Config, error) {
+	minVersion, err := s.tlsConfig.Version()
+	if err != nil {
+		return nil, err
+	}
+
+	cipherSuites, err := s.tlsConfig.CipherSuiteIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	clientAuth, err := s.tlsConfig.ClientAuthType()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.ReloadCertificate(); err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+		ClientAuth:   clientAuth,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.certificate.Load(), nil
+		},
+	}
+
+	if s.tlsConfig.ClientCAFile != "" {
+		ca, err := os.ReadFile(s.tlsConfig.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("tls client_ca_file: %v", err)
+		}
+
+		tlsConfig.ClientCAs = x509.NewCertPool()
+		if !tlsConfig.ClientCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("tls client_ca_file: no PEM certificate found in `%s`", s.tlsConfig.ClientCAFile)
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// ReloadCertificate reads the certificate and key again. On failure the
+// current certificate keeps being served.
+func (s *Server) ReloadCertificate() error {
+	if !s.tlsConfig.Enabled() {
+		return nil
+	}
+
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	modTime, err := s.certificateModTime()
+	if err != nil {
+		return err
+	}
+
+	certificate, err := tls.LoadX509KeyPair(s.tlsConfig.CertFile, s.tlsConfig.KeyFile)
+	if err != nil {
+		return fmt.Errorf("tls certificate: %v", err)
+	}
+
+	s.certificate.Store(&certificate)
+	s.modTime = modTime
+
+	log.Info().Str("cert_file", s.tlsConfig.CertFile).Array("tags", zerolog.Arr().Str("server").Str("tls").Str("reload")).Msg("TLS certificate loaded")
+
+	return nil
+}
+
+// certificateModTime returns the latest modification time of the cert and key
+// files, rotating either of them triggers a reload.
+func (s *Server) certificateModTime() (time.Time, error) {
+	var latest time.Time
+
+	for _, path := range []string{s.tlsConfig.CertFile, s.tlsConfig.KeyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("tls certificate: %v", err)
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+// WatchCertificate polls the certificate files and reloads them when they
+// change. It returns immediately when TLS is disabled.
+func (s *Server) WatchCertificate(interval time.Duration) {
+	if !s.tlsConfig.Enabled() {
+		return
+	}
+
+	if interval <= 0 {
+		interval = defaultCertificateWatchInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				modTime, err := s.certificateModTime()
+				if err != nil || s.loadedModTime().Equal(modTime) {
+					continue
+				}
+
+				if err := s.ReloadCertificate(); err != nil {
+					log.Error().Err(err).Stack().Str("cert_file", s.tlsConfig.CertFile).Array("tags", zerolog.Arr().Str("server").Str("tls").Str("watch")).Msg("Error reloading TLS certificate")
+				}
+			}
+		}
+	}()
+}
+
+func (s *Server) loadedModTime() time.Time {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	return s.modTime
+}
+
+// ListenAndServe listens on the configured port, with TLS when it is enabled.
+func (s *Server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(listener)
+}
+
+func (s *Server) Serve(listener net.Listener) error {
+	var err error
+	if s.tlsConfig.Enabled() {
+		err = s.srv.ServeTLS(listener, "", "")
+	} else {
+		err = s.srv.Serve(listener)
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *Server) TLSEnabled() bool {
+	return s.tlsConfig.Enabled()
+}
+
+// Shutdown stops accepting connections and waits for the active ones to go
+// idle, bounded by ctx. Hijacked connections are not tracked here.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	return s.srv.Shutdown(ctx)
+}
+
+// Close stops the certificate watcher and the listener.
+func (s *Server) Close() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	return s.srv.Close()
+}
@@ -0,0 +1,279 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/mock"
+	"github.com/kodefluence/altair/module/server/usecase"
+)
+
+func TestServer(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	newAppConfig := func(tlsConfig entity.AppTLSConfig, http2, h2c bool) *mock.MockAppConfig {
+		appConfig := mock.NewMockAppConfig(mockCtrl)
+		appConfig.EXPECT().Port().AnyTimes().Return(0)
+		appConfig.EXPECT().TLS().AnyTimes().Return(tlsConfig)
+		appConfig.EXPECT().HTTP2().AnyTimes().Return(http2)
+		appConfig.EXPECT().H2C().AnyTimes().Return(h2c)
+		return appConfig
+	}
+
+	serve := func(t *testing.T, server *usecase.Server) string {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.Nil(t, err)
+
+		go func() {
+			_ = server.Serve(listener)
+		}()
+
+		t.Cleanup(func() { _ = server.Close() })
+
+		return listener.Addr().String()
+	}
+
+	t.Run("Serve plain http", func(t *testing.T) {
+		server, err := usecase.NewServer(newAppConfig(entity.AppTLSConfig{}, true, false), handler)
+		assert.Nil(t, err)
+		assert.False(t, server.TLSEnabled())
+
+		res, err := http.Get("http://" + serve(t, server))
+		assert.Nil(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, 1, res.ProtoMajor)
+	})
+
+	t.Run("Shutdown after active requests complete", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		server, err := usecase.NewServer(newAppConfig(entity.AppTLSConfig{}, true, false), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusOK)
+		}))
+		assert.Nil(t, err)
+
+		addr := serve(t, server)
+
+		done := make(chan int)
+		go func() {
+			res, err := http.Get("http://" + addr)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+			done <- res.StatusCode
+		}()
+
+		<-started
+
+		shutdown := make(chan error)
+		go func() {
+			shutdown <- server.Shutdown(context.Background())
+		}()
+
+		assert.Eventually(t, func() bool {
+			_, err := net.Dial("tcp", addr)
+			return err != nil
+		}, time.Second, time.Millisecond*10)
+
+		close(release)
+
+		assert.Equal(t, http.StatusOK, <-done)
+		assert.Nil(t, <-shutdown)
+	})
+
+	t.Run("Serve h2c", func(t *testing.T) {
+		server, err := usecase.NewServer(newAppConfig(entity.AppTLSConfig{}, true, true), handler)
+		assert.Nil(t, err)
+
+		protocols := &http.Protocols{}
+		protocols.SetUnencryptedHTTP2(true)
+		client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+		res, err := client.Get("http://" + serve(t, server))
+		assert.Nil(t, err)
+		defer res.Body.Close()
+
+		assert.Equal(t, 2, res.ProtoMajor)
+	})
+
+	t.Run("Terminate tls", func(t *testing.T) {
+		dir := t.TempDir()
+		certFile, keyFile, cert := writeCertificate(t, dir, "altair", nil)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(cert)
+
+		t.Run("Negotiate http2", func(t *testing.T) {
+			server, err := usecase.NewServer(newAppConfig(entity.AppTLSConfig{CertFile: certFile, KeyFile: keyFile}, true, false), handler)
+			assert.Nil(t, err)
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}, ForceAttemptHTTP2: true}}
+
+			res, err := client.Get("https://" + serve(t, server))
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Equal(t, 2, res.ProtoMajor)
+		})
+
+		t.Run("Stay on http1 when http2 is disabled", func(t *testing.T) {
+			server, err := usecase.NewServer(newAppConfig(entity.AppTLSConfig{CertFile: certFile, KeyFile: keyFile}, false, false), handler)
+			assert.Nil(t, err)
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}, ForceAttemptHTTP2: true}}
+
+			res, err := client.Get("https://" + serve(t, server))
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, 1, res.ProtoMajor)
+		})
+
+		t.Run("Reject tls versions below min version", func(t *testing.T) {
+			server, err := usecase.NewServer(newAppConfig(entity.AppTLSConfig{CertFile: certFile, KeyFile: keyFile, MinVersion: "1.3"}, true, false), handler)
+			assert.Nil(t, err)
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, MaxVersion: tls.VersionTLS12}}}
+
+			_, err = client.Get("https://" + serve(t, server))
+			assert.NotNil(t, err)
+		})
+
+		t.Run("Verify client certificates", func(t *testing.T) {
+			clientCertFile, clientKeyFile, clientCert := writeCertificate(t, dir, "client", nil)
+
+			caFile := filepath.Join(dir, "clients.pem")
+			assert.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientCert.Raw}), 0600))
+
+			server, err := usecase.NewServer(newAppConfig(entity.AppTLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: "require_and_verify"}, true, false), handler)
+			assert.Nil(t, err)
+
+			addr := serve(t, server)
+
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+			_, err = client.Get("https://" + addr)
+			assert.NotNil(t, err)
+
+			clientKeyPair, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+			assert.Nil(t, err)
+
+			client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{clientKeyPair}}}}
+			res, err := client.Get("https://" + addr)
+			assert.Nil(t, err)
+			defer res.Body.Close()
+
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+		})
+
+		t.Run("Reload rotated certificate", func(t *testing.T) {
+			rotateDir := t.TempDir()
+			certFile, keyFile, _ := writeCertificate(t, rotateDir, "altair", big.NewInt(1))
+
+			server, err := usecase.NewServer(newAppConfig(entity.AppTLSConfig{CertFile: certFile, KeyFile: keyFile}, true, false), handler)
+			assert.Nil(t, err)
+
+			addr := serve(t, server)
+			servedSerial := func() *big.Int {
+				conn, err := tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+				assert.Nil(t, err)
+				defer conn.Close()
+
+				return conn.ConnectionState().PeerCertificates[0].SerialNumber
+			}
+
+			assert.Equal(t, int64(1), servedSerial().Int64())
+
+			t.Run("On demand", func(t *testing.T) {
+				writeCertificate(t, rotateDir, "altair", big.NewInt(2))
+				assert.Nil(t, server.ReloadCertificate())
+				assert.Equal(t, int64(2), servedSerial().Int64())
+			})
+
+			t.Run("Keep serving when the new files are broken", func(t *testing.T) {
+				assert.Nil(t, os.WriteFile(keyFile, []byte("broken"), 0600))
+				assert.NotNil(t, server.ReloadCertificate())
+				assert.Equal(t, int64(2), servedSerial().Int64())
+			})
+
+			t.Run("On file change", func(t *testing.T) {
+				server.WatchCertificate(time.Millisecond * 10)
+
+				writeCertificate(t, rotateDir, "altair", big.NewInt(3))
+				future := time.Now().Add(time.Minute)
+				assert.Nil(t, os.Chtimes(certFile, future, future))
+
+				assert.Eventually(t, func() bool { return servedSerial().Int64() == 3 }, time.Second, time.Millisecond*10)
+			})
+		})
+
+		t.Run("Return error when certificate is missing", func(t *testing.T) {
+			_, err := usecase.NewServer(newAppConfig(entity.AppTLSConfig{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile}, true, false), handler)
+			assert.NotNil(t, err)
+		})
+	})
+}
+
+func writeCertificate(t *testing.T, dir, commonName string, serial *big.Int) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	if serial == nil {
+		serial = big.NewInt(time.Now().UnixNano())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	certFile := filepath.Join(dir, commonName+".crt")
+	keyFile := filepath.Join(dir, commonName+".key")
+
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certFile, keyFile, cert
+}
@@ -0,0 +1,159 @@
+package entity
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMaxSize      = 64 << 20
+	defaultMaxEntrySize = 1 << 20
+	defaultTTL          = time.Minute
+)
+
+// CachePlugin holds all config variables
+type CachePlugin struct {
+	Config PluginConfig `yaml:"config"`
+}
+
+// PluginConfig bounds the in-memory cache. Routes opt in with a `cache` block,
+// DefaultTTL applies to the ones without a ttl.
+type PluginConfig struct {
+	MaxSize      string `yaml:"max_size"`
+	MaxEntrySize string `yaml:"max_entry_size"`
+	DefaultTTL   string `yaml:"default_ttl"`
+}
+
+// Limits are the parsed plugin config.
+type Limits struct {
+	MaxSize      int64
+	MaxEntrySize int64
+	DefaultTTL   time.Duration
+}
+
+// Entry is a cached response. Vary holds the request values of the headers
+// named by the Vary response header when the entry was stored.
+type Entry struct {
+	Host      string
+	Path      string
+	Status    int
+	Header    http.Header
+	Body      []byte
+	Vary      map[string]string
+	StoredAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Size approximates the memory held by the entry.
+func (e Entry) Size() int64 {
+	size := int64(len(e.Body) + len(e.Host) + len(e.Path))
+	for name, values := range e.Header {
+		size += int64(len(name))
+		for _, value := range values {
+			size += int64(len(value))
+		}
+	}
+
+	for name, value := range e.Vary {
+		size += int64(len(name) + len(value))
+	}
+
+	return size
+}
+
+// ParseVary returns the canonical header names listed by Vary header values.
+func ParseVary(values []string) []string {
+	names := []string{}
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+
+	return names
+}
+
+// VaryValues returns the request values of the named headers.
+func VaryValues(names []string, header http.Header) map[string]string {
+	values := map[string]string{}
+	for _, name := range names {
+		values[name] = strings.Join(header.Values(name), ",")
+	}
+
+	return values
+}
+
+// Limits returns the plugin config with its defaults applied.
+func (c CachePlugin) Limits() (Limits, error) {
+	limits := Limits{MaxSize: defaultMaxSize, MaxEntrySize: defaultMaxEntrySize, DefaultTTL: defaultTTL}
+
+	if c.Config.MaxSize != "" {
+		size, err := parseByteSize(c.Config.MaxSize)
+		if err != nil {
+			return Limits{}, fmt.Errorf("cache max_size is invalid: %v", err)
+		}
+		limits.MaxSize = size
+	}
+
+	if c.Config.MaxEntrySize != "" {
+		size, err := parseByteSize(c.Config.MaxEntrySize)
+		if err != nil {
+			return Limits{}, fmt.Errorf("cache max_entry_size is invalid: %v", err)
+		}
+		limits.MaxEntrySize = size
+	}
+
+	if c.Config.DefaultTTL != "" {
+		ttl, err := time.ParseDuration(c.Config.DefaultTTL)
+		if err != nil {
+			return Limits{}, fmt.Errorf("cache default_ttl is invalid: %v", err)
+		}
+
+		if ttl <= 0 {
+			return Limits{}, fmt.Errorf("cache default_ttl must be positive")
+		}
+		limits.DefaultTTL = ttl
+	}
+
+	if limits.MaxEntrySize > limits.MaxSize {
+		return Limits{}, fmt.Errorf("cache max_entry_size cannot be larger than max_size")
+	}
+
+	return limits, nil
+}
+
+var byteSizeUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// parseByteSize parses sizes such as `512KB`, `64MB` or a plain number of bytes.
+func parseByteSize(raw string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(raw))
+	multiplier := int64(1)
+
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("`%s` is not a valid size, expected a positive number optionally followed by B, KB, MB or GB", raw)
+	}
+
+	return size * multiplier, nil
+}
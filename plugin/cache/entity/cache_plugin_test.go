@@ -0,0 +1,53 @@
+package entity_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/cache/entity"
+)
+
+func TestCachePlugin(t *testing.T) {
+	t.Run("Limits", func(t *testing.T) {
+		t.Run("Return defaults", func(t *testing.T) {
+			limits, err := entity.CachePlugin{}.Limits()
+			assert.Nil(t, err)
+			assert.Equal(t, entity.Limits{MaxSize: 64 << 20, MaxEntrySize: 1 << 20, DefaultTTL: time.Minute}, limits)
+		})
+
+		t.Run("Parse sizes and ttl", func(t *testing.T) {
+			limits, err := entity.CachePlugin{Config: entity.PluginConfig{MaxSize: "1GB", MaxEntrySize: "512KB", DefaultTTL: "30s"}}.Limits()
+			assert.Nil(t, err)
+			assert.Equal(t, entity.Limits{MaxSize: 1 << 30, MaxEntrySize: 512 << 10, DefaultTTL: time.Second * 30}, limits)
+		})
+
+		t.Run("Invalid config", func(t *testing.T) {
+			t.Run("Return error", func(t *testing.T) {
+				for name, config := range map[string]entity.PluginConfig{
+					"invalid max size":          {MaxSize: "lots"},
+					"zero max entry size":       {MaxEntrySize: "0"},
+					"invalid ttl":               {DefaultTTL: "abc"},
+					"negative ttl":              {DefaultTTL: "-1s"},
+					"entry larger than the max": {MaxSize: "1KB", MaxEntrySize: "1MB"},
+				} {
+					_, err := entity.CachePlugin{Config: config}.Limits()
+					assert.NotNil(t, err, name)
+				}
+			})
+		})
+	})
+
+	t.Run("Vary", func(t *testing.T) {
+		t.Run("Return the request values of the named headers", func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Accept-Language", "id")
+
+			names := entity.ParseVary([]string{"accept-language, Accept-Encoding"})
+			assert.Equal(t, []string{"Accept-Language", "Accept-Encoding"}, names)
+			assert.Equal(t, map[string]string{"Accept-Language": "id", "Accept-Encoding": ""}, entity.VaryValues(names, header))
+		})
+	})
+}
@@ -0,0 +1,344 @@
+package downstream
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/cache/entity"
+)
+
+const (
+	pendingContextKey = "cache_plugin_pending"
+
+	resultHit    = "hit"
+	resultMiss   = "miss"
+	resultBypass = "bypass"
+)
+
+// pending is a cache miss waiting for the upstream response.
+type pending struct {
+	key         string
+	ttl         time.Duration
+	private     bool
+	credentials bool
+	preset      map[string]bool
+	recorder    *responseRecorder
+}
+
+// Cache implement downstream plugin interface
+type Cache struct {
+	limits  entity.Limits
+	store   Store
+	metrics []module.MetricController
+	now     func() time.Time
+}
+
+// NewCache create new downstream plugin answering GET and HEAD requests from
+// the cached upstream responses
+func NewCache(limits entity.Limits, store Store, metrics []module.MetricController) *Cache {
+	for _, m := range metrics {
+		m.InjectCounter("cache_lookups", "method", "path", "result")
+	}
+
+	return &Cache{limits: limits, store: store, metrics: metrics, now: time.Now}
+}
+
+// Name get the name of downstream plugin
+func (o *Cache) Name() string {
+	return "cache-plugin"
+}
+
+// Intervene answers the request from the cache when a fresh entry matches it,
+// otherwise it records the response written to the client so AfterResponse
+// can store it. Requests sending Cache-Control no-store skip the cache, no-cache
+// and max-age=0 skip the lookup.
+func (o *Cache) Intervene(c *gin.Context, proxyReq *http.Request, r module.RouterPath) error {
+	cache, _ := r.GetPluginConfig("cache").(*coreEntity.RouteCache)
+	if cache == nil || cache.Disabled {
+		return nil
+	}
+
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return nil
+	}
+
+	directives := parseCacheControl(c.Request.Header.Values("Cache-Control"))
+	if _, ok := directives["no-store"]; ok || c.Request.Header.Get("Range") != "" || c.Request.Header.Get("Upgrade") != "" {
+		o.lookup(c, c.Writer.Header(), resultBypass)
+		return nil
+	}
+
+	key := c.Request.Host + " " + c.Request.URL.RequestURI()
+	if cache.Private {
+		owner := ""
+		if r.GetAuth() == "oauth" {
+			owner = lastValue(proxyReq.Header, "Resource-Owner-ID")
+		}
+
+		if owner == "" {
+			o.lookup(c, c.Writer.Header(), resultBypass)
+			return nil
+		}
+
+		key += " resource_owner_id=" + owner
+	}
+
+	_, noCache := directives["no-cache"]
+	if maxAge, ok := directives["max-age"]; ok && maxAge == "0" {
+		noCache = true
+	}
+
+	if !noCache {
+		if entry, ok := o.store.Get(key, c.Request.Header); ok {
+			o.serve(c, entry)
+			return nil
+		}
+	}
+
+	o.lookup(c, c.Writer.Header(), resultMiss)
+
+	// Only a complete response can be stored.
+	if c.Request.Method != http.MethodGet {
+		return nil
+	}
+	proxyReq.Header.Del("If-None-Match")
+	proxyReq.Header.Del("If-Modified-Since")
+
+	ttl := o.limits.DefaultTTL
+	if parsed, err := time.ParseDuration(cache.TTL); err == nil && parsed > 0 {
+		ttl = parsed
+	}
+
+	preset := map[string]bool{}
+	for name := range c.Writer.Header() {
+		preset[name] = true
+	}
+
+	recorder := &responseRecorder{ResponseWriter: c.Writer, limit: o.limits.MaxEntrySize}
+	c.Writer = recorder
+	credentials := c.Request.Header.Get("Authorization") != "" || c.Request.Header.Get("Cookie") != ""
+	c.Set(pendingContextKey, &pending{key: key, ttl: ttl, private: cache.Private, credentials: credentials, preset: preset, recorder: recorder})
+
+	return nil
+}
+
+// AfterResponse stores the complete 200 responses the upstream allows to cache.
+// Headers set before the request was forwarded, such as the rate limit ones,
+// belong to that request and are not stored.
+func (o *Cache) AfterResponse(c *gin.Context, proxyReq *http.Request, r module.RouterPath) {
+	value, ok := c.Get(pendingContextKey)
+	if !ok {
+		return
+	}
+
+	p := value.(*pending)
+	c.Writer = p.recorder.ResponseWriter
+
+	if c.IsAborted() || p.recorder.overflow || c.Writer.Status() != http.StatusOK {
+		return
+	}
+
+	header := c.Writer.Header()
+
+	ttl, ok := o.ttl(header, p)
+	if !ok {
+		return
+	}
+
+	stored := http.Header{}
+	for name, values := range header {
+		if p.preset[name] || name == "Content-Length" {
+			continue
+		}
+		stored[name] = append([]string(nil), values...)
+	}
+
+	now := o.now()
+	o.store.Set(p.key, entity.Entry{
+		Host:      c.Request.Host,
+		Path:      c.Request.URL.Path,
+		Status:    http.StatusOK,
+		Header:    stored,
+		Body:      append([]byte(nil), p.recorder.body.Bytes()...),
+		Vary:      entity.VaryValues(entity.ParseVary(header.Values("Vary")), c.Request.Header),
+		StoredAt:  now,
+		ExpiresAt: now.Add(ttl),
+	})
+
+	log.Debug().Str("key", p.key).Dur("ttl", ttl).Array("tags", zerolog.Arr().Str("cache").Str("downstream").Str("store")).Msg("Response cached")
+}
+
+// ttl returns how long the response can be cached for. Shared responses honour
+// s-maxage before max-age, private responses only max-age. Without any the
+// route ttl applies. A shared response to a request sending Authorization or
+// Cookie is only cached when the upstream marks it public, s-maxage or
+// must-revalidate, see RFC 9111 section 3.5.
+func (o *Cache) ttl(header http.Header, p *pending) (time.Duration, bool) {
+	directives := parseCacheControl(header.Values("Cache-Control"))
+
+	for _, directive := range []string{"no-store", "no-cache"} {
+		if _, ok := directives[directive]; ok {
+			return 0, false
+		}
+	}
+
+	if !p.private {
+		if _, ok := directives["private"]; ok {
+			return 0, false
+		}
+
+		if header.Get("Set-Cookie") != "" {
+			return 0, false
+		}
+
+		if p.credentials && !sharedWithCredentials(directives) {
+			return 0, false
+		}
+	}
+
+	for _, name := range entity.ParseVary(header.Values("Vary")) {
+		if name == "*" {
+			return 0, false
+		}
+	}
+
+	ttl := p.ttl
+	for _, directive := range []string{"s-maxage", "max-age"} {
+		if directive == "s-maxage" && p.private {
+			continue
+		}
+
+		if value, ok := directives[directive]; ok {
+			seconds, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, false
+			}
+
+			ttl = time.Duration(seconds) * time.Second
+			break
+		}
+	}
+
+	return ttl, ttl > 0
+}
+
+func sharedWithCredentials(directives map[string]string) bool {
+	for _, directive := range []string{"public", "s-maxage", "must-revalidate"} {
+		if _, ok := directives[directive]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serve writes the cached entry, or 304 when the conditional headers of the
+// request match it.
+func (o *Cache) serve(c *gin.Context, entry entity.Entry) {
+	header := c.Writer.Header()
+	for name, values := range entry.Header {
+		header[name] = append([]string(nil), values...)
+	}
+
+	header.Set("Age", strconv.Itoa(int(o.now().Sub(entry.StoredAt).Seconds())))
+	o.lookup(c, header, resultHit)
+
+	if notModified(c.Request.Header, entry.Header) {
+		header.Del("Content-Length")
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		c.Abort()
+		return
+	}
+
+	header.Set("Content-Length", strconv.Itoa(len(entry.Body)))
+	c.Status(entry.Status)
+	c.Writer.WriteHeaderNow()
+
+	if c.Request.Method != http.MethodHead {
+		if _, err := c.Writer.Write(entry.Body); err != nil {
+			log.Error().Err(err).Stack().Str("path", c.FullPath()).Array("tags", zerolog.Arr().Str("cache").Str("downstream").Str("serve")).Msg("Error writing the cached response")
+		}
+	}
+
+	c.Abort()
+}
+
+func (o *Cache) lookup(c *gin.Context, header http.Header, result string) {
+	header.Set("X-Cache", strings.ToUpper(result))
+
+	for _, m := range o.metrics {
+		_ = m.Inc("cache_lookups", map[string]string{
+			"method": c.Request.Method,
+			"path":   c.FullPath(),
+			"result": result,
+		})
+	}
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when the request
+// has no If-None-Match, against the cached response.
+func notModified(request, cached http.Header) bool {
+	if ifNoneMatch := request.Get("If-None-Match"); ifNoneMatch != "" {
+		etag := strings.TrimPrefix(cached.Get("ETag"), "W/")
+		if etag == "" {
+			return false
+		}
+
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(request.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	lastModified, err := http.ParseTime(cached.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.After(ifModifiedSince)
+}
+
+// parseCacheControl returns the lower cased directives with their values.
+func parseCacheControl(values []string) map[string]string {
+	directives := map[string]string{}
+	for _, value := range values {
+		for _, directive := range strings.Split(value, ",") {
+			name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name == "" {
+				continue
+			}
+			directives[strings.ToLower(name)] = strings.Trim(argument, `"`)
+		}
+	}
+
+	return directives
+}
+
+// lastValue returns the value appended last, the oauth plugin adds its
+// headers after the ones sent by the client.
+func lastValue(header http.Header, name string) string {
+	values := header.Values(name)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
@@ -0,0 +1,256 @@
+package downstream_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module"
+	moduleMock "github.com/kodefluence/altair/module/mock"
+	"github.com/kodefluence/altair/plugin/cache/entity"
+	"github.com/kodefluence/altair/plugin/cache/module/cache/controller/downstream"
+	"github.com/kodefluence/altair/plugin/cache/module/cache/controller/downstream/mock"
+	"github.com/kodefluence/altair/plugin/cache/module/cache/usecase"
+)
+
+func TestCache(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	limits := entity.Limits{MaxSize: 1 << 20, MaxEntrySize: 64, DefaultTTL: time.Minute}
+	routePath := coreEntity.RouterPath{Cache: &coreEntity.RouteCache{TTL: "30s"}}
+
+	newCache := func(store downstream.Store) *downstream.Cache {
+		metric := moduleMock.NewMockMetricController(mockCtrl)
+		metric.EXPECT().InjectCounter("cache_lookups", "method", "path", "result")
+		metric.EXPECT().Inc("cache_lookups", gomock.Any()).Return(nil).AnyTimes()
+
+		return downstream.NewCache(limits, store, []module.MetricController{metric})
+	}
+
+	// serve runs the request through the plugin the way the generator does,
+	// upstream plays the upstream response when the plugin lets it through.
+	serve := func(cache *downstream.Cache, r coreEntity.RouterPath, req *http.Request, upstream func(c *gin.Context, proxyReq *http.Request)) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine := gin.New()
+		engine.Handle(req.Method, "/catalog/products", func(c *gin.Context) {
+			proxyReq := httptest.NewRequest(req.Method, "/catalog/products", nil)
+			proxyReq.Header = c.Request.Header.Clone()
+
+			assert.Nil(t, cache.Intervene(c, proxyReq, r))
+			if c.IsAborted() {
+				return
+			}
+
+			upstream(c, proxyReq)
+			cache.AfterResponse(c, proxyReq, r)
+		})
+		engine.ServeHTTP(w, req)
+
+		return w
+	}
+
+	products := func(c *gin.Context, proxyReq *http.Request) {
+		c.Writer.Header().Set("Content-Type", "application/json")
+		c.Writer.Header().Set("ETag", `"v1"`)
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.Write([]byte(`[{"id":1}]`))
+	}
+
+	notCalled := func(c *gin.Context, proxyReq *http.Request) {
+		t.Error("request was forwarded to the upstream")
+	}
+
+	t.Run("Name", func(t *testing.T) {
+		t.Run("Return cache-plugin", func(t *testing.T) {
+			assert.Equal(t, "cache-plugin", newCache(mock.NewMockStore(mockCtrl)).Name())
+		})
+	})
+
+	t.Run("Cache and serve responses", func(t *testing.T) {
+		cache := newCache(usecase.NewLRU(limits.MaxSize))
+
+		t.Run("Forward the first request without its conditional headers", func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/catalog/products", nil)
+			req.Header.Set("If-None-Match", `"v0"`)
+
+			w := serve(cache, routePath, req, func(c *gin.Context, proxyReq *http.Request) {
+				assert.Equal(t, "", proxyReq.Header.Get("If-None-Match"))
+				c.Writer.Header().Set("RateLimit-Remaining", "9")
+				products(c, proxyReq)
+			})
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+		})
+
+		t.Run("Serve the next requests from the cache", func(t *testing.T) {
+			w := serve(cache, routePath, httptest.NewRequest("GET", "/catalog/products", nil), notCalled)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+			assert.Equal(t, `[{"id":1}]`, w.Body.String())
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.Equal(t, "9", w.Header().Get("RateLimit-Remaining"))
+			assert.Equal(t, "0", w.Header().Get("Age"))
+		})
+
+		t.Run("Serve HEAD requests without body", func(t *testing.T) {
+			w := serve(cache, routePath, httptest.NewRequest("HEAD", "/catalog/products", nil), notCalled)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "", w.Body.String())
+		})
+
+		t.Run("Answer matching conditional requests with not modified", func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/catalog/products", nil)
+			req.Header.Set("If-None-Match", `W/"v0", "v1"`)
+
+			w := serve(cache, routePath, req, notCalled)
+
+			assert.Equal(t, http.StatusNotModified, w.Code)
+			assert.Equal(t, "", w.Body.String())
+		})
+
+		t.Run("Forward requests sending no-cache", func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/catalog/products", nil)
+			req.Header.Set("Cache-Control", "no-cache")
+
+			forwarded := false
+			serve(cache, routePath, req, func(c *gin.Context, proxyReq *http.Request) {
+				forwarded = true
+				products(c, proxyReq)
+			})
+
+			assert.True(t, forwarded)
+		})
+	})
+
+	t.Run("Do not cache", func(t *testing.T) {
+		for name, upstream := range map[string]func(c *gin.Context, proxyReq *http.Request){
+			"no-store responses": func(c *gin.Context, proxyReq *http.Request) {
+				c.Writer.Header().Set("Cache-Control", "no-store")
+				products(c, proxyReq)
+			},
+			"private responses": func(c *gin.Context, proxyReq *http.Request) {
+				c.Writer.Header().Set("Cache-Control", "private, max-age=60")
+				products(c, proxyReq)
+			},
+			"responses setting cookies": func(c *gin.Context, proxyReq *http.Request) {
+				c.Writer.Header().Set("Set-Cookie", "session=1")
+				products(c, proxyReq)
+			},
+			"responses varying on everything": func(c *gin.Context, proxyReq *http.Request) {
+				c.Writer.Header().Set("Vary", "*")
+				products(c, proxyReq)
+			},
+			"errors": func(c *gin.Context, proxyReq *http.Request) {
+				c.Status(http.StatusInternalServerError)
+				_, _ = c.Writer.Write([]byte("error"))
+			},
+			"responses larger than max_entry_size": func(c *gin.Context, proxyReq *http.Request) {
+				c.Status(http.StatusOK)
+				_, _ = c.Writer.Write(make([]byte, 65))
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				store := mock.NewMockStore(mockCtrl)
+				store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.Entry{}, false)
+
+				serve(newCache(store), routePath, httptest.NewRequest("GET", "/catalog/products", nil), upstream)
+			})
+		}
+
+		t.Run("Routes without cache", func(t *testing.T) {
+			w := serve(newCache(mock.NewMockStore(mockCtrl)), coreEntity.RouterPath{}, httptest.NewRequest("GET", "/catalog/products", nil), products)
+			assert.Equal(t, "", w.Header().Get("X-Cache"))
+		})
+
+		t.Run("Requests sending no-store", func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/catalog/products", nil)
+			req.Header.Set("Cache-Control", "no-store")
+
+			w := serve(newCache(mock.NewMockStore(mockCtrl)), routePath, req, products)
+			assert.Equal(t, "BYPASS", w.Header().Get("X-Cache"))
+		})
+	})
+
+	t.Run("Requests sending credentials", func(t *testing.T) {
+		for _, header := range []string{"Authorization", "Cookie"} {
+			t.Run("Do not cache the responses to requests sending "+header, func(t *testing.T) {
+				store := mock.NewMockStore(mockCtrl)
+				store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.Entry{}, false)
+
+				req := httptest.NewRequest("GET", "/catalog/products", nil)
+				req.Header.Set(header, "secret")
+
+				serve(newCache(store), routePath, req, func(c *gin.Context, proxyReq *http.Request) {
+					c.Writer.Header().Set("Cache-Control", "max-age=60")
+					products(c, proxyReq)
+				})
+			})
+		}
+
+		for _, cacheControl := range []string{"public", "s-maxage=60", "max-age=60, must-revalidate"} {
+			t.Run("Cache the responses marked "+cacheControl, func(t *testing.T) {
+				store := mock.NewMockStore(mockCtrl)
+				store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.Entry{}, false)
+				store.EXPECT().Set("example.com /catalog/products", gomock.Any())
+
+				req := httptest.NewRequest("GET", "/catalog/products", nil)
+				req.Header.Set("Authorization", "Bearer token")
+
+				serve(newCache(store), routePath, req, func(c *gin.Context, proxyReq *http.Request) {
+					c.Writer.Header().Set("Cache-Control", cacheControl)
+					products(c, proxyReq)
+				})
+			})
+		}
+	})
+
+	t.Run("Use the max-age of the response", func(t *testing.T) {
+		store := mock.NewMockStore(mockCtrl)
+		store.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.Entry{}, false)
+		store.EXPECT().Set("example.com /catalog/products", gomock.Any()).Do(func(key string, entry entity.Entry) {
+			assert.Equal(t, time.Second*10, entry.ExpiresAt.Sub(entry.StoredAt))
+			assert.Equal(t, "", entry.Header.Get("X-Cache"))
+		})
+
+		serve(newCache(store), routePath, httptest.NewRequest("GET", "/catalog/products", nil), func(c *gin.Context, proxyReq *http.Request) {
+			c.Writer.Header().Set("Cache-Control", "public, max-age=10")
+			products(c, proxyReq)
+		})
+	})
+
+	t.Run("Private cache", func(t *testing.T) {
+		private := coreEntity.RouterPath{Auth: "oauth", Cache: &coreEntity.RouteCache{Private: true}}
+
+		t.Run("Key responses by resource owner", func(t *testing.T) {
+			store := mock.NewMockStore(mockCtrl)
+			store.EXPECT().Get("example.com /catalog/products resource_owner_id=42", gomock.Any()).Return(entity.Entry{}, false)
+			store.EXPECT().Set("example.com /catalog/products resource_owner_id=42", gomock.Any())
+
+			req := httptest.NewRequest("GET", "/catalog/products", nil)
+			req.Header.Add("Resource-Owner-ID", "forged")
+			req.Header.Add("Resource-Owner-ID", "42")
+
+			serve(newCache(store), private, req, func(c *gin.Context, proxyReq *http.Request) {
+				c.Writer.Header().Set("Cache-Control", "private")
+				products(c, proxyReq)
+			})
+		})
+
+		t.Run("Bypass requests without resource owner", func(t *testing.T) {
+			w := serve(newCache(mock.NewMockStore(mockCtrl)), private, httptest.NewRequest("GET", "/catalog/products", nil), products)
+			assert.Equal(t, "BYPASS", w.Header().Get("X-Cache"))
+		})
+	})
+}
@@ -0,0 +1,13 @@
+package downstream
+
+import (
+	"net/http"
+
+	"github.com/kodefluence/altair/plugin/cache/entity"
+)
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./downstream.go
+type Store interface {
+	Get(key string, header http.Header) (entity.Entry, bool)
+	Set(key string, entry entity.Entry)
+}
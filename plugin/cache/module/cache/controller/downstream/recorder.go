@@ -0,0 +1,40 @@
+package downstream
+
+import (
+	"bytes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseRecorder copies the response body written to the client up to limit
+// bytes, a larger body is not cached.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.record(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.record([]byte(s))
+	return r.ResponseWriter.WriteString(s)
+}
+
+func (r *responseRecorder) record(data []byte) {
+	if r.overflow {
+		return
+	}
+
+	if int64(r.body.Len()+len(data)) > r.limit {
+		r.overflow = true
+		r.body.Reset()
+		return
+	}
+
+	r.body.Write(data)
+}
@@ -0,0 +1,7 @@
+package http
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./http.go
+
+type Purger interface {
+	Purge(host, pathPrefix string) int
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// PurgeController removes cached responses
+type PurgeController struct {
+	purger Purger
+}
+
+// NewPurge return struct of PurgeController
+func NewPurge(purger Purger) *PurgeController {
+	return &PurgeController{purger: purger}
+}
+
+// Method POST
+func (p *PurgeController) Method() string {
+	return "POST"
+}
+
+// Path /cache/purge
+func (p *PurgeController) Path() string {
+	return "/cache/purge"
+}
+
+// Control purge the responses cached for the `host` and `path_prefix` query
+// params, every response is purged without them
+func (p *PurgeController) Control(ktx kontext.Context, c *gin.Context) {
+	host := c.Query("host")
+	pathPrefix := c.Query("path_prefix")
+
+	purged := p.purger.Purge(host, pathPrefix)
+
+	log.Info().
+		Str("host", host).
+		Str("path_prefix", pathPrefix).
+		Int("purged", purged).
+		Array("tags", zerolog.Arr().Str("controller").Str("cache").Str("purge")).
+		Msg("Cached responses purged")
+
+	c.JSON(http.StatusOK, jsonapi.BuildResponse(
+		jsonapi.WithData(gin.H{"purged": purged}),
+	))
+}
@@ -0,0 +1,60 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/controller"
+	cacheHttp "github.com/kodefluence/altair/plugin/cache/module/cache/controller/http"
+	"github.com/kodefluence/altair/plugin/cache/module/cache/controller/http/mock"
+	"github.com/kodefluence/altair/testhelper"
+)
+
+type responsePurge struct {
+	Data struct {
+		Purged int `json:"purged"`
+	} `json:"data"`
+}
+
+func TestPurge(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("Method", func(t *testing.T) {
+		assert.Equal(t, "POST", cacheHttp.NewPurge(mock.NewMockPurger(mockCtrl)).Method())
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		assert.Equal(t, "/cache/purge", cacheHttp.NewPurge(mock.NewMockPurger(mockCtrl)).Path())
+	})
+
+	t.Run("Control", func(t *testing.T) {
+		t.Run("Given host and path prefix", func(t *testing.T) {
+			t.Run("Return how many responses were purged", func(t *testing.T) {
+				apiEngine := gin.New()
+
+				purger := mock.NewMockPurger(mockCtrl)
+				purger.EXPECT().Purge("shop.example.com", "/catalog").Return(3)
+
+				ctrl := cacheHttp.NewPurge(purger)
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				var response responsePurge
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path()+"?host=shop.example.com&path_prefix=/catalog", nil)
+
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Nil(t, err)
+
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, 3, response.Data.Purged)
+			})
+		})
+	})
+}
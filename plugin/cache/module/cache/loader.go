@@ -0,0 +1,16 @@
+package cache
+
+import (
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/cache/entity"
+	"github.com/kodefluence/altair/plugin/cache/module/cache/controller/downstream"
+	"github.com/kodefluence/altair/plugin/cache/module/cache/controller/http"
+	"github.com/kodefluence/altair/plugin/cache/module/cache/usecase"
+)
+
+func Load(appModule module.App, limits entity.Limits) {
+	lru := usecase.NewLRU(limits.MaxSize)
+
+	appModule.Controller().InjectHTTP(http.NewPurge(lru))
+	appModule.Controller().InjectDownstream(downstream.NewCache(limits, lru, appModule.Controller().ListMetric()))
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"container/list"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kodefluence/altair/plugin/cache/entity"
+)
+
+type lruItem struct {
+	key   string
+	base  string
+	entry entity.Entry
+	size  int64
+}
+
+type varyNames struct {
+	names []string
+	refs  int
+}
+
+// LRU keeps the responses in the process memory up to maxSize bytes, the least
+// recently used entries are evicted first. Entries of the same key differing
+// by their Vary request headers are stored side by side.
+type LRU struct {
+	maxSize int64
+	size    int64
+	items   map[string]*list.Element
+	order   *list.List
+	vary    map[string]*varyNames
+	lock    *sync.Mutex
+	now     func() time.Time
+}
+
+func NewLRU(maxSize int64) *LRU {
+	return &LRU{
+		maxSize: maxSize,
+		items:   map[string]*list.Element{},
+		order:   list.New(),
+		vary:    map[string]*varyNames{},
+		lock:    &sync.Mutex{},
+		now:     time.Now,
+	}
+}
+
+// Get returns the fresh entry of key matching the request header.
+func (l *LRU) Get(key string, header http.Header) (entity.Entry, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var names []string
+	if vary, ok := l.vary[key]; ok {
+		names = vary.names
+	}
+
+	element, ok := l.items[variantKey(key, entity.VaryValues(names, header))]
+	if !ok {
+		return entity.Entry{}, false
+	}
+
+	item := element.Value.(*lruItem)
+	if !l.now().Before(item.entry.ExpiresAt) {
+		l.remove(element)
+		return entity.Entry{}, false
+	}
+
+	l.order.MoveToFront(element)
+
+	return item.entry, true
+}
+
+// Set stores the entry under key, evicting the least recently used entries
+// until it fits.
+func (l *LRU) Set(key string, entry entity.Entry) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	item := &lruItem{key: variantKey(key, entry.Vary), base: key, entry: entry, size: entry.Size()}
+	if item.size > l.maxSize {
+		return
+	}
+
+	if element, ok := l.items[item.key]; ok {
+		l.remove(element)
+	}
+
+	for l.size+item.size > l.maxSize {
+		l.remove(l.order.Back())
+	}
+
+	names := make([]string, 0, len(entry.Vary))
+	for name := range entry.Vary {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	vary, ok := l.vary[key]
+	if !ok {
+		vary = &varyNames{}
+		l.vary[key] = vary
+	}
+	vary.names = names
+	vary.refs++
+
+	l.items[item.key] = l.order.PushFront(item)
+	l.size += item.size
+}
+
+// Purge removes the entries of host whose path starts with pathPrefix, empty
+// values match every entry. It returns how many entries were removed.
+func (l *LRU) Purge(host, pathPrefix string) int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	purged := 0
+	for _, element := range l.items {
+		entry := element.Value.(*lruItem).entry
+		if (host == "" || entry.Host == host) && strings.HasPrefix(entry.Path, pathPrefix) {
+			l.remove(element)
+			purged++
+		}
+	}
+
+	return purged
+}
+
+func (l *LRU) remove(element *list.Element) {
+	item := element.Value.(*lruItem)
+
+	l.order.Remove(element)
+	delete(l.items, item.key)
+	l.size -= item.size
+
+	if vary, ok := l.vary[item.base]; ok {
+		vary.refs--
+		if vary.refs <= 0 {
+			delete(l.vary, item.base)
+		}
+	}
+}
+
+func variantKey(key string, vary map[string]string) string {
+	names := make([]string, 0, len(vary))
+	for name := range vary {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	builder.WriteString(key)
+	for _, name := range names {
+		builder.WriteString("\x00")
+		builder.WriteString(name)
+		builder.WriteString("=")
+		builder.WriteString(vary[name])
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/cache/entity"
+)
+
+func TestLRU(t *testing.T) {
+	now := time.Now()
+
+	newEntry := func(path, body string) entity.Entry {
+		return entity.Entry{Host: "shop", Path: path, Status: http.StatusOK, Header: http.Header{}, Body: []byte(body), Vary: map[string]string{}, StoredAt: now, ExpiresAt: now.Add(time.Minute)}
+	}
+
+	t.Run("Return fresh entries", func(t *testing.T) {
+		lru := NewLRU(1024)
+		lru.now = func() time.Time { return now }
+
+		lru.Set("a", newEntry("/a", "a"))
+
+		entry, ok := lru.Get("a", http.Header{})
+		assert.True(t, ok)
+		assert.Equal(t, []byte("a"), entry.Body)
+
+		_, ok = lru.Get("b", http.Header{})
+		assert.False(t, ok)
+	})
+
+	t.Run("Forget expired entries", func(t *testing.T) {
+		lru := NewLRU(1024)
+		lru.now = func() time.Time { return now.Add(time.Minute) }
+
+		lru.Set("a", newEntry("/a", "a"))
+
+		_, ok := lru.Get("a", http.Header{})
+		assert.False(t, ok)
+		assert.Equal(t, int64(0), lru.size)
+	})
+
+	t.Run("Evict the least recently used entries", func(t *testing.T) {
+		lru := NewLRU(newEntry("/a", "aaaa").Size() * 2)
+		lru.now = func() time.Time { return now }
+
+		lru.Set("a", newEntry("/a", "aaaa"))
+		lru.Set("b", newEntry("/b", "bbbb"))
+
+		_, ok := lru.Get("a", http.Header{})
+		assert.True(t, ok)
+
+		lru.Set("c", newEntry("/c", "cccc"))
+
+		_, ok = lru.Get("b", http.Header{})
+		assert.False(t, ok)
+
+		_, ok = lru.Get("a", http.Header{})
+		assert.True(t, ok)
+	})
+
+	t.Run("Store variants side by side", func(t *testing.T) {
+		lru := NewLRU(1024)
+		lru.now = func() time.Time { return now }
+
+		english := newEntry("/a", "hello")
+		english.Vary = map[string]string{"Accept-Language": "en"}
+		lru.Set("a", english)
+
+		indonesian := newEntry("/a", "halo")
+		indonesian.Vary = map[string]string{"Accept-Language": "id"}
+		lru.Set("a", indonesian)
+
+		header := http.Header{}
+		header.Set("Accept-Language", "en")
+
+		entry, ok := lru.Get("a", header)
+		assert.True(t, ok)
+		assert.Equal(t, []byte("hello"), entry.Body)
+
+		header.Set("Accept-Language", "fr")
+		_, ok = lru.Get("a", header)
+		assert.False(t, ok)
+	})
+
+	t.Run("Purge entries by host and path prefix", func(t *testing.T) {
+		lru := NewLRU(1024)
+		lru.now = func() time.Time { return now }
+
+		lru.Set("a", newEntry("/catalog/a", "a"))
+		lru.Set("b", newEntry("/catalog/b", "b"))
+		lru.Set("c", newEntry("/users/c", "c"))
+
+		assert.Equal(t, 0, lru.Purge("other", ""))
+		assert.Equal(t, 2, lru.Purge("shop", "/catalog"))
+		assert.Equal(t, 1, lru.Purge("", ""))
+		assert.Equal(t, 0, len(lru.vary))
+	})
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/kodefluence/altair/module"
+)
+
+//go:embed config.sample.yml
+var sampleConfig []byte
+
+// Plugin implements module.Plugin for the cache plugin. The cache plugin does
+// not own any database schema, responses live in memory.
+type Plugin struct{}
+
+// Name implements module.Plugin.
+func (*Plugin) Name() string { return "cache" }
+
+// DependsOn implements module.Plugin. Every one is a soft dependency: metric
+// has to be loaded first for lookups to be counted, oauth has to intervene
+// first for private responses to be keyed by resource owner and ratelimit has
+// to intervene first so cached responses are still limited.
+func (*Plugin) DependsOn() []string { return []string{"metric", "oauth", "ratelimit"} }
+
+// Migrations implements module.Plugin. Cache owns no schema.
+func (*Plugin) Migrations(ctx module.PluginContext) []module.MigrationSet { return nil }
+
+// SampleConfig implements module.Plugin.
+func (*Plugin) SampleConfig() []byte { return sampleConfig }
+
+// Load implements module.Plugin and dispatches on PluginContext.Version.
+func (*Plugin) Load(ctx module.PluginContext) error {
+	switch ctx.Version {
+	case "1.0":
+		return loadV1_0(ctx)
+	default:
+		return fmt.Errorf("undefined template version: %s for cache plugin", ctx.Version)
+	}
+}
+
+// LoadCommand implements module.Plugin. Cache exposes no CLI subcommands.
+func (*Plugin) LoadCommand(ctx module.PluginContext) error { return nil }
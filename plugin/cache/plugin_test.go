@@ -0,0 +1,101 @@
+package cache_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/module/mock"
+	"github.com/kodefluence/altair/plugin/cache"
+	"github.com/kodefluence/altair/plugin/cache/entity"
+)
+
+// Assumption: cache plugin's identifier is "cache".
+func TestPluginName_IsCache(t *testing.T) {
+	assert.Equal(t, "cache", (&cache.Plugin{}).Name())
+}
+
+// Assumption: cache loads after metric, oauth and ratelimit when they are
+// active.
+func TestPluginDependsOn_MetricOauthAndRatelimit(t *testing.T) {
+	assert.Equal(t, []string{"metric", "oauth", "ratelimit"}, (&cache.Plugin{}).DependsOn())
+}
+
+// Assumption: SampleConfig returns the embedded sample with cache plugin
+// markers.
+func TestPluginSampleConfig_ContainsPluginAndVersion(t *testing.T) {
+	got := string((&cache.Plugin{}).SampleConfig())
+	assert.Contains(t, got, "plugin: cache")
+	assert.Contains(t, got, `version: "1.0"`)
+}
+
+// Assumption: cache owns no schema.
+func TestPluginMigrations_AlwaysNil(t *testing.T) {
+	assert.Nil(t, (&cache.Plugin{}).Migrations(module.PluginContext{}))
+}
+
+// Assumption: LoadCommand is always a no-op success.
+func TestPluginLoadCommand_AlwaysNil(t *testing.T) {
+	assert.Nil(t, (&cache.Plugin{}).LoadCommand(module.PluginContext{Version: "anything"}))
+}
+
+func TestPluginLoad_RejectsUnknownVersion(t *testing.T) {
+	err := (&cache.Plugin{}).Load(module.PluginContext{Version: "9.9"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "9.9")
+	assert.Contains(t, err.Error(), "cache")
+}
+
+func TestPluginLoad_V10WithMissingDecodeConfigErrors(t *testing.T) {
+	err := (&cache.Plugin{}).Load(module.PluginContext{Version: "1.0"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "DecodeConfig")
+}
+
+func TestPluginLoad_V10DecodeConfigErrorPropagated(t *testing.T) {
+	ctx := module.PluginContext{
+		Version: "1.0",
+		DecodeConfig: func(_ interface{}) error {
+			return errors.New("decode boom")
+		},
+	}
+	err := (&cache.Plugin{}).Load(ctx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "decode boom")
+}
+
+func TestPluginLoad_V10InvalidSizeErrors(t *testing.T) {
+	ctx := module.PluginContext{
+		Version: "1.0",
+		DecodeConfig: func(target interface{}) error {
+			target.(*entity.CachePlugin).Config.MaxSize = "lots"
+			return nil
+		},
+	}
+	err := (&cache.Plugin{}).Load(ctx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "max_size")
+}
+
+func TestPluginLoad_V10InjectsDownstreamAndPurge(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	controller := mock.NewMockController(mockCtrl)
+	controller.EXPECT().ListMetric().Return(nil)
+	controller.EXPECT().InjectHTTP(gomock.Any())
+	controller.EXPECT().InjectDownstream(gomock.Any())
+
+	appModule := mock.NewMockApp(mockCtrl)
+	appModule.EXPECT().Controller().Return(controller).AnyTimes()
+
+	ctx := module.PluginContext{
+		Version:      "1.0",
+		AppModule:    appModule,
+		DecodeConfig: func(target interface{}) error { return nil },
+	}
+	assert.Nil(t, (&cache.Plugin{}).Load(ctx))
+}
@@ -0,0 +1,32 @@
+package cache
+
+import (
+	"errors"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/cache/entity"
+	"github.com/kodefluence/altair/plugin/cache/module/cache"
+)
+
+// errMissingDecodeConfig guards against PluginContext values constructed
+// outside of plugin.runner.buildContext, which always populates DecodeConfig.
+var errMissingDecodeConfig = errors.New("cache plugin: PluginContext.DecodeConfig is nil")
+
+func loadV1_0(ctx module.PluginContext) error {
+	if ctx.DecodeConfig == nil {
+		return errMissingDecodeConfig
+	}
+	var cachePlugin entity.CachePlugin
+	if err := ctx.DecodeConfig(&cachePlugin); err != nil {
+		return err
+	}
+
+	limits, err := cachePlugin.Limits()
+	if err != nil {
+		return err
+	}
+
+	cache.Load(ctx.AppModule, limits)
+
+	return nil
+}
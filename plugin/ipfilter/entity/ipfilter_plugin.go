@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"fmt"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+)
+
+// IPFilterPlugin holds all config variables
+type IPFilterPlugin struct {
+	Config PluginConfig `yaml:"config"`
+}
+
+// PluginConfig holds the lists applied to every route without an ip_filter
+// block. An empty allow list allows every client that is not denied. The client
+// IP is resolved with the trusted_proxies of app.yml, TrustedProxies is only
+// kept to reject configs still setting it.
+type PluginConfig struct {
+	Allow          []string `yaml:"allow"`
+	Deny           []string `yaml:"deny"`
+	TrustedProxies []string `yaml:"trusted_proxies"`
+}
+
+// Rules are the plugin allow and deny lists, they can be replaced at runtime.
+type Rules struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// Rules returns the plugin wide rules.
+func (i IPFilterPlugin) Rules() (Rules, error) {
+	rules := Rules{Allow: i.Config.Allow, Deny: i.Config.Deny}
+	if err := rules.Validate(); err != nil {
+		return Rules{}, err
+	}
+
+	return rules, nil
+}
+
+// Validate rejects the trusted_proxies of the plugin config. The router and
+// every plugin resolve the client IP the same way, so the trusted proxies are
+// set once for the whole gateway in app.yml.
+func (i IPFilterPlugin) Validate() error {
+	if len(i.Config.TrustedProxies) > 0 {
+		return fmt.Errorf("ipfilter trusted_proxies is no longer supported, set `trusted_proxies` in app.yml instead")
+	}
+
+	return nil
+}
+
+// Validate reports the first entry that is not an IP or a CIDR.
+func (r Rules) Validate() error {
+	for _, entry := range r.Allow {
+		if _, err := coreEntity.ParseNetwork(entry); err != nil {
+			return fmt.Errorf("ipfilter allow %v", err)
+		}
+	}
+
+	for _, entry := range r.Deny {
+		if _, err := coreEntity.ParseNetwork(entry); err != nil {
+			return fmt.Errorf("ipfilter deny %v", err)
+		}
+	}
+
+	return nil
+}
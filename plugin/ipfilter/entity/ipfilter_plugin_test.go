@@ -0,0 +1,45 @@
+package entity_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/ipfilter/entity"
+)
+
+func TestIPFilterPlugin(t *testing.T) {
+	t.Run("Rules", func(t *testing.T) {
+		t.Run("Return the allow and deny lists", func(t *testing.T) {
+			ipFilterPlugin := entity.IPFilterPlugin{Config: entity.PluginConfig{Allow: []string{"10.0.0.0/8"}, Deny: []string{"203.0.113.7"}}}
+
+			rules, err := ipFilterPlugin.Rules()
+			assert.Nil(t, err)
+			assert.Equal(t, entity.Rules{Allow: []string{"10.0.0.0/8"}, Deny: []string{"203.0.113.7"}}, rules)
+		})
+
+		t.Run("Invalid config", func(t *testing.T) {
+			t.Run("Return error", func(t *testing.T) {
+				for name, config := range map[string]entity.PluginConfig{
+					"invalid allow": {Allow: []string{"office"}},
+					"invalid deny":  {Deny: []string{"10.0.0.0/33"}},
+				} {
+					_, err := entity.IPFilterPlugin{Config: config}.Rules()
+					assert.NotNil(t, err, name)
+				}
+			})
+		})
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		t.Run("Return nil without trusted proxies", func(t *testing.T) {
+			assert.Nil(t, entity.IPFilterPlugin{}.Validate())
+		})
+
+		t.Run("Return error when the plugin sets trusted proxies", func(t *testing.T) {
+			err := entity.IPFilterPlugin{Config: entity.PluginConfig{TrustedProxies: []string{"10.0.0.0/8"}}}.Validate()
+			assert.NotNil(t, err)
+			assert.Contains(t, err.Error(), "app.yml")
+		})
+	})
+}
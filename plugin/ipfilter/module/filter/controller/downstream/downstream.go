@@ -0,0 +1,12 @@
+package downstream
+
+import (
+	"net"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+)
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./downstream.go
+type Filter interface {
+	Allowed(ip net.IP, ipFilter *coreEntity.RouteIPFilter) bool
+}
@@ -0,0 +1,65 @@
+package downstream
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module"
+)
+
+// IPFilter implement downstream plugin interface
+type IPFilter struct {
+	filter   Filter
+	metrics  []module.MetricController
+	apiError module.ApiError
+}
+
+// NewIPFilter create new downstream plugin rejecting the clients not allowed to reach a route
+func NewIPFilter(filter Filter, metrics []module.MetricController, apiError module.ApiError) *IPFilter {
+	for _, m := range metrics {
+		m.InjectCounter("ipfilter_rejected_requests", "method", "path")
+	}
+
+	return &IPFilter{filter: filter, metrics: metrics, apiError: apiError}
+}
+
+// Name get the name of downstream plugin
+func (o *IPFilter) Name() string {
+	return "ipfilter-plugin"
+}
+
+// Intervene current request to reject the clients the plugin rules or the
+// ip_filter of the path do not allow. The client IP is resolved by the gateway
+// with the trusted_proxies of app.yml.
+func (o *IPFilter) Intervene(c *gin.Context, proxyReq *http.Request, r module.RouterPath) error {
+	ip := net.ParseIP(c.ClientIP())
+	ipFilter, _ := r.GetPluginConfig("ipfilter").(*coreEntity.RouteIPFilter)
+	if o.filter.Allowed(ip, ipFilter) {
+		return nil
+	}
+
+	for _, m := range o.metrics {
+		_ = m.Inc("ipfilter_rejected_requests", map[string]string{
+			"method": c.Request.Method,
+			"path":   c.FullPath(),
+		})
+	}
+
+	log.Warn().Str("request_id", proxyReq.Header.Get("X-Request-ID")).Str("client_ip", ip.String()).Str("path", c.FullPath()).Array("tags", zerolog.Arr().Str("ipfilter").Str("downstream").Str("rejected")).Msg("Client ip is not allowed")
+
+	ktx := kontext.Fabricate(kontext.WithDefaultContext(c))
+	ktx.Set("request_id", proxyReq.Header.Get("X-Request-ID"))
+
+	response := jsonapi.BuildResponse(o.apiError.ForbiddenError(ktx, "route", "client ip is not allowed"))
+	c.AbortWithStatusJSON(response.HTTPStatus(), response)
+
+	return fmt.Errorf("Client ip %s is not allowed", ip)
+}
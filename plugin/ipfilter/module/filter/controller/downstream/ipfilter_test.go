@@ -0,0 +1,87 @@
+package downstream_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/module/apierror"
+	moduleMock "github.com/kodefluence/altair/module/mock"
+	"github.com/kodefluence/altair/plugin/ipfilter/module/filter/controller/downstream"
+	"github.com/kodefluence/altair/plugin/ipfilter/module/filter/controller/downstream/mock"
+)
+
+func TestIPFilter(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	clientIP := net.ParseIP("203.0.113.7")
+	ipFilter := &coreEntity.RouteIPFilter{Allow: []string{"10.0.0.0/8"}}
+
+	newContext := func() (*gin.Context, *httptest.ResponseRecorder, *http.Request) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/admin/dashboard", nil)
+		c.Request.RemoteAddr = "203.0.113.7:41000"
+
+		proxyReq := httptest.NewRequest("GET", "/admin/dashboard", nil)
+		proxyReq.Header.Set("X-Request-ID", "request-1")
+
+		return c, w, proxyReq
+	}
+
+	newIPFilter := func(filter downstream.Filter) *downstream.IPFilter {
+		metric := moduleMock.NewMockMetricController(mockCtrl)
+		metric.EXPECT().InjectCounter("ipfilter_rejected_requests", "method", "path")
+		metric.EXPECT().Inc("ipfilter_rejected_requests", gomock.Any()).Return(nil).AnyTimes()
+
+		return downstream.NewIPFilter(filter, []module.MetricController{metric}, apierror.Provide())
+	}
+
+	t.Run("Name", func(t *testing.T) {
+		t.Run("Return ipfilter-plugin", func(t *testing.T) {
+			assert.Equal(t, "ipfilter-plugin", newIPFilter(mock.NewMockFilter(mockCtrl)).Name())
+		})
+	})
+
+	t.Run("Intervene", func(t *testing.T) {
+		t.Run("Client is allowed", func(t *testing.T) {
+			t.Run("Return nil", func(t *testing.T) {
+				c, _, proxyReq := newContext()
+
+				filter := mock.NewMockFilter(mockCtrl)
+				filter.EXPECT().Allowed(clientIP, ipFilter).Return(true)
+
+				err := newIPFilter(filter).Intervene(c, proxyReq, coreEntity.RouterPath{IPFilter: ipFilter})
+
+				assert.Nil(t, err)
+				assert.False(t, c.IsAborted())
+			})
+		})
+
+		t.Run("Client is not allowed", func(t *testing.T) {
+			t.Run("Return error and forbidden", func(t *testing.T) {
+				c, w, proxyReq := newContext()
+
+				filter := mock.NewMockFilter(mockCtrl)
+				filter.EXPECT().Allowed(clientIP, ipFilter).Return(false)
+
+				err := newIPFilter(filter).Intervene(c, proxyReq, coreEntity.RouterPath{IPFilter: ipFilter})
+
+				assert.NotNil(t, err)
+				assert.True(t, c.IsAborted())
+				assert.Equal(t, http.StatusForbidden, w.Code)
+				assert.Contains(t, w.Body.String(), "ERR0403")
+			})
+		})
+	})
+}
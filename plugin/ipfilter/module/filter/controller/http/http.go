@@ -0,0 +1,9 @@
+package http
+
+import "github.com/kodefluence/altair/plugin/ipfilter/entity"
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./http.go
+type RuleManager interface {
+	Rules() entity.Rules
+	Replace(rules entity.Rules) error
+}
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./http.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/kodefluence/altair/plugin/ipfilter/entity"
+)
+
+// MockRuleManager is a mock of RuleManager interface.
+type MockRuleManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockRuleManagerMockRecorder
+}
+
+// MockRuleManagerMockRecorder is the mock recorder for MockRuleManager.
+type MockRuleManagerMockRecorder struct {
+	mock *MockRuleManager
+}
+
+// NewMockRuleManager creates a new mock instance.
+func NewMockRuleManager(ctrl *gomock.Controller) *MockRuleManager {
+	mock := &MockRuleManager{ctrl: ctrl}
+	mock.recorder = &MockRuleManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRuleManager) EXPECT() *MockRuleManagerMockRecorder {
+	return m.recorder
+}
+
+// Replace mocks base method.
+func (m *MockRuleManager) Replace(rules entity.Rules) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", rules)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockRuleManagerMockRecorder) Replace(rules interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockRuleManager)(nil).Replace), rules)
+}
+
+// Rules mocks base method.
+func (m *MockRuleManager) Rules() entity.Rules {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rules")
+	ret0, _ := ret[0].(entity.Rules)
+	return ret0
+}
+
+// Rules indicates an expected call of Rules.
+func (mr *MockRuleManagerMockRecorder) Rules() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rules", reflect.TypeOf((*MockRuleManager)(nil).Rules))
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+)
+
+// RulesController shows the ip filter rules in effect
+type RulesController struct {
+	ruleManager RuleManager
+}
+
+// NewRules return struct of RulesController
+func NewRules(ruleManager RuleManager) *RulesController {
+	return &RulesController{ruleManager: ruleManager}
+}
+
+// Method GET
+func (rc *RulesController) Method() string {
+	return "GET"
+}
+
+// Path /ipfilter/rules
+func (rc *RulesController) Path() string {
+	return "/ipfilter/rules"
+}
+
+// Control show the plugin allow and deny lists
+func (rc *RulesController) Control(ktx kontext.Context, c *gin.Context) {
+	c.JSON(http.StatusOK, jsonapi.BuildResponse(jsonapi.WithData(rc.ruleManager.Rules())))
+}
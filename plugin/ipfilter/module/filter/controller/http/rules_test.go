@@ -0,0 +1,59 @@
+package http_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/controller"
+	"github.com/kodefluence/altair/plugin/ipfilter/entity"
+	ipFilterHttp "github.com/kodefluence/altair/plugin/ipfilter/module/filter/controller/http"
+	"github.com/kodefluence/altair/plugin/ipfilter/module/filter/controller/http/mock"
+	"github.com/kodefluence/altair/testhelper"
+)
+
+type responseRules struct {
+	Data entity.Rules `json:"data"`
+}
+
+func TestRules(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("Method", func(t *testing.T) {
+		assert.Equal(t, "GET", ipFilterHttp.NewRules(mock.NewMockRuleManager(mockCtrl)).Method())
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		assert.Equal(t, "/ipfilter/rules", ipFilterHttp.NewRules(mock.NewMockRuleManager(mockCtrl)).Path())
+	})
+
+	t.Run("Control", func(t *testing.T) {
+		t.Run("Return the rules in effect", func(t *testing.T) {
+			apiEngine := gin.New()
+
+			rules := entity.Rules{Allow: []string{"10.0.0.0/8"}, Deny: []string{"203.0.113.7"}}
+
+			ruleManager := mock.NewMockRuleManager(mockCtrl)
+			ruleManager.EXPECT().Rules().Return(rules)
+
+			ctrl := ipFilterHttp.NewRules(ruleManager)
+			controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+			var response responseRules
+			w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), nil)
+
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.Nil(t, err)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, rules, response.Data)
+		})
+	})
+}
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/ipfilter/entity"
+)
+
+// UpdateController replaces the ip filter rules
+type UpdateController struct {
+	ruleManager RuleManager
+	apiError    module.ApiError
+}
+
+// NewUpdate return struct of UpdateController
+func NewUpdate(ruleManager RuleManager, apiError module.ApiError) *UpdateController {
+	return &UpdateController{ruleManager: ruleManager, apiError: apiError}
+}
+
+// Method PUT
+func (uc *UpdateController) Method() string {
+	return "PUT"
+}
+
+// Path /ipfilter/rules
+func (uc *UpdateController) Path() string {
+	return "/ipfilter/rules"
+}
+
+// Control replace the plugin allow and deny lists, they apply to the next
+// request and last until altair restarts
+func (uc *UpdateController) Control(ktx kontext.Context, c *gin.Context) {
+	var rules entity.Rules
+
+	rawData, err := c.GetRawData()
+	if err != nil {
+		log.Error().
+			Err(err).
+			Stack().
+			Interface("request_id", c.Value("request_id")).
+			Array("tags", zerolog.Arr().Str("controller").Str("ipfilter").Str("update").Str("get_raw_data")).
+			Msg("Cannot get raw data")
+
+		c.JSON(http.StatusBadRequest, jsonapi.BuildResponse(uc.apiError.BadRequestError("request body")))
+		return
+	}
+
+	if err := json.Unmarshal(rawData, &rules); err != nil {
+		log.Error().
+			Err(err).
+			Stack().
+			Interface("request_id", c.Value("request_id")).
+			Array("tags", zerolog.Arr().Str("controller").Str("ipfilter").Str("update").Str("unmarshal")).
+			Msg("Cannot unmarshal json")
+
+		c.JSON(http.StatusBadRequest, jsonapi.BuildResponse(uc.apiError.BadRequestError("invalid json format")))
+		return
+	}
+
+	if err := uc.ruleManager.Replace(rules); err != nil {
+		c.JSON(http.StatusUnprocessableEntity, jsonapi.BuildResponse(uc.apiError.ValidationError(err.Error())))
+		return
+	}
+
+	log.Info().
+		Strs("allow", rules.Allow).
+		Strs("deny", rules.Deny).
+		Array("tags", zerolog.Arr().Str("controller").Str("ipfilter").Str("update")).
+		Msg("Ip filter rules replaced")
+
+	c.JSON(http.StatusOK, jsonapi.BuildResponse(jsonapi.WithData(uc.ruleManager.Rules())))
+}
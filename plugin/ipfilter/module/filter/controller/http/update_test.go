@@ -0,0 +1,90 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/controller"
+	"github.com/kodefluence/altair/plugin/ipfilter/entity"
+	ipFilterHttp "github.com/kodefluence/altair/plugin/ipfilter/module/filter/controller/http"
+	"github.com/kodefluence/altair/plugin/ipfilter/module/filter/controller/http/mock"
+	"github.com/kodefluence/altair/testhelper"
+)
+
+func TestUpdate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("Method", func(t *testing.T) {
+		assert.Equal(t, "PUT", ipFilterHttp.NewUpdate(mock.NewMockRuleManager(mockCtrl), apierror.Provide()).Method())
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		assert.Equal(t, "/ipfilter/rules", ipFilterHttp.NewUpdate(mock.NewMockRuleManager(mockCtrl), apierror.Provide()).Path())
+	})
+
+	t.Run("Control", func(t *testing.T) {
+		t.Run("Given valid rules", func(t *testing.T) {
+			t.Run("Return the rules in effect", func(t *testing.T) {
+				apiEngine := gin.New()
+
+				rules := entity.Rules{Allow: []string{}, Deny: []string{"203.0.113.7"}}
+
+				ruleManager := mock.NewMockRuleManager(mockCtrl)
+				ruleManager.EXPECT().Replace(entity.Rules{Deny: []string{"203.0.113.7"}}).Return(nil)
+				ruleManager.EXPECT().Rules().Return(rules)
+
+				ctrl := ipFilterHttp.NewUpdate(ruleManager, apierror.Provide())
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				var response responseRules
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), bytes.NewBufferString(`{"deny":["203.0.113.7"]}`))
+
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.Nil(t, err)
+
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, rules, response.Data)
+			})
+		})
+
+		t.Run("Given invalid json", func(t *testing.T) {
+			t.Run("Return bad request", func(t *testing.T) {
+				apiEngine := gin.New()
+
+				ctrl := ipFilterHttp.NewUpdate(mock.NewMockRuleManager(mockCtrl), apierror.Provide())
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), bytes.NewBufferString(`{"deny":`))
+
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			})
+		})
+
+		t.Run("Given invalid rules", func(t *testing.T) {
+			t.Run("Return unprocessable entity", func(t *testing.T) {
+				apiEngine := gin.New()
+
+				ruleManager := mock.NewMockRuleManager(mockCtrl)
+				ruleManager.EXPECT().Replace(gomock.Any()).Return(errors.New("ipfilter deny `everyone` is not an IP or a CIDR"))
+
+				ctrl := ipFilterHttp.NewUpdate(ruleManager, apierror.Provide())
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), bytes.NewBufferString(`{"deny":["everyone"]}`))
+
+				assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+				assert.Contains(t, w.Body.String(), "everyone")
+			})
+		})
+	})
+}
@@ -0,0 +1,16 @@
+package filter
+
+import (
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/ipfilter/module/filter/controller/downstream"
+	"github.com/kodefluence/altair/plugin/ipfilter/module/filter/controller/http"
+	"github.com/kodefluence/altair/plugin/ipfilter/module/filter/usecase"
+)
+
+func Load(appModule module.App, filter *usecase.Filter, apiError module.ApiError) {
+	appModule.Controller().InjectHTTP(
+		http.NewRules(filter),
+		http.NewUpdate(filter, apiError),
+	)
+	appModule.Controller().InjectDownstream(downstream.NewIPFilter(filter, appModule.Controller().ListMetric(), apiError))
+}
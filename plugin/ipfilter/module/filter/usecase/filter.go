@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"net"
+	"sync/atomic"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/plugin/ipfilter/entity"
+)
+
+// Filter decides which clients may reach a route. The plugin rules can be
+// replaced while requests are being filtered.
+type Filter struct {
+	rules atomic.Pointer[ruleSet]
+}
+
+type ruleSet struct {
+	rules entity.Rules
+	allow []*net.IPNet
+	deny  []*net.IPNet
+}
+
+// NewFilter returns a filter applying the plugin rules.
+func NewFilter(rules entity.Rules) (*Filter, error) {
+	filter := &Filter{}
+	if err := filter.Replace(rules); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// Rules returns the plugin rules in effect.
+func (f *Filter) Rules() entity.Rules {
+	return f.rules.Load().rules
+}
+
+// Replace swaps the plugin rules, the rules in effect are kept when the new
+// ones are invalid.
+func (f *Filter) Replace(rules entity.Rules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	if rules.Allow == nil {
+		rules.Allow = []string{}
+	}
+
+	if rules.Deny == nil {
+		rules.Deny = []string{}
+	}
+
+	f.rules.Store(&ruleSet{rules: rules, allow: parseNetworks(rules.Allow), deny: parseNetworks(rules.Deny)})
+
+	return nil
+}
+
+// Allowed reports whether the client may reach a path with the given
+// ip_filter. A denied client is rejected even when it is allowed, a path
+// allow list replaces the plugin one.
+func (f *Filter) Allowed(ip net.IP, ipFilter *coreEntity.RouteIPFilter) bool {
+	if ipFilter != nil && ipFilter.Disabled {
+		return true
+	}
+
+	rules := f.rules.Load()
+
+	if contains(rules.deny, ip) {
+		return false
+	}
+
+	allow := rules.allow
+
+	if ipFilter != nil {
+		pathAllow, pathDeny := ipFilter.Networks()
+		if contains(pathDeny, ip) {
+			return false
+		}
+
+		if len(pathAllow) > 0 {
+			allow = pathAllow
+		}
+	}
+
+	return len(allow) == 0 || contains(allow, ip)
+}
+
+// parseNetworks skips the invalid entries, the plugin config is validated when
+// it is loaded.
+func parseNetworks(entries []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, entry := range entries {
+		if network, err := coreEntity.ParseNetwork(entry); err == nil {
+			networks = append(networks, network)
+		}
+	}
+
+	return networks
+}
+
+func contains(networks []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,97 @@
+package usecase_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/plugin/ipfilter/entity"
+	"github.com/kodefluence/altair/plugin/ipfilter/module/filter/usecase"
+)
+
+func TestFilter(t *testing.T) {
+	office := net.ParseIP("10.1.2.3")
+	abusive := net.ParseIP("203.0.113.7")
+	stranger := net.ParseIP("198.51.100.2")
+
+	t.Run("Without rules", func(t *testing.T) {
+		t.Run("Allow every client", func(t *testing.T) {
+			filter, err := usecase.NewFilter(entity.Rules{})
+			assert.Nil(t, err)
+
+			assert.True(t, filter.Allowed(stranger, nil))
+			assert.Equal(t, entity.Rules{Allow: []string{}, Deny: []string{}}, filter.Rules())
+		})
+	})
+
+	t.Run("With plugin rules", func(t *testing.T) {
+		filter, err := usecase.NewFilter(entity.Rules{Allow: []string{"10.0.0.0/8", "203.0.113.0/24"}, Deny: []string{"203.0.113.7"}})
+		assert.Nil(t, err)
+
+		t.Run("Allow the clients in the allow list", func(t *testing.T) {
+			assert.True(t, filter.Allowed(office, nil))
+			assert.False(t, filter.Allowed(stranger, nil))
+		})
+
+		t.Run("Reject denied clients even when they are allowed", func(t *testing.T) {
+			assert.False(t, filter.Allowed(abusive, nil))
+		})
+
+		t.Run("Reject an unknown client", func(t *testing.T) {
+			assert.False(t, filter.Allowed(nil, nil))
+		})
+
+		t.Run("Replace the allow list with the path one", func(t *testing.T) {
+			ipFilter := &coreEntity.RouteIPFilter{Allow: []string{"198.51.100.0/24"}}
+
+			assert.True(t, filter.Allowed(stranger, ipFilter))
+			assert.False(t, filter.Allowed(office, ipFilter))
+		})
+
+		t.Run("Use the path networks parsed when the routes are loaded", func(t *testing.T) {
+			ipFilter := &coreEntity.RouteIPFilter{Allow: []string{"198.51.100.0/24"}, Deny: []string{"198.51.100.2"}}
+			assert.Nil(t, ipFilter.Validate())
+
+			assert.False(t, filter.Allowed(stranger, ipFilter))
+			assert.True(t, filter.Allowed(net.ParseIP("198.51.100.3"), ipFilter))
+			assert.False(t, filter.Allowed(office, ipFilter))
+		})
+
+		t.Run("Add the path deny list to the plugin one", func(t *testing.T) {
+			ipFilter := &coreEntity.RouteIPFilter{Deny: []string{"10.1.0.0/16"}}
+
+			assert.False(t, filter.Allowed(office, ipFilter))
+			assert.False(t, filter.Allowed(abusive, ipFilter))
+		})
+
+		t.Run("Allow every client when the path disables it", func(t *testing.T) {
+			assert.True(t, filter.Allowed(abusive, &coreEntity.RouteIPFilter{Disabled: true}))
+		})
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		t.Run("Apply the new rules", func(t *testing.T) {
+			filter, _ := usecase.NewFilter(entity.Rules{})
+
+			assert.Nil(t, filter.Replace(entity.Rules{Deny: []string{"198.51.100.0/24"}}))
+			assert.False(t, filter.Allowed(stranger, nil))
+			assert.Equal(t, []string{"198.51.100.0/24"}, filter.Rules().Deny)
+		})
+
+		t.Run("Keep the rules in effect when the new ones are invalid", func(t *testing.T) {
+			filter, _ := usecase.NewFilter(entity.Rules{Deny: []string{"198.51.100.0/24"}})
+
+			assert.NotNil(t, filter.Replace(entity.Rules{Deny: []string{"everyone"}}))
+			assert.False(t, filter.Allowed(stranger, nil))
+		})
+	})
+
+	t.Run("Invalid rules", func(t *testing.T) {
+		t.Run("Return error", func(t *testing.T) {
+			_, err := usecase.NewFilter(entity.Rules{Allow: []string{"office"}})
+			assert.NotNil(t, err)
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package ipfilter
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/kodefluence/altair/module"
+)
+
+//go:embed config.sample.yml
+var sampleConfig []byte
+
+// Plugin implements module.Plugin for the ipfilter plugin. The ipfilter plugin
+// does not own any database schema, rules live in memory.
+type Plugin struct{}
+
+// Name implements module.Plugin.
+func (*Plugin) Name() string { return "ipfilter" }
+
+// DependsOn implements module.Plugin. Metric is a soft dependency, it has to
+// be loaded first for rejections to be counted. Ties load alphabetically, so
+// ipfilter still intervenes before oauth and ratelimit do any work.
+func (*Plugin) DependsOn() []string { return []string{"metric"} }
+
+// Migrations implements module.Plugin. Ipfilter owns no schema.
+func (*Plugin) Migrations(ctx module.PluginContext) []module.MigrationSet { return nil }
+
+// SampleConfig implements module.Plugin.
+func (*Plugin) SampleConfig() []byte { return sampleConfig }
+
+// Load implements module.Plugin and dispatches on PluginContext.Version.
+func (*Plugin) Load(ctx module.PluginContext) error {
+	switch ctx.Version {
+	case "1.0":
+		return loadV1_0(ctx)
+	default:
+		return fmt.Errorf("undefined template version: %s for ipfilter plugin", ctx.Version)
+	}
+}
+
+// LoadCommand implements module.Plugin. Ipfilter exposes no CLI subcommands.
+func (*Plugin) LoadCommand(ctx module.PluginContext) error { return nil }
@@ -0,0 +1,100 @@
+package ipfilter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/module/mock"
+	"github.com/kodefluence/altair/plugin/ipfilter"
+	"github.com/kodefluence/altair/plugin/ipfilter/entity"
+)
+
+// Assumption: ipfilter plugin's identifier is "ipfilter".
+func TestPluginName_IsIPFilter(t *testing.T) {
+	assert.Equal(t, "ipfilter", (&ipfilter.Plugin{}).Name())
+}
+
+// Assumption: ipfilter loads after metric when it is active.
+func TestPluginDependsOn_Metric(t *testing.T) {
+	assert.Equal(t, []string{"metric"}, (&ipfilter.Plugin{}).DependsOn())
+}
+
+// Assumption: SampleConfig returns the embedded sample with ipfilter plugin
+// markers.
+func TestPluginSampleConfig_ContainsPluginAndVersion(t *testing.T) {
+	got := string((&ipfilter.Plugin{}).SampleConfig())
+	assert.Contains(t, got, "plugin: ipfilter")
+	assert.Contains(t, got, `version: "1.0"`)
+}
+
+// Assumption: ipfilter owns no schema.
+func TestPluginMigrations_AlwaysNil(t *testing.T) {
+	assert.Nil(t, (&ipfilter.Plugin{}).Migrations(module.PluginContext{}))
+}
+
+// Assumption: LoadCommand is always a no-op success.
+func TestPluginLoadCommand_AlwaysNil(t *testing.T) {
+	assert.Nil(t, (&ipfilter.Plugin{}).LoadCommand(module.PluginContext{Version: "anything"}))
+}
+
+func TestPluginLoad_RejectsUnknownVersion(t *testing.T) {
+	err := (&ipfilter.Plugin{}).Load(module.PluginContext{Version: "9.9"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "9.9")
+	assert.Contains(t, err.Error(), "ipfilter")
+}
+
+func TestPluginLoad_V10WithMissingDecodeConfigErrors(t *testing.T) {
+	err := (&ipfilter.Plugin{}).Load(module.PluginContext{Version: "1.0"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "DecodeConfig")
+}
+
+func TestPluginLoad_V10DecodeConfigErrorPropagated(t *testing.T) {
+	ctx := module.PluginContext{
+		Version: "1.0",
+		DecodeConfig: func(_ interface{}) error {
+			return errors.New("decode boom")
+		},
+	}
+	err := (&ipfilter.Plugin{}).Load(ctx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "decode boom")
+}
+
+func TestPluginLoad_V10TrustedProxiesErrors(t *testing.T) {
+	ctx := module.PluginContext{
+		Version: "1.0",
+		DecodeConfig: func(target interface{}) error {
+			target.(*entity.IPFilterPlugin).Config.TrustedProxies = []string{"10.0.0.0/8"}
+			return nil
+		},
+	}
+	err := (&ipfilter.Plugin{}).Load(ctx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "trusted_proxies")
+}
+
+func TestPluginLoad_V10InjectsDownstreamAndRules(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	controller := mock.NewMockController(mockCtrl)
+	controller.EXPECT().ListMetric().Return(nil)
+	controller.EXPECT().InjectHTTP(gomock.Any(), gomock.Any())
+	controller.EXPECT().InjectDownstream(gomock.Any())
+
+	appModule := mock.NewMockApp(mockCtrl)
+	appModule.EXPECT().Controller().Return(controller).AnyTimes()
+
+	ctx := module.PluginContext{
+		Version:      "1.0",
+		AppModule:    appModule,
+		DecodeConfig: func(target interface{}) error { return nil },
+	}
+	assert.Nil(t, (&ipfilter.Plugin{}).Load(ctx))
+}
@@ -0,0 +1,42 @@
+package ipfilter
+
+import (
+	"errors"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/ipfilter/entity"
+	"github.com/kodefluence/altair/plugin/ipfilter/module/filter"
+	"github.com/kodefluence/altair/plugin/ipfilter/module/filter/usecase"
+)
+
+// errMissingDecodeConfig guards against PluginContext values constructed
+// outside of plugin.runner.buildContext, which always populates DecodeConfig.
+var errMissingDecodeConfig = errors.New("ipfilter plugin: PluginContext.DecodeConfig is nil")
+
+func loadV1_0(ctx module.PluginContext) error {
+	if ctx.DecodeConfig == nil {
+		return errMissingDecodeConfig
+	}
+	var ipFilterPlugin entity.IPFilterPlugin
+	if err := ctx.DecodeConfig(&ipFilterPlugin); err != nil {
+		return err
+	}
+
+	if err := ipFilterPlugin.Validate(); err != nil {
+		return err
+	}
+
+	rules, err := ipFilterPlugin.Rules()
+	if err != nil {
+		return err
+	}
+
+	ipFilter, err := usecase.NewFilter(rules)
+	if err != nil {
+		return err
+	}
+
+	filter.Load(ctx.AppModule, ipFilter, ctx.ApiError)
+
+	return nil
+}
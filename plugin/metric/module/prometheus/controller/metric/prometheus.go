@@ -13,6 +13,9 @@ type PrometheusMetric struct {
 
 	histogramMetrics    map[string]*prometheus.HistogramVec
 	histogramMetricLock *sync.Mutex
+
+	gaugeMetrics    map[string]*prometheus.GaugeVec
+	gaugeMetricLock *sync.Mutex
 }
 
 func NewPrometheus() *PrometheusMetric {
@@ -22,6 +25,9 @@ func NewPrometheus() *PrometheusMetric {
 
 		histogramMetrics:    map[string]*prometheus.HistogramVec{},
 		histogramMetricLock: &sync.Mutex{},
+
+		gaugeMetrics:    map[string]*prometheus.GaugeVec{},
+		gaugeMetricLock: &sync.Mutex{},
 	}
 }
 
@@ -53,6 +59,20 @@ func (p *PrometheusMetric) InjectHistogram(metricName string, labels ...string)
 	p.histogramMetricLock.Unlock()
 }
 
+func (p *PrometheusMetric) InjectGauge(metricName string, labels ...string) {
+	if _, ok := p.gaugeMetrics[metricName]; ok {
+		return
+	}
+
+	p.gaugeMetricLock.Lock()
+	gaugeMetric := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: metricName,
+	}, labels)
+	_ = prometheus.Register(gaugeMetric)
+	p.gaugeMetrics[metricName] = gaugeMetric
+	p.gaugeMetricLock.Unlock()
+}
+
 func (p *PrometheusMetric) Inc(metricName string, labels map[string]string) error {
 	counterMetric, ok := p.counterMetrics[metricName]
 	if !ok {
@@ -84,3 +104,19 @@ func (p *PrometheusMetric) Observe(metricName string, value float64, labels map[
 
 	return nil
 }
+
+func (p *PrometheusMetric) Set(metricName string, value float64, labels map[string]string) error {
+	gaugeMetric, ok := p.gaugeMetrics[metricName]
+	if !ok {
+		return fmt.Errorf("Metric `%s` is not exists", metricName)
+	}
+
+	gauge, err := gaugeMetric.GetMetricWith(labels)
+	if err != nil {
+		return err
+	}
+
+	gauge.Set(value)
+
+	return nil
+}
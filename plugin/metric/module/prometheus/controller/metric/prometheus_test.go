@@ -31,6 +31,16 @@ func TestPrometheus(t *testing.T) {
 		})
 	})
 
+	t.Run("InjectGauge", func(t *testing.T) {
+		t.Run("Metric is not exists", func(t *testing.T) {
+			promMetric.InjectGauge("some_metric_gauge")
+		})
+
+		t.Run("Metric already exists", func(t *testing.T) {
+			promMetric.InjectGauge("some_metric_gauge")
+		})
+	})
+
 	t.Run("Inc", func(t *testing.T) {
 		t.Run("Run gracefully", func(t *testing.T) {
 			t.Run("Return nil", func(t *testing.T) {
@@ -72,4 +82,24 @@ func TestPrometheus(t *testing.T) {
 			})
 		})
 	})
+	t.Run("Set", func(t *testing.T) {
+		t.Run("Run gracefully", func(t *testing.T) {
+			t.Run("Return nil", func(t *testing.T) {
+				assert.Nil(t, promMetric.Set("some_metric_gauge", 1, nil))
+			})
+		})
+
+		t.Run("Metric is not exists", func(t *testing.T) {
+			t.Run("Return error", func(t *testing.T) {
+				assert.NotNil(t, promMetric.Set("some_metric_gauge_that_not_exists", 1, nil))
+			})
+		})
+
+		t.Run("Get metric with labels", func(t *testing.T) {
+			t.Run("Return error", func(t *testing.T) {
+				promMetric.InjectGauge("some_metric_gauge_with_labels", "label_a", "label_b")
+				assert.NotNil(t, promMetric.Set("some_metric_gauge_with_labels", 1, nil))
+			})
+		})
+	})
 }
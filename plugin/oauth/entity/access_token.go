@@ -0,0 +1,34 @@
+package entity
+
+import "strings"
+
+// AccessTokenVerifier verifies a jwt access token, it is nil when access tokens are opaque.
+type AccessTokenVerifier interface {
+	VerifyAccessToken(token string) (AccessTokenClaims, error)
+}
+
+// AccessTokenID returns the oauth_access_tokens id of a jwt access token. It
+// reports false for an opaque token, which is stored as the token itself.
+func AccessTokenID(verifier AccessTokenVerifier, token string) (int, bool) {
+	if verifier == nil {
+		return 0, false
+	}
+
+	claims, err := verifier.VerifyAccessToken(token)
+	if err != nil {
+		return 0, false
+	}
+
+	return claims.OauthAccessTokenID, true
+}
+
+// HasScope reports whether scope is one of the space separated scopes.
+func HasScope(scopes, scope string) bool {
+	for _, s := range strings.Fields(scopes) {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
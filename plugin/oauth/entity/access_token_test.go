@@ -0,0 +1,56 @@
+package entity_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+type accessTokenVerifier func(token string) (entity.AccessTokenClaims, error)
+
+func (f accessTokenVerifier) VerifyAccessToken(token string) (entity.AccessTokenClaims, error) {
+	return f(token)
+}
+
+func TestAccessToken(t *testing.T) {
+	t.Run("AccessTokenID", func(t *testing.T) {
+		t.Run("Given jwt access token", func(t *testing.T) {
+			t.Run("Return the id of its claims", func(t *testing.T) {
+				verifier := accessTokenVerifier(func(token string) (entity.AccessTokenClaims, error) {
+					return entity.AccessTokenClaims{ID: "jti", OauthAccessTokenID: 5}, nil
+				})
+
+				ID, ok := entity.AccessTokenID(verifier, "header.claims.signature")
+				assert.True(t, ok)
+				assert.Equal(t, 5, ID)
+			})
+		})
+
+		t.Run("Given opaque token", func(t *testing.T) {
+			t.Run("Return false", func(t *testing.T) {
+				verifier := accessTokenVerifier(func(token string) (entity.AccessTokenClaims, error) {
+					return entity.AccessTokenClaims{}, errors.New("invalid jwt")
+				})
+
+				_, ok := entity.AccessTokenID(verifier, "opaque-token")
+				assert.False(t, ok)
+			})
+		})
+
+		t.Run("Given no verifier", func(t *testing.T) {
+			t.Run("Return false", func(t *testing.T) {
+				_, ok := entity.AccessTokenID(nil, "opaque-token")
+				assert.False(t, ok)
+			})
+		})
+	})
+
+	t.Run("HasScope", func(t *testing.T) {
+		assert.True(t, entity.HasScope("openid users", entity.ScopeOpenID))
+		assert.False(t, entity.HasScope("openidx users", entity.ScopeOpenID))
+		assert.False(t, entity.HasScope("", entity.ScopeOpenID))
+	})
+}
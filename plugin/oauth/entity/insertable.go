@@ -13,12 +13,15 @@ type OauthAccessTokenInsertable struct {
 
 // OauthAccessGrantInsertable use for creating new access grant data
 type OauthAccessGrantInsertable struct {
-	OauthApplicationID int
-	ResourceOwnerID    int
-	Scopes             interface{}
-	Code               string
-	RedirectURI        interface{}
-	ExpiresIn          time.Time
+	OauthApplicationID  int
+	ResourceOwnerID     int
+	Scopes              interface{}
+	Code                string
+	RedirectURI         interface{}
+	CodeChallenge       interface{}
+	CodeChallengeMethod interface{}
+	Nonce               interface{}
+	ExpiresIn           time.Time
 }
 
 // OauthRefreshTokenInsertable use for creating new refresh token data
@@ -36,10 +39,12 @@ type OauthApplicationInsertable struct {
 	Scopes       interface{}
 	ClientUID    string
 	ClientSecret string
+	PKCERequired bool
 }
 
 // OauthApplicationUpdateable use for updating application data
 type OauthApplicationUpdateable struct {
-	Description interface{}
-	Scopes      interface{}
+	Description  interface{}
+	Scopes       interface{}
+	PKCERequired interface{}
 }
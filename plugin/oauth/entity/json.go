@@ -33,6 +33,7 @@ type OauthAccessTokenJSON struct {
 	CreatedAt          *time.Time             `json:"created_at"`
 	RevokedAT          *time.Time             `json:"revoked_at"`
 	RefreshToken       *OauthRefreshTokenJSON `json:"refresh_token,omitempty"`
+	IDToken            *string                `json:"id_token,omitempty"`
 }
 
 type OauthRefreshTokenJSON struct {
@@ -49,15 +50,17 @@ type OauthApplicationJSON struct {
 	Description  *string    `json:"description"`
 	Scopes       *string    `json:"scopes"`
 	ClientUID    *string    `json:"client_uid"`
-	ClientSecret *string    `json:"client_secret"`
+	ClientSecret *string    `json:"client_secret,omitempty"`
+	PKCERequired *bool      `json:"pkce_required"`
 	RevokedAt    *time.Time `json:"revoked_at"`
 	CreatedAt    *time.Time `json:"created_at"`
 	UpdatedAt    *time.Time `json:"updated_at"`
 }
 
 type OauthApplicationUpdateJSON struct {
-	Description *string `json:"description"`
-	Scopes      *string `json:"scopes"`
+	Description  *string `json:"description"`
+	Scopes       *string `json:"scopes"`
+	PKCERequired *bool   `json:"pkce_required"`
 }
 
 type AuthorizationRequestJSON struct {
@@ -70,12 +73,45 @@ type AuthorizationRequestJSON struct {
 
 	RedirectURI *string `json:"redirect_uri"`
 	Scopes      *string `json:"scopes"`
+
+	CodeChallenge       *string `json:"code_challenge"`
+	CodeChallengeMethod *string `json:"code_challenge_method"`
+
+	Nonce *string `json:"nonce"`
 }
 
 type RevokeAccessTokenRequestJSON struct {
 	Token *string `json:"token"`
 }
 
+type IntrospectionRequestJSON struct {
+	Token         *string `json:"token"`
+	TokenTypeHint *string `json:"token_type_hint"`
+
+	ClientUID    *string `json:"client_uid"`
+	ClientSecret *string `json:"client_secret"`
+}
+
+// IntrospectionJSON is the RFC 7662 introspection response of a token. An
+// inactive token only has `active` set, token_type is either `access_token` or
+// `refresh_token`, the same values as token_type_hint.
+type IntrospectionJSON struct {
+	Active    bool    `json:"active"`
+	Scope     *string `json:"scope,omitempty"`
+	ClientID  *string `json:"client_id,omitempty"`
+	Sub       *string `json:"sub,omitempty"`
+	Exp       *int    `json:"exp,omitempty"`
+	Iat       *int    `json:"iat,omitempty"`
+	TokenType *string `json:"token_type,omitempty"`
+}
+
+// RehashJSON counts the plaintext rows replaced by their keyed hash, per table
+type RehashJSON struct {
+	OauthApplications  int `json:"oauth_applications"`
+	OauthAccessTokens  int `json:"oauth_access_tokens"`
+	OauthRefreshTokens int `json:"oauth_refresh_tokens"`
+}
+
 type AccessTokenRequestJSON struct {
 	GrantType *string `json:"grant_type"`
 
@@ -84,8 +120,68 @@ type AccessTokenRequestJSON struct {
 
 	RefreshToken *string `json:"refresh_token"`
 
-	Code        *string `json:"code"`
-	RedirectURI *string `json:"redirect_uri"`
+	Code         *string `json:"code"`
+	RedirectURI  *string `json:"redirect_uri"`
+	CodeVerifier *string `json:"code_verifier"`
 
 	Scope *string `json:"scope"`
 }
+
+// AccessTokenClaims are the claims of a jwt access token. The ID is a random
+// identifier, the stored token never leaves the database. OauthAccessTokenID
+// points to the row in oauth_access_tokens, so a jwt can be revoked or
+// introspected like an opaque token.
+type AccessTokenClaims struct {
+	Issuer             string `json:"iss,omitempty"`
+	Subject            string `json:"sub"`
+	OauthApplicationID int    `json:"oauth_application_id"`
+	Scope              string `json:"scope,omitempty"`
+	ExpiresAt          int64  `json:"exp"`
+	IssuedAt           int64  `json:"iat"`
+	ID                 string `json:"jti"`
+	OauthAccessTokenID int    `json:"oauth_access_token_id"`
+}
+
+// IDTokenClaims are the claims of an openid connect id token, the audience is
+// the client_uid of the application.
+type IDTokenClaims struct {
+	Issuer          string `json:"iss"`
+	Subject         string `json:"sub"`
+	Audience        string `json:"aud"`
+	ExpiresAt       int64  `json:"exp"`
+	IssuedAt        int64  `json:"iat"`
+	Nonce           string `json:"nonce,omitempty"`
+	AccessTokenHash string `json:"at_hash,omitempty"`
+}
+
+type OpenIDConfigurationJSON struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+type JSONWebKeySetJSON struct {
+	Keys []JSONWebKeyJSON `json:"keys"`
+}
+
+type JSONWebKeyJSON struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
@@ -20,6 +20,7 @@ type OauthApplication struct {
 	Scopes       sql.NullString
 	ClientUID    string
 	ClientSecret string
+	PKCERequired bool
 	RevokedAt    sql.NullTime
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
@@ -27,15 +28,18 @@ type OauthApplication struct {
 
 // OauthAccessGrant is a struct returned from interfaces.OauthAccessGrantModel
 type OauthAccessGrant struct {
-	ID                 int
-	OauthApplicationID int
-	ResourceOwnerID    int
-	Code               string
-	RedirectURI        sql.NullString
-	Scopes             sql.NullString
-	ExpiresIn          time.Time
-	CreatedAt          time.Time
-	RevokedAT          sql.NullTime
+	ID                  int
+	OauthApplicationID  int
+	ResourceOwnerID     int
+	Code                string
+	RedirectURI         sql.NullString
+	CodeChallenge       sql.NullString
+	CodeChallengeMethod sql.NullString
+	Nonce               sql.NullString
+	Scopes              sql.NullString
+	ExpiresIn           time.Time
+	CreatedAt           time.Time
+	RevokedAT           sql.NullTime
 }
 
 // OauthAccessToken is a struct returned from interfaces.OauthAccessTokenModel
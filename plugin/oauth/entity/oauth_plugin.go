@@ -1,6 +1,26 @@
 package entity
 
-import "time"
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	TokenFormatOpaque = "opaque"
+	TokenFormatJWT    = "jwt"
+
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+
+	// ScopeOpenID requests an id token alongside the access token.
+	ScopeOpenID = "openid"
+
+	// MinTokenHashPepperLength is the minimum length of the pepper keying
+	// the hash of stored tokens and client secrets.
+	MinTokenHashPepperLength = 32
+)
 
 // OauthPlugin holds all config variables
 type OauthPlugin struct {
@@ -9,7 +29,8 @@ type OauthPlugin struct {
 
 // PluginConfig holds all config variables for oauth plugin
 type PluginConfig struct {
-	Database string `yaml:"database"`
+	Database        string `yaml:"database"`
+	TokenHashPepper string `yaml:"token_hash_pepper"`
 
 	AccessTokenTimeoutRaw       string `yaml:"access_token_timeout"`
 	AuthorizationCodeTimeoutRaw string `yaml:"authorization_code_timeout"`
@@ -22,6 +43,37 @@ type PluginConfig struct {
 	ImplicitGrant struct {
 		Active bool `yaml:"active"`
 	} `yaml:"implicit_grant"`
+
+	TokenFormat string    `yaml:"token_format"`
+	JWT         JWTConfig `yaml:"jwt"`
+
+	OpenID OpenIDConfig `yaml:"openid"`
+}
+
+// OpenIDConfig turns the oauth plugin into an openid connect provider. Id
+// tokens are signed with the jwt keys and carry the jwt issuer, userinfo
+// claims are fetched from the identity upstream.
+type OpenIDConfig struct {
+	Active             bool   `yaml:"active"`
+	UserinfoUpstream   string `yaml:"userinfo_upstream"`
+	UserinfoTimeoutRaw string `yaml:"userinfo_timeout"`
+}
+
+// JWTConfig holds the keys signing access tokens when token_format is jwt.
+// Every key is published in the JWKS and accepted when verifying a token, the
+// signing key signs new tokens. A key with only a public key is kept to verify
+// the tokens it signed before it was rotated out.
+type JWTConfig struct {
+	Issuer     string         `yaml:"issuer"`
+	SigningKey string         `yaml:"signing_key"`
+	Keys       []JWTKeyConfig `yaml:"keys"`
+}
+
+type JWTKeyConfig struct {
+	Kid        string `yaml:"kid"`
+	Algorithm  string `yaml:"algorithm"`
+	PrivateKey string `yaml:"private_key"`
+	PublicKey  string `yaml:"public_key"`
 }
 
 type RefreshTokenConfig struct {
@@ -33,6 +85,21 @@ func (o OauthPlugin) DatabaseInstance() string {
 	return o.Config.Database
 }
 
+// ValidateTokenHashPepper checks that the pepper keying the hash of stored
+// tokens and client secrets is set and long enough, so credentials are never
+// stored as plaintext.
+func (o OauthPlugin) ValidateTokenHashPepper() error {
+	if o.Config.TokenHashPepper == "" {
+		return fmt.Errorf("oauth token_hash_pepper must be set")
+	}
+
+	if len(o.Config.TokenHashPepper) < MinTokenHashPepperLength {
+		return fmt.Errorf("oauth token_hash_pepper must be at least %d characters", MinTokenHashPepperLength)
+	}
+
+	return nil
+}
+
 func (o OauthPlugin) AccessTokenTimeout() (time.Duration, error) {
 	return time.ParseDuration(o.Config.AccessTokenTimeoutRaw)
 }
@@ -44,3 +111,108 @@ func (o OauthPlugin) AuthorizationCodeTimeout() (time.Duration, error) {
 func (o OauthPlugin) RefreshTokenTimeout() (time.Duration, error) {
 	return time.ParseDuration(o.Config.RefreshToken.Timeout)
 }
+
+// TokenFormat returns the format of issued access tokens, opaque by default.
+func (o OauthPlugin) TokenFormat() string {
+	if o.Config.TokenFormat == "" {
+		return TokenFormatOpaque
+	}
+
+	return o.Config.TokenFormat
+}
+
+// ValidateTokenFormat checks the token format and, for jwt, that the signing
+// key exists and can sign.
+func (o OauthPlugin) ValidateTokenFormat() error {
+	switch o.TokenFormat() {
+	case TokenFormatOpaque:
+		return nil
+	case TokenFormatJWT:
+		return o.validateJWTKeys()
+	}
+
+	return fmt.Errorf("oauth token_format `%s` is not supported, use opaque or jwt", o.Config.TokenFormat)
+}
+
+// ValidateOpenID checks that an active openid provider has an issuer, a
+// signing key and an identity upstream.
+func (o OauthPlugin) ValidateOpenID() error {
+	if !o.Config.OpenID.Active {
+		return nil
+	}
+
+	if o.Config.JWT.Issuer == "" {
+		return fmt.Errorf("oauth openid requires jwt issuer")
+	}
+
+	upstream, err := url.Parse(o.Config.OpenID.UserinfoUpstream)
+	if err != nil || !upstream.IsAbs() {
+		return fmt.Errorf("oauth openid userinfo_upstream `%s` must be an absolute url", o.Config.OpenID.UserinfoUpstream)
+	}
+
+	if _, err := o.UserinfoTimeout(); err != nil {
+		return err
+	}
+
+	return o.validateJWTKeys()
+}
+
+// UserinfoTimeout returns the timeout of the identity upstream call, 5 seconds by default.
+func (o OauthPlugin) UserinfoTimeout() (time.Duration, error) {
+	if o.Config.OpenID.UserinfoTimeoutRaw == "" {
+		return time.Second * 5, nil
+	}
+
+	return time.ParseDuration(o.Config.OpenID.UserinfoTimeoutRaw)
+}
+
+// UsesKeySet reports whether the jwt keys are needed, either to sign access
+// tokens or id tokens.
+func (o OauthPlugin) UsesKeySet() bool {
+	return o.TokenFormat() == TokenFormatJWT || o.Config.OpenID.Active
+}
+
+func (o OauthPlugin) validateJWTKeys() error {
+	if len(o.Config.JWT.Keys) == 0 {
+		return fmt.Errorf("oauth jwt token format requires at least one key")
+	}
+
+	kids := map[string]JWTKeyConfig{}
+	for _, key := range o.Config.JWT.Keys {
+		if key.Kid == "" {
+			return fmt.Errorf("oauth jwt key kid cannot be empty")
+		}
+
+		if _, ok := kids[key.Kid]; ok {
+			return fmt.Errorf("oauth jwt key `%s` is declared twice", key.Kid)
+		}
+
+		switch key.Algorithm {
+		case AlgorithmRS256, AlgorithmES256, AlgorithmEdDSA:
+		default:
+			return fmt.Errorf("oauth jwt key `%s` algorithm `%s` is not supported, use RS256, ES256 or EdDSA", key.Kid, key.Algorithm)
+		}
+
+		if key.PrivateKey == "" && key.PublicKey == "" {
+			return fmt.Errorf("oauth jwt key `%s` requires private_key or public_key", key.Kid)
+		}
+
+		kids[key.Kid] = key
+	}
+
+	if kids[o.SigningKid()].PrivateKey == "" {
+		return fmt.Errorf("oauth jwt signing key `%s` requires private_key", o.SigningKid())
+	}
+
+	return nil
+}
+
+// SigningKid returns the kid of the key signing new tokens, the first key by
+// default.
+func (o OauthPlugin) SigningKid() string {
+	if o.Config.JWT.SigningKey != "" || len(o.Config.JWT.Keys) == 0 {
+		return o.Config.JWT.SigningKey
+	}
+
+	return o.Config.JWT.Keys[0].Kid
+}
@@ -86,4 +86,175 @@ func TestOauthPlugin(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("ValidateTokenHashPepper", func(t *testing.T) {
+		t.Run("Given empty pepper", func(t *testing.T) {
+			t.Run("Return error", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+
+				assert.Equal(t, "oauth token_hash_pepper must be set", oauthPlugin.ValidateTokenHashPepper().Error())
+			})
+		})
+
+		t.Run("Given pepper shorter than the minimum length", func(t *testing.T) {
+			t.Run("Return error", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+				oauthPlugin.Config.TokenHashPepper = "short-pepper"
+
+				assert.Equal(t, "oauth token_hash_pepper must be at least 32 characters", oauthPlugin.ValidateTokenHashPepper().Error())
+			})
+		})
+
+		t.Run("Given long enough pepper", func(t *testing.T) {
+			t.Run("Return nil", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+				oauthPlugin.Config.TokenHashPepper = "4f0c9d2e7b1a8c3f6e5d4b2a1908f7e6"
+
+				assert.Nil(t, oauthPlugin.ValidateTokenHashPepper())
+			})
+		})
+	})
+
+	t.Run("ValidateTokenFormat", func(t *testing.T) {
+		t.Run("Given empty token format", func(t *testing.T) {
+			t.Run("Return opaque and nil", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+
+				assert.Equal(t, entity.TokenFormatOpaque, oauthPlugin.TokenFormat())
+				assert.Nil(t, oauthPlugin.ValidateTokenFormat())
+			})
+		})
+
+		t.Run("Given unknown token format", func(t *testing.T) {
+			t.Run("Return error", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+				oauthPlugin.Config.TokenFormat = "paseto"
+
+				assert.NotNil(t, oauthPlugin.ValidateTokenFormat())
+			})
+		})
+
+		t.Run("Given jwt token format", func(t *testing.T) {
+			t.Run("With signing key and retired key, return nil", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+				oauthPlugin.Config.TokenFormat = entity.TokenFormatJWT
+				oauthPlugin.Config.JWT.SigningKey = "new"
+				oauthPlugin.Config.JWT.Keys = []entity.JWTKeyConfig{
+					{Kid: "old", Algorithm: entity.AlgorithmES256, PublicKey: "old.pub"},
+					{Kid: "new", Algorithm: entity.AlgorithmEdDSA, PrivateKey: "new.pem"},
+				}
+
+				assert.Equal(t, "new", oauthPlugin.SigningKid())
+				assert.Nil(t, oauthPlugin.ValidateTokenFormat())
+			})
+
+			t.Run("Without signing key, the first key sign the token", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+				oauthPlugin.Config.TokenFormat = entity.TokenFormatJWT
+				oauthPlugin.Config.JWT.Keys = []entity.JWTKeyConfig{
+					{Kid: "first", Algorithm: entity.AlgorithmRS256, PrivateKey: "first.pem"},
+				}
+
+				assert.Equal(t, "first", oauthPlugin.SigningKid())
+				assert.Nil(t, oauthPlugin.ValidateTokenFormat())
+			})
+
+			t.Run("When there is no key, return error", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+				oauthPlugin.Config.TokenFormat = entity.TokenFormatJWT
+
+				assert.NotNil(t, oauthPlugin.ValidateTokenFormat())
+			})
+
+			t.Run("When kid is declared twice, return error", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+				oauthPlugin.Config.TokenFormat = entity.TokenFormatJWT
+				oauthPlugin.Config.JWT.Keys = []entity.JWTKeyConfig{
+					{Kid: "key", Algorithm: entity.AlgorithmRS256, PrivateKey: "first.pem"},
+					{Kid: "key", Algorithm: entity.AlgorithmRS256, PrivateKey: "second.pem"},
+				}
+
+				assert.NotNil(t, oauthPlugin.ValidateTokenFormat())
+			})
+
+			t.Run("When algorithm is not supported, return error", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+				oauthPlugin.Config.TokenFormat = entity.TokenFormatJWT
+				oauthPlugin.Config.JWT.Keys = []entity.JWTKeyConfig{
+					{Kid: "key", Algorithm: "HS256", PrivateKey: "key.pem"},
+				}
+
+				assert.NotNil(t, oauthPlugin.ValidateTokenFormat())
+			})
+
+			t.Run("When signing key has no private key, return error", func(t *testing.T) {
+				oauthPlugin := entity.OauthPlugin{}
+				oauthPlugin.Config.TokenFormat = entity.TokenFormatJWT
+				oauthPlugin.Config.JWT.Keys = []entity.JWTKeyConfig{
+					{Kid: "key", Algorithm: entity.AlgorithmES256, PublicKey: "key.pub"},
+				}
+
+				assert.NotNil(t, oauthPlugin.ValidateTokenFormat())
+			})
+		})
+	})
+
+	t.Run("ValidateOpenID", func(t *testing.T) {
+		newOpenIDPlugin := func() entity.OauthPlugin {
+			oauthPlugin := entity.OauthPlugin{}
+			oauthPlugin.Config.OpenID.Active = true
+			oauthPlugin.Config.OpenID.UserinfoUpstream = "http://identity:8080/userinfo"
+			oauthPlugin.Config.JWT.Issuer = "https://auth.example.com"
+			oauthPlugin.Config.JWT.Keys = []entity.JWTKeyConfig{
+				{Kid: "key", Algorithm: entity.AlgorithmES256, PrivateKey: "key.pem"},
+			}
+			return oauthPlugin
+		}
+
+		t.Run("Given inactive openid, return nil and no key set is used", func(t *testing.T) {
+			oauthPlugin := entity.OauthPlugin{}
+
+			assert.Nil(t, oauthPlugin.ValidateOpenID())
+			assert.False(t, oauthPlugin.UsesKeySet())
+		})
+
+		t.Run("Given active openid with opaque access token, return nil and the key set is used", func(t *testing.T) {
+			oauthPlugin := newOpenIDPlugin()
+
+			assert.Nil(t, oauthPlugin.ValidateOpenID())
+			assert.True(t, oauthPlugin.UsesKeySet())
+
+			timeout, err := oauthPlugin.UserinfoTimeout()
+			assert.Nil(t, err)
+			assert.Equal(t, time.Second*5, timeout)
+		})
+
+		t.Run("Given active openid without issuer, return error", func(t *testing.T) {
+			oauthPlugin := newOpenIDPlugin()
+			oauthPlugin.Config.JWT.Issuer = ""
+
+			assert.NotNil(t, oauthPlugin.ValidateOpenID())
+		})
+
+		t.Run("Given active openid with relative userinfo upstream, return error", func(t *testing.T) {
+			oauthPlugin := newOpenIDPlugin()
+			oauthPlugin.Config.OpenID.UserinfoUpstream = "/userinfo"
+
+			assert.NotNil(t, oauthPlugin.ValidateOpenID())
+		})
+
+		t.Run("Given active openid with wrong userinfo timeout, return error", func(t *testing.T) {
+			oauthPlugin := newOpenIDPlugin()
+			oauthPlugin.Config.OpenID.UserinfoTimeoutRaw = "abc"
+
+			assert.NotNil(t, oauthPlugin.ValidateOpenID())
+		})
+
+		t.Run("Given active openid without key, return error", func(t *testing.T) {
+			oauthPlugin := newOpenIDPlugin()
+			oauthPlugin.Config.JWT.Keys = nil
+
+			assert.NotNil(t, oauthPlugin.ValidateOpenID())
+		})
+	})
 }
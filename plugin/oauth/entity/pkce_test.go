@@ -0,0 +1,61 @@
+package entity_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+func TestPKCE(t *testing.T) {
+	// Example verifier and challenge from RFC 7636 appendix B.
+	codeVerifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	codeChallenge := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	t.Run("ValidPKCEValue", func(t *testing.T) {
+		t.Run("Given unreserved characters within 43 and 128 characters", func(t *testing.T) {
+			t.Run("Return true", func(t *testing.T) {
+				assert.True(t, entity.ValidPKCEValue(codeVerifier))
+				assert.True(t, entity.ValidPKCEValue(strings.Repeat("a.~_", 32)))
+			})
+		})
+
+		t.Run("Given invalid value", func(t *testing.T) {
+			t.Run("Return false", func(t *testing.T) {
+				assert.False(t, entity.ValidPKCEValue(codeVerifier[:42]))
+				assert.False(t, entity.ValidPKCEValue(strings.Repeat("a", 129)))
+				assert.False(t, entity.ValidPKCEValue(codeVerifier[:42]+"+"))
+			})
+		})
+	})
+
+	t.Run("VerifyCodeVerifier", func(t *testing.T) {
+		t.Run("Given S256 challenge", func(t *testing.T) {
+			t.Run("Return true for the matching verifier", func(t *testing.T) {
+				assert.True(t, entity.VerifyCodeVerifier(codeVerifier, codeChallenge, entity.CodeChallengeMethodS256))
+			})
+
+			t.Run("Return false for other verifier", func(t *testing.T) {
+				assert.False(t, entity.VerifyCodeVerifier(strings.Repeat("a", 43), codeChallenge, entity.CodeChallengeMethodS256))
+			})
+		})
+
+		t.Run("Given plain challenge", func(t *testing.T) {
+			t.Run("Return true for the same verifier", func(t *testing.T) {
+				assert.True(t, entity.VerifyCodeVerifier(codeVerifier, codeVerifier, entity.CodeChallengeMethodPlain))
+			})
+
+			t.Run("Return false for the S256 verifier", func(t *testing.T) {
+				assert.False(t, entity.VerifyCodeVerifier(codeVerifier, codeChallenge, entity.CodeChallengeMethodPlain))
+			})
+		})
+
+		t.Run("Given invalid verifier", func(t *testing.T) {
+			t.Run("Return false", func(t *testing.T) {
+				assert.False(t, entity.VerifyCodeVerifier("short", "short", entity.CodeChallengeMethodPlain))
+			})
+		})
+	})
+}
@@ -0,0 +1,6 @@
+-- Irreversible once `token` holds hashed values: the keyed hash can not be turned back
+-- into the plaintext, and without the flag every hashed row is read as plaintext and
+-- never matches again. Only run it when `select count(*) from oauth_refresh_tokens where token_hashed = 1`
+-- returns 0.
+ALTER TABLE `oauth_refresh_tokens`
+  DROP COLUMN `token_hashed`;
\ No newline at end of file
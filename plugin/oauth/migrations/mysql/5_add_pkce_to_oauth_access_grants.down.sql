@@ -0,0 +1,3 @@
+ALTER TABLE `oauth_access_grants`
+  DROP COLUMN `code_challenge_method`,
+  DROP COLUMN `code_challenge`;
\ No newline at end of file
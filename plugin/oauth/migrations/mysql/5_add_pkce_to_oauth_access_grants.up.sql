@@ -0,0 +1,3 @@
+ALTER TABLE `oauth_access_grants`
+  ADD COLUMN `code_challenge` varchar(255) DEFAULT NULL AFTER `redirect_uri`,
+  ADD COLUMN `code_challenge_method` varchar(12) DEFAULT NULL AFTER `code_challenge`;
\ No newline at end of file
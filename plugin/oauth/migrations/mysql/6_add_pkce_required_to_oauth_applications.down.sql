@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_applications`
+  DROP COLUMN `pkce_required`;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_access_grants`
+  DROP COLUMN `nonce`;
\ No newline at end of file
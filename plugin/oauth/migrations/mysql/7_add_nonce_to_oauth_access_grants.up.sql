@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_access_grants`
+  ADD COLUMN `nonce` varchar(255) DEFAULT NULL AFTER `code_challenge_method`;
\ No newline at end of file
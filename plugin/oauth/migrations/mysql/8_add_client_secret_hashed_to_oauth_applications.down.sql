@@ -0,0 +1,6 @@
+-- Irreversible once `client_secret` holds hashed values: the keyed hash can not be turned back
+-- into the plaintext, and without the flag every hashed row is read as plaintext and
+-- never matches again. Only run it when `select count(*) from oauth_applications where client_secret_hashed = 1`
+-- returns 0.
+ALTER TABLE `oauth_applications`
+  DROP COLUMN `client_secret_hashed`;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_applications`
+  ADD COLUMN `client_secret_hashed` tinyint(1) NOT NULL DEFAULT 0 AFTER `client_secret`;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE `oauth_access_tokens`
+  ADD COLUMN `token_hashed` tinyint(1) NOT NULL DEFAULT 0 AFTER `token`;
\ No newline at end of file
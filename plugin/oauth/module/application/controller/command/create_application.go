@@ -20,6 +20,7 @@ type CreateOauthApplication struct {
 	flagOwnerType string
 	flagScope     string
 	flagDesc      string
+	flagPKCE      bool
 }
 
 // NewCreateOauthApplication return struct of CreateOauthApplication
@@ -62,10 +63,11 @@ func (m *CreateOauthApplication) Run(cmd *cobra.Command, args []string) {
 	}
 
 	oauthApplicationJSON := entity.OauthApplicationJSON{
-		OwnerID:     ownerID,
-		OwnerType:   util.ValueToPointer(m.flagOwnerType),
-		Description: description,
-		Scopes:      scope,
+		OwnerID:      ownerID,
+		OwnerType:    util.ValueToPointer(m.flagOwnerType),
+		Description:  description,
+		Scopes:       scope,
+		PKCERequired: util.ValueToPointer(m.flagPKCE),
 	}
 
 	oauthApplicationJSON, err := m.applicationManager.Create(kontext.Fabricate(), oauthApplicationJSON)
@@ -84,4 +86,5 @@ func (m *CreateOauthApplication) ModifyFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&m.flagOwnerType, "owner-type", "", "Owner Type. Enum: confidential, public")
 	flags.StringVar(&m.flagScope, "scope", "", "Scope of the application, separated by space")
 	flags.StringVar(&m.flagDesc, "desc", "", "Description of the application")
+	flags.BoolVar(&m.flagPKCE, "pkce-required", false, "Require PKCE on the authorization code grant of the application")
 }
@@ -39,6 +39,7 @@ func TestCreateApplication(t *testing.T) {
 			assert.Equal(t, 1, util.PointerToValue(e.OwnerID))
 			assert.Equal(t, "read write", util.PointerToValue(e.Scopes))
 			assert.Equal(t, "confidential", util.PointerToValue(e.OwnerType))
+			assert.True(t, util.PointerToValue(e.PKCERequired))
 			return oauthApplicationJSON, nil
 		})
 
@@ -48,7 +49,7 @@ func TestCreateApplication(t *testing.T) {
 		appController.InjectCommand(command)
 
 		// Given
-		cmd.SetArgs([]string{"oauth/application:create", "--owner-id", "1", "--scope", "read write", "--owner-type", "confidential", "--desc", "test"})
+		cmd.SetArgs([]string{"oauth/application:create", "--owner-id", "1", "--scope", "read write", "--owner-type", "confidential", "--desc", "test", "--pkce-required"})
 
 		// When
 		err := cmd.Execute()
@@ -30,6 +30,12 @@ func (o *ApplicationValidation) Name() string {
 	return "application-validation-plugin"
 }
 
+// ReadsBody tells the router to buffer the request body, client credentials
+// can be sent in the json body
+func (o *ApplicationValidation) ReadsBody(r module.RouterPath) bool {
+	return r.GetAuth() == "oauth_application"
+}
+
 // Intervene current request to check application_uid and application_secret
 func (o *ApplicationValidation) Intervene(c *gin.Context, proxyReq *http.Request, r module.RouterPath) error {
 	if r.GetAuth() != "oauth_application" {
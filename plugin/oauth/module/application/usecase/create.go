@@ -21,7 +21,9 @@ func (am *ApplicationManager) Create(ktx kontext.Context, e entity.OauthApplicat
 		return entity.OauthApplicationJSON{}, err
 	}
 
-	id, err := am.oauthApplicationRepo.Create(ktx, am.formatter.OauthApplicationInsertable(e), am.sqldb)
+	oauthApplicationInsertable := am.formatter.OauthApplicationInsertable(e)
+
+	id, err := am.oauthApplicationRepo.Create(ktx, oauthApplicationInsertable, am.sqldb)
 	if err != nil {
 		log.Error().
 			Err(err).
@@ -34,5 +36,12 @@ func (am *ApplicationManager) Create(ktx kontext.Context, e entity.OauthApplicat
 			jsonapi.BuildResponse(am.apiError.InternalServerError(ktx)).Errors
 	}
 
-	return am.One(ktx, id)
+	oauthApplicationJSON, jsonapiErr := am.One(ktx, id)
+	if jsonapiErr != nil {
+		return entity.OauthApplicationJSON{}, jsonapiErr
+	}
+
+	// Only the hash of the client secret is stored, this is the one response carrying it
+	oauthApplicationJSON.ClientSecret = &oauthApplicationInsertable.ClientSecret
+	return oauthApplicationJSON, nil
 }
@@ -41,9 +41,11 @@ func TestCreate(t *testing.T) {
 			}
 			oauthApplicationJSON := formatterUsecase.Application(oauthApplication)
 
+			var clientSecret string
 			gomock.InOrder(
 				oauthApplicationRepository.EXPECT().Create(ktx, gomock.Any(), sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthApplicationInsertable, tx db.TX) (int, exception.Exception) {
 					assert.Equal(t, oauthApplication.OwnerType, data.OwnerType)
+					clientSecret = data.ClientSecret
 					return oauthApplication.ID, nil
 				}),
 				oauthApplicationRepository.EXPECT().One(ktx, oauthApplication.ID, sqldb).Return(oauthApplication, nil),
@@ -52,6 +54,9 @@ func TestCreate(t *testing.T) {
 			result, err := applicationManager.Create(ktx, oauthApplicationJSON)
 
 			assert.Nil(t, err)
+			assert.NotEqual(t, "", clientSecret)
+
+			oauthApplicationJSON.ClientSecret = &clientSecret
 			assert.Equal(t, oauthApplicationJSON, result)
 		})
 
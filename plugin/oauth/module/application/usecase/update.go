@@ -12,8 +12,9 @@ import (
 // Update oauth application
 func (am *ApplicationManager) Update(ktx kontext.Context, ID int, e entity.OauthApplicationUpdateJSON) (entity.OauthApplicationJSON, jsonapi.Errors) {
 	err := am.oauthApplicationRepo.Update(ktx, ID, entity.OauthApplicationUpdateable{
-		Description: e.Description,
-		Scopes:      e.Scopes,
+		Description:  e.Description,
+		Scopes:       e.Scopes,
+		PKCERequired: e.PKCERequired,
 	}, am.sqldb)
 	if err != nil {
 		log.Error().
@@ -38,14 +38,16 @@ func TestUpdate(t *testing.T) {
 					oauthApplicationRepository := mock.NewMockOauthApplicationRepository(mockCtrl)
 
 					data := entity.OauthApplicationUpdateJSON{
-						Description: util.ValueToPointer("New description"),
-						Scopes:      util.ValueToPointer("users public"),
+						Description:  util.ValueToPointer("New description"),
+						Scopes:       util.ValueToPointer("users public"),
+						PKCERequired: util.ValueToPointer(true),
 					}
 
 					gomock.InOrder(
 						oauthApplicationRepository.EXPECT().Update(gomock.Any(), oauthApplication.ID, entity.OauthApplicationUpdateable{
-							Description: data.Description,
-							Scopes:      data.Scopes,
+							Description:  data.Description,
+							Scopes:       data.Scopes,
+							PKCERequired: data.PKCERequired,
 						}, gomock.Any()).Return(nil),
 						oauthApplicationRepository.EXPECT().One(ktx, 1, sqldb).Return(oauthApplication, nil),
 					)
@@ -75,8 +77,9 @@ func TestUpdate(t *testing.T) {
 
 					gomock.InOrder(
 						oauthApplicationRepository.EXPECT().Update(gomock.Any(), oauthApplication.ID, entity.OauthApplicationUpdateable{
-							Description: data.Description,
-							Scopes:      data.Scopes,
+							Description:  data.Description,
+							Scopes:       data.Scopes,
+							PKCERequired: data.PKCERequired,
 						}, gomock.Any()).Return(exception.Throw(errors.New("unexpected"))),
 					)
 
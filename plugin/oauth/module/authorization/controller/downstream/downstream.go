@@ -12,3 +12,8 @@ import (
 type OauthAccessTokenRepository interface {
 	OneByToken(ktx kontext.Context, token string, tx db.TX) (entity.OauthAccessToken, exception.Exception)
 }
+
+// TokenVerifier verifies jwt access tokens locally, it is nil when access tokens are opaque
+type TokenVerifier interface {
+	VerifyAccessToken(token string) (entity.AccessTokenClaims, error)
+}
@@ -1,6 +1,7 @@
 package downstream
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"net/http"
@@ -22,11 +23,12 @@ import (
 type Oauth struct {
 	oauthAccessTokenRepo OauthAccessTokenRepository
 	sqldb                db.DB
+	tokenVerifier        TokenVerifier
 }
 
 // NewOauth create new downstream plugin to check the validity of access token given by the users
-func NewOauth(oauthAccessTokenRepo OauthAccessTokenRepository, sqldb db.DB) *Oauth {
-	return &Oauth{oauthAccessTokenRepo: oauthAccessTokenRepo, sqldb: sqldb}
+func NewOauth(oauthAccessTokenRepo OauthAccessTokenRepository, sqldb db.DB, tokenVerifier TokenVerifier) *Oauth {
+	return &Oauth{oauthAccessTokenRepo: oauthAccessTokenRepo, sqldb: sqldb, tokenVerifier: tokenVerifier}
 }
 
 // Name get the name of downstream plugin
@@ -45,15 +47,29 @@ func (o *Oauth) Intervene(c *gin.Context, proxyReq *http.Request, r module.Route
 		return err
 	}
 
-	token, exc := o.oauthAccessTokenRepo.OneByToken(kontext.Fabricate(kontext.WithDefaultContext(c)), accessToken, o.sqldb)
-	if exc != nil {
-		if exc.Type() == exception.NotFound {
+	var token entity.OauthAccessToken
+
+	if o.tokenVerifier != nil && strings.Count(accessToken, ".") == 2 {
+		var verifyErr error
+
+		token, verifyErr = o.verifyToken(accessToken)
+		if verifyErr != nil {
 			c.AbortWithStatus(http.StatusUnauthorized)
-			return exc
+			return verifyErr
 		}
+	} else {
+		var exc exception.Exception
+
+		token, exc = o.oauthAccessTokenRepo.OneByToken(kontext.Fabricate(kontext.WithDefaultContext(c)), accessToken, o.sqldb)
+		if exc != nil {
+			if exc.Type() == exception.NotFound {
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return exc
+			}
 
-		c.AbortWithStatus(http.StatusServiceUnavailable)
-		return fmt.Errorf("Error connecting to model: %v", err)
+			c.AbortWithStatus(http.StatusServiceUnavailable)
+			return fmt.Errorf("Error connecting to model: %v", exc)
+		}
 	}
 
 	if time.Now().After(token.ExpiresIn) {
@@ -72,6 +88,29 @@ func (o *Oauth) Intervene(c *gin.Context, proxyReq *http.Request, r module.Route
 	return nil
 }
 
+// verifyToken checks a jwt access token without the database, a revoked jwt
+// stays valid until it expires.
+func (o *Oauth) verifyToken(accessToken string) (entity.OauthAccessToken, error) {
+	claims, err := o.tokenVerifier.VerifyAccessToken(accessToken)
+	if err != nil {
+		return entity.OauthAccessToken{}, err
+	}
+
+	resourceOwnerID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		return entity.OauthAccessToken{}, fmt.Errorf("Invalid jwt subject: %v", err)
+	}
+
+	return entity.OauthAccessToken{
+		ID:                 claims.OauthAccessTokenID,
+		OauthApplicationID: claims.OauthApplicationID,
+		ResourceOwnerID:    resourceOwnerID,
+		Scopes:             sql.NullString{String: claims.Scope, Valid: claims.Scope != ""},
+		ExpiresIn:          time.Unix(claims.ExpiresAt, 0),
+		CreatedAt:          time.Unix(claims.IssuedAt, 0),
+	}, nil
+}
+
 func (o *Oauth) validTokenScope(token entity.OauthAccessToken, r module.RouterPath) bool {
 	if r.GetScope() == "" {
 		return true
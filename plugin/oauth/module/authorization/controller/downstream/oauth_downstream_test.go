@@ -1,7 +1,12 @@
 package downstream_test
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
 	"database/sql"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/http"
@@ -21,6 +26,7 @@ import (
 	"github.com/kodefluence/altair/plugin/oauth/entity"
 	"github.com/kodefluence/altair/plugin/oauth/module/authorization/controller/downstream"
 	"github.com/kodefluence/altair/plugin/oauth/module/authorization/controller/downstream/mock"
+	keysetUsecase "github.com/kodefluence/altair/plugin/oauth/module/keyset/usecase"
 )
 
 func TestOauth(t *testing.T) {
@@ -32,7 +38,7 @@ func TestOauth(t *testing.T) {
 	t.Run("Name", func(t *testing.T) {
 		t.Run("Return oauth-plugin", func(t *testing.T) {
 			oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
-			oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb)
+			oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, nil)
 			assert.Equal(t, "oauth-plugin", oauthPlugin.Name())
 		})
 	})
@@ -69,7 +75,7 @@ func TestOauth(t *testing.T) {
 					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
 					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), token, sqldb).Return(entityAccessToken, nil)
 
-					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb)
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, nil)
 
 					err := oauthPlugin.Intervene(c, r, routePath)
 
@@ -109,7 +115,7 @@ func TestOauth(t *testing.T) {
 					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
 					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), token, sqldb).Return(entityAccessToken, nil)
 
-					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb)
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, nil)
 
 					err := oauthPlugin.Intervene(c, r, routePath)
 
@@ -136,7 +142,7 @@ func TestOauth(t *testing.T) {
 					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
 					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb)
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, nil)
 
 					err := oauthPlugin.Intervene(c, r, routePath)
 
@@ -169,7 +175,7 @@ func TestOauth(t *testing.T) {
 						oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
 						oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-						oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb)
+						oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, nil)
 
 						err := oauthPlugin.Intervene(c, r, routePath)
 
@@ -201,7 +207,7 @@ func TestOauth(t *testing.T) {
 						oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
 						oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 
-						oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb)
+						oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, nil)
 
 						err := oauthPlugin.Intervene(c, r, routePath)
 
@@ -234,7 +240,7 @@ func TestOauth(t *testing.T) {
 					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
 					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), token, sqldb).Return(entity.OauthAccessToken{}, exception.Throw(sql.ErrNoRows, exception.WithType(exception.NotFound)))
 
-					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb)
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, nil)
 
 					err := oauthPlugin.Intervene(c, r, routePath)
 
@@ -267,7 +273,7 @@ func TestOauth(t *testing.T) {
 					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
 					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), token, sqldb).Return(entity.OauthAccessToken{}, exception.Throw(errors.New("unexpected error")))
 
-					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb)
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, nil)
 
 					err := oauthPlugin.Intervene(c, r, routePath)
 
@@ -313,7 +319,7 @@ func TestOauth(t *testing.T) {
 					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
 					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), token, sqldb).Return(entityAccessToken, nil)
 
-					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb)
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, nil)
 
 					err := oauthPlugin.Intervene(c, r, routePath)
 
@@ -359,7 +365,7 @@ func TestOauth(t *testing.T) {
 					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
 					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), token, sqldb).Return(entityAccessToken, nil)
 
-					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb)
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, nil)
 
 					err := oauthPlugin.Intervene(c, r, routePath)
 
@@ -367,6 +373,170 @@ func TestOauth(t *testing.T) {
 					assert.Equal(t, http.StatusUnauthorized, c.Writer.Status())
 				})
 			})
+
+			t.Run("Jwt access token", func(t *testing.T) {
+				jwtToken := "header.claims.signature"
+
+				newContext := func(status int) *gin.Context {
+					c := &gin.Context{}
+					c.Request = &http.Request{
+						Header: http.Header{},
+					}
+					c.Request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", jwtToken))
+
+					responseWritterMock := coreMock.NewMockResponseWriter(mockCtrl)
+					responseWritterMock.EXPECT().WriteHeaderNow().AnyTimes()
+					responseWritterMock.EXPECT().WriteHeader(gomock.Any()).AnyTimes()
+					responseWritterMock.EXPECT().Status().Return(status).AnyTimes()
+
+					c.Writer = responseWritterMock
+					return c
+				}
+
+				t.Run("Valid token, return nil without querying the database", func(t *testing.T) {
+					c := newContext(http.StatusOK)
+					r, _ := http.NewRequest("GET", "https://github.com/kodefluence/altair", nil)
+					routePath := &coreEntity.RouterPath{Auth: "oauth", Scope: "public"}
+
+					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+					tokenVerifier := mock.NewMockTokenVerifier(mockCtrl)
+					tokenVerifier.EXPECT().VerifyAccessToken(jwtToken).Return(entity.AccessTokenClaims{
+						Subject:            "3",
+						OauthApplicationID: 2,
+						Scope:              "public user",
+						ExpiresAt:          time.Now().Add(time.Hour).Unix(),
+						IssuedAt:           time.Now().Unix(),
+						ID:                 "jti",
+						OauthAccessTokenID: 5,
+					}, nil)
+
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, tokenVerifier)
+
+					err := oauthPlugin.Intervene(c, r, routePath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, "3", r.Header.Get("Resource-Owner-ID"))
+					assert.Equal(t, "2", r.Header.Get("Oauth-Application-ID"))
+				})
+
+				t.Run("Invalid signature, return error with status 401", func(t *testing.T) {
+					c := newContext(http.StatusUnauthorized)
+					r, _ := http.NewRequest("GET", "https://github.com/kodefluence/altair", nil)
+					routePath := &coreEntity.RouterPath{Auth: "oauth"}
+
+					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+					tokenVerifier := mock.NewMockTokenVerifier(mockCtrl)
+					tokenVerifier.EXPECT().VerifyAccessToken(jwtToken).Return(entity.AccessTokenClaims{}, errors.New("invalid jwt"))
+
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, tokenVerifier)
+
+					err := oauthPlugin.Intervene(c, r, routePath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, http.StatusUnauthorized, c.Writer.Status())
+				})
+
+				t.Run("Expired token, return error with status 401", func(t *testing.T) {
+					c := newContext(http.StatusUnauthorized)
+					r, _ := http.NewRequest("GET", "https://github.com/kodefluence/altair", nil)
+					routePath := &coreEntity.RouterPath{Auth: "oauth"}
+
+					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+
+					tokenVerifier := mock.NewMockTokenVerifier(mockCtrl)
+					tokenVerifier.EXPECT().VerifyAccessToken(jwtToken).Return(entity.AccessTokenClaims{
+						Subject:   "3",
+						ExpiresAt: time.Now().Add(-time.Minute).Unix(),
+					}, nil)
+
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, tokenVerifier)
+
+					err := oauthPlugin.Intervene(c, r, routePath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, http.StatusUnauthorized, c.Writer.Status())
+				})
+
+				t.Run("Id token signed by the same key set, return error with status 401", func(t *testing.T) {
+					privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+					assert.Nil(t, err)
+					der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+					assert.Nil(t, err)
+
+					key, err := keysetUsecase.ParseKey("key-1", entity.AlgorithmES256, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil)
+					assert.Nil(t, err)
+					keySet, err := keysetUsecase.NewKeySet("https://altair.example", "key-1", []keysetUsecase.Key{key})
+					assert.Nil(t, err)
+
+					idToken, err := keySet.SignIDToken(entity.IDTokenClaims{
+						Subject:   "3",
+						Audience:  "client_uid",
+						ExpiresAt: time.Now().Add(time.Hour).Unix(),
+						IssuedAt:  time.Now().Unix(),
+					}, "access-token")
+					assert.Nil(t, err)
+
+					c := &gin.Context{}
+					c.Request = &http.Request{
+						Header: http.Header{},
+					}
+					c.Request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", idToken))
+
+					responseWritterMock := coreMock.NewMockResponseWriter(mockCtrl)
+					responseWritterMock.EXPECT().WriteHeaderNow().AnyTimes()
+					responseWritterMock.EXPECT().WriteHeader(http.StatusUnauthorized).Times(1)
+					responseWritterMock.EXPECT().Status().Return(http.StatusUnauthorized).AnyTimes()
+					c.Writer = responseWritterMock
+
+					r, _ := http.NewRequest("GET", "https://github.com/kodefluence/altair", nil)
+					routePath := &coreEntity.RouterPath{Auth: "oauth"}
+
+					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, keySet)
+
+					err = oauthPlugin.Intervene(c, r, routePath)
+
+					assert.NotNil(t, err)
+					assert.Equal(t, http.StatusUnauthorized, c.Writer.Status())
+					assert.Equal(t, "", r.Header.Get("Resource-Owner-ID"))
+				})
+
+				t.Run("Opaque token with jwt format enabled, return nil from the database lookup", func(t *testing.T) {
+					token := "token"
+
+					c := &gin.Context{}
+					c.Request = &http.Request{
+						Header: http.Header{},
+					}
+					c.Request.Header.Add("Authorization", fmt.Sprintf("Bearer %s", token))
+
+					r, _ := http.NewRequest("GET", "https://github.com/kodefluence/altair", nil)
+					routePath := &coreEntity.RouterPath{Auth: "oauth"}
+
+					oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+					oauthAccessTokenRepo.EXPECT().OneByToken(gomock.Any(), token, sqldb).Return(entity.OauthAccessToken{
+						OauthApplicationID: 1,
+						ResourceOwnerID:    1,
+						ExpiresIn:          time.Now().Add(time.Hour),
+					}, nil)
+
+					tokenVerifier := mock.NewMockTokenVerifier(mockCtrl)
+					tokenVerifier.EXPECT().VerifyAccessToken(gomock.Any()).Times(0)
+
+					oauthPlugin := downstream.NewOauth(oauthAccessTokenRepo, sqldb, tokenVerifier)
+
+					err := oauthPlugin.Intervene(c, r, routePath)
+
+					assert.Nil(t, err)
+					assert.Equal(t, "1", r.Header.Get("Resource-Owner-ID"))
+				})
+			})
 		})
 	})
 }
@@ -12,4 +12,5 @@ type Authorization interface {
 	GrantAuthorizationCode(ktx kontext.Context, authorizationReq entity.AuthorizationRequestJSON) (interface{}, jsonapi.Errors)
 	GrantToken(ktx kontext.Context, accessTokenReq entity.AccessTokenRequestJSON) (entity.OauthAccessTokenJSON, jsonapi.Errors)
 	RevokeToken(ktx kontext.Context, revokeAccessTokenReq entity.RevokeAccessTokenRequestJSON) jsonapi.Errors
+	Introspect(ktx kontext.Context, introspectionReq entity.IntrospectionRequestJSON) (entity.IntrospectionJSON, jsonapi.Errors)
 }
@@ -0,0 +1,80 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+// IntrospectController control flow of token introspection
+type IntrospectController struct {
+	authorizationUsecase Authorization
+	apiError             module.ApiError
+}
+
+// NewIntrospect create new introspect controller
+func NewIntrospect(authorizationUsecase Authorization, apiError module.ApiError) *IntrospectController {
+	return &IntrospectController{
+		authorizationUsecase: authorizationUsecase,
+		apiError:             apiError,
+	}
+}
+
+// Method POST
+func (o *IntrospectController) Method() string {
+	return "POST"
+}
+
+// Path /oauth/introspect
+func (o *IntrospectController) Path() string {
+	return "/oauth/introspect"
+}
+
+// Control introspecting access token or refresh token. The introspection is
+// responded as is instead of a jsonapi document, as RFC 7662 clients expect.
+func (o *IntrospectController) Control(ktx kontext.Context, c *gin.Context) {
+	var req entity.IntrospectionRequestJSON
+
+	rawData, err := c.GetRawData()
+	if err != nil {
+		log.Error().
+			Err(err).
+			Stack().
+			Interface("request_id", c.Value("request_id")).
+			Array("tags", zerolog.Arr().Str("controller").Str("authorization").Str("introspect").Str("get_raw_data")).
+			Msg("Cannot get raw data")
+
+		c.JSON(http.StatusBadRequest, jsonapi.BuildResponse(o.apiError.BadRequestError("request body")))
+		return
+	}
+
+	err = json.Unmarshal(rawData, &req)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Stack().
+			Interface("request_id", c.Value("request_id")).
+			Array("tags", zerolog.Arr().Str("controller").Str("authorization").Str("introspect").Str("unmarshal")).
+			Msg("Cannot unmarshal json")
+
+		c.JSON(http.StatusBadRequest, jsonapi.BuildResponse(o.apiError.BadRequestError("request body")))
+		return
+	}
+
+	data, jsonapierr := o.authorizationUsecase.Introspect(ktx, req)
+	if jsonapierr != nil {
+		c.JSON(jsonapierr.HTTPStatus(), jsonapi.BuildResponse(jsonapi.WithErrors(jsonapierr)))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, data)
+}
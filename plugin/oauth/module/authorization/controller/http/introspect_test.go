@@ -0,0 +1,132 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/controller"
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	authorizationHttp "github.com/kodefluence/altair/plugin/oauth/module/authorization/controller/http"
+	"github.com/kodefluence/altair/plugin/oauth/module/authorization/controller/http/mock"
+	"github.com/kodefluence/altair/testhelper"
+	"github.com/kodefluence/altair/util"
+)
+
+func TestIntrospect(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("Method", func(t *testing.T) {
+		authorizationService := mock.NewMockAuthorization(mockCtrl)
+		assert.Equal(t, "POST", authorizationHttp.NewIntrospect(authorizationService, apierror.Provide()).Method())
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		authorizationService := mock.NewMockAuthorization(mockCtrl)
+		assert.Equal(t, "/oauth/introspect", authorizationHttp.NewIntrospect(authorizationService, apierror.Provide()).Path())
+	})
+
+	t.Run("Control", func(t *testing.T) {
+		t.Run("Given request with json body", func(t *testing.T) {
+			introspectionRequest := entity.IntrospectionRequestJSON{
+				Token:        util.ValueToPointer("some-cool-token"),
+				ClientUID:    util.ValueToPointer("client_uid"),
+				ClientSecret: util.ValueToPointer("client_secret"),
+			}
+			encodedBytes, err := json.Marshal(introspectionRequest)
+			assert.Nil(t, err)
+
+			t.Run("Return introspection with status 200", func(t *testing.T) {
+				apiEngine := gin.Default()
+
+				authorizationService := mock.NewMockAuthorization(mockCtrl)
+				authorizationService.EXPECT().Introspect(gomock.Any(), introspectionRequest).Return(entity.IntrospectionJSON{
+					Active:   true,
+					Scope:    util.ValueToPointer("users"),
+					ClientID: util.ValueToPointer("client_uid"),
+					Sub:      util.ValueToPointer("1"),
+					Exp:      util.ValueToPointer(1700003600),
+					Iat:      util.ValueToPointer(1700000000),
+				}, nil)
+
+				ctrl := authorizationHttp.NewIntrospect(authorizationService, apierror.Provide())
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), bytes.NewReader(encodedBytes))
+				responseByte, err := io.ReadAll(w.Body)
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+				assert.Equal(t, `{"active":true,"scope":"users","client_id":"client_uid","sub":"1","exp":1700003600,"iat":1700000000}`, string(responseByte))
+			})
+
+			t.Run("Return inactive token with status 200", func(t *testing.T) {
+				apiEngine := gin.Default()
+
+				authorizationService := mock.NewMockAuthorization(mockCtrl)
+				authorizationService.EXPECT().Introspect(gomock.Any(), introspectionRequest).Return(entity.IntrospectionJSON{Active: false}, nil)
+
+				ctrl := authorizationHttp.NewIntrospect(authorizationService, apierror.Provide())
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), bytes.NewReader(encodedBytes))
+				responseByte, err := io.ReadAll(w.Body)
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, `{"active":false}`, string(responseByte))
+			})
+
+			t.Run("Unexpected error in authorization services", func(t *testing.T) {
+				t.Run("Return entity error status", func(t *testing.T) {
+					apiEngine := gin.Default()
+
+					authorizationService := mock.NewMockAuthorization(mockCtrl)
+					authorizationService.EXPECT().Introspect(gomock.Any(), introspectionRequest).Return(entity.IntrospectionJSON{}, testhelper.ErrInternalServer())
+
+					ctrl := authorizationHttp.NewIntrospect(authorizationService, apierror.Provide())
+					controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+					w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), bytes.NewReader(encodedBytes))
+					assert.Equal(t, http.StatusInternalServerError, w.Code)
+				})
+			})
+		})
+
+		t.Run("Given invalid request body", func(t *testing.T) {
+			t.Run("Return bad request", func(t *testing.T) {
+				apiEngine := gin.Default()
+
+				authorizationService := mock.NewMockAuthorization(mockCtrl)
+
+				ctrl := authorizationHttp.NewIntrospect(authorizationService, apierror.Provide())
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), testhelper.MockErrorIoReader{})
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			})
+		})
+
+		t.Run("Given request body but not json", func(t *testing.T) {
+			t.Run("Return bad request", func(t *testing.T) {
+				apiEngine := gin.Default()
+
+				authorizationService := mock.NewMockAuthorization(mockCtrl)
+
+				ctrl := authorizationHttp.NewIntrospect(authorizationService, apierror.Provide())
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), bytes.NewReader([]byte(`this is gonna be error`)))
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			})
+		})
+	})
+}
@@ -66,6 +66,21 @@ func (mr *MockAuthorizationMockRecorder) GrantToken(ktx, accessTokenReq interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantToken", reflect.TypeOf((*MockAuthorization)(nil).GrantToken), ktx, accessTokenReq)
 }
 
+// Introspect mocks base method.
+func (m *MockAuthorization) Introspect(ktx kontext.Context, introspectionReq entity.IntrospectionRequestJSON) (entity.IntrospectionJSON, jsonapi.Errors) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Introspect", ktx, introspectionReq)
+	ret0, _ := ret[0].(entity.IntrospectionJSON)
+	ret1, _ := ret[1].(jsonapi.Errors)
+	return ret0, ret1
+}
+
+// Introspect indicates an expected call of Introspect.
+func (mr *MockAuthorizationMockRecorder) Introspect(ktx, introspectionReq interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Introspect", reflect.TypeOf((*MockAuthorization)(nil).Introspect), ktx, introspectionReq)
+}
+
 // RevokeToken mocks base method.
 func (m *MockAuthorization) RevokeToken(ktx kontext.Context, revokeAccessTokenReq entity.RevokeAccessTokenRequestJSON) jsonapi.Errors {
 	m.ctrl.T.Helper()
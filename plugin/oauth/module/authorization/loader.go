@@ -20,14 +20,17 @@ func Load(
 	config entity.OauthPlugin,
 	sqldb db.DB,
 	apiError module.ApiError,
+	tokenSigner usecase.TokenSigner,
+	idTokenSigner usecase.IDTokenSigner,
 ) {
-	authorizationUsecase := usecase.NewAuthorization(oauthApplicationRepo, oauthAccessTokenRepo, oauthAccessGrantRepo, oauthRefreshTokenRepo, formatter, config, sqldb, apiError)
+	authorizationUsecase := usecase.NewAuthorization(oauthApplicationRepo, oauthAccessTokenRepo, oauthAccessGrantRepo, oauthRefreshTokenRepo, formatter, config, sqldb, apiError, tokenSigner, idTokenSigner)
 
 	appModule.Controller().InjectHTTP(
 		http.NewGrant(authorizationUsecase, apiError),
 		http.NewToken(authorizationUsecase, apiError),
 		http.NewRevoke(authorizationUsecase, apiError),
+		http.NewIntrospect(authorizationUsecase, apiError),
 	)
 
-	appModule.Controller().InjectDownstream(downstream.NewOauth(oauthAccessTokenRepo, sqldb))
+	appModule.Controller().InjectDownstream(downstream.NewOauth(oauthAccessTokenRepo, sqldb, tokenSigner))
 }
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+// accessToken format access token response, the token is signed as jwt when token_format is jwt
+func (a *Authorization) accessToken(ktx kontext.Context, e entity.OauthAccessToken, redirectURI string, refreshTokenJSON *entity.OauthRefreshTokenJSON) (entity.OauthAccessTokenJSON, jsonapi.Errors) {
+	data := a.formatter.AccessToken(e, redirectURI, refreshTokenJSON)
+	if a.tokenSigner == nil {
+		return data, nil
+	}
+
+	token, err := a.tokenSigner.Sign(a.formatter.AccessTokenClaims(e))
+	if err != nil {
+		log.Error().
+			Err(err).
+			Stack().
+			Interface("request_id", ktx.GetWithoutCheck("request_id")).
+			Array("tags", zerolog.Arr().Str("service").Str("authorization").Str("sign_access_token")).
+			Msg("Error signing access token")
+
+		return entity.OauthAccessTokenJSON{}, jsonapi.BuildResponse(a.apiError.InternalServerError(ktx)).Errors
+	}
+
+	data.Token = &token
+	return data, nil
+}
+
+// oneAccessToken find the access token of an opaque token, a jwt access token is found by its oauth_access_token_id claim
+func (a *Authorization) oneAccessToken(ktx kontext.Context, token string) (entity.OauthAccessToken, exception.Exception) {
+	if ID, ok := entity.AccessTokenID(a.tokenSigner, token); ok {
+		return a.oauthAccessTokenRepo.One(ktx, ID, a.sqldb)
+	}
+
+	return a.oauthAccessTokenRepo.OneByToken(ktx, token, a.sqldb)
+}
+
+// revokeAccessToken revoke the access token of an opaque token, a jwt access token is revoked by its oauth_access_token_id claim
+func (a *Authorization) revokeAccessToken(ktx kontext.Context, token string) exception.Exception {
+	if ID, ok := entity.AccessTokenID(a.tokenSigner, token); ok {
+		return a.oauthAccessTokenRepo.RevokeByID(ktx, ID, a.sqldb)
+	}
+
+	return a.oauthAccessTokenRepo.Revoke(ktx, token, a.sqldb)
+}
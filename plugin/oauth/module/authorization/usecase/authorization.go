@@ -22,6 +22,10 @@ type Formatter interface {
 	AccessGrant(e entity.OauthAccessGrant) entity.OauthAccessGrantJSON
 	AccessToken(e entity.OauthAccessToken, redirectURI string, refreshTokenJSON *entity.OauthRefreshTokenJSON) entity.OauthAccessTokenJSON
 	RefreshToken(e entity.OauthRefreshToken) entity.OauthRefreshTokenJSON
+	AccessTokenIntrospection(e entity.OauthAccessToken, application entity.OauthApplication) entity.IntrospectionJSON
+	RefreshTokenIntrospection(e entity.OauthRefreshToken, accessToken entity.OauthAccessToken, application entity.OauthApplication) entity.IntrospectionJSON
+	AccessTokenClaims(e entity.OauthAccessToken) entity.AccessTokenClaims
+	IDTokenClaims(e entity.OauthAccessToken, application entity.OauthApplication, nonce string) entity.IDTokenClaims
 }
 
 type OauthAccessGrantRepository interface {
@@ -36,12 +40,14 @@ type OauthAccessTokenRepository interface {
 	One(ktx kontext.Context, ID int, tx db.TX) (entity.OauthAccessToken, exception.Exception)
 	Create(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception)
 	Revoke(ktx kontext.Context, token string, tx db.TX) exception.Exception
+	RevokeByID(ktx kontext.Context, ID int, tx db.TX) exception.Exception
 }
 
 type OauthApplicationRepository interface {
 	Paginate(ktx kontext.Context, offset, limit int, tx db.TX) ([]entity.OauthApplication, exception.Exception)
 	Count(ktx kontext.Context, tx db.TX) (int, exception.Exception)
 	One(ktx kontext.Context, ID int, tx db.TX) (entity.OauthApplication, exception.Exception)
+	OneByUID(ktx kontext.Context, clientUID string, tx db.TX) (entity.OauthApplication, exception.Exception)
 	OneByUIDandSecret(ktx kontext.Context, clientUID, clientSecret string, tx db.TX) (entity.OauthApplication, exception.Exception)
 	Create(ktx kontext.Context, data entity.OauthApplicationInsertable, tx db.TX) (int, exception.Exception)
 	Update(ktx kontext.Context, ID int, data entity.OauthApplicationUpdateable, tx db.TX) exception.Exception
@@ -54,6 +60,17 @@ type OauthRefreshTokenRepository interface {
 	Revoke(ktx kontext.Context, token string, tx db.TX) exception.Exception
 }
 
+// TokenSigner signs access tokens as jwt, it is nil when access tokens are opaque
+type TokenSigner interface {
+	Sign(claims entity.AccessTokenClaims) (string, error)
+	VerifyAccessToken(token string) (entity.AccessTokenClaims, error)
+}
+
+// IDTokenSigner signs openid connect id tokens, it is nil when openid is not active
+type IDTokenSigner interface {
+	SignIDToken(claims entity.IDTokenClaims, accessToken string) (string, error)
+}
+
 // Authorization struct handle all of things related to oauth2 authorization
 type Authorization struct {
 	oauthApplicationRepo  OauthApplicationRepository
@@ -61,10 +78,12 @@ type Authorization struct {
 	oauthAccessGrantRepo  OauthAccessGrantRepository
 	oauthRefreshTokenRepo OauthRefreshTokenRepository
 
-	formatter Formatter
-	config    entity.OauthPlugin
-	sqldb     db.DB
-	apiError  module.ApiError
+	formatter     Formatter
+	config        entity.OauthPlugin
+	sqldb         db.DB
+	apiError      module.ApiError
+	tokenSigner   TokenSigner
+	idTokenSigner IDTokenSigner
 }
 
 func NewAuthorization(
@@ -76,6 +95,8 @@ func NewAuthorization(
 	config entity.OauthPlugin,
 	sqldb db.DB,
 	apiError module.ApiError,
+	tokenSigner TokenSigner,
+	idTokenSigner IDTokenSigner,
 ) *Authorization {
 	return &Authorization{
 		oauthApplicationRepo:  oauthApplicationRepo,
@@ -86,5 +107,7 @@ func NewAuthorization(
 		config:                config,
 		sqldb:                 sqldb,
 		apiError:              apiError,
+		tokenSigner:           tokenSigner,
+		idTokenSigner:         idTokenSigner,
 	}
 }
@@ -66,7 +66,7 @@ func (suite *AuthorizationBaseSuiteTest) SetupTest() {
 	suite.formatter = formatter.Provide(24*time.Hour, 24*time.Hour, 24*time.Hour)
 	suite.sqldb = mockdb.NewMockDB(suite.mockCtrl)
 	suite.apiError = apierror.Provide()
-	suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError)
+	suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, nil, nil)
 }
 
 func (suite *AuthorizationBaseSuiteTest) TearDownTest() {
@@ -15,7 +15,9 @@ func (a *Authorization) ClientCredential(ktx kontext.Context, accessTokenReq ent
 	var finalRefreshToken *entity.OauthRefreshToken
 
 	exc := a.sqldb.Transaction(ktx, "authorization-grant-client-credential", func(tx db.TX) exception.Exception {
-		id, err := a.oauthAccessTokenRepo.Create(ktx, a.formatter.AccessTokenClientCredentialInsertable(oauthApplication, accessTokenReq.Scope), tx)
+		oauthAccessTokenInsertable := a.formatter.AccessTokenClientCredentialInsertable(oauthApplication, accessTokenReq.Scope)
+
+		id, err := a.oauthAccessTokenRepo.Create(ktx, oauthAccessTokenInsertable, tx)
 		if err != nil {
 			return exception.Throw(err, exception.WithDetail("error creating new oauth access token"), exception.WithType(exception.Unexpected), exception.WithTitle("access token creation error"))
 		}
@@ -24,6 +26,7 @@ func (a *Authorization) ClientCredential(ktx kontext.Context, accessTokenReq ent
 		if err != nil {
 			return exception.Throw(err, exception.WithDetail("error selecting newly created access token"), exception.WithType(exception.Unexpected), exception.WithTitle("access token creation error"))
 		}
+		oauthAccessToken.Token = oauthAccessTokenInsertable.Token
 
 		if a.config.Config.RefreshToken.Active {
 			if refreshToken, err := a.GrantRefreshToken(ktx, oauthAccessToken, oauthApplication, tx); err != nil {
@@ -16,6 +16,7 @@ import (
 
 	"github.com/kodefluence/altair/plugin/oauth/entity"
 	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase"
+	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase/mock"
 	"github.com/kodefluence/altair/util"
 )
 
@@ -76,18 +77,19 @@ func (suite *ClientCredentialSuiteTest) SetupTest() {
 func (suite *ClientCredentialSuiteTest) TestValidateTokenGrantSuiteTest() {
 	suite.Run("Positive cases", func() {
 		suite.Subtest("When all parameters is valid, then it would return nil", func() {
-			suite.refreshTokenJSON = suite.formatter.RefreshToken(suite.refreshToken)
 			gomock.InOrder(
 				suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.accessTokenRequestJSON.ClientUID, *suite.accessTokenRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil),
 				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-grant-client-credential", gomock.Any()).DoAndReturn(func(ctx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
 					suite.oauthAccessTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
 						suite.Assert().Equal(*suite.accessTokenRequestJSON.Scope, data.Scopes)
 						suite.Assert().Equal(suite.oauthApplication.ID, data.OauthApplicationID)
+						suite.accessToken.Token = data.Token
 						return 1, nil
 					})
 					suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.accessToken, nil)
 					suite.oauthRefreshTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthRefreshTokenInsertable, tx db.TX) (int, exception.Exception) {
 						suite.Assert().Equal(suite.accessToken.ID, data.OauthAccessTokenID)
+						suite.refreshToken.Token = data.Token
 						return 1, nil
 					})
 					suite.oauthRefreshTokenRepo.EXPECT().One(suite.ktx, suite.refreshToken.ID, suite.sqldb).Return(suite.refreshToken, nil)
@@ -96,6 +98,7 @@ func (suite *ClientCredentialSuiteTest) TestValidateTokenGrantSuiteTest() {
 			)
 
 			accessTokenJSON, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
+			suite.refreshTokenJSON = suite.formatter.RefreshToken(suite.refreshToken)
 			byteAccessToken, _ := json.Marshal(accessTokenJSON)
 			byteExpectedAccessToken, _ := json.Marshal(suite.formatter.AccessToken(suite.accessToken, "", &suite.refreshTokenJSON))
 			suite.Assert().Nil(err)
@@ -104,7 +107,7 @@ func (suite *ClientCredentialSuiteTest) TestValidateTokenGrantSuiteTest() {
 
 		suite.Subtest("When all parameters is valid and refresh token config inactive, then it would return nil without access token", func() {
 			suite.config.Config.RefreshToken.Active = false
-			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, nil, nil)
 
 			suite.refreshTokenJSON = suite.formatter.RefreshToken(suite.refreshToken)
 			gomock.InOrder(
@@ -113,6 +116,7 @@ func (suite *ClientCredentialSuiteTest) TestValidateTokenGrantSuiteTest() {
 					suite.oauthAccessTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
 						suite.Assert().Equal(*suite.accessTokenRequestJSON.Scope, data.Scopes)
 						suite.Assert().Equal(suite.oauthApplication.ID, data.OauthApplicationID)
+						suite.accessToken.Token = data.Token
 						return 1, nil
 					})
 					suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.accessToken, nil)
@@ -126,6 +130,42 @@ func (suite *ClientCredentialSuiteTest) TestValidateTokenGrantSuiteTest() {
 			suite.Assert().Nil(err)
 			suite.Equal(string(byteExpectedAccessToken), string(byteAccessToken))
 		})
+
+		suite.Subtest("When token format is jwt, then it would return signed access token", func() {
+			tokenSigner := mock.NewMockTokenSigner(suite.mockCtrl)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, tokenSigner, nil)
+
+			gomock.InOrder(
+				suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.accessTokenRequestJSON.ClientUID, *suite.accessTokenRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil),
+				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-grant-client-credential", gomock.Any()).DoAndReturn(func(ctx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
+					suite.oauthAccessTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
+						suite.accessToken.Token = data.Token
+						return 1, nil
+					})
+					suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.accessToken, nil)
+					suite.oauthRefreshTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthRefreshTokenInsertable, tx db.TX) (int, exception.Exception) {
+						suite.refreshToken.Token = data.Token
+						return 1, nil
+					})
+					suite.oauthRefreshTokenRepo.EXPECT().One(suite.ktx, suite.refreshToken.ID, suite.sqldb).Return(suite.refreshToken, nil)
+					return f(suite.sqldb)
+				}),
+				tokenSigner.EXPECT().Sign(gomock.Any()).DoAndReturn(func(claims entity.AccessTokenClaims) (string, error) {
+					// jti is random, the claims never carry the token
+					expected := suite.formatter.AccessTokenClaims(suite.accessToken)
+					expected.ID = claims.ID
+					suite.Assert().Equal(expected, claims)
+					suite.Assert().NotEqual(suite.accessToken.Token, claims.ID)
+					suite.Assert().Equal(suite.accessToken.ID, claims.OauthAccessTokenID)
+					return "header.claims.signature", nil
+				}),
+			)
+
+			accessTokenJSON, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
+			suite.Assert().Nil(err)
+			suite.Assert().Equal("header.claims.signature", *accessTokenJSON.Token)
+			suite.Assert().Equal(suite.refreshToken.Token, *accessTokenJSON.RefreshToken.Token)
+		})
 	})
 
 	suite.Run("Negative cases", func() {
@@ -195,6 +235,27 @@ func (suite *ClientCredentialSuiteTest) TestValidateTokenGrantSuiteTest() {
 			suite.Assert().Equal("JSONAPI Error:\n[Internal server error] Detail: Something is not right, help us fix this problem. Contribute to https://github.com/kodefluence/altair. Tracing code: '<nil>', Code: ERR0500\n", err.Error())
 			suite.Assert().Equal(http.StatusInternalServerError, err.HTTPStatus())
 		})
+
+		suite.Subtest("When signing jwt access token failed, then it would return error", func() {
+			tokenSigner := mock.NewMockTokenSigner(suite.mockCtrl)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, tokenSigner, nil)
+
+			gomock.InOrder(
+				suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.accessTokenRequestJSON.ClientUID, *suite.accessTokenRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil),
+				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-grant-client-credential", gomock.Any()).DoAndReturn(func(ctx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
+					suite.oauthAccessTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).Return(1, nil)
+					suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.accessToken, nil)
+					suite.oauthRefreshTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).Return(1, nil)
+					suite.oauthRefreshTokenRepo.EXPECT().One(suite.ktx, suite.refreshToken.ID, suite.sqldb).Return(suite.refreshToken, nil)
+					return f(suite.sqldb)
+				}),
+				tokenSigner.EXPECT().Sign(gomock.Any()).Return("", errors.New("unexpected")),
+			)
+
+			_, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
+			suite.Assert().NotNil(err)
+			suite.Assert().Equal(http.StatusInternalServerError, err.HTTPStatus())
+		})
 	})
 }
 
@@ -44,3 +44,40 @@ func (a *Authorization) FindAndValidateApplication(ktx kontext.Context, clientUI
 
 	return oauthApplication, nil
 }
+
+// FindAndValidatePublicApplication find application of a public client by its client uid alone. Only
+// applications enforcing pkce could be public clients, the code verifier authenticates them in place of the client secret.
+func (a *Authorization) FindAndValidatePublicApplication(ktx kontext.Context, clientUID *string) (entity.OauthApplication, jsonapi.Errors) {
+	if clientUID == nil {
+		return entity.OauthApplication{}, jsonapi.BuildResponse(
+			a.apiError.ValidationError("client_uid cannot be empty"),
+		).Errors
+	}
+
+	oauthApplication, err := a.oauthApplicationRepo.OneByUID(ktx, *clientUID, a.sqldb)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Stack().
+			Interface("request_id", ktx.GetWithoutCheck("request_id")).
+			Str("client_uid", *clientUID).
+			Array("tags", zerolog.Arr().Str("service").Str("authorization").Str("find_public_application")).
+			Msg("application cannot be found because there was an error")
+
+		if err.Type() == exception.NotFound {
+			return entity.OauthApplication{},
+				jsonapi.BuildResponse(a.apiError.NotFoundError(ktx, "client_uid")).Errors
+		}
+
+		return entity.OauthApplication{},
+			jsonapi.BuildResponse(a.apiError.InternalServerError(ktx)).Errors
+	}
+
+	if !oauthApplication.PKCERequired {
+		return entity.OauthApplication{}, jsonapi.BuildResponse(
+			a.apiError.ValidationError("client_secret cannot be empty"),
+		).Errors
+	}
+
+	return oauthApplication, nil
+}
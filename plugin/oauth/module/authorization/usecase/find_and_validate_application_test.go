@@ -90,6 +90,51 @@ func (suite *FindAndValidateApplicationSuiteTest) TestFindAndValidateApplication
 	})
 }
 
+func (suite *FindAndValidateApplicationSuiteTest) TestFindAndValidatePublicApplication() {
+	suite.Run("Positive cases", func() {
+		suite.Subtest("When application requires pkce, then it would return the application without client_secret", func() {
+			suite.oauthApplication.PKCERequired = true
+			suite.oauthApplicationRepo.EXPECT().OneByUID(suite.ktx, *suite.clientUID, suite.sqldb).Return(suite.oauthApplication, nil)
+			oauthApplication, err := suite.authorization.FindAndValidatePublicApplication(suite.ktx, suite.clientUID)
+			suite.Assert().Nil(err)
+			suite.Assert().Equal(suite.oauthApplication, oauthApplication)
+		})
+	})
+
+	suite.Run("Negative cases", func() {
+		suite.Subtest("When client_uid is nil, then it would return error", func() {
+			oauthApplication, err := suite.authorization.FindAndValidatePublicApplication(suite.ktx, nil)
+			suite.Assert().Equal("JSONAPI Error:\n[Validation error] Detail: Validation error because of: client_uid cannot be empty, Code: ERR1442\n", err.Error())
+			suite.Assert().Equal(entity.OauthApplication{}, oauthApplication)
+		})
+
+		suite.Subtest("When application does not require pkce, then it would require client_secret", func() {
+			suite.oauthApplicationRepo.EXPECT().OneByUID(suite.ktx, *suite.clientUID, suite.sqldb).Return(suite.oauthApplication, nil)
+			oauthApplication, err := suite.authorization.FindAndValidatePublicApplication(suite.ktx, suite.clientUID)
+			suite.Assert().Equal("JSONAPI Error:\n[Validation error] Detail: Validation error because of: client_secret cannot be empty, Code: ERR1442\n", err.Error())
+			suite.Assert().Equal(http.StatusUnprocessableEntity, err.HTTPStatus())
+			suite.Assert().Equal(entity.OauthApplication{}, oauthApplication)
+		})
+
+		suite.Subtest("When oauth application repo return notfound, then it would return error", func() {
+			suite.oauthApplicationRepo.EXPECT().OneByUID(suite.ktx, *suite.clientUID, suite.sqldb).Return(entity.OauthApplication{}, exception.Throw(
+				errors.New("not found"),
+				exception.WithType(exception.NotFound),
+			))
+			oauthApplication, err := suite.authorization.FindAndValidatePublicApplication(suite.ktx, suite.clientUID)
+			suite.Assert().Equal("JSONAPI Error:\n[Not found error] Detail: Resource of `client_uid` is not found. Tracing code: `<nil>`, Code: ERR0404\n", err.Error())
+			suite.Assert().Equal(http.StatusNotFound, err.HTTPStatus())
+			suite.Assert().Equal(entity.OauthApplication{}, oauthApplication)
+		})
+
+		suite.Subtest("When oauth application repo return unexpected error, then it would return error", func() {
+			suite.oauthApplicationRepo.EXPECT().OneByUID(suite.ktx, *suite.clientUID, suite.sqldb).Return(entity.OauthApplication{}, exception.Throw(errors.New("unexpected")))
+			_, err := suite.authorization.FindAndValidatePublicApplication(suite.ktx, suite.clientUID)
+			suite.Assert().Equal(http.StatusInternalServerError, err.HTTPStatus())
+		})
+	})
+}
+
 func (suite *FindAndValidateApplicationSuiteTest) Subtest(testcase string, subtest func()) {
 	suite.SetupTest()
 	suite.AuthorizationBaseSuiteTest.Subtest(testcase, subtest)
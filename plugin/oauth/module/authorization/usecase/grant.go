@@ -10,11 +10,18 @@ import (
 	"github.com/kodefluence/altair/plugin/oauth/entity"
 )
 
-// Grant authorization an access code
+// Grant authorization an access code, applications enforcing pkce could omit the client secret
 func (a *Authorization) Grant(ktx kontext.Context, authorizationReq entity.AuthorizationRequestJSON) (entity.OauthAccessGrantJSON, jsonapi.Errors) {
 	var oauthAccessGrantJSON entity.OauthAccessGrantJSON
 
-	oauthApplication, jsonapiErr := a.FindAndValidateApplication(ktx, authorizationReq.ClientUID, authorizationReq.ClientSecret)
+	var oauthApplication entity.OauthApplication
+	var jsonapiErr jsonapi.Errors
+
+	if authorizationReq.ClientSecret == nil {
+		oauthApplication, jsonapiErr = a.FindAndValidatePublicApplication(ktx, authorizationReq.ClientUID)
+	} else {
+		oauthApplication, jsonapiErr = a.FindAndValidateApplication(ktx, authorizationReq.ClientUID, authorizationReq.ClientSecret)
+	}
 	if jsonapiErr != nil {
 		return oauthAccessGrantJSON, jsonapiErr
 	}
@@ -86,6 +86,7 @@ func (suite *GrantorSuiteTest) TestGrantor() {
 				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-implicit-grant", gomock.Any()).DoAndReturn(func(ktx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
 					suite.oauthAccessTokenRepo.EXPECT().Create(ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
 						suite.Assert().Equal(suite.oauthApplication.ID, data.OauthApplicationID)
+						suite.accessToken.Token = data.Token
 						return suite.accessToken.ID, nil
 					})
 					suite.oauthAccessTokenRepo.EXPECT().One(ktx, suite.accessToken.ID, suite.sqldb).Return(suite.accessToken, nil)
@@ -149,7 +150,7 @@ func (suite *GrantorSuiteTest) TestGrantor() {
 
 		suite.Subtest("When response type is token but implicit grant feature is inactive, then it would return error", func() {
 			suite.config.Config.ImplicitGrant.Active = false
-			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, nil, nil)
 			finalJson, err := suite.authorization.GrantAuthorizationCode(suite.ktx, suite.authorizationRequestJSON)
 			suite.Assert().Equal("JSONAPI Error:\n[Validation error] Detail: Validation error because of: response_type is invalid. Should be either `token` or `code`, Code: ERR1442\n", err.Error())
 			suite.Assert().Equal(http.StatusUnprocessableEntity, err.HTTPStatus())
@@ -9,7 +9,9 @@ import (
 )
 
 func (a *Authorization) GrantRefreshToken(ktx kontext.Context, oauthAccessToken entity.OauthAccessToken, oauthApplication entity.OauthApplication, tx db.TX) (entity.OauthRefreshToken, jsonapi.Errors) {
-	refreshTokenID, err := a.oauthRefreshTokenRepo.Create(ktx, a.formatter.RefreshTokenInsertable(oauthApplication, oauthAccessToken), tx)
+	oauthRefreshTokenInsertable := a.formatter.RefreshTokenInsertable(oauthApplication, oauthAccessToken)
+
+	refreshTokenID, err := a.oauthRefreshTokenRepo.Create(ktx, oauthRefreshTokenInsertable, tx)
 	if err != nil {
 		return entity.OauthRefreshToken{}, jsonapi.BuildResponse(a.apiError.InternalServerError(ktx)).Errors
 	}
@@ -19,5 +21,6 @@ func (a *Authorization) GrantRefreshToken(ktx kontext.Context, oauthAccessToken
 		return entity.OauthRefreshToken{}, jsonapi.BuildResponse(a.apiError.InternalServerError(ktx)).Errors
 	}
 
+	oauthRefreshToken.Token = oauthRefreshTokenInsertable.Token
 	return oauthRefreshToken, nil
 }
@@ -65,6 +65,7 @@ func (suite *GrantRefreshTokenSuiteTest) TestGrantRefreshTokenSuiteTest() {
 			gomock.InOrder(
 				suite.oauthRefreshTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthRefreshTokenInsertable, tx db.TX) (int, exception.Exception) {
 					suite.Assert().Equal(suite.accessToken.ID, data.OauthAccessTokenID)
+					suite.refreshToken.Token = data.Token
 					return 1, nil
 				}),
 				suite.oauthRefreshTokenRepo.EXPECT().One(suite.ktx, suite.refreshToken.ID, suite.sqldb).Return(suite.refreshToken, nil),
@@ -72,6 +73,7 @@ func (suite *GrantRefreshTokenSuiteTest) TestGrantRefreshTokenSuiteTest() {
 
 			refreshToken, err := suite.authorization.GrantRefreshToken(suite.ktx, suite.accessToken, suite.oauthApplication, suite.sqldb)
 			suite.Assert().Nil(err)
+			suite.Assert().NotEqual("", refreshToken.Token)
 			suite.Assert().Equal(suite.refreshToken, refreshToken)
 		})
 	})
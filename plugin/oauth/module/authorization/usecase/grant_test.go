@@ -88,6 +88,28 @@ func (suite *GrantSuiteTest) TestGrant() {
 			suite.Assert().Nil(err)
 			suite.Assert().Equal(suite.formatter.AccessGrant(suite.accessGrant), finalJson)
 		})
+
+		suite.Subtest("When client_secret is omitted by an application requiring pkce, it would return oauth access grant", func() {
+			suite.authorizationRequestJSON.ClientSecret = nil
+			suite.authorizationRequestJSON.CodeChallenge = util.ValueToPointer("E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM")
+			suite.authorizationRequestJSON.CodeChallengeMethod = util.ValueToPointer("S256")
+			suite.oauthApplication.PKCERequired = true
+
+			gomock.InOrder(
+				suite.oauthApplicationRepo.EXPECT().OneByUID(suite.ktx, *suite.authorizationRequestJSON.ClientUID, suite.sqldb).Return(suite.oauthApplication, nil),
+				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-grant-authorization-code", gomock.Any()).DoAndReturn(func(ktx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
+					suite.oauthAccessGrantRepo.EXPECT().Create(ktx, gomock.Any(), suite.sqldb).Return(suite.accessGrant.ID, nil)
+					suite.oauthAccessGrantRepo.EXPECT().One(ktx, suite.accessGrant.ID, suite.sqldb).Return(suite.accessGrant, nil)
+
+					suite.Assert().Nil(f(suite.sqldb))
+					return nil
+				}),
+			)
+
+			finalJson, err := suite.authorization.Grant(suite.ktx, suite.authorizationRequestJSON)
+			suite.Assert().Nil(err)
+			suite.Assert().Equal(suite.formatter.AccessGrant(suite.accessGrant), finalJson)
+		})
 	})
 
 	suite.Run("Negative cases", func() {
@@ -99,6 +121,25 @@ func (suite *GrantSuiteTest) TestGrant() {
 			suite.Assert().Equal(entity.OauthAccessGrantJSON{}, finalJson)
 		})
 
+		suite.Subtest("When client_secret is omitted by an application requiring pkce without code_challenge, then it would return error", func() {
+			suite.authorizationRequestJSON.ClientSecret = nil
+			suite.oauthApplication.PKCERequired = true
+			suite.oauthApplicationRepo.EXPECT().OneByUID(suite.ktx, *suite.authorizationRequestJSON.ClientUID, suite.sqldb).Return(suite.oauthApplication, nil)
+
+			finalJson, err := suite.authorization.Grant(suite.ktx, suite.authorizationRequestJSON)
+			suite.Assert().Equal(http.StatusForbidden, err.HTTPStatus())
+			suite.Assert().Equal(entity.OauthAccessGrantJSON{}, finalJson)
+		})
+
+		suite.Subtest("When client_secret is omitted by an application not requiring pkce, then it would return error", func() {
+			suite.authorizationRequestJSON.ClientSecret = nil
+			suite.oauthApplicationRepo.EXPECT().OneByUID(suite.ktx, *suite.authorizationRequestJSON.ClientUID, suite.sqldb).Return(suite.oauthApplication, nil)
+
+			finalJson, err := suite.authorization.Grant(suite.ktx, suite.authorizationRequestJSON)
+			suite.Assert().Equal("JSONAPI Error:\n[Validation error] Detail: Validation error because of: client_secret cannot be empty, Code: ERR1442\n", err.Error())
+			suite.Assert().Equal(entity.OauthAccessGrantJSON{}, finalJson)
+		})
+
 		suite.Subtest("When all parameter is valid, but scope is available in oauth application then it would return error", func() {
 			suite.authorizationRequestJSON.Scopes = util.ValueToPointer("public users admin")
 			suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.authorizationRequestJSON.ClientUID, *suite.authorizationRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil)
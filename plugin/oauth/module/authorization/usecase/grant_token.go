@@ -15,26 +15,36 @@ func (a *Authorization) GrantToken(ktx kontext.Context, accessTokenReq entity.Ac
 	var oauthApplication entity.OauthApplication
 	var jsonapierr jsonapi.Errors
 
-	if *accessTokenReq.GrantType != "refresh_token" {
+	switch {
+	case *accessTokenReq.GrantType == "authorization_code" && accessTokenReq.ClientSecret == nil:
+		// Public client, the code verifier checked against the authorization code authenticates it
+		oauthApplication, jsonapierr = a.FindAndValidatePublicApplication(ktx, accessTokenReq.ClientUID)
+	case *accessTokenReq.GrantType != "refresh_token":
 		oauthApplication, jsonapierr = a.FindAndValidateApplication(ktx, accessTokenReq.ClientUID, accessTokenReq.ClientSecret)
-		if jsonapierr != nil {
-			return entity.OauthAccessTokenJSON{}, jsonapierr
-		}
+	}
+	if jsonapierr != nil {
+		return entity.OauthAccessTokenJSON{}, jsonapierr
 	}
 
 	switch *accessTokenReq.GrantType {
 	case "authorization_code":
-		oauthAccessToken, oauthRefreshToken, redirectURI, jsonapierr := a.GrantTokenFromAuthorizationCode(ktx, accessTokenReq, oauthApplication)
+		oauthAccessToken, oauthRefreshToken, oauthAccessGrant, jsonapierr := a.GrantTokenFromAuthorizationCode(ktx, accessTokenReq, oauthApplication)
 		if jsonapierr != nil {
 			return entity.OauthAccessTokenJSON{}, jsonapierr
 		}
 
-		if oauthRefreshToken == nil {
-			return a.formatter.AccessToken(oauthAccessToken, redirectURI, nil), nil
+		var refreshTokenJSON *entity.OauthRefreshTokenJSON
+		if oauthRefreshToken != nil {
+			formattedRefreshToken := a.formatter.RefreshToken(*oauthRefreshToken)
+			refreshTokenJSON = &formattedRefreshToken
 		}
 
-		refreshTokenJSON := a.formatter.RefreshToken(*oauthRefreshToken)
-		return a.formatter.AccessToken(oauthAccessToken, redirectURI, &refreshTokenJSON), nil
+		oauthAccessTokenJSON, jsonapierr := a.accessToken(ktx, oauthAccessToken, oauthAccessGrant.RedirectURI.String, refreshTokenJSON)
+		if jsonapierr != nil {
+			return entity.OauthAccessTokenJSON{}, jsonapierr
+		}
+
+		return a.idToken(ktx, oauthAccessTokenJSON, oauthAccessToken, oauthApplication, oauthAccessGrant.Nonce.String)
 	case "refresh_token":
 		if a.config.Config.RefreshToken.Active {
 			oauthAccessToken, oauthRefreshToken, jsonapierr := a.GrantTokenFromRefreshToken(ktx, accessTokenReq)
@@ -43,7 +53,7 @@ func (a *Authorization) GrantToken(ktx kontext.Context, accessTokenReq entity.Ac
 			}
 
 			refreshTokenJSON := a.formatter.RefreshToken(oauthRefreshToken)
-			return a.formatter.AccessToken(oauthAccessToken, "", &refreshTokenJSON), nil
+			return a.accessToken(ktx, oauthAccessToken, "", &refreshTokenJSON)
 		}
 	case "client_credentials":
 		oauthAccessToken, oauthRefreshToken, jsonapierr := a.ClientCredential(ktx, accessTokenReq, oauthApplication)
@@ -52,11 +62,11 @@ func (a *Authorization) GrantToken(ktx kontext.Context, accessTokenReq entity.Ac
 		}
 
 		if oauthRefreshToken == nil {
-			return a.formatter.AccessToken(oauthAccessToken, "", nil), nil
+			return a.accessToken(ktx, oauthAccessToken, "", nil)
 		}
 
 		refreshTokenJSON := a.formatter.RefreshToken(*oauthRefreshToken)
-		return a.formatter.AccessToken(oauthAccessToken, "", &refreshTokenJSON), nil
+		return a.accessToken(ktx, oauthAccessToken, "", &refreshTokenJSON)
 	}
 
 	// This code is actually unreachable since there are already validation put in place in ValidateTokenGrant lol
@@ -10,9 +10,9 @@ import (
 	"github.com/kodefluence/altair/plugin/oauth/entity"
 )
 
-func (a *Authorization) GrantTokenFromAuthorizationCode(ktx kontext.Context, accessTokenReq entity.AccessTokenRequestJSON, oauthApplication entity.OauthApplication) (entity.OauthAccessToken, *entity.OauthRefreshToken, string, jsonapi.Errors) {
+func (a *Authorization) GrantTokenFromAuthorizationCode(ktx kontext.Context, accessTokenReq entity.AccessTokenRequestJSON, oauthApplication entity.OauthApplication) (entity.OauthAccessToken, *entity.OauthRefreshToken, entity.OauthAccessGrant, jsonapi.Errors) {
 	var finalOauthAccessToken entity.OauthAccessToken
-	var finalRedirectURI string
+	var finalOauthAccessGrant entity.OauthAccessGrant
 	var finalRefreshToken *entity.OauthRefreshToken
 
 	exc := a.sqldb.Transaction(ktx, "authorization-grant-token-from-refresh-token", func(tx db.TX) exception.Exception {
@@ -26,11 +26,13 @@ func (a *Authorization) GrantTokenFromAuthorizationCode(ktx kontext.Context, acc
 			return exception.Throw(err, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail("authorization code cannot be found because there was an error"))
 		}
 
-		if exc := a.ValidateTokenAuthorizationCode(ktx, accessTokenReq, oauthAccessGrant); exc != nil {
+		if exc := a.ValidateTokenAuthorizationCode(ktx, accessTokenReq, oauthAccessGrant, oauthApplication); exc != nil {
 			return exc
 		}
 
-		id, err := a.oauthAccessTokenRepo.Create(ktx, a.formatter.AccessTokenFromOauthAccessGrantInsertable(oauthAccessGrant, oauthApplication), tx)
+		oauthAccessTokenInsertable := a.formatter.AccessTokenFromOauthAccessGrantInsertable(oauthAccessGrant, oauthApplication)
+
+		id, err := a.oauthAccessTokenRepo.Create(ktx, oauthAccessTokenInsertable, tx)
 		if err != nil {
 			return exception.Throw(err, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail("error creating access token data"))
 		}
@@ -39,6 +41,7 @@ func (a *Authorization) GrantTokenFromAuthorizationCode(ktx kontext.Context, acc
 		if err != nil {
 			return exception.Throw(err, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail("error selecting newly created access token"))
 		}
+		oauthAccessToken.Token = oauthAccessTokenInsertable.Token
 
 		err = a.oauthAccessGrantRepo.Revoke(ktx, *accessTokenReq.Code, tx)
 		if err != nil {
@@ -54,13 +57,13 @@ func (a *Authorization) GrantTokenFromAuthorizationCode(ktx kontext.Context, acc
 		}
 
 		finalOauthAccessToken = oauthAccessToken
-		finalRedirectURI = oauthAccessGrant.RedirectURI.String
+		finalOauthAccessGrant = oauthAccessGrant
 
 		return nil
 	})
 	if exc != nil {
-		return entity.OauthAccessToken{}, nil, "", a.exceptionMapping(ktx, exc, zerolog.Arr().Str("service").Str("authorization").Str("refresh_token"))
+		return entity.OauthAccessToken{}, nil, entity.OauthAccessGrant{}, a.exceptionMapping(ktx, exc, zerolog.Arr().Str("service").Str("authorization").Str("refresh_token"))
 	}
 
-	return finalOauthAccessToken, finalRefreshToken, finalRedirectURI, nil
+	return finalOauthAccessToken, finalRefreshToken, finalOauthAccessGrant, nil
 }
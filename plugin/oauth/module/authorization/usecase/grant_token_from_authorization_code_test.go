@@ -16,6 +16,7 @@ import (
 
 	"github.com/kodefluence/altair/plugin/oauth/entity"
 	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase"
+	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase/mock"
 	"github.com/kodefluence/altair/util"
 )
 
@@ -90,7 +91,6 @@ func (suite *GrantTokenFromAuthorizationCodeTest) SetupTest() {
 func (suite *GrantTokenFromAuthorizationCodeTest) TestValidateTokenGrantSuiteTest() {
 	suite.Run("Positive cases", func() {
 		suite.Subtest("When all parameters is valid, then it would return nil", func() {
-			suite.refreshTokenJSON = suite.formatter.RefreshToken(suite.refreshToken)
 			gomock.InOrder(
 				suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.accessTokenRequestJSON.ClientUID, *suite.accessTokenRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil),
 				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-grant-token-from-refresh-token", gomock.Any()).DoAndReturn(func(ctx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
@@ -98,12 +98,14 @@ func (suite *GrantTokenFromAuthorizationCodeTest) TestValidateTokenGrantSuiteTes
 					suite.oauthAccessTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
 						suite.Assert().Equal(suite.accessGrant.Scopes.String, data.Scopes)
 						suite.Assert().Equal(suite.oauthApplication.ID, data.OauthApplicationID)
+						suite.accessToken.Token = data.Token
 						return 1, nil
 					})
 					suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.accessToken, nil)
 					suite.oauthAccessGrantRepo.EXPECT().Revoke(suite.ktx, *suite.accessTokenRequestJSON.Code, suite.sqldb).Return(nil)
 					suite.oauthRefreshTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthRefreshTokenInsertable, tx db.TX) (int, exception.Exception) {
 						suite.Assert().Equal(suite.accessToken.ID, data.OauthAccessTokenID)
+						suite.refreshToken.Token = data.Token
 						return 1, nil
 					})
 					suite.oauthRefreshTokenRepo.EXPECT().One(suite.ktx, suite.refreshToken.ID, suite.sqldb).Return(suite.refreshToken, nil)
@@ -112,15 +114,103 @@ func (suite *GrantTokenFromAuthorizationCodeTest) TestValidateTokenGrantSuiteTes
 			)
 
 			accessTokenJSON, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
+			suite.refreshTokenJSON = suite.formatter.RefreshToken(suite.refreshToken)
 			byteAccessToken, _ := json.Marshal(accessTokenJSON)
 			byteExpectedAccessToken, _ := json.Marshal(suite.formatter.AccessToken(suite.accessToken, suite.accessGrant.RedirectURI.String, &suite.refreshTokenJSON))
 			suite.Assert().Nil(err)
 			suite.Equal(string(byteExpectedAccessToken), string(byteAccessToken))
 		})
 
+		suite.Subtest("When client_secret is omitted by an application requiring pkce, then the code verifier authenticates it", func() {
+			suite.accessTokenRequestJSON.ClientSecret = nil
+			suite.accessTokenRequestJSON.CodeVerifier = util.ValueToPointer("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+			suite.accessGrant.CodeChallenge = sql.NullString{String: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", Valid: true}
+			suite.accessGrant.CodeChallengeMethod = sql.NullString{String: "S256", Valid: true}
+			suite.oauthApplication.PKCERequired = true
+
+			gomock.InOrder(
+				suite.oauthApplicationRepo.EXPECT().OneByUID(suite.ktx, *suite.accessTokenRequestJSON.ClientUID, suite.sqldb).Return(suite.oauthApplication, nil),
+				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-grant-token-from-refresh-token", gomock.Any()).DoAndReturn(func(ctx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
+					suite.oauthAccessGrantRepo.EXPECT().OneByCode(suite.ktx, *suite.accessTokenRequestJSON.Code, suite.sqldb).Return(suite.accessGrant, nil)
+					suite.oauthAccessTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
+						suite.accessToken.Token = data.Token
+						return 1, nil
+					})
+					suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.accessToken, nil)
+					suite.oauthAccessGrantRepo.EXPECT().Revoke(suite.ktx, *suite.accessTokenRequestJSON.Code, suite.sqldb).Return(nil)
+					suite.oauthRefreshTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthRefreshTokenInsertable, tx db.TX) (int, exception.Exception) {
+						suite.refreshToken.Token = data.Token
+						return 1, nil
+					})
+					suite.oauthRefreshTokenRepo.EXPECT().One(suite.ktx, suite.refreshToken.ID, suite.sqldb).Return(suite.refreshToken, nil)
+					return f(suite.sqldb)
+				}),
+			)
+
+			accessTokenJSON, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
+			suite.Assert().Nil(err)
+			suite.Assert().Equal(suite.accessToken.Token, *accessTokenJSON.Token)
+		})
+
+		suite.Subtest("When openid scope is granted, then it would return id token carrying the nonce of the authorization code", func() {
+			idTokenSigner := mock.NewMockIDTokenSigner(suite.mockCtrl)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, nil, idTokenSigner)
+
+			suite.accessGrant.Nonce = sql.NullString{String: "n-0S6_WzA2Mj", Valid: true}
+			suite.accessToken.Scopes = sql.NullString{String: "openid users", Valid: true}
+
+			gomock.InOrder(
+				suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.accessTokenRequestJSON.ClientUID, *suite.accessTokenRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil),
+				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-grant-token-from-refresh-token", gomock.Any()).DoAndReturn(func(ctx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
+					suite.oauthAccessGrantRepo.EXPECT().OneByCode(suite.ktx, *suite.accessTokenRequestJSON.Code, suite.sqldb).Return(suite.accessGrant, nil)
+					suite.oauthAccessTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
+						suite.accessToken.Token = data.Token
+						return 1, nil
+					})
+					suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.accessToken, nil)
+					suite.oauthAccessGrantRepo.EXPECT().Revoke(suite.ktx, *suite.accessTokenRequestJSON.Code, suite.sqldb).Return(nil)
+					suite.oauthRefreshTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).Return(1, nil)
+					suite.oauthRefreshTokenRepo.EXPECT().One(suite.ktx, suite.refreshToken.ID, suite.sqldb).Return(suite.refreshToken, nil)
+					return f(suite.sqldb)
+				}),
+				idTokenSigner.EXPECT().SignIDToken(suite.formatter.IDTokenClaims(suite.accessToken, suite.oauthApplication, "n-0S6_WzA2Mj"), gomock.Any()).DoAndReturn(func(claims entity.IDTokenClaims, accessToken string) (string, error) {
+					suite.Assert().Equal(suite.accessToken.Token, accessToken)
+					return "header.claims.signature", nil
+				}),
+			)
+
+			accessTokenJSON, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
+			suite.Assert().Nil(err)
+			suite.Assert().Equal(suite.accessToken.Token, *accessTokenJSON.Token)
+			suite.Assert().Equal("header.claims.signature", *accessTokenJSON.IDToken)
+		})
+
+		suite.Subtest("When openid is active but openid scope is not granted, then it would return nil without id token", func() {
+			idTokenSigner := mock.NewMockIDTokenSigner(suite.mockCtrl)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, nil, idTokenSigner)
+
+			gomock.InOrder(
+				suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.accessTokenRequestJSON.ClientUID, *suite.accessTokenRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil),
+				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-grant-token-from-refresh-token", gomock.Any()).DoAndReturn(func(ctx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
+					suite.oauthAccessGrantRepo.EXPECT().OneByCode(suite.ktx, *suite.accessTokenRequestJSON.Code, suite.sqldb).Return(suite.accessGrant, nil)
+					suite.oauthAccessTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).Return(1, nil)
+					suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.accessToken, nil)
+					suite.oauthAccessGrantRepo.EXPECT().Revoke(suite.ktx, *suite.accessTokenRequestJSON.Code, suite.sqldb).Return(nil)
+					suite.oauthRefreshTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).Return(1, nil)
+					suite.oauthRefreshTokenRepo.EXPECT().One(suite.ktx, suite.refreshToken.ID, suite.sqldb).Return(suite.refreshToken, nil)
+					return f(suite.sqldb)
+				}),
+			)
+			idTokenSigner.EXPECT().SignIDToken(gomock.Any(), gomock.Any()).Times(0)
+
+			accessTokenJSON, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
+			suite.Assert().Nil(err)
+			suite.Assert().Nil(accessTokenJSON.IDToken)
+		})
+
 		suite.Subtest("When all parameters is valid but refresh token is inactive, then it would return nil", func() {
 			suite.config.Config.RefreshToken.Active = false
-			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, nil, nil)
 
 			gomock.InOrder(
 				suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.accessTokenRequestJSON.ClientUID, *suite.accessTokenRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil),
@@ -129,6 +219,7 @@ func (suite *GrantTokenFromAuthorizationCodeTest) TestValidateTokenGrantSuiteTes
 					suite.oauthAccessTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
 						suite.Assert().Equal(suite.accessGrant.Scopes.String, data.Scopes)
 						suite.Assert().Equal(suite.oauthApplication.ID, data.OauthApplicationID)
+						suite.accessToken.Token = data.Token
 						return 1, nil
 					})
 					suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.accessToken, nil)
@@ -198,6 +289,35 @@ func (suite *GrantTokenFromAuthorizationCodeTest) TestValidateTokenGrantSuiteTes
 			suite.Assert().Equal(http.StatusForbidden, err.HTTPStatus())
 		})
 
+		suite.Subtest("When client_secret is omitted and the code verifier does not match, then it would return error", func() {
+			suite.accessTokenRequestJSON.ClientSecret = nil
+			suite.accessTokenRequestJSON.CodeVerifier = util.ValueToPointer("wrong-code-verifier-wrong-code-verifier-wrong")
+			suite.accessGrant.CodeChallenge = sql.NullString{String: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", Valid: true}
+			suite.accessGrant.CodeChallengeMethod = sql.NullString{String: "S256", Valid: true}
+			suite.oauthApplication.PKCERequired = true
+
+			gomock.InOrder(
+				suite.oauthApplicationRepo.EXPECT().OneByUID(suite.ktx, *suite.accessTokenRequestJSON.ClientUID, suite.sqldb).Return(suite.oauthApplication, nil),
+				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-grant-token-from-refresh-token", gomock.Any()).DoAndReturn(func(ctx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
+					suite.oauthAccessGrantRepo.EXPECT().OneByCode(suite.ktx, *suite.accessTokenRequestJSON.Code, suite.sqldb).Return(suite.accessGrant, nil)
+					return f(suite.sqldb)
+				}),
+			)
+
+			_, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
+			suite.Assert().NotNil(err)
+			suite.Assert().Equal("JSONAPI Error:\n[Forbidden resource access] Detail: code verifier does not match the code challenge, Code: ERR0403\n", err.Error())
+		})
+
+		suite.Subtest("When client_secret is omitted by an application not requiring pkce, then it would return error", func() {
+			suite.accessTokenRequestJSON.ClientSecret = nil
+			suite.oauthApplicationRepo.EXPECT().OneByUID(suite.ktx, *suite.accessTokenRequestJSON.ClientUID, suite.sqldb).Return(suite.oauthApplication, nil)
+
+			_, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
+			suite.Assert().NotNil(err)
+			suite.Assert().Equal(http.StatusUnprocessableEntity, err.HTTPStatus())
+		})
+
 		suite.Subtest("When access token creation failure, then it would return error", func() {
 			suite.refreshTokenJSON = suite.formatter.RefreshToken(suite.refreshToken)
 			gomock.InOrder(
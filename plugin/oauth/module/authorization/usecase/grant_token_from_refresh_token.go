@@ -42,7 +42,9 @@ func (a *Authorization) GrantTokenFromRefreshToken(ktx kontext.Context, accessTo
 			return exception.Throw(err, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail("error find oauth applications"))
 		}
 
-		oauthAccessTokenID, err := a.oauthAccessTokenRepo.Create(ktx, a.formatter.AccessTokenFromOauthRefreshTokenInsertable(oauthApplication, oldAccessToken), tx)
+		oauthAccessTokenInsertable := a.formatter.AccessTokenFromOauthRefreshTokenInsertable(oauthApplication, oldAccessToken)
+
+		oauthAccessTokenID, err := a.oauthAccessTokenRepo.Create(ktx, oauthAccessTokenInsertable, tx)
 		if err != nil {
 			return exception.Throw(err, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail("error creating access token"))
 		}
@@ -51,13 +53,16 @@ func (a *Authorization) GrantTokenFromRefreshToken(ktx kontext.Context, accessTo
 		if err != nil {
 			return exception.Throw(err, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail("error when selecting newly created access token"))
 		}
+		oauthAccessToken.Token = oauthAccessTokenInsertable.Token
 
 		err = a.oauthRefreshTokenRepo.Revoke(ktx, *accessTokenReq.RefreshToken, tx)
 		if err != nil {
 			return exception.Throw(err, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail("error revoke refresh token"))
 		}
 
-		oauthRefreshTokenID, err := a.oauthRefreshTokenRepo.Create(ktx, a.formatter.RefreshTokenInsertable(oauthApplication, oauthAccessToken), tx)
+		oauthRefreshTokenInsertable := a.formatter.RefreshTokenInsertable(oauthApplication, oauthAccessToken)
+
+		oauthRefreshTokenID, err := a.oauthRefreshTokenRepo.Create(ktx, oauthRefreshTokenInsertable, tx)
 		if err != nil {
 			return exception.Throw(err, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail("error creating refresh token"))
 		}
@@ -66,6 +71,7 @@ func (a *Authorization) GrantTokenFromRefreshToken(ktx kontext.Context, accessTo
 		if err != nil {
 			return exception.Throw(err, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail("error when selecting newly created refresh token"))
 		}
+		oauthRefreshToken.Token = oauthRefreshTokenInsertable.Token
 
 		finalOauthAccessToken = oauthAccessToken
 		finalOauthRefreshToken = oauthRefreshToken
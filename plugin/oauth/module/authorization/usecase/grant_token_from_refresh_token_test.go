@@ -99,7 +99,6 @@ func (suite *GrantTokenFromRefreshTokenSuiteTest) SetupTest() {
 func (suite *GrantTokenFromRefreshTokenSuiteTest) TestValidateTokenGrantSuiteTest() {
 	suite.Run("Positive cases", func() {
 		suite.Subtest("When all parameters is valid, then it would return nil", func() {
-			suite.refreshTokenJSON = suite.formatter.RefreshToken(suite.refreshToken)
 			gomock.InOrder(
 				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-grant-token-from-refresh-token", gomock.Any()).DoAndReturn(func(ctx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
 					suite.oauthRefreshTokenRepo.EXPECT().OneByToken(suite.ktx, *suite.accessTokenRequestJSON.RefreshToken, suite.sqldb).Return(suite.oldrefreshToken, nil)
@@ -108,12 +107,14 @@ func (suite *GrantTokenFromRefreshTokenSuiteTest) TestValidateTokenGrantSuiteTes
 					suite.oauthAccessTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
 						suite.Assert().Equal(suite.oldaccessToken.Scopes.String, data.Scopes)
 						suite.Assert().Equal(suite.oauthApplication.ID, data.OauthApplicationID)
+						suite.accessToken.Token = data.Token
 						return 2, nil
 					})
 					suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 2, suite.sqldb).Return(suite.accessToken, nil)
 					suite.oauthRefreshTokenRepo.EXPECT().Revoke(suite.ktx, *suite.accessTokenRequestJSON.RefreshToken, suite.sqldb).Return(nil)
 					suite.oauthRefreshTokenRepo.EXPECT().Create(suite.ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthRefreshTokenInsertable, tx db.TX) (int, exception.Exception) {
 						suite.Assert().Equal(suite.accessToken.ID, data.OauthAccessTokenID)
+						suite.refreshToken.Token = data.Token
 						return 2, nil
 					})
 					suite.oauthRefreshTokenRepo.EXPECT().One(suite.ktx, suite.refreshToken.ID, suite.sqldb).Return(suite.refreshToken, nil)
@@ -122,6 +123,7 @@ func (suite *GrantTokenFromRefreshTokenSuiteTest) TestValidateTokenGrantSuiteTes
 			)
 
 			accessTokenJSON, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
+			suite.refreshTokenJSON = suite.formatter.RefreshToken(suite.refreshToken)
 			byteAccessToken, _ := json.Marshal(accessTokenJSON)
 			byteExpectedAccessToken, _ := json.Marshal(suite.formatter.AccessToken(suite.accessToken, "", &suite.refreshTokenJSON))
 			suite.Assert().Nil(err)
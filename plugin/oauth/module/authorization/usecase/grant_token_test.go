@@ -76,7 +76,7 @@ func (suite *GrantTokenSuiteTest) TestGrantTokenSuiteTest() {
 		suite.Subtest("When grant type is refresh token but refresh token config is inactive, then it would return error", func() {
 			suite.accessTokenRequestJSON.GrantType = util.ValueToPointer("refresh_token")
 			suite.config.Config.RefreshToken.Active = false
-			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, nil, nil)
 
 			_, err := suite.authorization.GrantToken(suite.ktx, suite.accessTokenRequestJSON)
 			suite.Assert().NotNil(err)
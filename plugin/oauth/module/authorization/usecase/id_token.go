@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+// idToken add an openid connect id token to the access token response when the openid scope is granted
+func (a *Authorization) idToken(ktx kontext.Context, data entity.OauthAccessTokenJSON, e entity.OauthAccessToken, application entity.OauthApplication, nonce string) (entity.OauthAccessTokenJSON, jsonapi.Errors) {
+	if a.idTokenSigner == nil || !entity.HasScope(e.Scopes.String, entity.ScopeOpenID) {
+		return data, nil
+	}
+
+	idToken, err := a.idTokenSigner.SignIDToken(a.formatter.IDTokenClaims(e, application, nonce), *data.Token)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Stack().
+			Interface("request_id", ktx.GetWithoutCheck("request_id")).
+			Array("tags", zerolog.Arr().Str("service").Str("authorization").Str("sign_id_token")).
+			Msg("Error signing id token")
+
+		return entity.OauthAccessTokenJSON{}, jsonapi.BuildResponse(a.apiError.InternalServerError(ktx)).Errors
+	}
+
+	data.IDToken = &idToken
+	return data, nil
+}
@@ -8,12 +8,13 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/util"
 )
 
 // ImplicitGrant implementation refer to this RFC 6749 Section 4.2 https://www.rfc-editor.org/rfc/rfc6749#section-4.2
 // In altair we implement only confidential oauth application that can request implicit grant
 func (a *Authorization) ImplicitGrant(ktx kontext.Context, authorizationReq entity.AuthorizationRequestJSON) (entity.OauthAccessTokenJSON, jsonapi.Errors) {
-	var finalOauthTokenJSON entity.OauthAccessTokenJSON
+	var finalOauthAccessToken entity.OauthAccessToken
 
 	oauthApplication, jsonError := a.FindAndValidateApplication(ktx, authorizationReq.ClientUID, authorizationReq.ClientSecret)
 	if jsonError != nil {
@@ -25,7 +26,9 @@ func (a *Authorization) ImplicitGrant(ktx kontext.Context, authorizationReq enti
 	}
 
 	exc := a.sqldb.Transaction(ktx, "authorization-implicit-grant", func(tx db.TX) exception.Exception {
-		id, err := a.oauthAccessTokenRepo.Create(ktx, a.formatter.AccessTokenFromAuthorizationRequestInsertable(authorizationReq, oauthApplication), tx)
+		oauthAccessTokenInsertable := a.formatter.AccessTokenFromAuthorizationRequestInsertable(authorizationReq, oauthApplication)
+
+		id, err := a.oauthAccessTokenRepo.Create(ktx, oauthAccessTokenInsertable, tx)
 		if err != nil {
 			return exception.Throw(err, exception.WithDetail("error creating new oauth access token"), exception.WithType(exception.Unexpected), exception.WithTitle("access token creation error"))
 		}
@@ -34,13 +37,19 @@ func (a *Authorization) ImplicitGrant(ktx kontext.Context, authorizationReq enti
 		if err != nil {
 			return exception.Throw(err, exception.WithDetail("error selecting newly created access token"), exception.WithType(exception.Unexpected), exception.WithTitle("access token creation error"))
 		}
+		oauthAccessToken.Token = oauthAccessTokenInsertable.Token
 
-		finalOauthTokenJSON = a.formatter.AccessToken(oauthAccessToken, *authorizationReq.RedirectURI, nil)
+		finalOauthAccessToken = oauthAccessToken
 		return nil
 	})
 	if exc != nil {
 		return entity.OauthAccessTokenJSON{}, a.exceptionMapping(ktx, exc, zerolog.Arr().Str("service").Str("authorization").Str("grant_token"))
 	}
 
-	return finalOauthTokenJSON, nil
+	oauthAccessTokenJSON, jsonError := a.accessToken(ktx, finalOauthAccessToken, *authorizationReq.RedirectURI, nil)
+	if jsonError != nil {
+		return entity.OauthAccessTokenJSON{}, jsonError
+	}
+
+	return a.idToken(ktx, oauthAccessTokenJSON, finalOauthAccessToken, oauthApplication, util.PointerToValue(authorizationReq.Nonce))
 }
@@ -14,6 +14,8 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase"
+	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase/mock"
 	"github.com/kodefluence/altair/util"
 )
 
@@ -68,6 +70,7 @@ func (suite *ImplicitGrantSuiteTest) TestImplicitGrant() {
 				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-implicit-grant", gomock.Any()).DoAndReturn(func(ktx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
 					suite.oauthAccessTokenRepo.EXPECT().Create(ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
 						suite.Assert().Equal(suite.oauthApplication.ID, data.OauthApplicationID)
+						suite.accessToken.Token = data.Token
 						return suite.accessToken.ID, nil
 					})
 					suite.oauthAccessTokenRepo.EXPECT().One(ktx, suite.accessToken.ID, suite.sqldb).Return(suite.accessToken, nil)
@@ -81,6 +84,36 @@ func (suite *ImplicitGrantSuiteTest) TestImplicitGrant() {
 			suite.Assert().Nil(err)
 			suite.Assert().Equal(suite.formatter.AccessToken(suite.accessToken, *suite.authorizationRequestJSON.RedirectURI, nil), finalJson)
 		})
+
+		suite.Subtest("When openid scope is requested with nonce, it would return oauth access token with id token", func() {
+			idTokenSigner := mock.NewMockIDTokenSigner(suite.mockCtrl)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, nil, idTokenSigner)
+
+			suite.oauthApplication.Scopes = sql.NullString{String: "openid public", Valid: true}
+			suite.authorizationRequestJSON.Scopes = util.ValueToPointer("openid")
+			suite.authorizationRequestJSON.Nonce = util.ValueToPointer("n-0S6_WzA2Mj")
+			suite.accessToken.Scopes = sql.NullString{String: "openid", Valid: true}
+
+			gomock.InOrder(
+				suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.authorizationRequestJSON.ClientUID, *suite.authorizationRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil),
+				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-implicit-grant", gomock.Any()).DoAndReturn(func(ktx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
+					suite.oauthAccessTokenRepo.EXPECT().Create(ktx, gomock.Any(), suite.sqldb).DoAndReturn(func(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
+						suite.accessToken.Token = data.Token
+						return suite.accessToken.ID, nil
+					})
+					suite.oauthAccessTokenRepo.EXPECT().One(ktx, suite.accessToken.ID, suite.sqldb).Return(suite.accessToken, nil)
+					return f(suite.sqldb)
+				}),
+				idTokenSigner.EXPECT().SignIDToken(suite.formatter.IDTokenClaims(suite.accessToken, suite.oauthApplication, "n-0S6_WzA2Mj"), gomock.Any()).DoAndReturn(func(claims entity.IDTokenClaims, accessToken string) (string, error) {
+					suite.Assert().Equal(suite.accessToken.Token, accessToken)
+					return "header.claims.signature", nil
+				}),
+			)
+
+			finalJson, err := suite.authorization.ImplicitGrant(suite.ktx, suite.authorizationRequestJSON)
+			suite.Assert().Nil(err)
+			suite.Assert().Equal("header.claims.signature", *finalJson.IDToken)
+		})
 	})
 
 	suite.Run("Negative cases", func() {
@@ -143,6 +176,42 @@ func (suite *ImplicitGrantSuiteTest) TestImplicitGrant() {
 			suite.Assert().Equal(http.StatusInternalServerError, err.HTTPStatus())
 			suite.Assert().Equal(entity.OauthAccessTokenJSON{}, finalJson)
 		})
+
+		suite.Subtest("When openid scope is requested without nonce, then it would return error", func() {
+			idTokenSigner := mock.NewMockIDTokenSigner(suite.mockCtrl)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, nil, idTokenSigner)
+
+			suite.oauthApplication.Scopes = sql.NullString{String: "openid public", Valid: true}
+			suite.authorizationRequestJSON.Scopes = util.ValueToPointer("openid")
+			suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.authorizationRequestJSON.ClientUID, *suite.authorizationRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil)
+
+			finalJson, err := suite.authorization.ImplicitGrant(suite.ktx, suite.authorizationRequestJSON)
+			suite.Assert().Equal("JSONAPI Error:\n[Validation error] Detail: Validation error because of: nonce can't be empty when openid scope is requested with response_type token, Code: ERR1442\n", err.Error())
+			suite.Assert().Equal(entity.OauthAccessTokenJSON{}, finalJson)
+		})
+
+		suite.Subtest("When signing id token failed, then it would return error", func() {
+			idTokenSigner := mock.NewMockIDTokenSigner(suite.mockCtrl)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, nil, idTokenSigner)
+
+			suite.oauthApplication.Scopes = sql.NullString{String: "openid public", Valid: true}
+			suite.authorizationRequestJSON.Scopes = util.ValueToPointer("openid")
+			suite.authorizationRequestJSON.Nonce = util.ValueToPointer("n-0S6_WzA2Mj")
+			suite.accessToken.Scopes = sql.NullString{String: "openid", Valid: true}
+
+			gomock.InOrder(
+				suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, *suite.authorizationRequestJSON.ClientUID, *suite.authorizationRequestJSON.ClientSecret, suite.sqldb).Return(suite.oauthApplication, nil),
+				suite.sqldb.EXPECT().Transaction(suite.ktx, "authorization-implicit-grant", gomock.Any()).DoAndReturn(func(ktx kontext.Context, transactionKey string, f func(tx db.TX) exception.Exception) exception.Exception {
+					suite.oauthAccessTokenRepo.EXPECT().Create(ktx, gomock.Any(), suite.sqldb).Return(suite.accessToken.ID, nil)
+					suite.oauthAccessTokenRepo.EXPECT().One(ktx, suite.accessToken.ID, suite.sqldb).Return(suite.accessToken, nil)
+					return f(suite.sqldb)
+				}),
+				idTokenSigner.EXPECT().SignIDToken(gomock.Any(), gomock.Any()).Return("", errors.New("unexpected")),
+			)
+
+			_, err := suite.authorization.ImplicitGrant(suite.ktx, suite.authorizationRequestJSON)
+			suite.Assert().Equal(http.StatusInternalServerError, err.HTTPStatus())
+		})
 	})
 }
 
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+// Introspect tell whether the given access token or refresh token is active, see RFC 7662.
+// The token is searched as the type given by token_type_hint first, then as the other type.
+func (a *Authorization) Introspect(ktx kontext.Context, introspectionReq entity.IntrospectionRequestJSON) (entity.IntrospectionJSON, jsonapi.Errors) {
+	if introspectionReq.Token == nil {
+		return entity.IntrospectionJSON{}, jsonapi.BuildResponse(
+			a.apiError.ValidationError("token cannot be empty"),
+		).Errors
+	}
+
+	if _, jsonapierr := a.FindAndValidateApplication(ktx, introspectionReq.ClientUID, introspectionReq.ClientSecret); jsonapierr != nil {
+		return entity.IntrospectionJSON{}, jsonapierr
+	}
+
+	lookups := []func(kontext.Context, string) (entity.IntrospectionJSON, exception.Exception){a.introspectAccessToken, a.introspectRefreshToken}
+	if introspectionReq.TokenTypeHint != nil && *introspectionReq.TokenTypeHint == "refresh_token" {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+
+	for _, lookup := range lookups {
+		introspection, exc := lookup(ktx, *introspectionReq.Token)
+		if exc != nil {
+			return entity.IntrospectionJSON{}, a.exceptionMapping(ktx, exc, zerolog.Arr().Str("service").Str("authorization").Str("introspect"))
+		}
+
+		if introspection.Active {
+			return introspection, nil
+		}
+	}
+
+	return entity.IntrospectionJSON{Active: false}, nil
+}
+
+func (a *Authorization) introspectAccessToken(ktx kontext.Context, token string) (entity.IntrospectionJSON, exception.Exception) {
+	accessToken, exc := a.oneAccessToken(ktx, token)
+	if exc != nil {
+		return a.inactiveWhenNotFound(exc, "access token cannot be found because there was an error")
+	}
+
+	if time.Now().After(accessToken.ExpiresIn) {
+		return entity.IntrospectionJSON{Active: false}, nil
+	}
+
+	application, exc := a.activeApplication(ktx, accessToken.OauthApplicationID)
+	if exc != nil || application == nil {
+		return entity.IntrospectionJSON{Active: false}, exc
+	}
+
+	return a.formatter.AccessTokenIntrospection(accessToken, *application), nil
+}
+
+func (a *Authorization) introspectRefreshToken(ktx kontext.Context, token string) (entity.IntrospectionJSON, exception.Exception) {
+	refreshToken, exc := a.oauthRefreshTokenRepo.OneByToken(ktx, token, a.sqldb)
+	if exc != nil {
+		return a.inactiveWhenNotFound(exc, "refresh token cannot be found because there was an error")
+	}
+
+	if refreshToken.RevokedAT.Valid || time.Now().After(refreshToken.ExpiresIn) {
+		return entity.IntrospectionJSON{Active: false}, nil
+	}
+
+	// A refresh token can't be exchanged anymore once its access token is revoked.
+	accessToken, exc := a.oauthAccessTokenRepo.One(ktx, refreshToken.OauthAccessTokenID, a.sqldb)
+	if exc != nil {
+		return a.inactiveWhenNotFound(exc, "access token cannot be found because there was an error")
+	}
+
+	application, exc := a.activeApplication(ktx, accessToken.OauthApplicationID)
+	if exc != nil || application == nil {
+		return entity.IntrospectionJSON{Active: false}, exc
+	}
+
+	return a.formatter.RefreshTokenIntrospection(refreshToken, accessToken, *application), nil
+}
+
+// activeApplication returns nil when the application of a token is revoked.
+func (a *Authorization) activeApplication(ktx kontext.Context, ID int) (*entity.OauthApplication, exception.Exception) {
+	application, exc := a.oauthApplicationRepo.One(ktx, ID, a.sqldb)
+	if exc != nil {
+		if exc.Type() == exception.NotFound {
+			return nil, nil
+		}
+
+		return nil, exception.Throw(exc, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail("error find oauth applications"))
+	}
+
+	if application.RevokedAt.Valid {
+		return nil, nil
+	}
+
+	return &application, nil
+}
+
+func (a *Authorization) inactiveWhenNotFound(exc exception.Exception, detail string) (entity.IntrospectionJSON, exception.Exception) {
+	if exc.Type() == exception.NotFound {
+		return entity.IntrospectionJSON{Active: false}, nil
+	}
+
+	return entity.IntrospectionJSON{}, exception.Throw(exc, exception.WithType(exception.Unexpected), exception.WithTitle("Internal Server Error"), exception.WithDetail(detail))
+}
@@ -0,0 +1,204 @@
+package usecase_test
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase"
+	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase/mock"
+	"github.com/kodefluence/altair/util"
+)
+
+type IntrospectSuiteTest struct {
+	*AuthorizationBaseSuiteTest
+
+	introspectionRequest entity.IntrospectionRequestJSON
+	oauthApplication     entity.OauthApplication
+	oauthAccessToken     entity.OauthAccessToken
+	oauthRefreshToken    entity.OauthRefreshToken
+}
+
+func TestIntrospect(t *testing.T) {
+	suite.Run(t, &IntrospectSuiteTest{
+		AuthorizationBaseSuiteTest: &AuthorizationBaseSuiteTest{},
+	})
+}
+
+func (suite *IntrospectSuiteTest) SetupTest() {
+	suite.introspectionRequest = entity.IntrospectionRequestJSON{
+		Token:        util.ValueToPointer("some-token"),
+		ClientUID:    util.ValueToPointer("client_uid"),
+		ClientSecret: util.ValueToPointer("client_secret"),
+	}
+	suite.oauthApplication = entity.OauthApplication{
+		ID:        1,
+		ClientUID: "token_client_uid",
+	}
+	suite.oauthAccessToken = entity.OauthAccessToken{
+		ID:                 1,
+		OauthApplicationID: 1,
+		ResourceOwnerID:    2,
+		Token:              "some-token",
+		Scopes:             sql.NullString{String: "users", Valid: true},
+		ExpiresIn:          time.Now().Add(time.Hour),
+		CreatedAt:          time.Now(),
+	}
+	suite.oauthRefreshToken = entity.OauthRefreshToken{
+		ID:                 1,
+		OauthAccessTokenID: 1,
+		Token:              "some-token",
+		ExpiresIn:          time.Now().Add(time.Hour * 24),
+		CreatedAt:          time.Now(),
+	}
+}
+
+func (suite *IntrospectSuiteTest) Subtest(testcase string, subtest func()) {
+	suite.SetupTest()
+	suite.AuthorizationBaseSuiteTest.Subtest(testcase, subtest)
+	suite.TearDownTest()
+}
+
+func (suite *IntrospectSuiteTest) expectCaller() {
+	suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, "client_uid", "client_secret", suite.sqldb).Return(entity.OauthApplication{ID: 3}, nil)
+}
+
+func (suite *IntrospectSuiteTest) notFound() exception.Exception {
+	return exception.Throw(errors.New("not found"), exception.WithType(exception.NotFound))
+}
+
+func (suite *IntrospectSuiteTest) TestIntrospect() {
+	suite.Run("Positive cases", func() {
+		suite.Subtest("When token is an active access token, then it would return active introspection", func() {
+			suite.expectCaller()
+			suite.oauthAccessTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(suite.oauthAccessToken, nil)
+			suite.oauthApplicationRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.oauthApplication, nil)
+
+			introspection, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Nil(err)
+			suite.Assert().True(introspection.Active)
+			suite.Assert().Equal("users", *introspection.Scope)
+			suite.Assert().Equal("token_client_uid", *introspection.ClientID)
+			suite.Assert().Equal("2", *introspection.Sub)
+			suite.Assert().Equal(int(suite.oauthAccessToken.ExpiresIn.Unix()), *introspection.Exp)
+			suite.Assert().Equal(int(suite.oauthAccessToken.CreatedAt.Unix()), *introspection.Iat)
+			suite.Assert().Equal("access_token", *introspection.TokenType)
+		})
+
+		suite.Subtest("When token is a jwt access token, then it would introspect the access token of its claims", func() {
+			tokenSigner := mock.NewMockTokenSigner(suite.mockCtrl)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, tokenSigner, nil)
+
+			suite.expectCaller()
+			tokenSigner.EXPECT().VerifyAccessToken("some-token").Return(entity.AccessTokenClaims{ID: "jti", OauthAccessTokenID: 1}, nil)
+			suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.oauthAccessToken, nil)
+			suite.oauthApplicationRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.oauthApplication, nil)
+
+			introspection, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Nil(err)
+			suite.Assert().True(introspection.Active)
+			suite.Assert().Equal("access_token", *introspection.TokenType)
+		})
+
+		suite.Subtest("When token is an active refresh token, then it would return active introspection", func() {
+			suite.expectCaller()
+			suite.oauthAccessTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(entity.OauthAccessToken{}, suite.notFound())
+			suite.oauthRefreshTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(suite.oauthRefreshToken, nil)
+			suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.oauthAccessToken, nil)
+			suite.oauthApplicationRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.oauthApplication, nil)
+
+			introspection, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Nil(err)
+			suite.Assert().True(introspection.Active)
+			suite.Assert().Equal("2", *introspection.Sub)
+			suite.Assert().Equal(int(suite.oauthRefreshToken.ExpiresIn.Unix()), *introspection.Exp)
+			suite.Assert().Equal("refresh_token", *introspection.TokenType)
+		})
+
+		suite.Subtest("When token type hint is refresh_token, then it would look for refresh token first", func() {
+			suite.introspectionRequest.TokenTypeHint = util.ValueToPointer("refresh_token")
+			suite.expectCaller()
+			suite.oauthRefreshTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(suite.oauthRefreshToken, nil)
+			suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.oauthAccessToken, nil)
+			suite.oauthApplicationRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.oauthApplication, nil)
+
+			introspection, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Nil(err)
+			suite.Assert().Equal("refresh_token", *introspection.TokenType)
+		})
+
+		suite.Subtest("When token is not found, then it would return inactive introspection", func() {
+			suite.expectCaller()
+			suite.oauthAccessTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(entity.OauthAccessToken{}, suite.notFound())
+			suite.oauthRefreshTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(entity.OauthRefreshToken{}, suite.notFound())
+
+			introspection, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Nil(err)
+			suite.Assert().Equal(entity.IntrospectionJSON{Active: false}, introspection)
+		})
+
+		suite.Subtest("When access token is expired, then it would return inactive introspection", func() {
+			suite.oauthAccessToken.ExpiresIn = time.Now().Add(-time.Hour)
+			suite.expectCaller()
+			suite.oauthAccessTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(suite.oauthAccessToken, nil)
+			suite.oauthRefreshTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(entity.OauthRefreshToken{}, suite.notFound())
+
+			introspection, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Nil(err)
+			suite.Assert().False(introspection.Active)
+		})
+
+		suite.Subtest("When the access token of refresh token is revoked, then it would return inactive introspection", func() {
+			suite.introspectionRequest.TokenTypeHint = util.ValueToPointer("refresh_token")
+			suite.expectCaller()
+			suite.oauthRefreshTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(suite.oauthRefreshToken, nil)
+			suite.oauthAccessTokenRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(entity.OauthAccessToken{}, suite.notFound())
+			suite.oauthAccessTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(entity.OauthAccessToken{}, suite.notFound())
+
+			introspection, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Nil(err)
+			suite.Assert().False(introspection.Active)
+		})
+
+		suite.Subtest("When the application of the token is revoked, then it would return inactive introspection", func() {
+			suite.oauthApplication.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			suite.expectCaller()
+			suite.oauthAccessTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(suite.oauthAccessToken, nil)
+			suite.oauthApplicationRepo.EXPECT().One(suite.ktx, 1, suite.sqldb).Return(suite.oauthApplication, nil)
+			suite.oauthRefreshTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(entity.OauthRefreshToken{}, suite.notFound())
+
+			introspection, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Nil(err)
+			suite.Assert().False(introspection.Active)
+		})
+	})
+
+	suite.Run("Negative cases", func() {
+		suite.Subtest("When token is nil, then it would return error", func() {
+			suite.introspectionRequest.Token = nil
+			_, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Equal("JSONAPI Error:\n[Validation error] Detail: Validation error because of: token cannot be empty, Code: ERR1442\n", err.Error())
+			suite.Assert().Equal(http.StatusUnprocessableEntity, err.HTTPStatus())
+		})
+
+		suite.Subtest("When application credentials are not found, then it would return error", func() {
+			suite.oauthApplicationRepo.EXPECT().OneByUIDandSecret(suite.ktx, "client_uid", "client_secret", suite.sqldb).Return(entity.OauthApplication{}, suite.notFound())
+			_, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Equal(http.StatusNotFound, err.HTTPStatus())
+		})
+
+		suite.Subtest("When finding access token return unexpected error, then it would return error", func() {
+			suite.expectCaller()
+			suite.oauthAccessTokenRepo.EXPECT().OneByToken(suite.ktx, "some-token", suite.sqldb).Return(entity.OauthAccessToken{}, exception.Throw(errors.New("unexpected"), exception.WithType(exception.Unexpected)))
+
+			_, err := suite.authorization.Introspect(suite.ktx, suite.introspectionRequest)
+			suite.Assert().Equal(http.StatusInternalServerError, err.HTTPStatus())
+		})
+	})
+}
@@ -79,6 +79,20 @@ func (mr *MockFormatterMockRecorder) AccessToken(e, redirectURI, refreshTokenJSO
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccessToken", reflect.TypeOf((*MockFormatter)(nil).AccessToken), e, redirectURI, refreshTokenJSON)
 }
 
+// AccessTokenClaims mocks base method.
+func (m *MockFormatter) AccessTokenClaims(e entity.OauthAccessToken) entity.AccessTokenClaims {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccessTokenClaims", e)
+	ret0, _ := ret[0].(entity.AccessTokenClaims)
+	return ret0
+}
+
+// AccessTokenClaims indicates an expected call of AccessTokenClaims.
+func (mr *MockFormatterMockRecorder) AccessTokenClaims(e interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccessTokenClaims", reflect.TypeOf((*MockFormatter)(nil).AccessTokenClaims), e)
+}
+
 // AccessTokenClientCredentialInsertable mocks base method.
 func (m *MockFormatter) AccessTokenClientCredentialInsertable(application entity.OauthApplication, scope *string) entity.OauthAccessTokenInsertable {
 	m.ctrl.T.Helper()
@@ -135,6 +149,34 @@ func (mr *MockFormatterMockRecorder) AccessTokenFromOauthRefreshTokenInsertable(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccessTokenFromOauthRefreshTokenInsertable", reflect.TypeOf((*MockFormatter)(nil).AccessTokenFromOauthRefreshTokenInsertable), application, accessToken)
 }
 
+// AccessTokenIntrospection mocks base method.
+func (m *MockFormatter) AccessTokenIntrospection(e entity.OauthAccessToken, application entity.OauthApplication) entity.IntrospectionJSON {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccessTokenIntrospection", e, application)
+	ret0, _ := ret[0].(entity.IntrospectionJSON)
+	return ret0
+}
+
+// AccessTokenIntrospection indicates an expected call of AccessTokenIntrospection.
+func (mr *MockFormatterMockRecorder) AccessTokenIntrospection(e, application interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccessTokenIntrospection", reflect.TypeOf((*MockFormatter)(nil).AccessTokenIntrospection), e, application)
+}
+
+// IDTokenClaims mocks base method.
+func (m *MockFormatter) IDTokenClaims(e entity.OauthAccessToken, application entity.OauthApplication, nonce string) entity.IDTokenClaims {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IDTokenClaims", e, application, nonce)
+	ret0, _ := ret[0].(entity.IDTokenClaims)
+	return ret0
+}
+
+// IDTokenClaims indicates an expected call of IDTokenClaims.
+func (mr *MockFormatterMockRecorder) IDTokenClaims(e, application, nonce interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IDTokenClaims", reflect.TypeOf((*MockFormatter)(nil).IDTokenClaims), e, application, nonce)
+}
+
 // OauthApplicationInsertable mocks base method.
 func (m *MockFormatter) OauthApplicationInsertable(r entity.OauthApplicationJSON) entity.OauthApplicationInsertable {
 	m.ctrl.T.Helper()
@@ -177,6 +219,20 @@ func (mr *MockFormatterMockRecorder) RefreshTokenInsertable(application, accessT
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokenInsertable", reflect.TypeOf((*MockFormatter)(nil).RefreshTokenInsertable), application, accessToken)
 }
 
+// RefreshTokenIntrospection mocks base method.
+func (m *MockFormatter) RefreshTokenIntrospection(e entity.OauthRefreshToken, accessToken entity.OauthAccessToken, application entity.OauthApplication) entity.IntrospectionJSON {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokenIntrospection", e, accessToken, application)
+	ret0, _ := ret[0].(entity.IntrospectionJSON)
+	return ret0
+}
+
+// RefreshTokenIntrospection indicates an expected call of RefreshTokenIntrospection.
+func (mr *MockFormatterMockRecorder) RefreshTokenIntrospection(e, accessToken, application interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokenIntrospection", reflect.TypeOf((*MockFormatter)(nil).RefreshTokenIntrospection), e, accessToken, application)
+}
+
 // MockOauthAccessGrantRepository is a mock of OauthAccessGrantRepository interface.
 type MockOauthAccessGrantRepository struct {
 	ctrl     *gomock.Controller
@@ -341,6 +397,20 @@ func (mr *MockOauthAccessTokenRepositoryMockRecorder) Revoke(ktx, token, tx inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockOauthAccessTokenRepository)(nil).Revoke), ktx, token, tx)
 }
 
+// RevokeByID mocks base method.
+func (m *MockOauthAccessTokenRepository) RevokeByID(ktx kontext.Context, ID int, tx db.TX) exception.Exception {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeByID", ktx, ID, tx)
+	ret0, _ := ret[0].(exception.Exception)
+	return ret0
+}
+
+// RevokeByID indicates an expected call of RevokeByID.
+func (mr *MockOauthAccessTokenRepositoryMockRecorder) RevokeByID(ktx, ID, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeByID", reflect.TypeOf((*MockOauthAccessTokenRepository)(nil).RevokeByID), ktx, ID, tx)
+}
+
 // MockOauthApplicationRepository is a mock of OauthApplicationRepository interface.
 type MockOauthApplicationRepository struct {
 	ctrl     *gomock.Controller
@@ -409,6 +479,21 @@ func (mr *MockOauthApplicationRepositoryMockRecorder) One(ktx, ID, tx interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "One", reflect.TypeOf((*MockOauthApplicationRepository)(nil).One), ktx, ID, tx)
 }
 
+// OneByUID mocks base method.
+func (m *MockOauthApplicationRepository) OneByUID(ktx kontext.Context, clientUID string, tx db.TX) (entity.OauthApplication, exception.Exception) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OneByUID", ktx, clientUID, tx)
+	ret0, _ := ret[0].(entity.OauthApplication)
+	ret1, _ := ret[1].(exception.Exception)
+	return ret0, ret1
+}
+
+// OneByUID indicates an expected call of OneByUID.
+func (mr *MockOauthApplicationRepositoryMockRecorder) OneByUID(ktx, clientUID, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OneByUID", reflect.TypeOf((*MockOauthApplicationRepository)(nil).OneByUID), ktx, clientUID, tx)
+}
+
 // OneByUIDandSecret mocks base method.
 func (m *MockOauthApplicationRepository) OneByUIDandSecret(ktx kontext.Context, clientUID, clientSecret string, tx db.TX) (entity.OauthApplication, exception.Exception) {
 	m.ctrl.T.Helper()
@@ -534,3 +619,94 @@ func (mr *MockOauthRefreshTokenRepositoryMockRecorder) Revoke(ktx, token, tx int
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockOauthRefreshTokenRepository)(nil).Revoke), ktx, token, tx)
 }
+
+// MockTokenSigner is a mock of TokenSigner interface.
+type MockTokenSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenSignerMockRecorder
+}
+
+// MockTokenSignerMockRecorder is the mock recorder for MockTokenSigner.
+type MockTokenSignerMockRecorder struct {
+	mock *MockTokenSigner
+}
+
+// NewMockTokenSigner creates a new mock instance.
+func NewMockTokenSigner(ctrl *gomock.Controller) *MockTokenSigner {
+	mock := &MockTokenSigner{ctrl: ctrl}
+	mock.recorder = &MockTokenSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenSigner) EXPECT() *MockTokenSignerMockRecorder {
+	return m.recorder
+}
+
+// Sign mocks base method.
+func (m *MockTokenSigner) Sign(claims entity.AccessTokenClaims) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", claims)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockTokenSignerMockRecorder) Sign(claims interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockTokenSigner)(nil).Sign), claims)
+}
+
+// VerifyAccessToken mocks base method.
+func (m *MockTokenSigner) VerifyAccessToken(token string) (entity.AccessTokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAccessToken", token)
+	ret0, _ := ret[0].(entity.AccessTokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAccessToken indicates an expected call of VerifyAccessToken.
+func (mr *MockTokenSignerMockRecorder) VerifyAccessToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAccessToken", reflect.TypeOf((*MockTokenSigner)(nil).VerifyAccessToken), token)
+}
+
+// MockIDTokenSigner is a mock of IDTokenSigner interface.
+type MockIDTokenSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockIDTokenSignerMockRecorder
+}
+
+// MockIDTokenSignerMockRecorder is the mock recorder for MockIDTokenSigner.
+type MockIDTokenSignerMockRecorder struct {
+	mock *MockIDTokenSigner
+}
+
+// NewMockIDTokenSigner creates a new mock instance.
+func NewMockIDTokenSigner(ctrl *gomock.Controller) *MockIDTokenSigner {
+	mock := &MockIDTokenSigner{ctrl: ctrl}
+	mock.recorder = &MockIDTokenSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIDTokenSigner) EXPECT() *MockIDTokenSignerMockRecorder {
+	return m.recorder
+}
+
+// SignIDToken mocks base method.
+func (m *MockIDTokenSigner) SignIDToken(claims entity.IDTokenClaims, accessToken string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignIDToken", claims, accessToken)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignIDToken indicates an expected call of SignIDToken.
+func (mr *MockIDTokenSignerMockRecorder) SignIDToken(claims, accessToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIDToken", reflect.TypeOf((*MockIDTokenSigner)(nil).SignIDToken), claims, accessToken)
+}
@@ -17,7 +17,7 @@ func (a *Authorization) RevokeToken(ktx kontext.Context, revokeAccessTokenReq en
 		).Errors
 	}
 
-	exc := a.oauthAccessTokenRepo.Revoke(ktx, *revokeAccessTokenReq.Token, a.sqldb)
+	exc := a.revokeAccessToken(ktx, *revokeAccessTokenReq.Token)
 	if exc != nil {
 		return a.exceptionMapping(ktx, exc, zerolog.Arr().Str("service").Str("authorization").Str("revoke_token"))
 	}
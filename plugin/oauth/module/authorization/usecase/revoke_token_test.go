@@ -9,6 +9,8 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase"
+	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase/mock"
 	"github.com/kodefluence/altair/util"
 )
 
@@ -43,6 +45,26 @@ func (suite *RevokeTokenSuiteTest) TestRevokeToken() {
 			err := suite.authorization.RevokeToken(suite.ktx, suite.revokeRequest)
 			suite.Assert().Nil(err)
 		})
+
+		suite.Subtest("When token is a jwt access token, then it would revoke the access token of its claims", func() {
+			tokenSigner := mock.NewMockTokenSigner(suite.mockCtrl)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, tokenSigner, nil)
+
+			tokenSigner.EXPECT().VerifyAccessToken(*suite.revokeRequest.Token).Return(entity.AccessTokenClaims{ID: "jti", OauthAccessTokenID: 5}, nil)
+			suite.oauthAccessTokenRepo.EXPECT().RevokeByID(suite.ktx, 5, suite.sqldb).Return(nil)
+			err := suite.authorization.RevokeToken(suite.ktx, suite.revokeRequest)
+			suite.Assert().Nil(err)
+		})
+
+		suite.Subtest("When token format is jwt but token is opaque, then it would revoke the token as is", func() {
+			tokenSigner := mock.NewMockTokenSigner(suite.mockCtrl)
+			suite.authorization = usecase.NewAuthorization(suite.oauthApplicationRepo, suite.oauthAccessTokenRepo, suite.oauthAccessGrantRepo, suite.oauthRefreshTokenRepo, suite.formatter, suite.config, suite.sqldb, suite.apiError, tokenSigner, nil)
+
+			tokenSigner.EXPECT().VerifyAccessToken(*suite.revokeRequest.Token).Return(entity.AccessTokenClaims{}, errors.New("invalid jwt"))
+			suite.oauthAccessTokenRepo.EXPECT().Revoke(suite.ktx, *suite.revokeRequest.Token, suite.sqldb).Return(nil)
+			err := suite.authorization.RevokeToken(suite.ktx, suite.revokeRequest)
+			suite.Assert().Nil(err)
+		})
 	})
 
 	suite.Run("Negative cases", func() {
@@ -67,5 +67,53 @@ func (a *Authorization) ValidateAuthorizationGrant(ktx kontext.Context, r entity
 		).Errors
 	}
 
+	if err := a.validateNonce(r); err != nil {
+		return err
+	}
+
+	if *r.ResponseType == "code" {
+		return a.validateCodeChallenge(ktx, r, application)
+	}
+
+	return nil
+}
+
+// validateNonce requires the nonce when an id token is issued straight from the
+// authorization request, the code flow carries it over to the token request.
+func (a *Authorization) validateNonce(r entity.AuthorizationRequestJSON) jsonapi.Errors {
+	if r.Nonce != nil && len(*r.Nonce) > 255 {
+		return jsonapi.BuildResponse(a.apiError.ValidationError("nonce can't be longer than 255 characters")).Errors
+	}
+
+	if a.idTokenSigner != nil && *r.ResponseType == "token" && entity.HasScope(*r.Scopes, entity.ScopeOpenID) && util.PointerToValue(r.Nonce) == "" {
+		return jsonapi.BuildResponse(a.apiError.ValidationError("nonce can't be empty when openid scope is requested with response_type token")).Errors
+	}
+
+	return nil
+}
+
+func (a *Authorization) validateCodeChallenge(ktx kontext.Context, r entity.AuthorizationRequestJSON, application entity.OauthApplication) jsonapi.Errors {
+	if r.CodeChallenge == nil {
+		if r.CodeChallengeMethod != nil {
+			return jsonapi.BuildResponse(a.apiError.ValidationError("code_challenge can't be empty when code_challenge_method is set")).Errors
+		}
+
+		if application.PKCERequired {
+			return jsonapi.BuildResponse(
+				a.apiError.ForbiddenError(ktx, "authorization_code", "this application requires pkce, code_challenge can't be empty"),
+			).Errors
+		}
+
+		return nil
+	}
+
+	if r.CodeChallengeMethod != nil && *r.CodeChallengeMethod != entity.CodeChallengeMethodS256 && *r.CodeChallengeMethod != entity.CodeChallengeMethodPlain {
+		return jsonapi.BuildResponse(a.apiError.ValidationError("code_challenge_method is invalid. Should be either `S256` or `plain`")).Errors
+	}
+
+	if !entity.ValidPKCEValue(*r.CodeChallenge) {
+		return jsonapi.BuildResponse(a.apiError.ValidationError("code_challenge must be 43 to 128 characters of letters, digits, `-`, `.`, `_` or `~`")).Errors
+	}
+
 	return nil
 }
@@ -3,6 +3,7 @@ package usecase_test
 import (
 	"database/sql"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -83,6 +84,66 @@ func (suite *ValidateAuthorizationGrantSuiteTest) TestValidateAuthorizationGrant
 			suite.Assert().Equal(http.StatusForbidden, err.HTTPStatus())
 		})
 	})
+
+	suite.Run("PKCE", func() {
+		suite.Subtest("When code challenge is valid, then it would return nil", func() {
+			suite.authorizationRequestJSON.CodeChallenge = util.ValueToPointer("E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM")
+			suite.authorizationRequestJSON.CodeChallengeMethod = util.ValueToPointer("S256")
+			suite.oauthApplication.PKCERequired = true
+			err := suite.authorization.ValidateAuthorizationGrant(suite.ktx, suite.authorizationRequestJSON, suite.oauthApplication)
+			suite.Assert().Nil(err)
+		})
+
+		suite.Subtest("When code challenge method is not set, then it would return nil", func() {
+			suite.authorizationRequestJSON.CodeChallenge = util.ValueToPointer("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+			err := suite.authorization.ValidateAuthorizationGrant(suite.ktx, suite.authorizationRequestJSON, suite.oauthApplication)
+			suite.Assert().Nil(err)
+		})
+
+		suite.Subtest("When application requires pkce but code challenge is empty, then it would return error", func() {
+			suite.oauthApplication.PKCERequired = true
+			err := suite.authorization.ValidateAuthorizationGrant(suite.ktx, suite.authorizationRequestJSON, suite.oauthApplication)
+			suite.Assert().Equal("JSONAPI Error:\n[Forbidden error] Detail: Resource of `authorization_code` is forbidden to be accessed, because of: this application requires pkce, code_challenge can't be empty. Tracing code: `<nil>`, Code: ERR0403\n", err.Error())
+			suite.Assert().Equal(http.StatusForbidden, err.HTTPStatus())
+		})
+
+		suite.Subtest("When code challenge method is set without code challenge, then it would return error", func() {
+			suite.authorizationRequestJSON.CodeChallengeMethod = util.ValueToPointer("S256")
+			err := suite.authorization.ValidateAuthorizationGrant(suite.ktx, suite.authorizationRequestJSON, suite.oauthApplication)
+			suite.Assert().Equal("JSONAPI Error:\n[Validation error] Detail: Validation error because of: code_challenge can't be empty when code_challenge_method is set, Code: ERR1442\n", err.Error())
+		})
+
+		suite.Subtest("When code challenge method is unknown, then it would return error", func() {
+			suite.authorizationRequestJSON.CodeChallenge = util.ValueToPointer("E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM")
+			suite.authorizationRequestJSON.CodeChallengeMethod = util.ValueToPointer("S512")
+			err := suite.authorization.ValidateAuthorizationGrant(suite.ktx, suite.authorizationRequestJSON, suite.oauthApplication)
+			suite.Assert().Equal("JSONAPI Error:\n[Validation error] Detail: Validation error because of: code_challenge_method is invalid. Should be either `S256` or `plain`, Code: ERR1442\n", err.Error())
+			suite.Assert().Equal(http.StatusUnprocessableEntity, err.HTTPStatus())
+		})
+
+		suite.Subtest("When code challenge is too short, then it would return error", func() {
+			suite.authorizationRequestJSON.CodeChallenge = util.ValueToPointer("short")
+			err := suite.authorization.ValidateAuthorizationGrant(suite.ktx, suite.authorizationRequestJSON, suite.oauthApplication)
+			suite.Assert().Equal(http.StatusUnprocessableEntity, err.HTTPStatus())
+		})
+	})
+
+	suite.Run("Nonce", func() {
+		suite.Subtest("When nonce is longer than 255 characters, then it would return error", func() {
+			suite.authorizationRequestJSON.Nonce = util.ValueToPointer(strings.Repeat("n", 256))
+			err := suite.authorization.ValidateAuthorizationGrant(suite.ktx, suite.authorizationRequestJSON, suite.oauthApplication)
+			suite.Assert().Equal("JSONAPI Error:\n[Validation error] Detail: Validation error because of: nonce can't be longer than 255 characters, Code: ERR1442\n", err.Error())
+		})
+
+		suite.Subtest("When openid is not active, then nonce is not required for response_type token", func() {
+			suite.authorizationRequestJSON.ResponseType = util.ValueToPointer("token")
+			suite.authorizationRequestJSON.Scopes = util.ValueToPointer("openid")
+			suite.oauthApplication.Scopes = sql.NullString{String: "openid", Valid: true}
+			suite.oauthApplication.OwnerType = "confidential"
+			err := suite.authorization.ValidateAuthorizationGrant(suite.ktx, suite.authorizationRequestJSON, suite.oauthApplication)
+			suite.Assert().Nil(err)
+		})
+	})
 }
 
 func (suite *ValidateAuthorizationGrantSuiteTest) Subtest(testcase string, subtest func()) {
@@ -10,7 +10,11 @@ import (
 	"github.com/kodefluence/altair/plugin/oauth/entity"
 )
 
-func (a *Authorization) ValidateTokenAuthorizationCode(ktx kontext.Context, r entity.AccessTokenRequestJSON, data entity.OauthAccessGrant) exception.Exception {
+func (a *Authorization) ValidateTokenAuthorizationCode(ktx kontext.Context, r entity.AccessTokenRequestJSON, data entity.OauthAccessGrant, application entity.OauthApplication) exception.Exception {
+	if data.OauthApplicationID != application.ID {
+		return exception.Throw(errors.New("forbidden"), exception.WithType(exception.Forbidden), exception.WithDetail("authorization code was issued to another application"), exception.WithTitle("Forbidden resource access"))
+	}
+
 	if data.RevokedAT.Valid {
 		return exception.Throw(errors.New("forbidden"), exception.WithType(exception.Forbidden), exception.WithDetail("authorization code already used"), exception.WithTitle("Forbidden resource access"))
 	}
@@ -23,5 +27,32 @@ func (a *Authorization) ValidateTokenAuthorizationCode(ktx kontext.Context, r en
 		return exception.Throw(errors.New("forbidden"), exception.WithType(exception.Forbidden), exception.WithDetail("redirect uri is different from one that generated before"), exception.WithTitle("Forbidden resource access"))
 	}
 
+	return a.validateCodeVerifier(r, data, application)
+}
+
+// validateCodeVerifier checks the PKCE code verifier against the code challenge
+// of the authorization code. A code verifier sent for an authorization code
+// without a code challenge is rejected as well.
+func (a *Authorization) validateCodeVerifier(r entity.AccessTokenRequestJSON, data entity.OauthAccessGrant, application entity.OauthApplication) exception.Exception {
+	if !data.CodeChallenge.Valid {
+		if application.PKCERequired {
+			return exception.Throw(errors.New("forbidden"), exception.WithType(exception.Forbidden), exception.WithDetail("authorization code was generated without code challenge"), exception.WithTitle("Forbidden resource access"))
+		}
+
+		if r.CodeVerifier != nil {
+			return exception.Throw(errors.New("forbidden"), exception.WithType(exception.Forbidden), exception.WithDetail("code verifier is not expected for this authorization code"), exception.WithTitle("Forbidden resource access"))
+		}
+
+		return nil
+	}
+
+	if r.CodeVerifier == nil {
+		return exception.Throw(errors.New("forbidden"), exception.WithType(exception.Forbidden), exception.WithDetail("code verifier can't be empty"), exception.WithTitle("Forbidden resource access"))
+	}
+
+	if !entity.VerifyCodeVerifier(*r.CodeVerifier, data.CodeChallenge.String, data.CodeChallengeMethod.String) {
+		return exception.Throw(errors.New("forbidden"), exception.WithType(exception.Forbidden), exception.WithDetail("code verifier does not match the code challenge"), exception.WithTitle("Forbidden resource access"))
+	}
+
 	return nil
 }
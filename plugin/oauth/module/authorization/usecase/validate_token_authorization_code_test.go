@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kodefluence/monorepo/exception"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/kodefluence/altair/plugin/oauth/entity"
@@ -16,6 +17,7 @@ type ValidateTokenAuthorizationCodeSuiteTest struct {
 
 	accessTokenRequestJSON entity.AccessTokenRequestJSON
 	accessGrant            entity.OauthAccessGrant
+	application            entity.OauthApplication
 }
 
 func TestValidateTokenAuthorizationCode(t *testing.T) {
@@ -47,23 +49,34 @@ func (suite *ValidateTokenAuthorizationCodeSuiteTest) SetupTest() {
 		CreatedAt: time.Now().Add(-24 * time.Hour),
 		RevokedAT: sql.NullTime{},
 	}
+	suite.application = entity.OauthApplication{
+		ID:        1,
+		OwnerType: "public",
+	}
 }
 
 func (suite *ValidateTokenAuthorizationCodeSuiteTest) TestValidateTokenGrantSuiteTest() {
 	suite.Run("Positive cases", func() {
 		suite.Subtest("When all parameters is valid, then it would return nil", func() {
-			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant)
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
 			suite.Assert().Nil(exc)
 		})
 	})
 
 	suite.Run("Negative cases", func() {
+		suite.Subtest("When access grant is issued to another application, then it would return jsonapi option", func() {
+			suite.accessGrant.OauthApplicationID = 2
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
+			suite.Assert().NotNil(exc)
+			suite.Assert().Equal("authorization code was issued to another application", exc.Detail())
+		})
+
 		suite.Subtest("When access grant is already revoked, then it would return jsonapi option", func() {
 			suite.accessGrant.RevokedAT = sql.NullTime{
 				Time:  time.Now().Add(-1 * time.Hour),
 				Valid: true,
 			}
-			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant)
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
 			suite.Assert().NotNil(exc)
 			suite.Assert().Equal("forbidden", exc.Error())
 			suite.Assert().Equal("Forbidden resource access", exc.Title())
@@ -72,7 +85,7 @@ func (suite *ValidateTokenAuthorizationCodeSuiteTest) TestValidateTokenGrantSuit
 
 		suite.Subtest("When access grant is already expired, then it would return jsonapi option", func() {
 			suite.accessGrant.ExpiresIn = time.Now().Add(-1 * time.Hour)
-			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant)
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
 			suite.Assert().NotNil(exc)
 			suite.Assert().Equal("forbidden", exc.Error())
 			suite.Assert().Equal("Forbidden resource access", exc.Title())
@@ -81,13 +94,67 @@ func (suite *ValidateTokenAuthorizationCodeSuiteTest) TestValidateTokenGrantSuit
 
 		suite.Subtest("When access grant redirect uri is different, then it would return jsonapi option", func() {
 			suite.accessGrant.RedirectURI.String = ""
-			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant)
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
 			suite.Assert().NotNil(exc)
 			suite.Assert().Equal("forbidden", exc.Error())
 			suite.Assert().Equal("Forbidden resource access", exc.Title())
 			suite.Assert().Equal("redirect uri is different from one that generated before", exc.Detail())
 		})
 	})
+
+	suite.Run("PKCE", func() {
+		codeVerifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+
+		withCodeChallenge := func() {
+			suite.accessGrant.CodeChallenge = sql.NullString{String: "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", Valid: true}
+			suite.accessGrant.CodeChallengeMethod = sql.NullString{String: "S256", Valid: true}
+		}
+
+		suite.Subtest("When code verifier matches the code challenge, then it would return nil", func() {
+			withCodeChallenge()
+			suite.accessTokenRequestJSON.CodeVerifier = util.ValueToPointer(codeVerifier)
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
+			suite.Assert().Nil(exc)
+		})
+
+		suite.Subtest("When plain code verifier matches the code challenge, then it would return nil", func() {
+			suite.accessGrant.CodeChallenge = sql.NullString{String: codeVerifier, Valid: true}
+			suite.accessGrant.CodeChallengeMethod = sql.NullString{String: "plain", Valid: true}
+			suite.accessTokenRequestJSON.CodeVerifier = util.ValueToPointer(codeVerifier)
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
+			suite.Assert().Nil(exc)
+		})
+
+		suite.Subtest("When code verifier is empty, then it would return exception", func() {
+			withCodeChallenge()
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
+			suite.Assert().NotNil(exc)
+			suite.Assert().Equal(exception.Forbidden, exc.Type())
+			suite.Assert().Equal("code verifier can't be empty", exc.Detail())
+		})
+
+		suite.Subtest("When code verifier does not match, then it would return exception", func() {
+			withCodeChallenge()
+			suite.accessTokenRequestJSON.CodeVerifier = util.ValueToPointer(codeVerifier[1:] + "a")
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
+			suite.Assert().NotNil(exc)
+			suite.Assert().Equal("code verifier does not match the code challenge", exc.Detail())
+		})
+
+		suite.Subtest("When code verifier is sent without code challenge, then it would return exception", func() {
+			suite.accessTokenRequestJSON.CodeVerifier = util.ValueToPointer(codeVerifier)
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
+			suite.Assert().NotNil(exc)
+			suite.Assert().Equal("code verifier is not expected for this authorization code", exc.Detail())
+		})
+
+		suite.Subtest("When application requires pkce and there is no code challenge, then it would return exception", func() {
+			suite.application.PKCERequired = true
+			exc := suite.authorization.ValidateTokenAuthorizationCode(suite.ktx, suite.accessTokenRequestJSON, suite.accessGrant, suite.application)
+			suite.Assert().NotNil(exc)
+			suite.Assert().Equal("authorization code was generated without code challenge", exc.Detail())
+		})
+	})
 }
 
 func (suite *ValidateTokenAuthorizationCodeSuiteTest) Subtest(testcase string, subtest func()) {
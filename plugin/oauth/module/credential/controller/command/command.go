@@ -0,0 +1,13 @@
+package command
+
+import (
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./command.go
+type Credential interface {
+	Rehash(ktx kontext.Context) (entity.RehashJSON, jsonapi.Errors)
+}
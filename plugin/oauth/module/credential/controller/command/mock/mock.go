@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./command.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/kodefluence/altair/plugin/oauth/entity"
+	jsonapi "github.com/kodefluence/monorepo/jsonapi"
+	kontext "github.com/kodefluence/monorepo/kontext"
+)
+
+// MockCredential is a mock of Credential interface.
+type MockCredential struct {
+	ctrl     *gomock.Controller
+	recorder *MockCredentialMockRecorder
+}
+
+// MockCredentialMockRecorder is the mock recorder for MockCredential.
+type MockCredentialMockRecorder struct {
+	mock *MockCredential
+}
+
+// NewMockCredential creates a new mock instance.
+func NewMockCredential(ctrl *gomock.Controller) *MockCredential {
+	mock := &MockCredential{ctrl: ctrl}
+	mock.recorder = &MockCredentialMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCredential) EXPECT() *MockCredentialMockRecorder {
+	return m.recorder
+}
+
+// Rehash mocks base method.
+func (m *MockCredential) Rehash(ktx kontext.Context) (entity.RehashJSON, jsonapi.Errors) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rehash", ktx)
+	ret0, _ := ret[0].(entity.RehashJSON)
+	ret1, _ := ret[1].(jsonapi.Errors)
+	return ret0, ret1
+}
+
+// Rehash indicates an expected call of Rehash.
+func (mr *MockCredentialMockRecorder) Rehash(ktx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rehash", reflect.TypeOf((*MockCredential)(nil).Rehash), ktx)
+}
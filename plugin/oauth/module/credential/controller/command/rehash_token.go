@@ -0,0 +1,52 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// RehashToken struct of RehashToken command
+type RehashToken struct {
+	credential Credential
+}
+
+// NewRehashToken return struct of RehashToken
+func NewRehashToken(credential Credential) *RehashToken {
+	return &RehashToken{
+		credential: credential,
+	}
+}
+
+// Use return name of command
+func (r *RehashToken) Use() string {
+	return "oauth/token:rehash"
+}
+
+// Short return short description of command
+func (r *RehashToken) Short() string {
+	return "Replace plaintext tokens and client secrets stored in database with their keyed hash"
+}
+
+// Example return example of command
+func (r *RehashToken) Example() string {
+	return "altair plugin oauth/token:rehash"
+}
+
+// Run run command
+func (r *RehashToken) Run(cmd *cobra.Command, args []string) {
+	result, err := r.credential.Rehash(kontext.Fabricate())
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	content, _ := json.Marshal(result)
+	fmt.Println("Success rehashing oauth credentials:", string(content))
+}
+
+// ModifyFlags modify flags of command
+func (r *RehashToken) ModifyFlags(flags *pflag.FlagSet) {}
@@ -0,0 +1,62 @@
+package command_test
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/controller"
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/module/credential/controller/command"
+	"github.com/kodefluence/altair/plugin/oauth/module/credential/controller/command/mock"
+	"github.com/kodefluence/altair/testhelper"
+)
+
+func TestRehashToken(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("Given no arguments, when command is executed then it should rehash the credentials", func(t *testing.T) {
+		cmd := &cobra.Command{
+			Use: "test",
+		}
+
+		credential := mock.NewMockCredential(mockCtrl)
+		credential.EXPECT().Rehash(gomock.Any()).Return(entity.RehashJSON{OauthApplications: 1, OauthAccessTokens: 2, OauthRefreshTokens: 3}, nil)
+
+		appController := controller.Provide(nil, nil, cmd)
+		appController.InjectCommand(command.NewRehashToken(credential))
+
+		// Given
+		cmd.SetArgs([]string{"oauth/token:rehash"})
+
+		// When
+		err := cmd.Execute()
+
+		// Then
+		assert.Nil(t, err)
+	})
+
+	t.Run("Given no arguments, when there is error in command execution then it should print the error", func(t *testing.T) {
+		cmd := &cobra.Command{
+			Use: "test",
+		}
+
+		credential := mock.NewMockCredential(mockCtrl)
+		credential.EXPECT().Rehash(gomock.Any()).Return(entity.RehashJSON{}, testhelper.ErrInternalServer())
+
+		appController := controller.Provide(nil, nil, cmd)
+		appController.InjectCommand(command.NewRehashToken(credential))
+
+		// Given
+		cmd.SetArgs([]string{"oauth/token:rehash"})
+
+		// When
+		err := cmd.Execute()
+
+		// Then
+		assert.Nil(t, err)
+	})
+}
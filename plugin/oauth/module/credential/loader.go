@@ -0,0 +1,21 @@
+package credential
+
+import (
+	"github.com/kodefluence/monorepo/db"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/oauth/module/credential/controller/command"
+	"github.com/kodefluence/altair/plugin/oauth/module/credential/usecase"
+)
+
+func LoadCommand(
+	appModule module.App,
+	sqldb db.DB,
+	oauthApplicationRepo usecase.Rehashable,
+	oauthAccessTokenRepo usecase.Rehashable,
+	oauthRefreshTokenRepo usecase.Rehashable,
+	apiError module.ApiError,
+) {
+	credential := usecase.NewCredential(sqldb, oauthApplicationRepo, oauthAccessTokenRepo, oauthRefreshTokenRepo, apiError)
+	appModule.Controller().InjectCommand(command.NewRehashToken(credential))
+}
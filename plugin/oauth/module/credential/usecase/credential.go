@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"github.com/kodefluence/monorepo/db"
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/kodefluence/monorepo/kontext"
+
+	"github.com/kodefluence/altair/module"
+)
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./credential.go
+
+// Rehashable replace plaintext values stored before hashing with their keyed hash
+type Rehashable interface {
+	Rehash(ktx kontext.Context, tx db.TX) (int, exception.Exception)
+}
+
+// Credential manage tokens and client secrets stored in database
+type Credential struct {
+	sqldb                 db.DB
+	oauthApplicationRepo  Rehashable
+	oauthAccessTokenRepo  Rehashable
+	oauthRefreshTokenRepo Rehashable
+	apiError              module.ApiError
+}
+
+func NewCredential(
+	sqldb db.DB,
+	oauthApplicationRepo Rehashable,
+	oauthAccessTokenRepo Rehashable,
+	oauthRefreshTokenRepo Rehashable,
+	apiError module.ApiError,
+) *Credential {
+	return &Credential{
+		sqldb:                 sqldb,
+		oauthApplicationRepo:  oauthApplicationRepo,
+		oauthAccessTokenRepo:  oauthAccessTokenRepo,
+		oauthRefreshTokenRepo: oauthRefreshTokenRepo,
+		apiError:              apiError,
+	}
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./credential.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	db "github.com/kodefluence/monorepo/db"
+	exception "github.com/kodefluence/monorepo/exception"
+	kontext "github.com/kodefluence/monorepo/kontext"
+)
+
+// MockRehashable is a mock of Rehashable interface.
+type MockRehashable struct {
+	ctrl     *gomock.Controller
+	recorder *MockRehashableMockRecorder
+}
+
+// MockRehashableMockRecorder is the mock recorder for MockRehashable.
+type MockRehashableMockRecorder struct {
+	mock *MockRehashable
+}
+
+// NewMockRehashable creates a new mock instance.
+func NewMockRehashable(ctrl *gomock.Controller) *MockRehashable {
+	mock := &MockRehashable{ctrl: ctrl}
+	mock.recorder = &MockRehashableMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRehashable) EXPECT() *MockRehashableMockRecorder {
+	return m.recorder
+}
+
+// Rehash mocks base method.
+func (m *MockRehashable) Rehash(ktx kontext.Context, tx db.TX) (int, exception.Exception) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rehash", ktx, tx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(exception.Exception)
+	return ret0, ret1
+}
+
+// Rehash indicates an expected call of Rehash.
+func (mr *MockRehashableMockRecorder) Rehash(ktx, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rehash", reflect.TypeOf((*MockRehashable)(nil).Rehash), ktx, tx)
+}
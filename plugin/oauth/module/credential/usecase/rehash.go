@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+// Rehash replace the plaintext client secrets, access tokens and refresh tokens stored before hashing with their keyed hash.
+// Rows are flagged once hashed, so it is safe to run again after a partial failure.
+func (c *Credential) Rehash(ktx kontext.Context) (entity.RehashJSON, jsonapi.Errors) {
+	var result entity.RehashJSON
+
+	for _, rehash := range []struct {
+		table string
+		repo  Rehashable
+		total *int
+	}{
+		{table: "oauth_applications", repo: c.oauthApplicationRepo, total: &result.OauthApplications},
+		{table: "oauth_access_tokens", repo: c.oauthAccessTokenRepo, total: &result.OauthAccessTokens},
+		{table: "oauth_refresh_tokens", repo: c.oauthRefreshTokenRepo, total: &result.OauthRefreshTokens},
+	} {
+		total, exc := rehash.repo.Rehash(ktx, c.sqldb)
+		*rehash.total = total
+		if exc != nil && exc.Type() == exception.BadInput {
+			return result, jsonapi.BuildResponse(c.apiError.ValidationError(exc.Detail())).Errors
+		}
+
+		if exc != nil {
+			log.Error().
+				Err(exc).
+				Stack().
+				Interface("request_id", ktx.GetWithoutCheck("request_id")).
+				Str("table", rehash.table).
+				Array("tags", zerolog.Arr().Str("service").Str("credential").Str("rehash")).
+				Msg("Error rehashing plaintext credentials")
+
+			return result, jsonapi.BuildResponse(c.apiError.InternalServerError(ktx)).Errors
+		}
+	}
+
+	return result, nil
+}
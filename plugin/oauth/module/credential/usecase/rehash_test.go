@@ -0,0 +1,83 @@
+package usecase_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/kodefluence/monorepo/db/mock"
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/module/credential/usecase"
+	"github.com/kodefluence/altair/plugin/oauth/module/credential/usecase/mock"
+)
+
+func TestRehash(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	sqldb := mockdb.NewMockDB(mockCtrl)
+
+	t.Run("Given plaintext credentials", func(t *testing.T) {
+		t.Run("Return total of rehashed rows per table", func(t *testing.T) {
+			ktx := kontext.Fabricate()
+			oauthApplicationRepo := mock.NewMockRehashable(mockCtrl)
+			oauthAccessTokenRepo := mock.NewMockRehashable(mockCtrl)
+			oauthRefreshTokenRepo := mock.NewMockRehashable(mockCtrl)
+
+			gomock.InOrder(
+				oauthApplicationRepo.EXPECT().Rehash(ktx, sqldb).Return(2, nil),
+				oauthAccessTokenRepo.EXPECT().Rehash(ktx, sqldb).Return(10, nil),
+				oauthRefreshTokenRepo.EXPECT().Rehash(ktx, sqldb).Return(5, nil),
+			)
+
+			credential := usecase.NewCredential(sqldb, oauthApplicationRepo, oauthAccessTokenRepo, oauthRefreshTokenRepo, apierror.Provide())
+			result, err := credential.Rehash(ktx)
+
+			assert.Nil(t, err)
+			assert.Equal(t, entity.RehashJSON{OauthApplications: 2, OauthAccessTokens: 10, OauthRefreshTokens: 5}, result)
+		})
+
+		t.Run("When rehashing a table failed, return internal server error and stop", func(t *testing.T) {
+			ktx := kontext.Fabricate()
+			oauthApplicationRepo := mock.NewMockRehashable(mockCtrl)
+			oauthAccessTokenRepo := mock.NewMockRehashable(mockCtrl)
+			oauthRefreshTokenRepo := mock.NewMockRehashable(mockCtrl)
+
+			gomock.InOrder(
+				oauthApplicationRepo.EXPECT().Rehash(ktx, sqldb).Return(2, nil),
+				oauthAccessTokenRepo.EXPECT().Rehash(ktx, sqldb).Return(3, exception.Throw(errors.New("unexpected error"))),
+			)
+			oauthRefreshTokenRepo.EXPECT().Rehash(gomock.Any(), gomock.Any()).Times(0)
+
+			credential := usecase.NewCredential(sqldb, oauthApplicationRepo, oauthAccessTokenRepo, oauthRefreshTokenRepo, apierror.Provide())
+			result, err := credential.Rehash(ktx)
+
+			assert.NotNil(t, err)
+			assert.Equal(t, http.StatusInternalServerError, err.HTTPStatus())
+			assert.Equal(t, entity.RehashJSON{OauthApplications: 2, OauthAccessTokens: 3}, result)
+		})
+
+		t.Run("When token hash pepper is not set, return validation error", func(t *testing.T) {
+			ktx := kontext.Fabricate()
+			oauthApplicationRepo := mock.NewMockRehashable(mockCtrl)
+			oauthAccessTokenRepo := mock.NewMockRehashable(mockCtrl)
+			oauthRefreshTokenRepo := mock.NewMockRehashable(mockCtrl)
+
+			oauthApplicationRepo.EXPECT().Rehash(ktx, sqldb).Return(0, exception.Throw(errors.New("token_hash_pepper is not set"), exception.WithType(exception.BadInput), exception.WithDetail("oauth token_hash_pepper must be set to rehash credentials")))
+			oauthAccessTokenRepo.EXPECT().Rehash(gomock.Any(), gomock.Any()).Times(0)
+			oauthRefreshTokenRepo.EXPECT().Rehash(gomock.Any(), gomock.Any()).Times(0)
+
+			credential := usecase.NewCredential(sqldb, oauthApplicationRepo, oauthAccessTokenRepo, oauthRefreshTokenRepo, apierror.Provide())
+			_, err := credential.Rehash(ktx)
+
+			assert.NotNil(t, err)
+			assert.Equal(t, http.StatusUnprocessableEntity, err.HTTPStatus())
+		})
+	})
+}
@@ -15,6 +15,20 @@ func (f *Formatter) AccessGrantFromAuthorizationRequestInsertable(r entity.Autho
 	accessGrantInsertable.Scopes = util.PointerToValue(r.Scopes)
 	accessGrantInsertable.Code = util.SHA1()
 	accessGrantInsertable.RedirectURI = util.PointerToValue(r.RedirectURI)
+
+	if r.CodeChallenge != nil {
+		accessGrantInsertable.CodeChallenge = *r.CodeChallenge
+		accessGrantInsertable.CodeChallengeMethod = entity.CodeChallengeMethodPlain
+
+		if r.CodeChallengeMethod != nil {
+			accessGrantInsertable.CodeChallengeMethod = *r.CodeChallengeMethod
+		}
+	}
+
+	if r.Nonce != nil {
+		accessGrantInsertable.Nonce = *r.Nonce
+	}
+
 	accessGrantInsertable.ExpiresIn = time.Now().Add(f.codeExpiresIn)
 
 	return accessGrantInsertable
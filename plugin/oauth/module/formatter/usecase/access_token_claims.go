@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+// AccessTokenClaims format access token as the claims of a jwt access token, the jti is random so the claims never carry the token
+func (*Formatter) AccessTokenClaims(e entity.OauthAccessToken) entity.AccessTokenClaims {
+	return entity.AccessTokenClaims{
+		Subject:            strconv.Itoa(e.ResourceOwnerID),
+		OauthApplicationID: e.OauthApplicationID,
+		Scope:              e.Scopes.String,
+		ExpiresAt:          e.ExpiresIn.Unix(),
+		IssuedAt:           e.CreatedAt.Unix(),
+		ID:                 uuid.New().String(),
+		OauthAccessTokenID: e.ID,
+	}
+}
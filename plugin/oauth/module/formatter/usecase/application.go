@@ -5,12 +5,13 @@ import (
 	"github.com/kodefluence/altair/util"
 )
 
+// Application format oauth application response, the client secret is stored as a keyed hash so it is left out
 func (f *Formatter) Application(application entity.OauthApplication) entity.OauthApplicationJSON {
 	oauthApplicationJSON := entity.OauthApplicationJSON{
 		ID:           &application.ID,
 		OwnerType:    &application.OwnerType,
 		ClientUID:    &application.ClientUID,
-		ClientSecret: &application.ClientSecret,
+		PKCERequired: &application.PKCERequired,
 		CreatedAt:    &application.CreatedAt,
 		UpdatedAt:    &application.UpdatedAt,
 	}
@@ -69,6 +69,12 @@ func TestOauthApplication(t *testing.T) {
 				oauthApplicationJSON := newFormatter().ApplicationList(oauthApplications)
 				assert.Equal(t, len(oauthApplications), len(oauthApplicationJSON))
 			})
+
+			t.Run("Return oauth application without the stored client secret", func(t *testing.T) {
+				oauthApplicationJSON := newFormatter().Application(entity.OauthApplication{ID: 1, ClientUID: "clientuid01", ClientSecret: "clientsecret01"})
+				assert.Nil(t, oauthApplicationJSON.ClientSecret)
+				assert.Equal(t, "clientuid01", *oauthApplicationJSON.ClientUID)
+			})
 		})
 	})
 
@@ -91,6 +97,18 @@ func TestOauthApplication(t *testing.T) {
 				assert.Equal(t, *oauthApplicationJSON.Scopes, insertable.Scopes)
 				assert.NotEqual(t, "", insertable.ClientUID)
 				assert.NotEqual(t, "", insertable.ClientSecret)
+				assert.False(t, insertable.PKCERequired)
+			})
+		})
+
+		t.Run("Given application requiring pkce", func(t *testing.T) {
+			t.Run("Return oauth application insertable requiring pkce", func(t *testing.T) {
+				insertable := newFormatter().OauthApplicationInsertable(entity.OauthApplicationJSON{
+					OwnerType:    util.ValueToPointer("public"),
+					PKCERequired: util.ValueToPointer(true),
+				})
+
+				assert.True(t, insertable.PKCERequired)
 			})
 		})
 	})
@@ -157,6 +175,49 @@ func TestOauthApplication(t *testing.T) {
 				assert.Equal(t, *authorizationRequest.RedirectURI, insertable.RedirectURI)
 				assert.NotEqual(t, "", insertable.Code)
 				assert.NotEqual(t, time.Time{}, insertable.ExpiresIn)
+				assert.Nil(t, insertable.CodeChallenge)
+				assert.Nil(t, insertable.CodeChallengeMethod)
+				assert.Nil(t, insertable.Nonce)
+			})
+		})
+
+		t.Run("Given authorization request with nonce", func(t *testing.T) {
+			t.Run("Return oauth access grant insertable with nonce", func(t *testing.T) {
+				authorizationRequest := entity.AuthorizationRequestJSON{
+					ResourceOwnerID: util.ValueToPointer(1),
+					Nonce:           util.ValueToPointer("n-0S6_WzA2Mj"),
+				}
+
+				insertable := newFormatter().AccessGrantFromAuthorizationRequestInsertable(authorizationRequest, entity.OauthApplication{ID: 1})
+
+				assert.Equal(t, "n-0S6_WzA2Mj", insertable.Nonce)
+			})
+		})
+
+		t.Run("Given authorization request with code challenge", func(t *testing.T) {
+			t.Run("Return oauth access grant insertable with code challenge and its method", func(t *testing.T) {
+				authorizationRequest := entity.AuthorizationRequestJSON{
+					ResourceOwnerID:     util.ValueToPointer(1),
+					RedirectURI:         util.ValueToPointer("https://github.com"),
+					CodeChallenge:       util.ValueToPointer("E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"),
+					CodeChallengeMethod: util.ValueToPointer("S256"),
+				}
+
+				insertable := newFormatter().AccessGrantFromAuthorizationRequestInsertable(authorizationRequest, entity.OauthApplication{ID: 1})
+
+				assert.Equal(t, *authorizationRequest.CodeChallenge, insertable.CodeChallenge)
+				assert.Equal(t, "S256", insertable.CodeChallengeMethod)
+			})
+
+			t.Run("Default the method to plain", func(t *testing.T) {
+				authorizationRequest := entity.AuthorizationRequestJSON{
+					ResourceOwnerID: util.ValueToPointer(1),
+					CodeChallenge:   util.ValueToPointer("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"),
+				}
+
+				insertable := newFormatter().AccessGrantFromAuthorizationRequestInsertable(authorizationRequest, entity.OauthApplication{ID: 1})
+
+				assert.Equal(t, "plain", insertable.CodeChallengeMethod)
 			})
 		})
 	})
@@ -521,6 +582,102 @@ func TestOauthApplication(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("AccessTokenIntrospection", func(t *testing.T) {
+		t.Run("Given access token and its application", func(t *testing.T) {
+			t.Run("Return active introspection", func(t *testing.T) {
+				accessToken := entity.OauthAccessToken{
+					ResourceOwnerID: 1,
+					Scopes:          sql.NullString{String: "user store", Valid: true},
+					ExpiresIn:       time.Unix(1700003600, 0),
+					CreatedAt:       time.Unix(1700000000, 0),
+				}
+
+				introspection := newFormatter().AccessTokenIntrospection(accessToken, entity.OauthApplication{ClientUID: "client_uid"})
+
+				assert.Equal(t, entity.IntrospectionJSON{
+					Active:    true,
+					Scope:     util.ValueToPointer("user store"),
+					ClientID:  util.ValueToPointer("client_uid"),
+					Sub:       util.ValueToPointer("1"),
+					Exp:       util.ValueToPointer(1700003600),
+					Iat:       util.ValueToPointer(1700000000),
+					TokenType: util.ValueToPointer("access_token"),
+				}, introspection)
+			})
+		})
+	})
+
+	t.Run("RefreshTokenIntrospection", func(t *testing.T) {
+		t.Run("Given refresh token, its access token and application", func(t *testing.T) {
+			t.Run("Return active introspection with the lifetime of refresh token", func(t *testing.T) {
+				refreshToken := entity.OauthRefreshToken{
+					ExpiresIn: time.Unix(1700086400, 0),
+					CreatedAt: time.Unix(1700000000, 0),
+				}
+				accessToken := entity.OauthAccessToken{
+					ResourceOwnerID: 1,
+					ExpiresIn:       time.Unix(1700003600, 0),
+				}
+
+				introspection := newFormatter().RefreshTokenIntrospection(refreshToken, accessToken, entity.OauthApplication{ClientUID: "client_uid"})
+
+				assert.Nil(t, introspection.Scope)
+				assert.Equal(t, 1700086400, *introspection.Exp)
+				assert.Equal(t, 1700000000, *introspection.Iat)
+				assert.Equal(t, "refresh_token", *introspection.TokenType)
+			})
+		})
+	})
+
+	t.Run("AccessTokenClaims", func(t *testing.T) {
+		t.Run("Given access token", func(t *testing.T) {
+			t.Run("Return jwt claims with a random jti and the access token id", func(t *testing.T) {
+				accessToken := entity.OauthAccessToken{
+					ID:                 5,
+					OauthApplicationID: 2,
+					ResourceOwnerID:    1,
+					Token:              "opaque-token",
+					Scopes:             sql.NullString{String: "user store", Valid: true},
+					ExpiresIn:          time.Unix(1700003600, 0),
+					CreatedAt:          time.Unix(1700000000, 0),
+				}
+
+				claims := newFormatter().AccessTokenClaims(accessToken)
+				assert.Equal(t, entity.AccessTokenClaims{
+					Subject:            "1",
+					OauthApplicationID: 2,
+					Scope:              "user store",
+					ExpiresAt:          1700003600,
+					IssuedAt:           1700000000,
+					ID:                 claims.ID,
+					OauthAccessTokenID: 5,
+				}, claims)
+				assert.NotEmpty(t, claims.ID)
+				assert.NotEqual(t, claims.ID, newFormatter().AccessTokenClaims(accessToken).ID)
+			})
+		})
+	})
+
+	t.Run("IDTokenClaims", func(t *testing.T) {
+		t.Run("Given access token, application and nonce", func(t *testing.T) {
+			t.Run("Return id token claims with client uid as audience", func(t *testing.T) {
+				accessToken := entity.OauthAccessToken{
+					ResourceOwnerID: 1,
+					ExpiresIn:       time.Unix(1700003600, 0),
+					CreatedAt:       time.Unix(1700000000, 0),
+				}
+
+				assert.Equal(t, entity.IDTokenClaims{
+					Subject:   "1",
+					Audience:  "client_uid",
+					ExpiresAt: 1700003600,
+					IssuedAt:  1700000000,
+					Nonce:     "n-0S6_WzA2Mj",
+				}, newFormatter().IDTokenClaims(accessToken, entity.OauthApplication{ClientUID: "client_uid"}, "n-0S6_WzA2Mj"))
+			})
+		})
+	})
 }
 
 func newFormatter() *usecase.Formatter {
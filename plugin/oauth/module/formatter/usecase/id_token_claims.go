@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"strconv"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+// IDTokenClaims format access token as the claims of an openid connect id token, the id token expires with the access token
+func (*Formatter) IDTokenClaims(e entity.OauthAccessToken, application entity.OauthApplication, nonce string) entity.IDTokenClaims {
+	return entity.IDTokenClaims{
+		Subject:   strconv.Itoa(e.ResourceOwnerID),
+		Audience:  application.ClientUID,
+		ExpiresAt: e.ExpiresIn.Unix(),
+		IssuedAt:  e.CreatedAt.Unix(),
+		Nonce:     nonce,
+	}
+}
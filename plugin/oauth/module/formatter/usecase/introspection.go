@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/util"
+)
+
+// AccessTokenIntrospection format an active access token as introspection response
+func (*Formatter) AccessTokenIntrospection(e entity.OauthAccessToken, application entity.OauthApplication) entity.IntrospectionJSON {
+	return introspection(e, application, e.ExpiresIn, e.CreatedAt, "access_token")
+}
+
+// RefreshTokenIntrospection format an active refresh token as introspection response, the scope and
+// resource owner are the ones of the access token it was issued with
+func (*Formatter) RefreshTokenIntrospection(e entity.OauthRefreshToken, accessToken entity.OauthAccessToken, application entity.OauthApplication) entity.IntrospectionJSON {
+	return introspection(accessToken, application, e.ExpiresIn, e.CreatedAt, "refresh_token")
+}
+
+func introspection(accessToken entity.OauthAccessToken, application entity.OauthApplication, expiresIn, createdAt time.Time, tokenType string) entity.IntrospectionJSON {
+	data := entity.IntrospectionJSON{
+		Active:    true,
+		ClientID:  &application.ClientUID,
+		Sub:       util.ValueToPointer(strconv.Itoa(accessToken.ResourceOwnerID)),
+		Exp:       util.ValueToPointer(int(expiresIn.Unix())),
+		Iat:       util.ValueToPointer(int(createdAt.Unix())),
+		TokenType: util.ValueToPointer(tokenType),
+	}
+
+	if accessToken.Scopes.Valid {
+		data.Scope = &accessToken.Scopes.String
+	}
+
+	return data
+}
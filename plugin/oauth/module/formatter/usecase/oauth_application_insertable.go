@@ -15,6 +15,7 @@ func (*Formatter) OauthApplicationInsertable(r entity.OauthApplicationJSON) enti
 	oauthApplicationInsertable.OwnerType = *r.OwnerType
 	oauthApplicationInsertable.Description = util.PointerToValue(r.Description)
 	oauthApplicationInsertable.Scopes = util.PointerToValue(r.Scopes)
+	oauthApplicationInsertable.PKCERequired = util.PointerToValue(r.PKCERequired)
 	oauthApplicationInsertable.ClientUID = util.SHA1()
 	oauthApplicationInsertable.ClientSecret = aurelia.Hash(oauthApplicationInsertable.ClientUID, uuid.New().String())
 
@@ -0,0 +1,10 @@
+package http
+
+import (
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./http.go
+type KeySet interface {
+	JWKS() entity.JSONWebKeySetJSON
+}
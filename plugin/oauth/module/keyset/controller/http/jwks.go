@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodefluence/monorepo/kontext"
+)
+
+// JWKSController serve the public keys verifying jwt access tokens
+type JWKSController struct {
+	keySet KeySet
+}
+
+// NewJWKS create new jwks controller
+func NewJWKS(keySet KeySet) *JWKSController {
+	return &JWKSController{keySet: keySet}
+}
+
+// Method GET
+func (o *JWKSController) Method() string {
+	return "GET"
+}
+
+// Path /.well-known/jwks.json
+func (o *JWKSController) Path() string {
+	return "/.well-known/jwks.json"
+}
+
+// Control responding the json web key set
+func (o *JWKSController) Control(ktx kontext.Context, c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, o.keySet.JWKS())
+}
@@ -0,0 +1,55 @@
+package http_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/controller"
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	keysetHttp "github.com/kodefluence/altair/plugin/oauth/module/keyset/controller/http"
+	"github.com/kodefluence/altair/plugin/oauth/module/keyset/controller/http/mock"
+	"github.com/kodefluence/altair/testhelper"
+)
+
+func TestJWKS(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("Method", func(t *testing.T) {
+		assert.Equal(t, "GET", keysetHttp.NewJWKS(mock.NewMockKeySet(mockCtrl)).Method())
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		assert.Equal(t, "/.well-known/jwks.json", keysetHttp.NewJWKS(mock.NewMockKeySet(mockCtrl)).Path())
+	})
+
+	t.Run("Control", func(t *testing.T) {
+		t.Run("Return json web key set with status 200", func(t *testing.T) {
+			apiEngine := gin.Default()
+
+			keySet := mock.NewMockKeySet(mockCtrl)
+			keySet.EXPECT().JWKS().Return(entity.JSONWebKeySetJSON{
+				Keys: []entity.JSONWebKeyJSON{
+					{Kty: "OKP", Kid: "key-1", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+				},
+			})
+
+			ctrl := keysetHttp.NewJWKS(keySet)
+			controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+			w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), nil)
+			responseByte, err := io.ReadAll(w.Body)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+			assert.Equal(t, `{"keys":[{"kty":"OKP","kid":"key-1","use":"sig","alg":"EdDSA","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`, string(responseByte))
+		})
+	})
+}
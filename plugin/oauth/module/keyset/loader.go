@@ -0,0 +1,12 @@
+package keyset
+
+import (
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/oauth/module/keyset/controller/http"
+	"github.com/kodefluence/altair/plugin/oauth/module/keyset/usecase"
+)
+
+// Load serve the jwks on the public module, outside of the basic auth of /_plugins.
+func Load(publicModule module.App, keySet *usecase.KeySet) {
+	publicModule.Controller().InjectHTTP(http.NewJWKS(keySet))
+}
@@ -0,0 +1,48 @@
+package keyset
+
+import (
+	"os"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/module/keyset/usecase"
+)
+
+// Provide reads the jwt keys of the config. It returns nil when access tokens
+// are opaque and openid is not active.
+func Provide(config entity.OauthPlugin) (*usecase.KeySet, error) {
+	if err := config.ValidateTokenFormat(); err != nil {
+		return nil, err
+	}
+
+	if err := config.ValidateOpenID(); err != nil {
+		return nil, err
+	}
+
+	if !config.UsesKeySet() {
+		return nil, nil
+	}
+
+	var keys []usecase.Key
+	for _, keyConfig := range config.Config.JWT.Keys {
+		var privateKeyPEM, publicKeyPEM []byte
+		var err error
+
+		if keyConfig.PrivateKey != "" {
+			privateKeyPEM, err = os.ReadFile(keyConfig.PrivateKey)
+		} else {
+			publicKeyPEM, err = os.ReadFile(keyConfig.PublicKey)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := usecase.ParseKey(keyConfig.Kid, keyConfig.Algorithm, privateKeyPEM, publicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return usecase.NewKeySet(config.Config.JWT.Issuer, config.SigningKid(), keys)
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+const minRSAKeyBits = 2048
+
+// Key is one key of the key set. A key without private key only verifies.
+type Key struct {
+	kid        string
+	algorithm  string
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
+}
+
+// ParseKey parses the PEM encoded private key or, without it, public key of a
+// kid and checks it fits the algorithm.
+func ParseKey(kid, algorithm string, privateKeyPEM, publicKeyPEM []byte) (Key, error) {
+	key := Key{kid: kid, algorithm: algorithm}
+
+	if len(privateKeyPEM) > 0 {
+		privateKey, err := parsePrivateKey(privateKeyPEM)
+		if err != nil {
+			return Key{}, fmt.Errorf("jwt key `%s`: %v", kid, err)
+		}
+
+		key.privateKey = privateKey
+		key.publicKey = privateKey.Public()
+	} else {
+		publicKey, err := parsePublicKey(publicKeyPEM)
+		if err != nil {
+			return Key{}, fmt.Errorf("jwt key `%s`: %v", kid, err)
+		}
+
+		key.publicKey = publicKey
+	}
+
+	if err := key.checkAlgorithm(); err != nil {
+		return Key{}, fmt.Errorf("jwt key `%s`: %v", kid, err)
+	}
+
+	return key, nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("private key is not pem encoded")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("private key type %T is not supported", privateKey)
+	}
+
+	return signer, nil
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("public key is not pem encoded")
+	}
+
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func (k Key) checkAlgorithm() error {
+	switch publicKey := k.publicKey.(type) {
+	case *rsa.PublicKey:
+		if k.algorithm != entity.AlgorithmRS256 {
+			break
+		}
+
+		if publicKey.N.BitLen() < minRSAKeyBits {
+			return fmt.Errorf("rsa key must be at least %d bits", minRSAKeyBits)
+		}
+
+		return nil
+	case *ecdsa.PublicKey:
+		if k.algorithm != entity.AlgorithmES256 {
+			break
+		}
+
+		if publicKey.Curve != elliptic.P256() {
+			return fmt.Errorf("ES256 requires a P-256 key")
+		}
+
+		return nil
+	case ed25519.PublicKey:
+		if k.algorithm == entity.AlgorithmEdDSA {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%T cannot be used with %s", k.publicKey, k.algorithm)
+}
+
+// jwk formats the public key as a JSON Web Key.
+func (k Key) jwk() entity.JSONWebKeyJSON {
+	jwk := entity.JSONWebKeyJSON{Kid: k.kid, Use: "sig", Alg: k.algorithm}
+
+	switch publicKey := k.publicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case *ecdsa.PublicKey:
+		jwk.Kty = "EC"
+		jwk.Crv = "P-256"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, 32)))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, 32)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+
+	return jwk
+}
@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+const (
+	tokenType   = "at+jwt"
+	idTokenType = "JWT"
+)
+
+var ErrInvalidToken = errors.New("invalid jwt")
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// KeySet signs access tokens with the signing key and verifies them with any
+// key of the set, so tokens signed before a rotation stay valid until they
+// expire.
+type KeySet struct {
+	issuer  string
+	signing Key
+	keys    map[string]Key
+	jwks    entity.JSONWebKeySetJSON
+}
+
+// NewKeySet create new key set, the signing key must have a private key.
+func NewKeySet(issuer, signingKid string, keys []Key) (*KeySet, error) {
+	keySet := &KeySet{issuer: issuer, keys: map[string]Key{}, jwks: entity.JSONWebKeySetJSON{Keys: []entity.JSONWebKeyJSON{}}}
+
+	for _, key := range keys {
+		keySet.keys[key.kid] = key
+		keySet.jwks.Keys = append(keySet.jwks.Keys, key.jwk())
+	}
+
+	signing, ok := keySet.keys[signingKid]
+	if !ok || signing.privateKey == nil {
+		return nil, fmt.Errorf("jwt signing key `%s` does not exist or has no private key", signingKid)
+	}
+	keySet.signing = signing
+
+	return keySet, nil
+}
+
+// Issuer returns the iss claim of signed tokens.
+func (k *KeySet) Issuer() string {
+	return k.issuer
+}
+
+// JWKS returns the public keys of the set.
+func (k *KeySet) JWKS() entity.JSONWebKeySetJSON {
+	return k.jwks
+}
+
+// SigningAlgorithm returns the algorithm of the signing key.
+func (k *KeySet) SigningAlgorithm() string {
+	return k.signing.algorithm
+}
+
+// Sign signs the claims with the signing key.
+func (k *KeySet) Sign(claims entity.AccessTokenClaims) (string, error) {
+	claims.Issuer = k.issuer
+	return k.sign(claims, tokenType)
+}
+
+// SignIDToken signs openid connect id token claims with the signing key, the
+// at_hash claim is derived from the access token issued alongside.
+func (k *KeySet) SignIDToken(claims entity.IDTokenClaims, accessToken string) (string, error) {
+	claims.Issuer = k.issuer
+	claims.AccessTokenHash = k.signing.hashHalf(accessToken)
+	return k.sign(claims, idTokenType)
+}
+
+func (k *KeySet) sign(claims interface{}, typ string) (string, error) {
+	encodedHeader, err := encodeSegment(header{Alg: k.signing.algorithm, Kid: k.signing.kid, Typ: typ})
+	if err != nil {
+		return "", err
+	}
+
+	encodedClaims, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := encodedHeader + "." + encodedClaims
+
+	signature, err := k.signing.sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// VerifyAccessToken checks the signature of an access token and returns its
+// claims. Expiration is left to the caller.
+func (k *KeySet) VerifyAccessToken(token string) (entity.AccessTokenClaims, error) {
+	var claims entity.AccessTokenClaims
+
+	if err := k.verify(token, tokenType, &claims); err != nil || claims.Issuer != k.issuer {
+		return entity.AccessTokenClaims{}, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// VerifyIDToken checks the signature of an openid connect id token and returns
+// its claims. Expiration is left to the caller.
+func (k *KeySet) VerifyIDToken(token string) (entity.IDTokenClaims, error) {
+	var claims entity.IDTokenClaims
+
+	if err := k.verify(token, idTokenType, &claims); err != nil || claims.Issuer != k.issuer {
+		return entity.IDTokenClaims{}, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// verify decodes the claims of a token signed by any key of the set, the typ
+// header must match so an id token could never pass as an access token.
+func (k *KeySet) verify(token, typ string, claims interface{}) error {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return ErrInvalidToken
+	}
+
+	var h header
+	if err := decodeSegment(segments[0], &h); err != nil || h.Typ != typ {
+		return ErrInvalidToken
+	}
+
+	key, ok := k.keys[h.Kid]
+	if !ok || h.Alg != key.algorithm {
+		return ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(segments[2])
+	if err != nil || !key.verify([]byte(segments[0]+"."+segments[1]), signature) {
+		return ErrInvalidToken
+	}
+
+	if err := decodeSegment(segments[1], claims); err != nil {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
+
+func (key Key) sign(signingInput []byte) ([]byte, error) {
+	if key.algorithm == entity.AlgorithmEdDSA {
+		return key.privateKey.Sign(rand.Reader, signingInput, crypto.Hash(0))
+	}
+
+	digest := sha256.Sum256(signingInput)
+
+	if key.algorithm == entity.AlgorithmES256 {
+		r, s, err := ecdsa.Sign(rand.Reader, key.privateKey.(*ecdsa.PrivateKey), digest[:])
+		if err != nil {
+			return nil, err
+		}
+
+		return append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...), nil
+	}
+
+	return key.privateKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
+
+func (key Key) verify(signingInput, signature []byte) bool {
+	switch publicKey := key.publicKey.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(publicKey, signingInput, signature)
+	case *ecdsa.PublicKey:
+		if len(signature) != 64 {
+			return false
+		}
+
+		digest := sha256.Sum256(signingInput)
+		return ecdsa.Verify(publicKey, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:]))
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(signingInput)
+		return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature) == nil
+	}
+
+	return false
+}
+
+// hashHalf is the left half of the hash of value, SHA-256 for RS256 and ES256,
+// SHA-512 for EdDSA.
+func (key Key) hashHalf(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	var digest []byte
+	if key.algorithm == entity.AlgorithmEdDSA {
+		sum := sha512.Sum512([]byte(value))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(value))
+		digest = sum[:]
+	}
+
+	return base64.RawURLEncoding.EncodeToString(digest[:len(digest)/2])
+}
+
+func encodeSegment(v interface{}) (string, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(content), nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	content, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, v)
+}
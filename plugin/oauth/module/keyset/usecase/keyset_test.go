@@ -0,0 +1,245 @@
+package usecase_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/module/keyset/usecase"
+)
+
+func generatePrivateKeyPEM(t *testing.T, algorithm string) []byte {
+	var privateKey interface{}
+	var err error
+
+	switch algorithm {
+	case entity.AlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case entity.AlgorithmES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case entity.AlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	}
+	assert.Nil(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	assert.Nil(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func publicKeyPEM(t *testing.T, privateKeyPEM []byte) []byte {
+	block, _ := pem.Decode(privateKeyPEM)
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	assert.Nil(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(privateKey.(crypto.Signer).Public())
+	assert.Nil(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func atHash(algorithm, accessToken string) string {
+	if algorithm == entity.AlgorithmEdDSA {
+		digest := sha512.Sum512([]byte(accessToken))
+		return base64.RawURLEncoding.EncodeToString(digest[:32])
+	}
+
+	digest := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(digest[:16])
+}
+
+func claims() entity.AccessTokenClaims {
+	return entity.AccessTokenClaims{
+		Subject:            "1",
+		OauthApplicationID: 2,
+		Scope:              "public users",
+		ExpiresAt:          1700003600,
+		IssuedAt:           1700000000,
+		ID:                 "8f14e45f-ceea-467f-a0e6-b5c1d2e3f4a5",
+		OauthAccessTokenID: 5,
+	}
+}
+
+func TestKeySet(t *testing.T) {
+	for _, algorithm := range []string{entity.AlgorithmRS256, entity.AlgorithmES256, entity.AlgorithmEdDSA} {
+		t.Run("Given "+algorithm+" key", func(t *testing.T) {
+			key, err := usecase.ParseKey("key-1", algorithm, generatePrivateKeyPEM(t, algorithm), nil)
+			assert.Nil(t, err)
+
+			keySet, err := usecase.NewKeySet("https://altair.example", "key-1", []usecase.Key{key})
+			assert.Nil(t, err)
+
+			t.Run("When claims are signed, then it could be verified with the same claims", func(t *testing.T) {
+				token, err := keySet.Sign(claims())
+				assert.Nil(t, err)
+				assert.Equal(t, 2, strings.Count(token, "."))
+
+				verifiedClaims, err := keySet.VerifyAccessToken(token)
+				assert.Nil(t, err)
+
+				expectedClaims := claims()
+				expectedClaims.Issuer = "https://altair.example"
+				assert.Equal(t, expectedClaims, verifiedClaims)
+			})
+
+			t.Run("When the token payload is tampered, then it return invalid token", func(t *testing.T) {
+				token, err := keySet.Sign(claims())
+				assert.Nil(t, err)
+
+				segments := strings.Split(token, ".")
+				segments[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"2","oauth_application_id":2,"exp":1700003600,"iat":1700000000,"jti":"8f14e45f-ceea-467f-a0e6-b5c1d2e3f4a5","oauth_access_token_id":5}`))
+
+				_, err = keySet.VerifyAccessToken(strings.Join(segments, "."))
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			})
+
+			t.Run("When id token claims are signed, then it carry the issuer and at_hash", func(t *testing.T) {
+				idToken, err := keySet.SignIDToken(entity.IDTokenClaims{Subject: "1", Audience: "client_uid", Nonce: "n-0S6_WzA2Mj"}, "access-token")
+				assert.Nil(t, err)
+
+				segments := strings.Split(idToken, ".")
+				assert.Equal(t, 3, len(segments))
+
+				headerContent, err := base64.RawURLEncoding.DecodeString(segments[0])
+				assert.Nil(t, err)
+				assert.Contains(t, string(headerContent), `"typ":"JWT"`)
+
+				var claims entity.IDTokenClaims
+				content, err := base64.RawURLEncoding.DecodeString(segments[1])
+				assert.Nil(t, err)
+				assert.Nil(t, json.Unmarshal(content, &claims))
+				assert.Equal(t, "https://altair.example", claims.Issuer)
+				assert.Equal(t, "n-0S6_WzA2Mj", claims.Nonce)
+				assert.Equal(t, atHash(algorithm, "access-token"), claims.AccessTokenHash)
+				assert.Equal(t, algorithm, keySet.SigningAlgorithm())
+			})
+
+			t.Run("When id token is verified, then it return the id token claims", func(t *testing.T) {
+				idToken, err := keySet.SignIDToken(entity.IDTokenClaims{Subject: "1", Audience: "client_uid", Nonce: "n-0S6_WzA2Mj"}, "access-token")
+				assert.Nil(t, err)
+
+				idTokenClaims, err := keySet.VerifyIDToken(idToken)
+				assert.Nil(t, err)
+				assert.Equal(t, "1", idTokenClaims.Subject)
+				assert.Equal(t, "client_uid", idTokenClaims.Audience)
+			})
+
+			t.Run("When id token is verified as access token, then it return invalid token", func(t *testing.T) {
+				idToken, err := keySet.SignIDToken(entity.IDTokenClaims{Subject: "1", Audience: "client_uid"}, "access-token")
+				assert.Nil(t, err)
+
+				_, err = keySet.VerifyAccessToken(idToken)
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			})
+
+			t.Run("When access token is verified as id token, then it return invalid token", func(t *testing.T) {
+				token, err := keySet.Sign(claims())
+				assert.Nil(t, err)
+
+				_, err = keySet.VerifyIDToken(token)
+				assert.Equal(t, usecase.ErrInvalidToken, err)
+			})
+
+			t.Run("When the jwks is requested, then it return the public key", func(t *testing.T) {
+				jwks := keySet.JWKS()
+				assert.Equal(t, 1, len(jwks.Keys))
+				assert.Equal(t, "key-1", jwks.Keys[0].Kid)
+				assert.Equal(t, algorithm, jwks.Keys[0].Alg)
+				assert.Equal(t, "sig", jwks.Keys[0].Use)
+			})
+		})
+	}
+
+	t.Run("Given rotated keys", func(t *testing.T) {
+		oldPrivateKeyPEM := generatePrivateKeyPEM(t, entity.AlgorithmES256)
+
+		oldKey, err := usecase.ParseKey("old", entity.AlgorithmES256, oldPrivateKeyPEM, nil)
+		assert.Nil(t, err)
+
+		newKey, err := usecase.ParseKey("new", entity.AlgorithmRS256, generatePrivateKeyPEM(t, entity.AlgorithmRS256), nil)
+		assert.Nil(t, err)
+
+		oldKeySet, err := usecase.NewKeySet("", "old", []usecase.Key{oldKey})
+		assert.Nil(t, err)
+
+		oldToken, err := oldKeySet.Sign(claims())
+		assert.Nil(t, err)
+
+		retiredKey, err := usecase.ParseKey("old", entity.AlgorithmES256, nil, publicKeyPEM(t, oldPrivateKeyPEM))
+		assert.Nil(t, err)
+
+		keySet, err := usecase.NewKeySet("", "new", []usecase.Key{newKey, retiredKey})
+		assert.Nil(t, err)
+
+		t.Run("When token is signed with the retired key, then it still could be verified", func(t *testing.T) {
+			_, err := keySet.VerifyAccessToken(oldToken)
+			assert.Nil(t, err)
+		})
+
+		t.Run("When token is signed after rotation, then it use the new kid", func(t *testing.T) {
+			token, err := keySet.Sign(claims())
+			assert.Nil(t, err)
+
+			headerContent, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+			assert.Nil(t, err)
+			assert.Equal(t, `{"alg":"RS256","kid":"new","typ":"at+jwt"}`, string(headerContent))
+			assert.Equal(t, 2, len(keySet.JWKS().Keys))
+		})
+
+		t.Run("When the signing key has no private key, then it return error", func(t *testing.T) {
+			_, err := usecase.NewKeySet("", "old", []usecase.Key{newKey, retiredKey})
+			assert.NotNil(t, err)
+		})
+
+		t.Run("When the token is signed by unknown kid, then it return invalid token", func(t *testing.T) {
+			_, err := oldKeySet.VerifyAccessToken(func() string {
+				token, _ := keySet.Sign(claims())
+				return token
+			}())
+			assert.Equal(t, usecase.ErrInvalidToken, err)
+		})
+
+		t.Run("When the issuer is different, then it return invalid token", func(t *testing.T) {
+			issuerKeySet, err := usecase.NewKeySet("https://other.example", "new", []usecase.Key{newKey})
+			assert.Nil(t, err)
+
+			token, err := keySet.Sign(claims())
+			assert.Nil(t, err)
+
+			_, err = issuerKeySet.VerifyAccessToken(token)
+			assert.Equal(t, usecase.ErrInvalidToken, err)
+		})
+
+		t.Run("When token is malformed, then it return invalid token", func(t *testing.T) {
+			_, err := keySet.VerifyAccessToken("not-a-jwt")
+			assert.Equal(t, usecase.ErrInvalidToken, err)
+		})
+	})
+}
+
+func TestParseKey(t *testing.T) {
+	t.Run("Given algorithm that does not match the key, then it return error", func(t *testing.T) {
+		_, err := usecase.ParseKey("key-1", entity.AlgorithmRS256, generatePrivateKeyPEM(t, entity.AlgorithmEdDSA), nil)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Given invalid pem, then it return error", func(t *testing.T) {
+		_, err := usecase.ParseKey("key-1", entity.AlgorithmRS256, []byte("invalid"), nil)
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodefluence/monorepo/kontext"
+)
+
+// DiscoveryController serve the openid provider metadata
+type DiscoveryController struct {
+	openIDUsecase OpenID
+}
+
+// NewDiscovery create new openid discovery controller
+func NewDiscovery(openIDUsecase OpenID) *DiscoveryController {
+	return &DiscoveryController{openIDUsecase: openIDUsecase}
+}
+
+// Method GET
+func (o *DiscoveryController) Method() string {
+	return "GET"
+}
+
+// Path /.well-known/openid-configuration
+func (o *DiscoveryController) Path() string {
+	return "/.well-known/openid-configuration"
+}
+
+// Control responding the openid provider metadata
+func (o *DiscoveryController) Control(ktx kontext.Context, c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, o.openIDUsecase.Configuration())
+}
@@ -0,0 +1,55 @@
+package http_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/controller"
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	openidHttp "github.com/kodefluence/altair/plugin/oauth/module/openid/controller/http"
+	"github.com/kodefluence/altair/plugin/oauth/module/openid/controller/http/mock"
+	"github.com/kodefluence/altair/testhelper"
+)
+
+func TestDiscovery(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("Method", func(t *testing.T) {
+		assert.Equal(t, "GET", openidHttp.NewDiscovery(mock.NewMockOpenID(mockCtrl)).Method())
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		assert.Equal(t, "/.well-known/openid-configuration", openidHttp.NewDiscovery(mock.NewMockOpenID(mockCtrl)).Path())
+	})
+
+	t.Run("Control", func(t *testing.T) {
+		t.Run("Return openid provider metadata with status 200", func(t *testing.T) {
+			apiEngine := gin.Default()
+
+			openIDUsecase := mock.NewMockOpenID(mockCtrl)
+			openIDUsecase.EXPECT().Configuration().Return(entity.OpenIDConfigurationJSON{
+				Issuer:  "https://auth.example.com",
+				JWKSURI: "https://auth.example.com/.well-known/jwks.json",
+			})
+
+			ctrl := openidHttp.NewDiscovery(openIDUsecase)
+			controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+			w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), nil)
+			responseByte, err := io.ReadAll(w.Body)
+			assert.Nil(t, err)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+			assert.Contains(t, string(responseByte), `"issuer":"https://auth.example.com"`)
+			assert.Contains(t, string(responseByte), `"jwks_uri":"https://auth.example.com/.well-known/jwks.json"`)
+		})
+	})
+}
@@ -0,0 +1,14 @@
+package http
+
+import (
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./http.go
+type OpenID interface {
+	Configuration() entity.OpenIDConfigurationJSON
+	Userinfo(ktx kontext.Context, token string) (map[string]interface{}, jsonapi.Errors)
+}
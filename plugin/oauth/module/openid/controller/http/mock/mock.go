@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./http.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/kodefluence/altair/plugin/oauth/entity"
+	jsonapi "github.com/kodefluence/monorepo/jsonapi"
+	kontext "github.com/kodefluence/monorepo/kontext"
+)
+
+// MockOpenID is a mock of OpenID interface.
+type MockOpenID struct {
+	ctrl     *gomock.Controller
+	recorder *MockOpenIDMockRecorder
+}
+
+// MockOpenIDMockRecorder is the mock recorder for MockOpenID.
+type MockOpenIDMockRecorder struct {
+	mock *MockOpenID
+}
+
+// NewMockOpenID creates a new mock instance.
+func NewMockOpenID(ctrl *gomock.Controller) *MockOpenID {
+	mock := &MockOpenID{ctrl: ctrl}
+	mock.recorder = &MockOpenIDMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOpenID) EXPECT() *MockOpenIDMockRecorder {
+	return m.recorder
+}
+
+// Configuration mocks base method.
+func (m *MockOpenID) Configuration() entity.OpenIDConfigurationJSON {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Configuration")
+	ret0, _ := ret[0].(entity.OpenIDConfigurationJSON)
+	return ret0
+}
+
+// Configuration indicates an expected call of Configuration.
+func (mr *MockOpenIDMockRecorder) Configuration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Configuration", reflect.TypeOf((*MockOpenID)(nil).Configuration))
+}
+
+// Userinfo mocks base method.
+func (m *MockOpenID) Userinfo(ktx kontext.Context, token string) (map[string]interface{}, jsonapi.Errors) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Userinfo", ktx, token)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(jsonapi.Errors)
+	return ret0, ret1
+}
+
+// Userinfo indicates an expected call of Userinfo.
+func (mr *MockOpenIDMockRecorder) Userinfo(ktx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Userinfo", reflect.TypeOf((*MockOpenID)(nil).Userinfo), ktx, token)
+}
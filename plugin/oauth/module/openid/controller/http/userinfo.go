@@ -0,0 +1,61 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+
+	"github.com/kodefluence/altair/module"
+)
+
+// UserinfoController control flow of openid connect userinfo
+type UserinfoController struct {
+	openIDUsecase OpenID
+	apiError      module.ApiError
+}
+
+// NewUserinfo create new userinfo controller
+func NewUserinfo(openIDUsecase OpenID, apiError module.ApiError) *UserinfoController {
+	return &UserinfoController{
+		openIDUsecase: openIDUsecase,
+		apiError:      apiError,
+	}
+}
+
+// Method GET
+func (o *UserinfoController) Method() string {
+	return "GET"
+}
+
+// Path /oauth/userinfo
+func (o *UserinfoController) Path() string {
+	return "/oauth/userinfo"
+}
+
+// Control responding the claims of the bearer token's resource owner. The
+// claims are responded as is instead of a jsonapi document, as openid connect
+// clients expect.
+func (o *UserinfoController) Control(ktx kontext.Context, c *gin.Context) {
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" || token == c.GetHeader("Authorization") {
+		c.Header("WWW-Authenticate", `Bearer`)
+		c.JSON(http.StatusUnauthorized, jsonapi.BuildResponse(o.apiError.UnauthorizedError()))
+		return
+	}
+
+	claims, jsonapierr := o.openIDUsecase.Userinfo(ktx, token)
+	if jsonapierr != nil {
+		if jsonapierr.HTTPStatus() == http.StatusUnauthorized {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		}
+
+		c.JSON(jsonapierr.HTTPStatus(), jsonapi.BuildResponse(jsonapi.WithErrors(jsonapierr)))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, claims)
+}
@@ -0,0 +1,94 @@
+package http_test
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/controller"
+	openidHttp "github.com/kodefluence/altair/plugin/oauth/module/openid/controller/http"
+	"github.com/kodefluence/altair/plugin/oauth/module/openid/controller/http/mock"
+	"github.com/kodefluence/altair/testhelper"
+)
+
+func TestUserinfo(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("Method", func(t *testing.T) {
+		assert.Equal(t, "GET", openidHttp.NewUserinfo(mock.NewMockOpenID(mockCtrl), apierror.Provide()).Method())
+	})
+
+	t.Run("Path", func(t *testing.T) {
+		assert.Equal(t, "/oauth/userinfo", openidHttp.NewUserinfo(mock.NewMockOpenID(mockCtrl), apierror.Provide()).Path())
+	})
+
+	t.Run("Control", func(t *testing.T) {
+		withBearer := func(token string) func(req *http.Request) {
+			return func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+
+		t.Run("Given bearer token", func(t *testing.T) {
+			t.Run("Return claims with status 200", func(t *testing.T) {
+				apiEngine := gin.Default()
+
+				openIDUsecase := mock.NewMockOpenID(mockCtrl)
+				openIDUsecase.EXPECT().Userinfo(gomock.Any(), "some-token").Return(map[string]interface{}{"sub": "1", "email": "jane@example.com"}, nil)
+
+				ctrl := openidHttp.NewUserinfo(openIDUsecase, apierror.Provide())
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), nil, withBearer("some-token"))
+				responseByte, err := io.ReadAll(w.Body)
+				assert.Nil(t, err)
+				assert.Equal(t, http.StatusOK, w.Code)
+				assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+				assert.Equal(t, `{"email":"jane@example.com","sub":"1"}`, string(responseByte))
+			})
+
+			t.Run("Return unauthorized with invalid_token challenge when token is invalid", func(t *testing.T) {
+				apiEngine := gin.Default()
+
+				openIDUsecase := mock.NewMockOpenID(mockCtrl)
+				openIDUsecase.EXPECT().Userinfo(gomock.Any(), "some-token").DoAndReturn(func(ktx kontext.Context, token string) (map[string]interface{}, jsonapi.Errors) {
+					return nil, jsonapi.BuildResponse(apierror.Provide().UnauthorizedError()).Errors
+				})
+
+				ctrl := openidHttp.NewUserinfo(openIDUsecase, apierror.Provide())
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), nil, withBearer("some-token"))
+				assert.Equal(t, http.StatusUnauthorized, w.Code)
+				assert.Equal(t, `Bearer error="invalid_token"`, w.Header().Get("WWW-Authenticate"))
+			})
+		})
+
+		t.Run("Given no bearer token", func(t *testing.T) {
+			t.Run("Return unauthorized with bearer challenge", func(t *testing.T) {
+				apiEngine := gin.Default()
+
+				openIDUsecase := mock.NewMockOpenID(mockCtrl)
+				openIDUsecase.EXPECT().Userinfo(gomock.Any(), gomock.Any()).Times(0)
+
+				ctrl := openidHttp.NewUserinfo(openIDUsecase, apierror.Provide())
+				controller.Provide(apiEngine.Handle, apierror.Provide(), &cobra.Command{}).InjectHTTP(ctrl)
+
+				w := testhelper.PerformRequest(apiEngine, ctrl.Method(), ctrl.Path(), nil, func(req *http.Request) {
+					req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+				})
+				assert.Equal(t, http.StatusUnauthorized, w.Code)
+				assert.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+			})
+		})
+	})
+}
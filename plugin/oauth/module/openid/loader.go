@@ -0,0 +1,29 @@
+package openid
+
+import (
+	"github.com/kodefluence/monorepo/db"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/module/openid/controller/http"
+	"github.com/kodefluence/altair/plugin/oauth/module/openid/usecase"
+)
+
+// Load serve openid discovery and userinfo on the public module, outside of the basic auth of /_plugins.
+func Load(
+	publicModule module.App,
+	oauthAccessTokenRepo usecase.OauthAccessTokenRepository,
+	tokenVerifier usecase.TokenVerifier,
+	identityUpstream usecase.IdentityUpstream,
+	config entity.OauthPlugin,
+	signingAlgorithm string,
+	sqldb db.DB,
+	apiError module.ApiError,
+) {
+	openIDUsecase := usecase.NewOpenID(oauthAccessTokenRepo, tokenVerifier, identityUpstream, usecase.NewConfiguration(config, signingAlgorithm), sqldb, apiError)
+
+	publicModule.Controller().InjectHTTP(
+		http.NewDiscovery(openIDUsecase),
+		http.NewUserinfo(openIDUsecase, apiError),
+	)
+}
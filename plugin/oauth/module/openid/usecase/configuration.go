@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"strings"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+// NewConfiguration build the openid provider metadata. Endpoints are resolved
+// against the issuer, which is expected to be the public url of altair.
+func NewConfiguration(config entity.OauthPlugin, signingAlgorithm string) entity.OpenIDConfigurationJSON {
+	issuer := strings.TrimSuffix(config.Config.JWT.Issuer, "/")
+
+	responseTypes := []string{"code"}
+	grantTypes := []string{"authorization_code", "client_credentials"}
+
+	if config.Config.ImplicitGrant.Active {
+		responseTypes = append(responseTypes, "token")
+		grantTypes = append(grantTypes, "implicit")
+	}
+
+	if config.Config.RefreshToken.Active {
+		grantTypes = append(grantTypes, "refresh_token")
+	}
+
+	return entity.OpenIDConfigurationJSON{
+		Issuer:                            config.Config.JWT.Issuer,
+		AuthorizationEndpoint:             issuer + "/_plugins/oauth/authorizations",
+		TokenEndpoint:                     issuer + "/_plugins/oauth/authorizations/token",
+		UserinfoEndpoint:                  issuer + "/oauth/userinfo",
+		JWKSURI:                           issuer + "/.well-known/jwks.json",
+		ScopesSupported:                   []string{entity.ScopeOpenID},
+		ResponseTypesSupported:            responseTypes,
+		GrantTypesSupported:               grantTypes,
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{signingAlgorithm},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{entity.CodeChallengeMethodS256, entity.CodeChallengeMethodPlain},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "nonce", "at_hash"},
+	}
+}
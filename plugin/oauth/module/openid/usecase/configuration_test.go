@@ -0,0 +1,41 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/module/openid/usecase"
+)
+
+func TestNewConfiguration(t *testing.T) {
+	t.Run("Given issuer with trailing slash, then the endpoints are resolved against it", func(t *testing.T) {
+		config := entity.OauthPlugin{}
+		config.Config.JWT.Issuer = "https://auth.example.com/"
+
+		configuration := usecase.NewConfiguration(config, entity.AlgorithmES256)
+
+		assert.Equal(t, "https://auth.example.com/", configuration.Issuer)
+		assert.Equal(t, "https://auth.example.com/_plugins/oauth/authorizations", configuration.AuthorizationEndpoint)
+		assert.Equal(t, "https://auth.example.com/_plugins/oauth/authorizations/token", configuration.TokenEndpoint)
+		assert.Equal(t, "https://auth.example.com/oauth/userinfo", configuration.UserinfoEndpoint)
+		assert.Equal(t, "https://auth.example.com/.well-known/jwks.json", configuration.JWKSURI)
+		assert.Equal(t, []string{"ES256"}, configuration.IDTokenSigningAlgValuesSupported)
+		assert.Equal(t, []string{"code"}, configuration.ResponseTypesSupported)
+		assert.Equal(t, []string{"authorization_code", "client_credentials"}, configuration.GrantTypesSupported)
+		assert.Equal(t, []string{"client_secret_post", "none"}, configuration.TokenEndpointAuthMethodsSupported)
+	})
+
+	t.Run("Given implicit grant and refresh token are active, then they are advertised", func(t *testing.T) {
+		config := entity.OauthPlugin{}
+		config.Config.JWT.Issuer = "https://auth.example.com"
+		config.Config.ImplicitGrant.Active = true
+		config.Config.RefreshToken.Active = true
+
+		configuration := usecase.NewConfiguration(config, entity.AlgorithmRS256)
+
+		assert.Equal(t, []string{"code", "token"}, configuration.ResponseTypesSupported)
+		assert.Equal(t, []string{"authorization_code", "client_credentials", "implicit", "refresh_token"}, configuration.GrantTypesSupported)
+	})
+}
@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./openid.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/kodefluence/altair/plugin/oauth/entity"
+	db "github.com/kodefluence/monorepo/db"
+	exception "github.com/kodefluence/monorepo/exception"
+	kontext "github.com/kodefluence/monorepo/kontext"
+)
+
+// MockOauthAccessTokenRepository is a mock of OauthAccessTokenRepository interface.
+type MockOauthAccessTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOauthAccessTokenRepositoryMockRecorder
+}
+
+// MockOauthAccessTokenRepositoryMockRecorder is the mock recorder for MockOauthAccessTokenRepository.
+type MockOauthAccessTokenRepositoryMockRecorder struct {
+	mock *MockOauthAccessTokenRepository
+}
+
+// NewMockOauthAccessTokenRepository creates a new mock instance.
+func NewMockOauthAccessTokenRepository(ctrl *gomock.Controller) *MockOauthAccessTokenRepository {
+	mock := &MockOauthAccessTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockOauthAccessTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOauthAccessTokenRepository) EXPECT() *MockOauthAccessTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// One mocks base method.
+func (m *MockOauthAccessTokenRepository) One(ktx kontext.Context, ID int, tx db.TX) (entity.OauthAccessToken, exception.Exception) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "One", ktx, ID, tx)
+	ret0, _ := ret[0].(entity.OauthAccessToken)
+	ret1, _ := ret[1].(exception.Exception)
+	return ret0, ret1
+}
+
+// One indicates an expected call of One.
+func (mr *MockOauthAccessTokenRepositoryMockRecorder) One(ktx, ID, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "One", reflect.TypeOf((*MockOauthAccessTokenRepository)(nil).One), ktx, ID, tx)
+}
+
+// OneByToken mocks base method.
+func (m *MockOauthAccessTokenRepository) OneByToken(ktx kontext.Context, token string, tx db.TX) (entity.OauthAccessToken, exception.Exception) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OneByToken", ktx, token, tx)
+	ret0, _ := ret[0].(entity.OauthAccessToken)
+	ret1, _ := ret[1].(exception.Exception)
+	return ret0, ret1
+}
+
+// OneByToken indicates an expected call of OneByToken.
+func (mr *MockOauthAccessTokenRepositoryMockRecorder) OneByToken(ktx, token, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OneByToken", reflect.TypeOf((*MockOauthAccessTokenRepository)(nil).OneByToken), ktx, token, tx)
+}
+
+// MockTokenVerifier is a mock of TokenVerifier interface.
+type MockTokenVerifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockTokenVerifierMockRecorder
+}
+
+// MockTokenVerifierMockRecorder is the mock recorder for MockTokenVerifier.
+type MockTokenVerifierMockRecorder struct {
+	mock *MockTokenVerifier
+}
+
+// NewMockTokenVerifier creates a new mock instance.
+func NewMockTokenVerifier(ctrl *gomock.Controller) *MockTokenVerifier {
+	mock := &MockTokenVerifier{ctrl: ctrl}
+	mock.recorder = &MockTokenVerifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTokenVerifier) EXPECT() *MockTokenVerifierMockRecorder {
+	return m.recorder
+}
+
+// VerifyAccessToken mocks base method.
+func (m *MockTokenVerifier) VerifyAccessToken(token string) (entity.AccessTokenClaims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyAccessToken", token)
+	ret0, _ := ret[0].(entity.AccessTokenClaims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyAccessToken indicates an expected call of VerifyAccessToken.
+func (mr *MockTokenVerifierMockRecorder) VerifyAccessToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyAccessToken", reflect.TypeOf((*MockTokenVerifier)(nil).VerifyAccessToken), token)
+}
+
+// MockIdentityUpstream is a mock of IdentityUpstream interface.
+type MockIdentityUpstream struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdentityUpstreamMockRecorder
+}
+
+// MockIdentityUpstreamMockRecorder is the mock recorder for MockIdentityUpstream.
+type MockIdentityUpstreamMockRecorder struct {
+	mock *MockIdentityUpstream
+}
+
+// NewMockIdentityUpstream creates a new mock instance.
+func NewMockIdentityUpstream(ctrl *gomock.Controller) *MockIdentityUpstream {
+	mock := &MockIdentityUpstream{ctrl: ctrl}
+	mock.recorder = &MockIdentityUpstreamMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdentityUpstream) EXPECT() *MockIdentityUpstreamMockRecorder {
+	return m.recorder
+}
+
+// Claims mocks base method.
+func (m *MockIdentityUpstream) Claims(ktx kontext.Context, accessToken entity.OauthAccessToken) (map[string]interface{}, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claims", ktx, accessToken)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claims indicates an expected call of Claims.
+func (mr *MockIdentityUpstreamMockRecorder) Claims(ktx, accessToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claims", reflect.TypeOf((*MockIdentityUpstream)(nil).Claims), ktx, accessToken)
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"github.com/kodefluence/monorepo/db"
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/kodefluence/monorepo/kontext"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./openid.go
+
+type OauthAccessTokenRepository interface {
+	OneByToken(ktx kontext.Context, token string, tx db.TX) (entity.OauthAccessToken, exception.Exception)
+	One(ktx kontext.Context, ID int, tx db.TX) (entity.OauthAccessToken, exception.Exception)
+}
+
+// TokenVerifier maps a jwt access token to its stored access token id, it is nil when access tokens are opaque
+type TokenVerifier interface {
+	VerifyAccessToken(token string) (entity.AccessTokenClaims, error)
+}
+
+// IdentityUpstream returns the claims of the resource owner of an access token
+type IdentityUpstream interface {
+	Claims(ktx kontext.Context, accessToken entity.OauthAccessToken) (map[string]interface{}, error)
+}
+
+// OpenID struct handle the openid connect endpoints
+type OpenID struct {
+	oauthAccessTokenRepo OauthAccessTokenRepository
+	tokenVerifier        TokenVerifier
+	identityUpstream     IdentityUpstream
+
+	configuration entity.OpenIDConfigurationJSON
+	sqldb         db.DB
+	apiError      module.ApiError
+}
+
+func NewOpenID(
+	oauthAccessTokenRepo OauthAccessTokenRepository,
+	tokenVerifier TokenVerifier,
+	identityUpstream IdentityUpstream,
+	configuration entity.OpenIDConfigurationJSON,
+	sqldb db.DB,
+	apiError module.ApiError,
+) *OpenID {
+	return &OpenID{
+		oauthAccessTokenRepo: oauthAccessTokenRepo,
+		tokenVerifier:        tokenVerifier,
+		identityUpstream:     identityUpstream,
+		configuration:        configuration,
+		sqldb:                sqldb,
+		apiError:             apiError,
+	}
+}
+
+// Configuration returns the openid provider metadata served in discovery
+func (o *OpenID) Configuration() entity.OpenIDConfigurationJSON {
+	return o.configuration
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+// Userinfo returns the claims of the resource owner of an access token granted the openid scope.
+// The claims come from the identity upstream, the sub claim is always the resource owner id.
+func (o *OpenID) Userinfo(ktx kontext.Context, token string) (map[string]interface{}, jsonapi.Errors) {
+	accessToken, exc := o.oneAccessToken(ktx, token)
+	if exc != nil {
+		if exc.Type() == exception.NotFound {
+			return nil, jsonapi.BuildResponse(o.apiError.UnauthorizedError()).Errors
+		}
+
+		log.Error().
+			Err(exc).
+			Stack().
+			Interface("request_id", ktx.GetWithoutCheck("request_id")).
+			Array("tags", zerolog.Arr().Str("service").Str("openid").Str("userinfo")).
+			Msg("Error finding access token")
+
+		return nil, jsonapi.BuildResponse(o.apiError.InternalServerError(ktx)).Errors
+	}
+
+	if time.Now().After(accessToken.ExpiresIn) {
+		return nil, jsonapi.BuildResponse(o.apiError.UnauthorizedError()).Errors
+	}
+
+	if !entity.HasScope(accessToken.Scopes.String, entity.ScopeOpenID) {
+		return nil, jsonapi.BuildResponse(o.apiError.ForbiddenError(ktx, "userinfo", "access token is not granted openid scope")).Errors
+	}
+
+	claims, err := o.identityUpstream.Claims(ktx, accessToken)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Stack().
+			Interface("request_id", ktx.GetWithoutCheck("request_id")).
+			Array("tags", zerolog.Arr().Str("service").Str("openid").Str("userinfo").Str("identity_upstream")).
+			Msg("Error fetching claims from identity upstream")
+
+		return nil, jsonapi.BuildResponse(o.apiError.ServiceUnavailableError(ktx, "identity upstream")).Errors
+	}
+
+	if claims == nil {
+		claims = map[string]interface{}{}
+	}
+	claims["sub"] = strconv.Itoa(accessToken.ResourceOwnerID)
+
+	return claims, nil
+}
+
+// oneAccessToken find the access token of an opaque token, a jwt access token is found by its oauth_access_token_id claim
+func (o *OpenID) oneAccessToken(ktx kontext.Context, token string) (entity.OauthAccessToken, exception.Exception) {
+	if ID, ok := entity.AccessTokenID(o.tokenVerifier, token); ok {
+		return o.oauthAccessTokenRepo.One(ktx, ID, o.sqldb)
+	}
+
+	return o.oauthAccessTokenRepo.OneByToken(ktx, token, o.sqldb)
+}
@@ -0,0 +1,140 @@
+package usecase_test
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/kodefluence/monorepo/db/mock"
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/module/openid/usecase"
+	"github.com/kodefluence/altair/plugin/oauth/module/openid/usecase/mock"
+)
+
+func TestUserinfo(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ktx := kontext.Fabricate()
+	sqldb := mockdb.NewMockDB(mockCtrl)
+
+	accessToken := entity.OauthAccessToken{
+		OauthApplicationID: 2,
+		ResourceOwnerID:    1,
+		Token:              "stored-token",
+		Scopes:             sql.NullString{String: "openid profile", Valid: true},
+		ExpiresIn:          time.Now().Add(time.Hour),
+	}
+
+	t.Run("Given opaque access token granted openid scope", func(t *testing.T) {
+		t.Run("Return claims of identity upstream with sub of the resource owner", func(t *testing.T) {
+			oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+			oauthAccessTokenRepo.EXPECT().OneByToken(ktx, "stored-token", sqldb).Return(accessToken, nil)
+
+			identityUpstream := mock.NewMockIdentityUpstream(mockCtrl)
+			identityUpstream.EXPECT().Claims(ktx, accessToken).Return(map[string]interface{}{"sub": "spoofed", "email": "jane@example.com"}, nil)
+
+			openID := usecase.NewOpenID(oauthAccessTokenRepo, nil, identityUpstream, entity.OpenIDConfigurationJSON{}, sqldb, apierror.Provide())
+
+			claims, err := openID.Userinfo(ktx, "stored-token")
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]interface{}{"sub": "1", "email": "jane@example.com"}, claims)
+		})
+	})
+
+	t.Run("Given jwt access token", func(t *testing.T) {
+		t.Run("Return claims of the access token of its claims", func(t *testing.T) {
+			tokenVerifier := mock.NewMockTokenVerifier(mockCtrl)
+			tokenVerifier.EXPECT().VerifyAccessToken("header.claims.signature").Return(entity.AccessTokenClaims{ID: "jti", OauthAccessTokenID: 5}, nil)
+
+			oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+			oauthAccessTokenRepo.EXPECT().One(ktx, 5, sqldb).Return(accessToken, nil)
+
+			identityUpstream := mock.NewMockIdentityUpstream(mockCtrl)
+			identityUpstream.EXPECT().Claims(ktx, accessToken).Return(nil, nil)
+
+			openID := usecase.NewOpenID(oauthAccessTokenRepo, tokenVerifier, identityUpstream, entity.OpenIDConfigurationJSON{}, sqldb, apierror.Provide())
+
+			claims, err := openID.Userinfo(ktx, "header.claims.signature")
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]interface{}{"sub": "1"}, claims)
+		})
+	})
+
+	t.Run("Given unknown or revoked access token", func(t *testing.T) {
+		t.Run("Return unauthorized error", func(t *testing.T) {
+			oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+			oauthAccessTokenRepo.EXPECT().OneByToken(ktx, "unknown-token", sqldb).Return(entity.OauthAccessToken{}, exception.Throw(sql.ErrNoRows, exception.WithType(exception.NotFound)))
+
+			openID := usecase.NewOpenID(oauthAccessTokenRepo, nil, mock.NewMockIdentityUpstream(mockCtrl), entity.OpenIDConfigurationJSON{}, sqldb, apierror.Provide())
+
+			_, err := openID.Userinfo(ktx, "unknown-token")
+			assert.Equal(t, http.StatusUnauthorized, err.HTTPStatus())
+		})
+	})
+
+	t.Run("Given expired access token", func(t *testing.T) {
+		t.Run("Return unauthorized error", func(t *testing.T) {
+			expiredAccessToken := accessToken
+			expiredAccessToken.ExpiresIn = time.Now().Add(-time.Minute)
+
+			oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+			oauthAccessTokenRepo.EXPECT().OneByToken(ktx, "stored-token", sqldb).Return(expiredAccessToken, nil)
+
+			openID := usecase.NewOpenID(oauthAccessTokenRepo, nil, mock.NewMockIdentityUpstream(mockCtrl), entity.OpenIDConfigurationJSON{}, sqldb, apierror.Provide())
+
+			_, err := openID.Userinfo(ktx, "stored-token")
+			assert.Equal(t, http.StatusUnauthorized, err.HTTPStatus())
+		})
+	})
+
+	t.Run("Given access token without openid scope", func(t *testing.T) {
+		t.Run("Return forbidden error", func(t *testing.T) {
+			publicAccessToken := accessToken
+			publicAccessToken.Scopes = sql.NullString{String: "public", Valid: true}
+
+			oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+			oauthAccessTokenRepo.EXPECT().OneByToken(ktx, "stored-token", sqldb).Return(publicAccessToken, nil)
+
+			openID := usecase.NewOpenID(oauthAccessTokenRepo, nil, mock.NewMockIdentityUpstream(mockCtrl), entity.OpenIDConfigurationJSON{}, sqldb, apierror.Provide())
+
+			_, err := openID.Userinfo(ktx, "stored-token")
+			assert.Equal(t, http.StatusForbidden, err.HTTPStatus())
+		})
+	})
+
+	t.Run("Given database error", func(t *testing.T) {
+		t.Run("Return internal server error", func(t *testing.T) {
+			oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+			oauthAccessTokenRepo.EXPECT().OneByToken(ktx, "stored-token", sqldb).Return(entity.OauthAccessToken{}, exception.Throw(errors.New("unexpected")))
+
+			openID := usecase.NewOpenID(oauthAccessTokenRepo, nil, mock.NewMockIdentityUpstream(mockCtrl), entity.OpenIDConfigurationJSON{}, sqldb, apierror.Provide())
+
+			_, err := openID.Userinfo(ktx, "stored-token")
+			assert.Equal(t, http.StatusInternalServerError, err.HTTPStatus())
+		})
+	})
+
+	t.Run("Given identity upstream error", func(t *testing.T) {
+		t.Run("Return service unavailable error", func(t *testing.T) {
+			oauthAccessTokenRepo := mock.NewMockOauthAccessTokenRepository(mockCtrl)
+			oauthAccessTokenRepo.EXPECT().OneByToken(ktx, "stored-token", sqldb).Return(accessToken, nil)
+
+			identityUpstream := mock.NewMockIdentityUpstream(mockCtrl)
+			identityUpstream.EXPECT().Claims(ktx, accessToken).Return(nil, errors.New("connection refused"))
+
+			openID := usecase.NewOpenID(oauthAccessTokenRepo, nil, identityUpstream, entity.OpenIDConfigurationJSON{}, sqldb, apierror.Provide())
+
+			_, err := openID.Userinfo(ktx, "stored-token")
+			assert.Equal(t, http.StatusServiceUnavailable, err.HTTPStatus())
+		})
+	})
+}
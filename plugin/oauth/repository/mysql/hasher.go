@@ -0,0 +1,137 @@
+package mysql
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kodefluence/monorepo/db"
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/kodefluence/monorepo/kontext"
+)
+
+// rehashBatchSize is the number of plaintext rows rehashed per select
+const rehashBatchSize = 500
+
+// Hasher compute the keyed hash stored in place of tokens and client secrets.
+// Lookups hash the given value with the same pepper, so the plaintext never
+// touch the database. The oauth plugin does not load without a pepper, a
+// hasher without one disables hashing and stores values as plaintext.
+type Hasher struct {
+	pepper []byte
+}
+
+// NewHasher create new Hasher keyed by the given pepper
+func NewHasher(pepper string) *Hasher {
+	return &Hasher{pepper: []byte(pepper)}
+}
+
+// Active return true when the hasher has a pepper to hash with
+func (h *Hasher) Active() bool {
+	return len(h.pepper) > 0
+}
+
+// Hash return hex encoded HMAC-SHA256 of the value, or the value itself when hashing is disabled
+func (h *Hasher) Hash(value string) string {
+	if !h.Active() {
+		return value
+	}
+
+	mac := hmac.New(sha256.New, h.pepper)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// hashed return the flag stored alongside a value written with Hash
+func (h *Hasher) hashed() int {
+	if !h.Active() {
+		return 0
+	}
+
+	return 1
+}
+
+// match return the condition and arguments finding value in column. Rows
+// stored before hashing was enabled still match their plaintext until they are
+// rehashed, so upgrading does not invalidate live credentials.
+func (h *Hasher) match(column, hashedColumn, value string) (string, []interface{}) {
+	if !h.Active() {
+		return fmt.Sprintf("%s = ? and %s = 0", column, hashedColumn), []interface{}{value}
+	}
+
+	return fmt.Sprintf("((%s = ? and %s = 1) or (%s = ? and %s = 0))", column, hashedColumn, column, hashedColumn), []interface{}{h.Hash(value), value}
+}
+
+// rehash replace plaintext values of the column, flagged by hashedColumn, with their keyed hash
+func (h *Hasher) rehash(ktx kontext.Context, name, table, column, hashedColumn string, tx db.TX) (int, exception.Exception) {
+	if !h.Active() {
+		return 0, exception.Throw(errors.New("token_hash_pepper is not set"), exception.WithType(exception.BadInput), exception.WithDetail("oauth token_hash_pepper must be set to rehash credentials"))
+	}
+
+	total := 0
+
+	for {
+		plaintexts, exc := h.plaintexts(ktx, name, table, column, hashedColumn, tx)
+		if exc != nil {
+			return total, exc
+		}
+
+		for ID, plaintext := range plaintexts {
+			result, err := tx.ExecContext(
+				ktx,
+				fmt.Sprintf("%s-update", name),
+				fmt.Sprintf("update %s set %s = ?, %s = 1 where id = ? and %s = 0", table, column, hashedColumn, hashedColumn),
+				h.Hash(plaintext), ID,
+			)
+			if err != nil {
+				return total, err
+			}
+
+			rows, err := result.RowsAffected()
+			if err != nil {
+				return total, err
+			}
+
+			total += int(rows)
+		}
+
+		if len(plaintexts) < rehashBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (*Hasher) plaintexts(ktx kontext.Context, name, table, column, hashedColumn string, tx db.TX) (map[int]string, exception.Exception) {
+	plaintexts := map[int]string{}
+
+	ctxWithTimeout, cf := context.WithTimeout(ktx.Ctx(), time.Second*10)
+	defer cf()
+
+	rows, err := tx.QueryContext(
+		kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout)),
+		fmt.Sprintf("%s-select", name),
+		fmt.Sprintf("select id, %s from %s where %s = 0 limit ?", column, table, hashedColumn),
+		rehashBatchSize,
+	)
+	if err != nil {
+		return plaintexts, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ID int
+		var plaintext string
+
+		if err := rows.Scan(&ID, &plaintext); err != nil {
+			return plaintexts, err
+		}
+
+		plaintexts[ID] = plaintext
+	}
+
+	return plaintexts, rows.Err()
+}
@@ -0,0 +1,107 @@
+package mysql_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/kodefluence/monorepo/db/mock"
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/stretchr/testify/assert"
+
+	repository "github.com/kodefluence/altair/plugin/oauth/repository/mysql"
+)
+
+func TestHasher(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	t.Run("Hash", func(t *testing.T) {
+		t.Run("Given pepper and value, then it return hex encoded hmac sha256", func(t *testing.T) {
+			assert.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", repository.NewHasher("Jefe").Hash("what do ya want for nothing?"))
+		})
+
+		t.Run("Given different pepper, then it return different hash", func(t *testing.T) {
+			assert.NotEqual(t, repository.NewHasher("pepper").Hash("token"), repository.NewHasher("another pepper").Hash("token"))
+		})
+
+		t.Run("Given empty pepper, then hashing is disabled and it return the value", func(t *testing.T) {
+			assert.False(t, repository.NewHasher("").Active())
+			assert.Equal(t, "token", repository.NewHasher("").Hash("token"))
+		})
+	})
+
+	t.Run("Rehash", func(t *testing.T) {
+		hasher := repository.NewHasher("pepper")
+
+		t.Run("Given plaintext rows, then it replace each token with its hash and return the total", func(t *testing.T) {
+			sqldb := mockdb.NewMockDB(mockCtrl)
+			rows := mockdb.NewMockRows(mockCtrl)
+			result := mockdb.NewMockResult(mockCtrl)
+
+			sqldb.EXPECT().QueryContext(gomock.Any(), "oauth-access-token-rehash-select", "select id, token from oauth_access_tokens where token_hashed = 0 limit ?", 500).Return(rows, nil)
+			gomock.InOrder(
+				rows.EXPECT().Next().Return(true),
+				rows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
+					*dest[0].(*int) = 1
+					*dest[1].(*string) = "token"
+					return nil
+				}),
+				rows.EXPECT().Next().Return(false),
+				rows.EXPECT().Err().Return(nil),
+				rows.EXPECT().Close().Return(nil),
+			)
+
+			sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-rehash-update", "update oauth_access_tokens set token = ?, token_hashed = 1 where id = ? and token_hashed = 0", hasher.Hash("token"), 1).Return(result, nil)
+			result.EXPECT().RowsAffected().Return(int64(1), nil)
+
+			total, err := repository.NewOauthAccessToken(hasher).Rehash(kontext.Fabricate(), sqldb)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, total)
+		})
+
+		t.Run("Given hasher without pepper, then it return error without touching the database", func(t *testing.T) {
+			sqldb := mockdb.NewMockDB(mockCtrl)
+
+			total, err := repository.NewOauthAccessToken(repository.NewHasher("")).Rehash(kontext.Fabricate(), sqldb)
+			assert.NotNil(t, err)
+			assert.Equal(t, exception.BadInput, err.Type())
+			assert.Equal(t, 0, total)
+		})
+
+		t.Run("Given select error, then it return error", func(t *testing.T) {
+			sqldb := mockdb.NewMockDB(mockCtrl)
+
+			sqldb.EXPECT().QueryContext(gomock.Any(), "oauth-refresh-token-rehash-select", "select id, token from oauth_refresh_tokens where token_hashed = 0 limit ?", 500).Return(nil, exception.Throw(errors.New("unexpected error")))
+
+			total, err := repository.NewOauthRefreshToken(hasher).Rehash(kontext.Fabricate(), sqldb)
+			assert.NotNil(t, err)
+			assert.Equal(t, 0, total)
+		})
+
+		t.Run("Given update error, then it return error", func(t *testing.T) {
+			sqldb := mockdb.NewMockDB(mockCtrl)
+			rows := mockdb.NewMockRows(mockCtrl)
+
+			sqldb.EXPECT().QueryContext(gomock.Any(), "oauth-application-rehash-select", "select id, client_secret from oauth_applications where client_secret_hashed = 0 limit ?", 500).Return(rows, nil)
+			gomock.InOrder(
+				rows.EXPECT().Next().Return(true),
+				rows.EXPECT().Scan(gomock.Any(), gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
+					*dest[0].(*int) = 1
+					*dest[1].(*string) = "secret"
+					return nil
+				}),
+				rows.EXPECT().Next().Return(false),
+				rows.EXPECT().Err().Return(nil),
+				rows.EXPECT().Close().Return(nil),
+			)
+
+			sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-application-rehash-update", "update oauth_applications set client_secret = ?, client_secret_hashed = 1 where id = ? and client_secret_hashed = 0", hasher.Hash("secret"), 1).Return(nil, exception.Throw(errors.New("unexpected error")))
+
+			total, err := repository.NewOauthApplication(hasher).Rehash(kontext.Fabricate(), sqldb)
+			assert.NotNil(t, err)
+			assert.Equal(t, 0, total)
+		})
+	})
+}
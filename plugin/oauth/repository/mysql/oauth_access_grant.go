@@ -30,7 +30,7 @@ func (*OauthAccessGrant) One(ktx kontext.Context, ID int, tx db.TX) (entity.Oaut
 	row := tx.QueryRowContext(
 		kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout)),
 		"oauth-access-grant-one",
-		"select id, oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at from oauth_access_grants where id = ? limit 1",
+		"select id, oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at, code_challenge, code_challenge_method, nonce from oauth_access_grants where id = ? limit 1",
 		ID,
 	)
 	err := row.Scan(
@@ -43,6 +43,9 @@ func (*OauthAccessGrant) One(ktx kontext.Context, ID int, tx db.TX) (entity.Oaut
 		&oauthAccessGrant.ExpiresIn,
 		&oauthAccessGrant.CreatedAt,
 		&oauthAccessGrant.RevokedAT,
+		&oauthAccessGrant.CodeChallenge,
+		&oauthAccessGrant.CodeChallengeMethod,
+		&oauthAccessGrant.Nonce,
 	)
 
 	return oauthAccessGrant, err
@@ -58,7 +61,7 @@ func (*OauthAccessGrant) OneByCode(ktx kontext.Context, code string, tx db.TX) (
 	row := tx.QueryRowContext(
 		kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout)),
 		"oauth-access-grant-one-by-code",
-		"select id, oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at from oauth_access_grants where code = ? limit 1",
+		"select id, oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at, code_challenge, code_challenge_method, nonce from oauth_access_grants where code = ? limit 1",
 		code,
 	)
 	err := row.Scan(
@@ -71,6 +74,9 @@ func (*OauthAccessGrant) OneByCode(ktx kontext.Context, code string, tx db.TX) (
 		&oauthAccessGrant.ExpiresIn,
 		&oauthAccessGrant.CreatedAt,
 		&oauthAccessGrant.RevokedAT,
+		&oauthAccessGrant.CodeChallenge,
+		&oauthAccessGrant.CodeChallengeMethod,
+		&oauthAccessGrant.Nonce,
 	)
 
 	return oauthAccessGrant, err
@@ -81,12 +87,15 @@ func (*OauthAccessGrant) Create(ktx kontext.Context, data entity.OauthAccessGran
 	result, err := tx.ExecContext(
 		ktx,
 		"oauth-access-grant-create",
-		"insert into oauth_access_grants (oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, now(), null)",
+		"insert into oauth_access_grants (oauth_application_id, resource_owner_id, scopes, code, redirect_uri, code_challenge, code_challenge_method, nonce, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, ?, ?, ?, now(), null)",
 		data.OauthApplicationID,
 		data.ResourceOwnerID,
 		data.Scopes,
 		data.Code,
 		data.RedirectURI,
+		data.CodeChallenge,
+		data.CodeChallengeMethod,
+		data.Nonce,
 		data.ExpiresIn,
 	)
 	if err != nil {
@@ -33,7 +33,7 @@ func TestOauthAccessGrant(t *testing.T) {
 				sqldb.EXPECT().QueryRowContext(
 					gomock.Any(),
 					"oauth-access-grant-one",
-					"select id, oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at from oauth_access_grants where id = ? limit 1",
+					"select id, oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at, code_challenge, code_challenge_method, nonce from oauth_access_grants where id = ? limit 1",
 					expectedData.ID,
 				).Return(row)
 				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
@@ -68,7 +68,7 @@ func TestOauthAccessGrant(t *testing.T) {
 				sqldb.EXPECT().QueryRowContext(
 					gomock.Any(),
 					"oauth-access-grant-one-by-code",
-					"select id, oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at from oauth_access_grants where code = ? limit 1",
+					"select id, oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at, code_challenge, code_challenge_method, nonce from oauth_access_grants where code = ? limit 1",
 					expectedData.Code,
 				).Return(row)
 				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
@@ -108,8 +108,8 @@ func TestOauthAccessGrant(t *testing.T) {
 				sqldb.EXPECT().ExecContext(
 					gomock.Any(),
 					"oauth-access-grant-create",
-					"insert into oauth_access_grants (oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, now(), null)",
-					insertable.OauthApplicationID, insertable.ResourceOwnerID, insertable.Scopes, insertable.Code, insertable.RedirectURI, insertable.ExpiresIn,
+					"insert into oauth_access_grants (oauth_application_id, resource_owner_id, scopes, code, redirect_uri, code_challenge, code_challenge_method, nonce, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, ?, ?, ?, now(), null)",
+					insertable.OauthApplicationID, insertable.ResourceOwnerID, insertable.Scopes, insertable.Code, insertable.RedirectURI, insertable.CodeChallenge, insertable.CodeChallengeMethod, insertable.Nonce, insertable.ExpiresIn,
 				).Return(result, nil)
 
 				result.EXPECT().LastInsertId().Return(int64(expectedID), nil)
@@ -136,8 +136,8 @@ func TestOauthAccessGrant(t *testing.T) {
 				sqldb.EXPECT().ExecContext(
 					gomock.Any(),
 					"oauth-access-grant-create",
-					"insert into oauth_access_grants (oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, now(), null)",
-					insertable.OauthApplicationID, insertable.ResourceOwnerID, insertable.Scopes, insertable.Code, insertable.RedirectURI, insertable.ExpiresIn,
+					"insert into oauth_access_grants (oauth_application_id, resource_owner_id, scopes, code, redirect_uri, code_challenge, code_challenge_method, nonce, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, ?, ?, ?, now(), null)",
+					insertable.OauthApplicationID, insertable.ResourceOwnerID, insertable.Scopes, insertable.Code, insertable.RedirectURI, insertable.CodeChallenge, insertable.CodeChallengeMethod, insertable.Nonce, insertable.ExpiresIn,
 				).Return(nil, exception.Throw(errors.New("unexpected")))
 
 				oauthAccessGrantModel := repository.NewOauthAccessGrant()
@@ -163,8 +163,8 @@ func TestOauthAccessGrant(t *testing.T) {
 				sqldb.EXPECT().ExecContext(
 					gomock.Any(),
 					"oauth-access-grant-create",
-					"insert into oauth_access_grants (oauth_application_id, resource_owner_id, scopes, code, redirect_uri, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, now(), null)",
-					insertable.OauthApplicationID, insertable.ResourceOwnerID, insertable.Scopes, insertable.Code, insertable.RedirectURI, insertable.ExpiresIn,
+					"insert into oauth_access_grants (oauth_application_id, resource_owner_id, scopes, code, redirect_uri, code_challenge, code_challenge_method, nonce, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, ?, ?, ?, now(), null)",
+					insertable.OauthApplicationID, insertable.ResourceOwnerID, insertable.Scopes, insertable.Code, insertable.RedirectURI, insertable.CodeChallenge, insertable.CodeChallengeMethod, insertable.Nonce, insertable.ExpiresIn,
 				).Return(result, nil)
 
 				result.EXPECT().LastInsertId().Return(int64(0), exception.Throw(errors.New("unexpected error")))
@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/kodefluence/monorepo/db"
@@ -13,25 +14,28 @@ import (
 )
 
 // OauthAccessToken handle all database operation to oauth_access_tokens
-type OauthAccessToken struct{}
+type OauthAccessToken struct {
+	hasher *Hasher
+}
 
-// NewOauthAccessToken create new OauthAccessTokens struct
-func NewOauthAccessToken() *OauthAccessToken {
-	return &OauthAccessToken{}
+// NewOauthAccessToken create new OauthAccessTokens struct, tokens are stored as keyed hash of the hasher
+func NewOauthAccessToken(hasher *Hasher) *OauthAccessToken {
+	return &OauthAccessToken{hasher: hasher}
 }
 
-// OneByToken get oauth access token data by token string
-func (*OauthAccessToken) OneByToken(ktx kontext.Context, token string, tx db.TX) (entity.OauthAccessToken, exception.Exception) {
+// OneByToken get oauth access token data by token string, the returned token is the stored value
+func (o *OauthAccessToken) OneByToken(ktx kontext.Context, token string, tx db.TX) (entity.OauthAccessToken, exception.Exception) {
 	var oauthAccessToken entity.OauthAccessToken
 
 	ctxWithTimeout, cf := context.WithTimeout(ktx.Ctx(), time.Second*10)
 	defer cf()
 
+	condition, args := o.hasher.match("token", "token_hashed", token)
 	row := tx.QueryRowContext(
 		kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout)),
 		"oauth-access-token-one-by-token",
-		"select id, oauth_application_id, resource_owner_id, token, scopes, expires_in, created_at, revoked_at from oauth_access_tokens where token = ? and revoked_at is null limit 1",
-		token,
+		fmt.Sprintf("select id, oauth_application_id, resource_owner_id, token, scopes, expires_in, created_at, revoked_at from oauth_access_tokens where %s and revoked_at is null limit 1", condition),
+		args...,
 	)
 	err := row.Scan(
 		&oauthAccessToken.ID,
@@ -75,12 +79,12 @@ func (*OauthAccessToken) One(ktx kontext.Context, ID int, tx db.TX) (entity.Oaut
 }
 
 // Create new oauth access token
-func (*OauthAccessToken) Create(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
+func (o *OauthAccessToken) Create(ktx kontext.Context, data entity.OauthAccessTokenInsertable, tx db.TX) (int, exception.Exception) {
 	result, err := tx.ExecContext(
 		ktx,
 		"oauth-access-token-create",
-		"insert into oauth_access_tokens (oauth_application_id, resource_owner_id, token, scopes, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, now(), null)",
-		data.OauthApplicationID, data.ResourceOwnerID, data.Token, data.Scopes, data.ExpiresIn)
+		"insert into oauth_access_tokens (oauth_application_id, resource_owner_id, token, token_hashed, scopes, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, now(), null)",
+		data.OauthApplicationID, data.ResourceOwnerID, o.hasher.Hash(data.Token), o.hasher.hashed(), data.Scopes, data.ExpiresIn)
 	if err != nil {
 		return 0, err
 	}
@@ -94,12 +98,13 @@ func (*OauthAccessToken) Create(ktx kontext.Context, data entity.OauthAccessToke
 }
 
 // Revoke oauth access token
-func (*OauthAccessToken) Revoke(ktx kontext.Context, token string, tx db.TX) exception.Exception {
+func (o *OauthAccessToken) Revoke(ktx kontext.Context, token string, tx db.TX) exception.Exception {
+	condition, args := o.hasher.match("token", "token_hashed", token)
 	result, err := tx.ExecContext(
 		ktx,
 		"oauth-access-token-revoke",
-		"update oauth_access_tokens set revoked_at = now() where token = ?",
-		token,
+		fmt.Sprintf("update oauth_access_tokens set revoked_at = now() where %s", condition),
+		args...,
 	)
 	if err != nil {
 		return err
@@ -116,3 +121,32 @@ func (*OauthAccessToken) Revoke(ktx kontext.Context, token string, tx db.TX) exc
 
 	return nil
 }
+
+// RevokeByID revoke oauth access token by id, it is used to revoke a jwt access token
+func (*OauthAccessToken) RevokeByID(ktx kontext.Context, ID int, tx db.TX) exception.Exception {
+	result, err := tx.ExecContext(
+		ktx,
+		"oauth-access-token-revoke-by-id",
+		"update oauth_access_tokens set revoked_at = now() where id = ? and revoked_at is null",
+		ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return exception.Throw(errors.New("not found"), exception.WithType(exception.NotFound), exception.WithDetail("oauth access token is not found"), exception.WithTitle("Not Found"))
+	}
+
+	return nil
+}
+
+// Rehash replace plaintext tokens stored before hashing with their keyed hash
+func (o *OauthAccessToken) Rehash(ktx kontext.Context, tx db.TX) (int, exception.Exception) {
+	return o.hasher.rehash(ktx, "oauth-access-token-rehash", "oauth_access_tokens", "token", "token_hashed", tx)
+}
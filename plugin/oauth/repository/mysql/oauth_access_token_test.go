@@ -19,6 +19,8 @@ func TestOauthAccessToken(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
+	hasher := repository.NewHasher("pepper")
+
 	t.Run("one", func(t *testing.T) {
 		t.Run("Given oauth access token", func(t *testing.T) {
 			t.Run("When database operation complete it will return oauth access token data", func(t *testing.T) {
@@ -45,7 +47,7 @@ func TestOauthAccessToken(t *testing.T) {
 					return nil
 				})
 
-				oauthAccessTokenModel := repository.NewOauthAccessToken()
+				oauthAccessTokenModel := repository.NewOauthAccessToken(hasher)
 				data, err := oauthAccessTokenModel.One(kontext.Fabricate(), expectedData.ID, sqldb)
 
 				assert.Nil(t, err)
@@ -69,8 +71,8 @@ func TestOauthAccessToken(t *testing.T) {
 				sqldb.EXPECT().QueryRowContext(
 					gomock.Any(),
 					"oauth-access-token-one-by-token",
-					"select id, oauth_application_id, resource_owner_id, token, scopes, expires_in, created_at, revoked_at from oauth_access_tokens where token = ? and revoked_at is null limit 1",
-					expectedData.Token,
+					"select id, oauth_application_id, resource_owner_id, token, scopes, expires_in, created_at, revoked_at from oauth_access_tokens where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0)) and revoked_at is null limit 1",
+					hasher.Hash(expectedData.Token), expectedData.Token,
 				).Return(row)
 				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
 					val0, _ := dest[0].(*int)
@@ -81,13 +83,55 @@ func TestOauthAccessToken(t *testing.T) {
 					return nil
 				})
 
-				oauthAccessTokenModel := repository.NewOauthAccessToken()
+				oauthAccessTokenModel := repository.NewOauthAccessToken(hasher)
 				data, err := oauthAccessTokenModel.OneByToken(kontext.Fabricate(), expectedData.Token, sqldb)
 
 				assert.Nil(t, err)
 				assert.Equal(t, expectedData, data)
 			})
+		})
+
+		t.Run("Given plaintext oauth access token stored before hashing", func(t *testing.T) {
+			t.Run("When it is not rehashed yet, then it still match its plaintext", func(t *testing.T) {
+				sqldb := mockdb.NewMockDB(mockCtrl)
+				row := mockdb.NewMockRow(mockCtrl)
 
+				sqldb.EXPECT().QueryRowContext(
+					gomock.Any(),
+					"oauth-access-token-one-by-token",
+					"select id, oauth_application_id, resource_owner_id, token, scopes, expires_in, created_at, revoked_at from oauth_access_tokens where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0)) and revoked_at is null limit 1",
+					hasher.Hash("legacy-token"), "legacy-token",
+				).Return(row)
+				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
+					*dest[0].(*int) = 7
+					*dest[3].(*string) = "legacy-token"
+					return nil
+				})
+
+				data, err := repository.NewOauthAccessToken(hasher).OneByToken(kontext.Fabricate(), "legacy-token", sqldb)
+
+				assert.Nil(t, err)
+				assert.Equal(t, entity.OauthAccessToken{ID: 7, Token: "legacy-token"}, data)
+			})
+		})
+
+		t.Run("Given hasher without pepper", func(t *testing.T) {
+			t.Run("Then it only match plaintext tokens", func(t *testing.T) {
+				sqldb := mockdb.NewMockDB(mockCtrl)
+				row := mockdb.NewMockRow(mockCtrl)
+
+				sqldb.EXPECT().QueryRowContext(
+					gomock.Any(),
+					"oauth-access-token-one-by-token",
+					"select id, oauth_application_id, resource_owner_id, token, scopes, expires_in, created_at, revoked_at from oauth_access_tokens where token = ? and token_hashed = 0 and revoked_at is null limit 1",
+					"token",
+				).Return(row)
+				row.EXPECT().Scan(gomock.Any()).Return(nil)
+
+				_, err := repository.NewOauthAccessToken(repository.NewHasher("")).OneByToken(kontext.Fabricate(), "token", sqldb)
+
+				assert.Nil(t, err)
+			})
 		})
 	})
 
@@ -106,13 +150,13 @@ func TestOauthAccessToken(t *testing.T) {
 					ExpiresIn:          time.Now().Add(time.Hour * 24),
 				}
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-create", "insert into oauth_access_tokens (oauth_application_id, resource_owner_id, token, scopes, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, now(), null)",
-					insertable.OauthApplicationID, insertable.ResourceOwnerID, insertable.Token, insertable.Scopes, insertable.ExpiresIn,
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-create", "insert into oauth_access_tokens (oauth_application_id, resource_owner_id, token, token_hashed, scopes, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, now(), null)",
+					insertable.OauthApplicationID, insertable.ResourceOwnerID, hasher.Hash(insertable.Token), 1, insertable.Scopes, insertable.ExpiresIn,
 				).Return(result, nil)
 
 				result.EXPECT().LastInsertId().Return(int64(expectedID), nil)
 
-				oauthAccessTokenModel := repository.NewOauthAccessToken()
+				oauthAccessTokenModel := repository.NewOauthAccessToken(hasher)
 				ID, err := oauthAccessTokenModel.Create(kontext.Fabricate(), insertable, sqldb)
 
 				assert.Equal(t, expectedID, ID)
@@ -130,11 +174,11 @@ func TestOauthAccessToken(t *testing.T) {
 					ExpiresIn:          time.Now().Add(time.Hour * 24),
 				}
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-create", "insert into oauth_access_tokens (oauth_application_id, resource_owner_id, token, scopes, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, now(), null)",
-					insertable.OauthApplicationID, insertable.ResourceOwnerID, insertable.Token, insertable.Scopes, insertable.ExpiresIn,
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-create", "insert into oauth_access_tokens (oauth_application_id, resource_owner_id, token, token_hashed, scopes, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, now(), null)",
+					insertable.OauthApplicationID, insertable.ResourceOwnerID, hasher.Hash(insertable.Token), 1, insertable.Scopes, insertable.ExpiresIn,
 				).Return(nil, exception.Throw(errors.New("unexpected")))
 
-				oauthAccessTokenModel := repository.NewOauthAccessToken()
+				oauthAccessTokenModel := repository.NewOauthAccessToken(hasher)
 				_, err := oauthAccessTokenModel.Create(kontext.Fabricate(), insertable, sqldb)
 
 				assert.Equal(t, exception.Unexpected, err.Type())
@@ -153,13 +197,13 @@ func TestOauthAccessToken(t *testing.T) {
 					ExpiresIn:          time.Now().Add(time.Hour * 24),
 				}
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-create", "insert into oauth_access_tokens (oauth_application_id, resource_owner_id, token, scopes, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, now(), null)",
-					insertable.OauthApplicationID, insertable.ResourceOwnerID, insertable.Token, insertable.Scopes, insertable.ExpiresIn,
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-create", "insert into oauth_access_tokens (oauth_application_id, resource_owner_id, token, token_hashed, scopes, expires_in, created_at, revoked_at) values(?, ?, ?, ?, ?, ?, now(), null)",
+					insertable.OauthApplicationID, insertable.ResourceOwnerID, hasher.Hash(insertable.Token), 1, insertable.Scopes, insertable.ExpiresIn,
 				).Return(result, nil)
 
 				result.EXPECT().LastInsertId().Return(int64(0), exception.Throw(errors.New("unexpected error")))
 
-				oauthAccessTokenModel := repository.NewOauthAccessToken()
+				oauthAccessTokenModel := repository.NewOauthAccessToken(hasher)
 				ID, err := oauthAccessTokenModel.Create(kontext.Fabricate(), insertable, sqldb)
 
 				assert.Equal(t, 0, ID)
@@ -177,10 +221,10 @@ func TestOauthAccessToken(t *testing.T) {
 
 				token := "token"
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke", "update oauth_access_tokens set revoked_at = now() where token = ?", token).Return(result, nil)
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke", "update oauth_access_tokens set revoked_at = now() where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0))", hasher.Hash(token), token).Return(result, nil)
 				result.EXPECT().RowsAffected().Return(int64(1), nil)
 
-				oauthAccessTokenModel := repository.NewOauthAccessToken()
+				oauthAccessTokenModel := repository.NewOauthAccessToken(hasher)
 				err := oauthAccessTokenModel.Revoke(kontext.Fabricate(), token, sqldb)
 				assert.Nil(t, err)
 			})
@@ -191,10 +235,10 @@ func TestOauthAccessToken(t *testing.T) {
 
 				token := "token"
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke", "update oauth_access_tokens set revoked_at = now() where token = ?", token).Return(result, nil)
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke", "update oauth_access_tokens set revoked_at = now() where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0))", hasher.Hash(token), token).Return(result, nil)
 				result.EXPECT().RowsAffected().Return(int64(0), nil)
 
-				oauthAccessTokenModel := repository.NewOauthAccessToken()
+				oauthAccessTokenModel := repository.NewOauthAccessToken(hasher)
 				err := oauthAccessTokenModel.Revoke(kontext.Fabricate(), token, sqldb)
 				assert.NotNil(t, err)
 				assert.Equal(t, exception.NotFound, err.Type())
@@ -204,9 +248,9 @@ func TestOauthAccessToken(t *testing.T) {
 				sqldb := mockdb.NewMockDB(mockCtrl)
 				token := "token"
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke", "update oauth_access_tokens set revoked_at = now() where token = ?", token).Return(nil, exception.Throw(errors.New("unexpected error")))
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke", "update oauth_access_tokens set revoked_at = now() where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0))", hasher.Hash(token), token).Return(nil, exception.Throw(errors.New("unexpected error")))
 
-				oauthAccessTokenModel := repository.NewOauthAccessToken()
+				oauthAccessTokenModel := repository.NewOauthAccessToken(hasher)
 				err := oauthAccessTokenModel.Revoke(kontext.Fabricate(), token, sqldb)
 				assert.NotNil(t, err)
 			})
@@ -217,13 +261,49 @@ func TestOauthAccessToken(t *testing.T) {
 
 				token := "token"
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke", "update oauth_access_tokens set revoked_at = now() where token = ?", token).Return(result, nil)
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke", "update oauth_access_tokens set revoked_at = now() where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0))", hasher.Hash(token), token).Return(result, nil)
 				result.EXPECT().RowsAffected().Return(int64(0), exception.Throw(errors.New("unexpected error")))
 
-				oauthAccessTokenModel := repository.NewOauthAccessToken()
+				oauthAccessTokenModel := repository.NewOauthAccessToken(hasher)
 				err := oauthAccessTokenModel.Revoke(kontext.Fabricate(), token, sqldb)
 				assert.NotNil(t, err)
 			})
 		})
 	})
+
+	t.Run("RevokeByID", func(t *testing.T) {
+		t.Run("Given context and id", func(t *testing.T) {
+			t.Run("When database operation complete it will return nil", func(t *testing.T) {
+				sqldb := mockdb.NewMockDB(mockCtrl)
+				result := mockdb.NewMockResult(mockCtrl)
+
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke-by-id", "update oauth_access_tokens set revoked_at = now() where id = ? and revoked_at is null", 1).Return(result, nil)
+				result.EXPECT().RowsAffected().Return(int64(1), nil)
+
+				err := repository.NewOauthAccessToken(hasher).RevokeByID(kontext.Fabricate(), 1, sqldb)
+				assert.Nil(t, err)
+			})
+
+			t.Run("When database operation complete but there is no updated rows it will return error", func(t *testing.T) {
+				sqldb := mockdb.NewMockDB(mockCtrl)
+				result := mockdb.NewMockResult(mockCtrl)
+
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke-by-id", "update oauth_access_tokens set revoked_at = now() where id = ? and revoked_at is null", 1).Return(result, nil)
+				result.EXPECT().RowsAffected().Return(int64(0), nil)
+
+				err := repository.NewOauthAccessToken(hasher).RevokeByID(kontext.Fabricate(), 1, sqldb)
+				assert.NotNil(t, err)
+				assert.Equal(t, exception.NotFound, err.Type())
+			})
+
+			t.Run("When database operation failed it will return error", func(t *testing.T) {
+				sqldb := mockdb.NewMockDB(mockCtrl)
+
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-access-token-revoke-by-id", "update oauth_access_tokens set revoked_at = now() where id = ? and revoked_at is null", 1).Return(nil, exception.Throw(errors.New("unexpected error")))
+
+				err := repository.NewOauthAccessToken(hasher).RevokeByID(kontext.Fabricate(), 1, sqldb)
+				assert.NotNil(t, err)
+			})
+		})
+	})
 }
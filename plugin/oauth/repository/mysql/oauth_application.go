@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/kodefluence/monorepo/db"
@@ -13,11 +14,12 @@ import (
 
 // OauthApplication handle all database operation of `oauth_applications` table
 type OauthApplication struct {
+	hasher *Hasher
 }
 
-// NewOauthApplication create new OauthApplication struct with db.DB
-func NewOauthApplication() *OauthApplication {
-	return &OauthApplication{}
+// NewOauthApplication create new OauthApplication struct, client secrets are stored as keyed hash of the hasher
+func NewOauthApplication(hasher *Hasher) *OauthApplication {
+	return &OauthApplication{hasher: hasher}
 }
 
 // Paginate oauth_applications data
@@ -30,7 +32,7 @@ func (*OauthApplication) Paginate(ktx kontext.Context, offset, limit int, tx db.
 	rows, err := tx.QueryContext(
 		kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout)),
 		"oauth-application-paginate",
-		"select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at from oauth_applications limit ?, ?",
+		"select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications limit ?, ?",
 		offset,
 		limit,
 	)
@@ -46,6 +48,7 @@ func (*OauthApplication) Paginate(ktx kontext.Context, offset, limit int, tx db.
 			&OauthApplication.ID, &OauthApplication.OwnerID, &OauthApplication.OwnerType, &OauthApplication.Description,
 			&OauthApplication.Scopes, &OauthApplication.ClientUID, &OauthApplication.ClientSecret,
 			&OauthApplication.RevokedAt, &OauthApplication.CreatedAt, &OauthApplication.UpdatedAt,
+			&OauthApplication.PKCERequired,
 		)
 		if err != nil {
 			return OauthApplications, err
@@ -86,13 +89,39 @@ func (*OauthApplication) One(ktx kontext.Context, ID int, tx db.TX) (entity.Oaut
 	row := tx.QueryRowContext(
 		kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout)),
 		"oauth-application-one",
-		"select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at from oauth_applications where id = ?",
+		"select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications where id = ?",
 		ID,
 	)
 	if err := row.Scan(
 		&data.ID, &data.OwnerID, &data.OwnerType, &data.Description,
 		&data.Scopes, &data.ClientUID, &data.ClientSecret,
 		&data.RevokedAt, &data.CreatedAt, &data.UpdatedAt,
+		&data.PKCERequired,
+	); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+// OneByUID get one oauth_applications by client uid, for public clients authenticated without client secret
+func (*OauthApplication) OneByUID(ktx kontext.Context, clientUID string, tx db.TX) (entity.OauthApplication, exception.Exception) {
+	var data entity.OauthApplication
+
+	ctxWithTimeout, cf := context.WithTimeout(ktx.Ctx(), time.Second*10)
+	defer cf()
+
+	row := tx.QueryRowContext(
+		kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout)),
+		"oauth-application-one-by-uid",
+		"select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications where client_uid = ? limit 1",
+		clientUID,
+	)
+	if err := row.Scan(
+		&data.ID, &data.OwnerID, &data.OwnerType, &data.Description,
+		&data.Scopes, &data.ClientUID, &data.ClientSecret,
+		&data.RevokedAt, &data.CreatedAt, &data.UpdatedAt,
+		&data.PKCERequired,
 	); err != nil {
 		return data, err
 	}
@@ -101,23 +130,24 @@ func (*OauthApplication) One(ktx kontext.Context, ID int, tx db.TX) (entity.Oaut
 }
 
 // OneByUIDandSecret get one oauth_applications by client uid and client secret
-func (*OauthApplication) OneByUIDandSecret(ktx kontext.Context, clientUID, clientSecret string, tx db.TX) (entity.OauthApplication, exception.Exception) {
+func (o *OauthApplication) OneByUIDandSecret(ktx kontext.Context, clientUID, clientSecret string, tx db.TX) (entity.OauthApplication, exception.Exception) {
 	var data entity.OauthApplication
 
 	ctxWithTimeout, cf := context.WithTimeout(ktx.Ctx(), time.Second*10)
 	defer cf()
 
+	condition, args := o.hasher.match("client_secret", "client_secret_hashed", clientSecret)
 	row := tx.QueryRowContext(
 		kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout)),
 		"oauth-application-one-by-id-and-secret",
-		"select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at from oauth_applications where client_uid = ? and client_secret = ? limit 1",
-		clientUID,
-		clientSecret,
+		fmt.Sprintf("select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications where client_uid = ? and %s limit 1", condition),
+		append([]interface{}{clientUID}, args...)...,
 	)
 	if err := row.Scan(
 		&data.ID, &data.OwnerID, &data.OwnerType, &data.Description,
 		&data.Scopes, &data.ClientUID, &data.ClientSecret,
 		&data.RevokedAt, &data.CreatedAt, &data.UpdatedAt,
+		&data.PKCERequired,
 	); err != nil {
 		return data, err
 	}
@@ -126,12 +156,12 @@ func (*OauthApplication) OneByUIDandSecret(ktx kontext.Context, clientUID, clien
 }
 
 // Create new oauth_applications data
-func (*OauthApplication) Create(ktx kontext.Context, data entity.OauthApplicationInsertable, tx db.TX) (int, exception.Exception) {
+func (o *OauthApplication) Create(ktx kontext.Context, data entity.OauthApplicationInsertable, tx db.TX) (int, exception.Exception) {
 	res, err := tx.ExecContext(
 		ktx,
 		"oauth-application-create",
-		"insert into oauth_applications (owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at) values(?, ?, ?, ?, ?, ?, null, now(), now())",
-		data.OwnerID, data.OwnerType, data.Description, data.Scopes, data.ClientUID, data.ClientSecret)
+		"insert into oauth_applications (owner_id, owner_type, description, scopes, client_uid, client_secret, client_secret_hashed, pkce_required, revoked_at, created_at, updated_at) values(?, ?, ?, ?, ?, ?, ?, ?, null, now(), now())",
+		data.OwnerID, data.OwnerType, data.Description, data.Scopes, data.ClientUID, o.hasher.Hash(data.ClientSecret), o.hasher.hashed(), data.PKCERequired)
 	if err != nil {
 		return 0, err
 	}
@@ -149,7 +179,12 @@ func (*OauthApplication) Update(ktx kontext.Context, ID int, data entity.OauthAp
 	_, err := tx.ExecContext(
 		kontext.Fabricate(),
 		"oauth-application-update",
-		"update oauth_applications set description = ?, scopes = ?, updated_at = now() where id = ?",
-		data.Description, data.Scopes, ID)
+		"update oauth_applications set description = ?, scopes = ?, pkce_required = coalesce(?, pkce_required), updated_at = now() where id = ?",
+		data.Description, data.Scopes, data.PKCERequired, ID)
 	return err
 }
+
+// Rehash replace plaintext client secrets stored before hashing with their keyed hash
+func (o *OauthApplication) Rehash(ktx kontext.Context, tx db.TX) (int, exception.Exception) {
+	return o.hasher.rehash(ktx, "oauth-application-rehash", "oauth_applications", "client_secret", "client_secret_hashed", tx)
+}
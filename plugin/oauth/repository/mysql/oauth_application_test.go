@@ -19,6 +19,8 @@ func TestOauthApplication(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
+	hasher := repository.NewHasher("pepper")
+
 	t.Run("Paginate", func(t *testing.T) {
 		t.Run("Given offset and limit", func(t *testing.T) {
 			t.Run("When database operation complete then it will return array of oauth applications data", func(t *testing.T) {
@@ -37,13 +39,14 @@ func TestOauthApplication(t *testing.T) {
 					},
 				}
 
-				sqldb.EXPECT().QueryContext(gomock.Any(), "oauth-application-paginate", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at from oauth_applications limit ?, ?", offset, limit).Return(rows, nil)
+				sqldb.EXPECT().QueryContext(gomock.Any(), "oauth-application-paginate", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications limit ?, ?", offset, limit).Return(rows, nil)
 				rows.EXPECT().Next().Return(true).Times(len(expectedOauthApplications))
 
 				rows.EXPECT().Scan(
 					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 					gomock.Any(), gomock.Any(), gomock.Any(),
 					gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(),
 				).DoAndReturn(func(dest ...interface{}) exception.Exception {
 					val, _ := dest[0].(*int)
 					*val = expectedOauthApplications[0].ID
@@ -54,6 +57,7 @@ func TestOauthApplication(t *testing.T) {
 					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 					gomock.Any(), gomock.Any(), gomock.Any(),
 					gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(),
 				).DoAndReturn(func(dest ...interface{}) exception.Exception {
 					val, _ := dest[0].(*int)
 					*val = expectedOauthApplications[1].ID
@@ -65,7 +69,7 @@ func TestOauthApplication(t *testing.T) {
 				rows.EXPECT().Err().Return(nil)
 				rows.EXPECT().Close()
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				oauthApplications, err := oauthApplicationModel.Paginate(kontext.Fabricate(), offset, limit, sqldb)
 
 				assert.Nil(t, err)
@@ -77,9 +81,9 @@ func TestOauthApplication(t *testing.T) {
 				limit := 10
 
 				sqldb := mockdb.NewMockDB(mockCtrl)
-				sqldb.EXPECT().QueryContext(gomock.Any(), "oauth-application-paginate", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at from oauth_applications limit ?, ?", offset, limit).Return(nil, exception.Throw(errors.New("unexpected")))
+				sqldb.EXPECT().QueryContext(gomock.Any(), "oauth-application-paginate", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications limit ?, ?", offset, limit).Return(nil, exception.Throw(errors.New("unexpected")))
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				oauthApplications, err := oauthApplicationModel.Paginate(kontext.Fabricate(), offset, limit, sqldb)
 
 				assert.NotNil(t, err)
@@ -103,13 +107,14 @@ func TestOauthApplication(t *testing.T) {
 					},
 				}
 
-				sqldb.EXPECT().QueryContext(gomock.Any(), "oauth-application-paginate", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at from oauth_applications limit ?, ?", offset, limit).Return(rows, nil)
+				sqldb.EXPECT().QueryContext(gomock.Any(), "oauth-application-paginate", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications limit ?, ?", offset, limit).Return(rows, nil)
 				rows.EXPECT().Next().Return(true).Times(len(expectedOauthApplications))
 
 				rows.EXPECT().Scan(
 					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 					gomock.Any(), gomock.Any(), gomock.Any(),
 					gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(),
 				).DoAndReturn(func(dest ...interface{}) exception.Exception {
 					val, _ := dest[0].(*int)
 					*val = expectedOauthApplications[0].ID
@@ -120,13 +125,14 @@ func TestOauthApplication(t *testing.T) {
 					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 					gomock.Any(), gomock.Any(), gomock.Any(),
 					gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(),
 				).DoAndReturn(func(dest ...interface{}) exception.Exception {
 					return exception.Throw(errors.New("unexpected"))
 				})
 
 				rows.EXPECT().Close()
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				oauthApplications, err := oauthApplicationModel.Paginate(kontext.Fabricate(), offset, limit, sqldb)
 
 				assert.NotNil(t, err)
@@ -150,7 +156,7 @@ func TestOauthApplication(t *testing.T) {
 				return nil
 			})
 
-			oauthApplicationModel := repository.NewOauthApplication()
+			oauthApplicationModel := repository.NewOauthApplication(hasher)
 			total, err := oauthApplicationModel.Count(kontext.Fabricate(), sqldb)
 
 			assert.Nil(t, err)
@@ -168,7 +174,7 @@ func TestOauthApplication(t *testing.T) {
 				return exception.Throw(errors.New("unexpected error"))
 			})
 
-			oauthApplicationModel := repository.NewOauthApplication()
+			oauthApplicationModel := repository.NewOauthApplication(hasher)
 			total, err := oauthApplicationModel.Count(kontext.Fabricate(), sqldb)
 
 			assert.NotNil(t, err)
@@ -188,7 +194,7 @@ func TestOauthApplication(t *testing.T) {
 					OwnerType: "confidential",
 				}
 
-				sqldb.EXPECT().QueryRowContext(gomock.Any(), "oauth-application-one", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at from oauth_applications where id = ?", expectedData.ID).Return(row)
+				sqldb.EXPECT().QueryRowContext(gomock.Any(), "oauth-application-one", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications where id = ?", expectedData.ID).Return(row)
 				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
 					val0, _ := dest[0].(*int)
 					*val0 = expectedData.ID
@@ -197,7 +203,7 @@ func TestOauthApplication(t *testing.T) {
 					return nil
 				})
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				data, err := oauthApplicationModel.One(kontext.Fabricate(), expectedData.ID, sqldb)
 
 				assert.Nil(t, err)
@@ -210,12 +216,12 @@ func TestOauthApplication(t *testing.T) {
 
 				expectedData := entity.OauthApplication{}
 
-				sqldb.EXPECT().QueryRowContext(gomock.Any(), "oauth-application-one", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at from oauth_applications where id = ?", expectedData.ID).Return(row)
+				sqldb.EXPECT().QueryRowContext(gomock.Any(), "oauth-application-one", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications where id = ?", expectedData.ID).Return(row)
 				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
 					return exception.Throw(errors.New("unexpected error"))
 				})
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				data, err := oauthApplicationModel.One(kontext.Fabricate(), expectedData.ID, sqldb)
 
 				assert.NotNil(t, err)
@@ -238,7 +244,7 @@ func TestOauthApplication(t *testing.T) {
 					ClientSecret: "secret",
 				}
 
-				sqldb.EXPECT().QueryRowContext(gomock.Any(), "oauth-application-one-by-id-and-secret", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at from oauth_applications where client_uid = ? and client_secret = ? limit 1", expectedData.ClientUID, expectedData.ClientSecret).Return(row)
+				sqldb.EXPECT().QueryRowContext(gomock.Any(), "oauth-application-one-by-id-and-secret", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications where client_uid = ? and ((client_secret = ? and client_secret_hashed = 1) or (client_secret = ? and client_secret_hashed = 0)) limit 1", expectedData.ClientUID, hasher.Hash(expectedData.ClientSecret), expectedData.ClientSecret).Return(row)
 				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
 					val0, _ := dest[0].(*int)
 					*val0 = expectedData.ID
@@ -251,7 +257,7 @@ func TestOauthApplication(t *testing.T) {
 					return nil
 				})
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				data, err := oauthApplicationModel.OneByUIDandSecret(kontext.Fabricate(), expectedData.ClientUID, expectedData.ClientSecret, sqldb)
 
 				assert.Nil(t, err)
@@ -264,12 +270,12 @@ func TestOauthApplication(t *testing.T) {
 
 				expectedData := entity.OauthApplication{}
 
-				sqldb.EXPECT().QueryRowContext(gomock.Any(), "oauth-application-one-by-id-and-secret", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at from oauth_applications where client_uid = ? and client_secret = ? limit 1", "uid", "secret").Return(row)
+				sqldb.EXPECT().QueryRowContext(gomock.Any(), "oauth-application-one-by-id-and-secret", "select id, owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at, pkce_required from oauth_applications where client_uid = ? and ((client_secret = ? and client_secret_hashed = 1) or (client_secret = ? and client_secret_hashed = 0)) limit 1", "uid", hasher.Hash("secret"), "secret").Return(row)
 				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
 					return exception.Throw(errors.New("unexpected error"))
 				})
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				data, err := oauthApplicationModel.OneByUIDandSecret(kontext.Fabricate(), "uid", "secret", sqldb)
 
 				assert.NotNil(t, err)
@@ -295,18 +301,20 @@ func TestOauthApplication(t *testing.T) {
 					ClientSecret: "client-secret",
 				}
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-application-create", "insert into oauth_applications (owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at) values(?, ?, ?, ?, ?, ?, null, now(), now())",
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-application-create", "insert into oauth_applications (owner_id, owner_type, description, scopes, client_uid, client_secret, client_secret_hashed, pkce_required, revoked_at, created_at, updated_at) values(?, ?, ?, ?, ?, ?, ?, ?, null, now(), now())",
 					insertable.OwnerID,
 					insertable.OwnerType,
 					insertable.Description,
 					insertable.Scopes,
 					insertable.ClientUID,
-					insertable.ClientSecret,
+					hasher.Hash(insertable.ClientSecret),
+					1,
+					insertable.PKCERequired,
 				).Return(result, nil)
 
 				result.EXPECT().LastInsertId().Return(int64(expectedID), nil)
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				ID, err := oauthApplicationModel.Create(kontext.Fabricate(), insertable, sqldb)
 
 				assert.Equal(t, expectedID, ID)
@@ -325,16 +333,18 @@ func TestOauthApplication(t *testing.T) {
 					ClientSecret: "client-secret",
 				}
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-application-create", "insert into oauth_applications (owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at) values(?, ?, ?, ?, ?, ?, null, now(), now())",
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-application-create", "insert into oauth_applications (owner_id, owner_type, description, scopes, client_uid, client_secret, client_secret_hashed, pkce_required, revoked_at, created_at, updated_at) values(?, ?, ?, ?, ?, ?, ?, ?, null, now(), now())",
 					insertable.OwnerID,
 					insertable.OwnerType,
 					insertable.Description,
 					insertable.Scopes,
 					insertable.ClientUID,
-					insertable.ClientSecret,
+					hasher.Hash(insertable.ClientSecret),
+					1,
+					insertable.PKCERequired,
 				).Return(nil, exception.Throw(errors.New("unexpected")))
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				_, err := oauthApplicationModel.Create(kontext.Fabricate(), insertable, sqldb)
 
 				assert.Equal(t, exception.Unexpected, err.Type())
@@ -354,18 +364,20 @@ func TestOauthApplication(t *testing.T) {
 					ClientSecret: "client-secret",
 				}
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-application-create", "insert into oauth_applications (owner_id, owner_type, description, scopes, client_uid, client_secret, revoked_at, created_at, updated_at) values(?, ?, ?, ?, ?, ?, null, now(), now())",
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-application-create", "insert into oauth_applications (owner_id, owner_type, description, scopes, client_uid, client_secret, client_secret_hashed, pkce_required, revoked_at, created_at, updated_at) values(?, ?, ?, ?, ?, ?, ?, ?, null, now(), now())",
 					insertable.OwnerID,
 					insertable.OwnerType,
 					insertable.Description,
 					insertable.Scopes,
 					insertable.ClientUID,
-					insertable.ClientSecret,
+					hasher.Hash(insertable.ClientSecret),
+					1,
+					insertable.PKCERequired,
 				).Return(result, nil)
 
 				result.EXPECT().LastInsertId().Return(int64(0), exception.Throw(errors.New("unexpected error")))
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				ID, err := oauthApplicationModel.Create(kontext.Fabricate(), insertable, sqldb)
 
 				assert.Equal(t, 0, ID)
@@ -387,9 +399,9 @@ func TestOauthApplication(t *testing.T) {
 					Scopes:      "public user",
 				}
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-application-update", "update oauth_applications set description = ?, scopes = ?, updated_at = now() where id = ?", data.Description, data.Scopes, ID).Return(result, nil)
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-application-update", "update oauth_applications set description = ?, scopes = ?, pkce_required = coalesce(?, pkce_required), updated_at = now() where id = ?", data.Description, data.Scopes, data.PKCERequired, ID).Return(result, nil)
 
-				oauthApplicationModel := repository.NewOauthApplication()
+				oauthApplicationModel := repository.NewOauthApplication(hasher)
 				err := oauthApplicationModel.Update(kontext.Fabricate(), ID, data, sqldb)
 				assert.Nil(t, err)
 			})
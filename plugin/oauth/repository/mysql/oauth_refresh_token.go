@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/kodefluence/monorepo/db"
@@ -13,25 +14,28 @@ import (
 )
 
 // OauthRefreshToken is a connector to oauth_refresh_tokens table
-type OauthRefreshToken struct{}
+type OauthRefreshToken struct {
+	hasher *Hasher
+}
 
-// NewOauthRefreshToken create new OauthRefreshToken struct
-func NewOauthRefreshToken() *OauthRefreshToken {
-	return &OauthRefreshToken{}
+// NewOauthRefreshToken create new OauthRefreshToken struct, tokens are stored as keyed hash of the hasher
+func NewOauthRefreshToken(hasher *Hasher) *OauthRefreshToken {
+	return &OauthRefreshToken{hasher: hasher}
 }
 
-// OneByToken selecting one oauth refresh token data based on given token data
-func (*OauthRefreshToken) OneByToken(ktx kontext.Context, token string, tx db.TX) (entity.OauthRefreshToken, exception.Exception) {
+// OneByToken selecting one oauth refresh token data based on given token data, the returned token is the stored value
+func (o *OauthRefreshToken) OneByToken(ktx kontext.Context, token string, tx db.TX) (entity.OauthRefreshToken, exception.Exception) {
 	var oauthRefreshToken entity.OauthRefreshToken
 
 	ctxWithTimeout, cf := context.WithTimeout(ktx.Ctx(), time.Second*10)
 	defer cf()
 
+	condition, args := o.hasher.match("token", "token_hashed", token)
 	row := tx.QueryRowContext(
 		kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout)),
 		"oauth-refresh-token-one-by-token",
-		"select id, oauth_access_token_id, token, expires_in, created_at, revoked_at from oauth_refresh_tokens where token = ? and revoked_at is null limit 1",
-		token,
+		fmt.Sprintf("select id, oauth_access_token_id, token, expires_in, created_at, revoked_at from oauth_refresh_tokens where %s and revoked_at is null limit 1", condition),
+		args...,
 	)
 	err := row.Scan(
 		&oauthRefreshToken.ID,
@@ -71,12 +75,12 @@ func (*OauthRefreshToken) One(ktx kontext.Context, ID int, tx db.TX) (entity.Oau
 }
 
 // Create new oauth refresh token based on oauth refresh token insertable
-func (*OauthRefreshToken) Create(ktx kontext.Context, data entity.OauthRefreshTokenInsertable, tx db.TX) (int, exception.Exception) {
+func (o *OauthRefreshToken) Create(ktx kontext.Context, data entity.OauthRefreshTokenInsertable, tx db.TX) (int, exception.Exception) {
 	result, err := tx.ExecContext(
 		ktx,
 		"oauth-refresh-token-create",
-		"insert into oauth_refresh_tokens (oauth_access_token_id, token, expires_in, created_at, revoked_at) values(?, ?, ?, now(), null)",
-		data.OauthAccessTokenID, data.Token, data.ExpiresIn)
+		"insert into oauth_refresh_tokens (oauth_access_token_id, token, token_hashed, expires_in, created_at, revoked_at) values(?, ?, ?, ?, now(), null)",
+		data.OauthAccessTokenID, o.hasher.Hash(data.Token), o.hasher.hashed(), data.ExpiresIn)
 	if err != nil {
 		return 0, err
 	}
@@ -90,8 +94,9 @@ func (*OauthRefreshToken) Create(ktx kontext.Context, data entity.OauthRefreshTo
 }
 
 // Revoke given token
-func (*OauthRefreshToken) Revoke(ktx kontext.Context, token string, tx db.TX) exception.Exception {
-	result, err := tx.ExecContext(ktx, "oauth-refresh-token-revoke", "update oauth_refresh_tokens set revoked_at = now() where token = ?", token)
+func (o *OauthRefreshToken) Revoke(ktx kontext.Context, token string, tx db.TX) exception.Exception {
+	condition, args := o.hasher.match("token", "token_hashed", token)
+	result, err := tx.ExecContext(ktx, "oauth-refresh-token-revoke", fmt.Sprintf("update oauth_refresh_tokens set revoked_at = now() where %s", condition), args...)
 	if err != nil {
 		return err
 	}
@@ -107,3 +112,8 @@ func (*OauthRefreshToken) Revoke(ktx kontext.Context, token string, tx db.TX) ex
 
 	return nil
 }
+
+// Rehash replace plaintext tokens stored before hashing with their keyed hash
+func (o *OauthRefreshToken) Rehash(ktx kontext.Context, tx db.TX) (int, exception.Exception) {
+	return o.hasher.rehash(ktx, "oauth-refresh-token-rehash", "oauth_refresh_tokens", "token", "token_hashed", tx)
+}
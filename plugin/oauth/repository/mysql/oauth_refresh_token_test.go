@@ -19,6 +19,8 @@ func TestOauthRefreshToken(t *testing.T) {
 	mockCtrl := gomock.NewController(t)
 	defer mockCtrl.Finish()
 
+	hasher := repository.NewHasher("pepper")
+
 	t.Run("one", func(t *testing.T) {
 		t.Run("Given oauth refresh token", func(t *testing.T) {
 			t.Run("When database operation complete it will return oauth refresh token data", func(t *testing.T) {
@@ -40,7 +42,7 @@ func TestOauthRefreshToken(t *testing.T) {
 					return nil
 				})
 
-				oauthRefreshTokenModel := repository.NewOauthRefreshToken()
+				oauthRefreshTokenModel := repository.NewOauthRefreshToken(hasher)
 				data, err := oauthRefreshTokenModel.One(kontext.Fabricate(), expectedData.ID, sqldb)
 
 				assert.Nil(t, err)
@@ -61,7 +63,7 @@ func TestOauthRefreshToken(t *testing.T) {
 					Token: "token",
 				}
 
-				sqldb.EXPECT().QueryRowContext(gomock.Any(), "oauth-refresh-token-one-by-token", "select id, oauth_access_token_id, token, expires_in, created_at, revoked_at from oauth_refresh_tokens where token = ? and revoked_at is null limit 1", expectedData.Token).Return(row)
+				sqldb.EXPECT().QueryRowContext(gomock.Any(), "oauth-refresh-token-one-by-token", "select id, oauth_access_token_id, token, expires_in, created_at, revoked_at from oauth_refresh_tokens where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0)) and revoked_at is null limit 1", hasher.Hash(expectedData.Token), expectedData.Token).Return(row)
 				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
 					val0, _ := dest[0].(*int)
 					*val0 = expectedData.ID
@@ -71,7 +73,7 @@ func TestOauthRefreshToken(t *testing.T) {
 					return nil
 				})
 
-				oauthRefreshTokenModel := repository.NewOauthRefreshToken()
+				oauthRefreshTokenModel := repository.NewOauthRefreshToken(hasher)
 				data, err := oauthRefreshTokenModel.OneByToken(kontext.Fabricate(), expectedData.Token, sqldb)
 
 				assert.Nil(t, err)
@@ -93,13 +95,13 @@ func TestOauthRefreshToken(t *testing.T) {
 					ExpiresIn: time.Now().Add(time.Hour * 24),
 				}
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-create", "insert into oauth_refresh_tokens (oauth_access_token_id, token, expires_in, created_at, revoked_at) values(?, ?, ?, now(), null)",
-					insertable.OauthAccessTokenID, insertable.Token, insertable.ExpiresIn,
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-create", "insert into oauth_refresh_tokens (oauth_access_token_id, token, token_hashed, expires_in, created_at, revoked_at) values(?, ?, ?, ?, now(), null)",
+					insertable.OauthAccessTokenID, hasher.Hash(insertable.Token), 1, insertable.ExpiresIn,
 				).Return(result, nil)
 
 				result.EXPECT().LastInsertId().Return(int64(expectedID), nil)
 
-				oauthRefreshTokenModel := repository.NewOauthRefreshToken()
+				oauthRefreshTokenModel := repository.NewOauthRefreshToken(hasher)
 				ID, err := oauthRefreshTokenModel.Create(kontext.Fabricate(), insertable, sqldb)
 
 				assert.Equal(t, expectedID, ID)
@@ -114,11 +116,11 @@ func TestOauthRefreshToken(t *testing.T) {
 					ExpiresIn: time.Now().Add(time.Hour * 24),
 				}
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-create", "insert into oauth_refresh_tokens (oauth_access_token_id, token, expires_in, created_at, revoked_at) values(?, ?, ?, now(), null)",
-					insertable.OauthAccessTokenID, insertable.Token, insertable.ExpiresIn,
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-create", "insert into oauth_refresh_tokens (oauth_access_token_id, token, token_hashed, expires_in, created_at, revoked_at) values(?, ?, ?, ?, now(), null)",
+					insertable.OauthAccessTokenID, hasher.Hash(insertable.Token), 1, insertable.ExpiresIn,
 				).Return(nil, exception.Throw(errors.New("unexpected")))
 
-				oauthRefreshTokenModel := repository.NewOauthRefreshToken()
+				oauthRefreshTokenModel := repository.NewOauthRefreshToken(hasher)
 				_, err := oauthRefreshTokenModel.Create(kontext.Fabricate(), insertable, sqldb)
 
 				assert.Equal(t, exception.Unexpected, err.Type())
@@ -134,13 +136,13 @@ func TestOauthRefreshToken(t *testing.T) {
 					ExpiresIn: time.Now().Add(time.Hour * 24),
 				}
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-create", "insert into oauth_refresh_tokens (oauth_access_token_id, token, expires_in, created_at, revoked_at) values(?, ?, ?, now(), null)",
-					insertable.OauthAccessTokenID, insertable.Token, insertable.ExpiresIn,
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-create", "insert into oauth_refresh_tokens (oauth_access_token_id, token, token_hashed, expires_in, created_at, revoked_at) values(?, ?, ?, ?, now(), null)",
+					insertable.OauthAccessTokenID, hasher.Hash(insertable.Token), 1, insertable.ExpiresIn,
 				).Return(result, nil)
 
 				result.EXPECT().LastInsertId().Return(int64(0), exception.Throw(errors.New("unexpected error")))
 
-				oauthRefreshTokenModel := repository.NewOauthRefreshToken()
+				oauthRefreshTokenModel := repository.NewOauthRefreshToken(hasher)
 				ID, err := oauthRefreshTokenModel.Create(kontext.Fabricate(), insertable, sqldb)
 
 				assert.Equal(t, 0, ID)
@@ -158,10 +160,10 @@ func TestOauthRefreshToken(t *testing.T) {
 
 				token := "token"
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-revoke", "update oauth_refresh_tokens set revoked_at = now() where token = ?", token).Return(result, nil)
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-revoke", "update oauth_refresh_tokens set revoked_at = now() where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0))", hasher.Hash(token), token).Return(result, nil)
 				result.EXPECT().RowsAffected().Return(int64(1), nil)
 
-				oauthRefreshTokenModel := repository.NewOauthRefreshToken()
+				oauthRefreshTokenModel := repository.NewOauthRefreshToken(hasher)
 				err := oauthRefreshTokenModel.Revoke(kontext.Fabricate(), token, sqldb)
 				assert.Nil(t, err)
 			})
@@ -172,10 +174,10 @@ func TestOauthRefreshToken(t *testing.T) {
 
 				token := "token"
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-revoke", "update oauth_refresh_tokens set revoked_at = now() where token = ?", token).Return(result, nil)
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-revoke", "update oauth_refresh_tokens set revoked_at = now() where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0))", hasher.Hash(token), token).Return(result, nil)
 				result.EXPECT().RowsAffected().Return(int64(0), nil)
 
-				oauthRefreshTokenModel := repository.NewOauthRefreshToken()
+				oauthRefreshTokenModel := repository.NewOauthRefreshToken(hasher)
 				err := oauthRefreshTokenModel.Revoke(kontext.Fabricate(), token, sqldb)
 				assert.NotNil(t, err)
 				assert.Equal(t, exception.NotFound, err.Type())
@@ -185,9 +187,9 @@ func TestOauthRefreshToken(t *testing.T) {
 				sqldb := mockdb.NewMockDB(mockCtrl)
 				token := "token"
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-revoke", "update oauth_refresh_tokens set revoked_at = now() where token = ?", token).Return(nil, exception.Throw(errors.New("unexpected error")))
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-revoke", "update oauth_refresh_tokens set revoked_at = now() where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0))", hasher.Hash(token), token).Return(nil, exception.Throw(errors.New("unexpected error")))
 
-				oauthRefreshTokenModel := repository.NewOauthRefreshToken()
+				oauthRefreshTokenModel := repository.NewOauthRefreshToken(hasher)
 				err := oauthRefreshTokenModel.Revoke(kontext.Fabricate(), token, sqldb)
 				assert.NotNil(t, err)
 			})
@@ -198,10 +200,10 @@ func TestOauthRefreshToken(t *testing.T) {
 
 				token := "token"
 
-				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-revoke", "update oauth_refresh_tokens set revoked_at = now() where token = ?", token).Return(result, nil)
+				sqldb.EXPECT().ExecContext(gomock.Any(), "oauth-refresh-token-revoke", "update oauth_refresh_tokens set revoked_at = now() where ((token = ? and token_hashed = 1) or (token = ? and token_hashed = 0))", hasher.Hash(token), token).Return(result, nil)
 				result.EXPECT().RowsAffected().Return(int64(0), exception.Throw(errors.New("unexpected error")))
 
-				oauthRefreshTokenModel := repository.NewOauthRefreshToken()
+				oauthRefreshTokenModel := repository.NewOauthRefreshToken(hasher)
 				err := oauthRefreshTokenModel.Revoke(kontext.Fabricate(), token, sqldb)
 				assert.NotNil(t, err)
 			})
@@ -0,0 +1,58 @@
+package upstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kodefluence/monorepo/kontext"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+)
+
+// Identity fetch userinfo claims from the identity service behind altair
+type Identity struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewIdentity create new identity upstream calling endpoint
+func NewIdentity(endpoint string, timeout time.Duration) *Identity {
+	return &Identity{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: timeout},
+	}
+}
+
+// Claims request the claims of the access token's resource owner. The access
+// token is identified with the same headers a proxied oauth route receives.
+func (i *Identity) Claims(ktx kontext.Context, accessToken entity.OauthAccessToken) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ktx.Ctx(), http.MethodGet, i.endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Resource-Owner-ID", strconv.Itoa(accessToken.ResourceOwnerID))
+	req.Header.Set("Oauth-Application-ID", strconv.Itoa(accessToken.OauthApplicationID))
+	req.Header.Set("Scope", accessToken.Scopes.String)
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("identity upstream responded with status %d", resp.StatusCode)
+	}
+
+	var claims map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return nil, fmt.Errorf("identity upstream responded with invalid claims: %v", err)
+	}
+
+	return claims, nil
+}
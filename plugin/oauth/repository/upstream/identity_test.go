@@ -0,0 +1,69 @@
+package upstream_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/oauth/entity"
+	"github.com/kodefluence/altair/plugin/oauth/repository/upstream"
+)
+
+func TestIdentity(t *testing.T) {
+	accessToken := entity.OauthAccessToken{
+		OauthApplicationID: 2,
+		ResourceOwnerID:    1,
+		Scopes:             sql.NullString{String: "openid profile", Valid: true},
+	}
+
+	t.Run("Claims", func(t *testing.T) {
+		t.Run("Given identity upstream responding claims, then it return the claims", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "1", r.Header.Get("Resource-Owner-ID"))
+				assert.Equal(t, "2", r.Header.Get("Oauth-Application-ID"))
+				assert.Equal(t, "openid profile", r.Header.Get("Scope"))
+				_, _ = w.Write([]byte(`{"name":"Jane","email_verified":true}`))
+			}))
+			defer server.Close()
+
+			claims, err := upstream.NewIdentity(server.URL, time.Second).Claims(kontext.Fabricate(), accessToken)
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]interface{}{"name": "Jane", "email_verified": true}, claims)
+		})
+
+		t.Run("Given identity upstream responding non 200 status, then it return error", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer server.Close()
+
+			_, err := upstream.NewIdentity(server.URL, time.Second).Claims(kontext.Fabricate(), accessToken)
+			assert.NotNil(t, err)
+		})
+
+		t.Run("Given identity upstream responding invalid json, then it return error", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`[]`))
+			}))
+			defer server.Close()
+
+			_, err := upstream.NewIdentity(server.URL, time.Second).Claims(kontext.Fabricate(), accessToken)
+			assert.NotNil(t, err)
+		})
+
+		t.Run("Given identity upstream slower than the timeout, then it return error", func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(time.Millisecond * 100)
+			}))
+			defer server.Close()
+
+			_, err := upstream.NewIdentity(server.URL, time.Millisecond*10).Claims(kontext.Fabricate(), accessToken)
+			assert.NotNil(t, err)
+		})
+	})
+}
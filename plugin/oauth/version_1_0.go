@@ -8,8 +8,13 @@ import (
 	"github.com/kodefluence/altair/plugin/oauth/entity"
 	"github.com/kodefluence/altair/plugin/oauth/module/application"
 	"github.com/kodefluence/altair/plugin/oauth/module/authorization"
+	"github.com/kodefluence/altair/plugin/oauth/module/authorization/usecase"
+	"github.com/kodefluence/altair/plugin/oauth/module/credential"
 	"github.com/kodefluence/altair/plugin/oauth/module/formatter"
+	"github.com/kodefluence/altair/plugin/oauth/module/keyset"
+	"github.com/kodefluence/altair/plugin/oauth/module/openid"
 	"github.com/kodefluence/altair/plugin/oauth/repository/mysql"
+	"github.com/kodefluence/altair/plugin/oauth/repository/upstream"
 )
 
 // errMissingDecodeConfig and errMissingDatabase guard against PluginContext
@@ -33,6 +38,10 @@ func loadV1_0(ctx module.PluginContext) error {
 		return err
 	}
 
+	if err := oauthPluginConfig.ValidateTokenHashPepper(); err != nil {
+		return err
+	}
+
 	sqldb, _, err := ctx.Database(oauthPluginConfig.DatabaseInstance())
 	if err != nil {
 		return err
@@ -61,15 +70,48 @@ func loadV1_0(ctx module.PluginContext) error {
 		refreshTokenConfig.Timeout = refreshTokenTimeout
 	}
 
-	oauthApplicationRepo := mysql.NewOauthApplication()
-	oauthAccessTokenRepo := mysql.NewOauthAccessToken()
+	hasher := mysql.NewHasher(oauthPluginConfig.Config.TokenHashPepper)
+	oauthApplicationRepo := mysql.NewOauthApplication(hasher)
+	oauthAccessTokenRepo := mysql.NewOauthAccessToken(hasher)
 	oauthAccessGrantRepo := mysql.NewOauthAccessGrant()
-	oauthRefreshTokenRepo := mysql.NewOauthRefreshToken()
+	oauthRefreshTokenRepo := mysql.NewOauthRefreshToken(hasher)
 
 	formatter := formatter.Provide(accessTokenTimeout, authorizationCodeTimeout, refreshTokenConfig.Timeout)
 
+	keySet, err := keyset.Provide(oauthPluginConfig)
+	if err != nil {
+		return err
+	}
+
+	// The signers stay nil interfaces when unused, a nil *KeySet would not.
+	var tokenSigner usecase.TokenSigner
+	var idTokenSigner usecase.IDTokenSigner
+	if keySet != nil {
+		if oauthPluginConfig.TokenFormat() == entity.TokenFormatJWT {
+			tokenSigner = keySet
+		}
+
+		if oauthPluginConfig.Config.OpenID.Active {
+			idTokenSigner = keySet
+		}
+
+		if ctx.PublicModule != nil {
+			keyset.Load(ctx.PublicModule, keySet)
+		}
+	}
+
 	application.Load(ctx.AppModule, sqldb, oauthApplicationRepo, formatter, ctx.ApiError)
-	authorization.Load(ctx.AppModule, oauthApplicationRepo, oauthAccessTokenRepo, oauthAccessGrantRepo, oauthRefreshTokenRepo, formatter, oauthPluginConfig, sqldb, ctx.ApiError)
+	authorization.Load(ctx.AppModule, oauthApplicationRepo, oauthAccessTokenRepo, oauthAccessGrantRepo, oauthRefreshTokenRepo, formatter, oauthPluginConfig, sqldb, ctx.ApiError, tokenSigner, idTokenSigner)
+
+	if idTokenSigner != nil && ctx.PublicModule != nil {
+		userinfoTimeout, err := oauthPluginConfig.UserinfoTimeout()
+		if err != nil {
+			return err
+		}
+
+		identityUpstream := upstream.NewIdentity(oauthPluginConfig.Config.OpenID.UserinfoUpstream, userinfoTimeout)
+		openid.Load(ctx.PublicModule, oauthAccessTokenRepo, tokenSigner, identityUpstream, oauthPluginConfig, keySet.SigningAlgorithm(), sqldb, ctx.ApiError)
+	}
 
 	return nil
 }
@@ -86,6 +128,10 @@ func loadCommandV1_0(ctx module.PluginContext) error {
 		return err
 	}
 
+	if err := oauthPluginConfig.ValidateTokenHashPepper(); err != nil {
+		return err
+	}
+
 	sqldb, _, err := ctx.Database(oauthPluginConfig.DatabaseInstance())
 	if err != nil {
 		return err
@@ -111,10 +157,14 @@ func loadCommandV1_0(ctx module.PluginContext) error {
 		refreshTokenConfig.Timeout = refreshTokenTimeout
 	}
 
-	oauthApplicationRepo := mysql.NewOauthApplication()
+	hasher := mysql.NewHasher(oauthPluginConfig.Config.TokenHashPepper)
+	oauthApplicationRepo := mysql.NewOauthApplication(hasher)
+	oauthAccessTokenRepo := mysql.NewOauthAccessToken(hasher)
+	oauthRefreshTokenRepo := mysql.NewOauthRefreshToken(hasher)
 	formatter := formatter.Provide(accessTokenTimeout, authorizationCodeTimeout, refreshTokenConfig.Timeout)
 
 	application.LoadCommand(ctx.AppModule, sqldb, oauthApplicationRepo, formatter, ctx.ApiError)
+	credential.LoadCommand(ctx.AppModule, sqldb, oauthApplicationRepo, oauthAccessTokenRepo, oauthRefreshTokenRepo, ctx.ApiError)
 
 	return nil
 }
@@ -0,0 +1,273 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+)
+
+const (
+	KeyClientIP           = "client_ip"
+	KeyResourceOwnerID    = "resource_owner_id"
+	KeyOauthApplicationID = "oauth_application_id"
+	KeyHeaderPrefix       = "header:"
+
+	AlgorithmTokenBucket   = "token_bucket"
+	AlgorithmSlidingWindow = "sliding_window"
+
+	StoreMemory = "memory"
+	StoreMySQL  = "mysql"
+	StoreRedis  = "redis"
+
+	defaultPeriod         = time.Minute
+	defaultRedisTimeout   = time.Millisecond * 100
+	defaultRedisKeyPrefix = "altair:ratelimit:"
+)
+
+// RateLimitPlugin holds all config variables
+type RateLimitPlugin struct {
+	Config PluginConfig `yaml:"config"`
+}
+
+// PluginConfig holds the limit applied to every route without a rate_limit
+// block. A zero limit only limits the routes with a rate_limit block.
+type PluginConfig struct {
+	Key       string      `yaml:"key"`
+	Limit     int         `yaml:"limit"`
+	Period    string      `yaml:"period"`
+	Burst     int         `yaml:"burst"`
+	Algorithm string      `yaml:"algorithm"`
+	Store     StoreConfig `yaml:"store"`
+}
+
+// StoreConfig selects where the counters live. The memory store counts per
+// altair instance, mysql and redis share the counters across instances.
+// SyncInterval counts sliding windows locally and syncs them with the store
+// on that interval instead of on every request.
+type StoreConfig struct {
+	Type         string      `yaml:"type"`
+	Database     string      `yaml:"database"`
+	Redis        RedisConfig `yaml:"redis"`
+	SyncInterval string      `yaml:"sync_interval"`
+}
+
+type RedisConfig struct {
+	Address   string `yaml:"address"`
+	Password  string `yaml:"password"`
+	DB        int    `yaml:"db"`
+	KeyPrefix string `yaml:"key_prefix"`
+	Timeout   string `yaml:"timeout"`
+}
+
+// Policy limits a key to Limit requests every Period. With the token bucket
+// algorithm the bucket holds up to Burst tokens, the sliding window algorithm
+// weights the previous window by how much of it still overlaps the period.
+type Policy struct {
+	Key       string
+	Limit     int
+	Period    time.Duration
+	Burst     int
+	Algorithm string
+}
+
+// Result is the state of a bucket after a request tried to take a token.
+type Result struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
+
+// Policy returns the plugin wide policy.
+func (r RateLimitPlugin) Policy() (Policy, error) {
+	policy := Policy{Key: r.Config.Key, Limit: r.Config.Limit, Burst: r.Config.Burst, Algorithm: r.Config.Algorithm}
+
+	if r.Config.Period != "" {
+		period, err := time.ParseDuration(r.Config.Period)
+		if err != nil {
+			return Policy{}, err
+		}
+		policy.Period = period
+	}
+
+	if err := policy.validate(); err != nil {
+		return Policy{}, err
+	}
+
+	policy = policy.withDefaults()
+
+	if err := r.validateStore(policy); err != nil {
+		return Policy{}, err
+	}
+
+	return policy, nil
+}
+
+// StoreType returns the configured store, memory by default.
+func (r RateLimitPlugin) StoreType() string {
+	if r.Config.Store.Type == "" {
+		return StoreMemory
+	}
+
+	return r.Config.Store.Type
+}
+
+// DatabaseInstance returns the database.yml instance of the mysql store.
+func (r RateLimitPlugin) DatabaseInstance() string {
+	return r.Config.Store.Database
+}
+
+// SyncInterval returns how often local counters are synced, zero when every
+// request goes to the store.
+func (r RateLimitPlugin) SyncInterval() (time.Duration, error) {
+	if r.Config.Store.SyncInterval == "" {
+		return 0, nil
+	}
+
+	return time.ParseDuration(r.Config.Store.SyncInterval)
+}
+
+// RedisTimeout returns the deadline of one redis command.
+func (r RateLimitPlugin) RedisTimeout() (time.Duration, error) {
+	if r.Config.Store.Redis.Timeout == "" {
+		return defaultRedisTimeout, nil
+	}
+
+	return time.ParseDuration(r.Config.Store.Redis.Timeout)
+}
+
+// RedisKeyPrefix returns the prefix of every key written to redis.
+func (r RateLimitPlugin) RedisKeyPrefix() string {
+	if r.Config.Store.Redis.KeyPrefix == "" {
+		return defaultRedisKeyPrefix
+	}
+
+	return r.Config.Store.Redis.KeyPrefix
+}
+
+func (r RateLimitPlugin) validateStore(policy Policy) error {
+	syncInterval, err := r.SyncInterval()
+	if err != nil {
+		return fmt.Errorf("ratelimit store sync_interval is invalid: %v", err)
+	}
+
+	if syncInterval < 0 {
+		return fmt.Errorf("ratelimit store sync_interval cannot be negative")
+	}
+
+	if syncInterval > 0 && policy.Algorithm != AlgorithmSlidingWindow {
+		return fmt.Errorf("ratelimit store sync_interval requires the sliding_window algorithm")
+	}
+
+	switch r.StoreType() {
+	case StoreMemory:
+	case StoreMySQL:
+		if r.DatabaseInstance() == "" {
+			return fmt.Errorf("ratelimit mysql store requires store.database")
+		}
+
+		if policy.Algorithm != AlgorithmSlidingWindow {
+			return fmt.Errorf("ratelimit mysql store requires the sliding_window algorithm")
+		}
+	case StoreRedis:
+		if r.Config.Store.Redis.Address == "" {
+			return fmt.Errorf("ratelimit redis store requires store.redis.address")
+		}
+
+		if _, err := r.RedisTimeout(); err != nil {
+			return fmt.Errorf("ratelimit store redis.timeout is invalid: %v", err)
+		}
+	default:
+		return fmt.Errorf("ratelimit store `%s` is not supported, use memory, mysql or redis", r.Config.Store.Type)
+	}
+
+	return nil
+}
+
+// Override applies the rate_limit block of a router path over the policy. It
+// returns false when the request is not limited.
+func (p Policy) Override(rateLimit *coreEntity.RouteRateLimit) (Policy, bool) {
+	if rateLimit != nil {
+		if rateLimit.Disabled {
+			return Policy{}, false
+		}
+
+		if rateLimit.Key != "" {
+			p.Key = rateLimit.Key
+		}
+
+		if rateLimit.Limit > 0 {
+			p.Limit = rateLimit.Limit
+			// The plugin burst is sized for the plugin limit.
+			p.Burst = 0
+		}
+
+		if rateLimit.Burst > 0 {
+			p.Burst = rateLimit.Burst
+		}
+
+		if period, err := time.ParseDuration(rateLimit.Period); err == nil && period > 0 {
+			p.Period = period
+		}
+	}
+
+	p = p.withDefaults()
+
+	return p, p.Limit > 0
+}
+
+// String formats the policy as the RateLimit-Policy header value.
+func (p Policy) String() string {
+	if p.Algorithm == AlgorithmSlidingWindow {
+		return fmt.Sprintf("%d;w=%d", p.Limit, int(p.Period.Seconds()))
+	}
+
+	return fmt.Sprintf("%d;w=%d;burst=%d", p.Limit, int(p.Period.Seconds()), p.Burst)
+}
+
+func (p Policy) withDefaults() Policy {
+	if p.Key == "" {
+		p.Key = KeyClientIP
+	}
+
+	if p.Algorithm == "" {
+		p.Algorithm = AlgorithmTokenBucket
+	}
+
+	if p.Period <= 0 {
+		p.Period = defaultPeriod
+	}
+
+	if p.Burst <= 0 {
+		p.Burst = p.Limit
+	}
+
+	return p
+}
+
+func (p Policy) validate() error {
+	switch {
+	case p.Key == "", p.Key == KeyClientIP, p.Key == KeyResourceOwnerID, p.Key == KeyOauthApplicationID:
+	case strings.HasPrefix(p.Key, KeyHeaderPrefix) && strings.TrimSpace(strings.TrimPrefix(p.Key, KeyHeaderPrefix)) != "":
+	default:
+		return fmt.Errorf("ratelimit key `%s` is not supported, use client_ip, resource_owner_id, oauth_application_id or header:<name>", p.Key)
+	}
+
+	switch p.Algorithm {
+	case "", AlgorithmTokenBucket, AlgorithmSlidingWindow:
+	default:
+		return fmt.Errorf("ratelimit algorithm `%s` is not supported, use token_bucket or sliding_window", p.Algorithm)
+	}
+
+	if p.Limit < 0 || p.Burst < 0 {
+		return fmt.Errorf("ratelimit limit and burst cannot be negative")
+	}
+
+	if p.Period < 0 {
+		return fmt.Errorf("ratelimit period cannot be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package entity_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+func TestRateLimitPlugin(t *testing.T) {
+	t.Run("Policy", func(t *testing.T) {
+		t.Run("Return policy with defaults", func(t *testing.T) {
+			rateLimitPlugin := entity.RateLimitPlugin{}
+			rateLimitPlugin.Config.Limit = 60
+
+			policy, err := rateLimitPlugin.Policy()
+			assert.Nil(t, err)
+			assert.Equal(t, entity.Policy{Key: "client_ip", Limit: 60, Period: time.Minute, Burst: 60, Algorithm: "token_bucket"}, policy)
+			assert.Equal(t, "60;w=60;burst=60", policy.String())
+		})
+
+		t.Run("Return sliding window policy", func(t *testing.T) {
+			rateLimitPlugin := entity.RateLimitPlugin{}
+			rateLimitPlugin.Config.Limit = 60
+			rateLimitPlugin.Config.Algorithm = "sliding_window"
+			rateLimitPlugin.Config.Store = entity.StoreConfig{Type: "mysql", Database: "main_database", SyncInterval: "1s"}
+
+			policy, err := rateLimitPlugin.Policy()
+			assert.Nil(t, err)
+			assert.Equal(t, "sliding_window", policy.Algorithm)
+			assert.Equal(t, "60;w=60", policy.String())
+		})
+
+		t.Run("Invalid config", func(t *testing.T) {
+			t.Run("Return error", func(t *testing.T) {
+				for name, config := range map[string]entity.PluginConfig{
+					"unknown key":                     {Key: "cookie", Limit: 1},
+					"empty header":                    {Key: "header:", Limit: 1},
+					"invalid period":                  {Limit: 1, Period: "abc"},
+					"negative limit":                  {Limit: -1},
+					"unknown algorithm":               {Limit: 1, Algorithm: "leaky_bucket"},
+					"unknown store":                   {Limit: 1, Store: entity.StoreConfig{Type: "memcached"}},
+					"mysql store without database":    {Limit: 1, Algorithm: "sliding_window", Store: entity.StoreConfig{Type: "mysql"}},
+					"mysql store with token bucket":   {Limit: 1, Store: entity.StoreConfig{Type: "mysql", Database: "main_database"}},
+					"redis store without address":     {Limit: 1, Store: entity.StoreConfig{Type: "redis"}},
+					"invalid redis timeout":           {Limit: 1, Store: entity.StoreConfig{Type: "redis", Redis: entity.RedisConfig{Address: "localhost:6379", Timeout: "abc"}}},
+					"sync interval with token bucket": {Limit: 1, Store: entity.StoreConfig{SyncInterval: "1s"}},
+					"negative sync interval":          {Limit: 1, Algorithm: "sliding_window", Store: entity.StoreConfig{SyncInterval: "-1s"}},
+				} {
+					_, err := entity.RateLimitPlugin{Config: config}.Policy()
+					assert.NotNil(t, err, name)
+				}
+			})
+		})
+	})
+
+	t.Run("Store", func(t *testing.T) {
+		t.Run("Return defaults", func(t *testing.T) {
+			rateLimitPlugin := entity.RateLimitPlugin{}
+
+			syncInterval, err := rateLimitPlugin.SyncInterval()
+			assert.Nil(t, err)
+			assert.Equal(t, time.Duration(0), syncInterval)
+
+			redisTimeout, err := rateLimitPlugin.RedisTimeout()
+			assert.Nil(t, err)
+			assert.Equal(t, time.Millisecond*100, redisTimeout)
+
+			assert.Equal(t, "memory", rateLimitPlugin.StoreType())
+			assert.Equal(t, "altair:ratelimit:", rateLimitPlugin.RedisKeyPrefix())
+		})
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		policy := entity.Policy{Key: "client_ip", Limit: 600, Period: time.Minute, Burst: 100, Algorithm: "token_bucket"}
+
+		t.Run("Keep the policy without rate_limit", func(t *testing.T) {
+			overridden, limited := policy.Override(nil)
+			assert.True(t, limited)
+			assert.Equal(t, policy, overridden)
+		})
+
+		t.Run("Replace the fields set by rate_limit", func(t *testing.T) {
+			overridden, limited := policy.Override(&coreEntity.RouteRateLimit{Key: "header:X-Api-Key", Limit: 10, Period: "1s"})
+			assert.True(t, limited)
+			assert.Equal(t, entity.Policy{Key: "header:X-Api-Key", Limit: 10, Period: time.Second, Burst: 10, Algorithm: "token_bucket"}, overridden)
+		})
+
+		t.Run("Disable limiting", func(t *testing.T) {
+			_, limited := policy.Override(&coreEntity.RouteRateLimit{Disabled: true})
+			assert.False(t, limited)
+		})
+
+		t.Run("Do not limit without limit", func(t *testing.T) {
+			_, limited := entity.Policy{}.Override(&coreEntity.RouteRateLimit{Key: "client_ip"})
+			assert.False(t, limited)
+		})
+	})
+}
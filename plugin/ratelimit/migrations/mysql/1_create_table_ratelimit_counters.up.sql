@@ -0,0 +1,12 @@
+CREATE TABLE `ratelimit_counters` (
+  `key_hash` char(64) NOT NULL,
+  `window_start` bigint(20) NOT NULL,
+
+  `hits` int(11) unsigned NOT NULL,
+
+  `expires_at` DATETIME NOT NULL,
+
+  PRIMARY KEY (`key_hash`, `window_start`),
+  KEY `expires_at` (`expires_at`)
+
+) ENGINE=InnoDB CHARSET=utf8 COLLATE=utf8_general_ci;
@@ -0,0 +1,12 @@
+package downstream
+
+import (
+	"context"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./downstream.go
+type Store interface {
+	Take(ctx context.Context, key string, policy entity.Policy) (entity.Result, error)
+}
@@ -0,0 +1,140 @@
+package downstream
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kodefluence/monorepo/jsonapi"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+const globalScope = "global"
+
+// RateLimit implement downstream plugin interface
+type RateLimit struct {
+	policy   entity.Policy
+	store    Store
+	metrics  []module.MetricController
+	apiError module.ApiError
+}
+
+// NewRateLimit create new downstream plugin limiting how many requests a client can send
+func NewRateLimit(policy entity.Policy, store Store, metrics []module.MetricController, apiError module.ApiError) *RateLimit {
+	for _, m := range metrics {
+		m.InjectCounter("ratelimit_rejected_requests", "method", "path", "key")
+	}
+
+	return &RateLimit{policy: policy, store: store, metrics: metrics, apiError: apiError}
+}
+
+// Name get the name of downstream plugin
+func (o *RateLimit) Name() string {
+	return "ratelimit-plugin"
+}
+
+// Intervene current request to take a token from the bucket of the client.
+// The plugin policy is shared by every route, a route or path with its own
+// rate_limit is counted per path. When the store fails the request goes
+// through.
+func (o *RateLimit) Intervene(c *gin.Context, proxyReq *http.Request, r module.RouterPath) error {
+	rateLimit, _ := r.GetPluginConfig("ratelimit").(*coreEntity.RouteRateLimit)
+
+	policy, limited := o.policy.Override(rateLimit)
+	if !limited {
+		return nil
+	}
+
+	scope := globalScope
+	if rateLimit != nil {
+		scope = c.FullPath()
+	}
+
+	key := fmt.Sprintf("%s|%s|%s", scope, policy.Key, o.keyValue(c, proxyReq, r, policy.Key))
+
+	result, err := o.store.Take(proxyReq.Context(), key, policy)
+	if err != nil {
+		log.Error().Err(err).Stack().Str("request_id", proxyReq.Header.Get("X-Request-ID")).Str("path", c.FullPath()).Array("tags", zerolog.Arr().Str("ratelimit").Str("downstream").Str("store")).Msg("Error taking a rate limit token, letting the request through")
+		return nil
+	}
+
+	header := c.Writer.Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(policy.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	header.Set("RateLimit-Policy", policy.String())
+
+	if result.Allowed {
+		return nil
+	}
+
+	retryAfter := ceilSeconds(result.RetryAfter)
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	header.Set("Retry-After", strconv.Itoa(retryAfter))
+
+	for _, m := range o.metrics {
+		_ = m.Inc("ratelimit_rejected_requests", map[string]string{
+			"method": c.Request.Method,
+			"path":   c.FullPath(),
+			"key":    policy.Key,
+		})
+	}
+
+	ktx := kontext.Fabricate(kontext.WithDefaultContext(c))
+	ktx.Set("request_id", proxyReq.Header.Get("X-Request-ID"))
+
+	response := jsonapi.BuildResponse(o.apiError.TooManyRequestsError(ktx))
+	c.AbortWithStatusJSON(response.HTTPStatus(), response)
+
+	return fmt.Errorf("Rate limit exceeded for %s, retry after %s", policy.Key, result.RetryAfter)
+}
+
+// keyValue resolves what the bucket is counted by. The oauth identifiers are
+// only trusted on oauth routes, where the oauth plugin set them, any other
+// request is counted by client ip. The gateway resolves it with the
+// trusted_proxies of app.yml, X-Forwarded-For sent by anyone else is ignored.
+func (o *RateLimit) keyValue(c *gin.Context, proxyReq *http.Request, r module.RouterPath, key string) string {
+	switch {
+	case key == entity.KeyResourceOwnerID && r.GetAuth() == "oauth":
+		if value := lastValue(proxyReq.Header, "Resource-Owner-ID"); value != "" {
+			return value
+		}
+	case key == entity.KeyOauthApplicationID && r.GetAuth() == "oauth":
+		if value := lastValue(proxyReq.Header, "Oauth-Application-ID"); value != "" {
+			return value
+		}
+	case strings.HasPrefix(key, entity.KeyHeaderPrefix):
+		if value := c.Request.Header.Get(strings.TrimSpace(strings.TrimPrefix(key, entity.KeyHeaderPrefix))); value != "" {
+			return value
+		}
+	}
+
+	return c.ClientIP()
+}
+
+// lastValue returns the value appended last, the oauth plugin adds its
+// headers after the ones sent by the client.
+func lastValue(header http.Header, name string) string {
+	values := header.Values(name)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[len(values)-1]
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
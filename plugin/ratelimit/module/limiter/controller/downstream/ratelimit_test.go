@@ -0,0 +1,182 @@
+package downstream_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	coreEntity "github.com/kodefluence/altair/entity"
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/module/apierror"
+	moduleMock "github.com/kodefluence/altair/module/mock"
+	routerUsecase "github.com/kodefluence/altair/module/router/usecase"
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+	"github.com/kodefluence/altair/plugin/ratelimit/module/limiter/controller/downstream"
+	"github.com/kodefluence/altair/plugin/ratelimit/module/limiter/controller/downstream/mock"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.ReleaseMode)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	policy := entity.Policy{Key: "client_ip", Limit: 60, Period: time.Minute, Burst: 10, Algorithm: "token_bucket"}
+
+	newContext := func() (*gin.Context, *httptest.ResponseRecorder, *http.Request) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/users/me", nil)
+		c.Request.RemoteAddr = "10.0.0.1:1234"
+
+		proxyReq := httptest.NewRequest("GET", "/users/me", nil)
+		proxyReq.Header.Set("X-Request-ID", "request-1")
+
+		return c, w, proxyReq
+	}
+
+	newRateLimit := func(store downstream.Store) *downstream.RateLimit {
+		metric := moduleMock.NewMockMetricController(mockCtrl)
+		metric.EXPECT().InjectCounter("ratelimit_rejected_requests", "method", "path", "key")
+		metric.EXPECT().Inc("ratelimit_rejected_requests", gomock.Any()).Return(nil).AnyTimes()
+
+		return downstream.NewRateLimit(policy, store, []module.MetricController{metric}, apierror.Provide())
+	}
+
+	t.Run("Name", func(t *testing.T) {
+		t.Run("Return ratelimit-plugin", func(t *testing.T) {
+			assert.Equal(t, "ratelimit-plugin", newRateLimit(mock.NewMockStore(mockCtrl)).Name())
+		})
+	})
+
+	t.Run("Intervene", func(t *testing.T) {
+		t.Run("Token taken", func(t *testing.T) {
+			t.Run("Return nil with rate limit headers", func(t *testing.T) {
+				c, w, proxyReq := newContext()
+
+				store := mock.NewMockStore(mockCtrl)
+				store.EXPECT().Take(gomock.Any(), "global|client_ip|10.0.0.1", policy).Return(entity.Result{Allowed: true, Remaining: 9, Reset: time.Second * 6}, nil)
+
+				err := newRateLimit(store).Intervene(c, proxyReq, coreEntity.RouterPath{})
+
+				assert.Nil(t, err)
+				assert.Equal(t, "60", w.Header().Get("RateLimit-Limit"))
+				assert.Equal(t, "9", w.Header().Get("RateLimit-Remaining"))
+				assert.Equal(t, "6", w.Header().Get("RateLimit-Reset"))
+				assert.Equal(t, "60;w=60;burst=10", w.Header().Get("RateLimit-Policy"))
+				assert.Equal(t, "", w.Header().Get("Retry-After"))
+			})
+		})
+
+		t.Run("Bucket is empty", func(t *testing.T) {
+			t.Run("Return error and too many requests", func(t *testing.T) {
+				c, w, proxyReq := newContext()
+
+				store := mock.NewMockStore(mockCtrl)
+				store.EXPECT().Take(gomock.Any(), gomock.Any(), policy).Return(entity.Result{Allowed: false, RetryAfter: time.Millisecond * 1500, Reset: time.Second * 6}, nil)
+
+				err := newRateLimit(store).Intervene(c, proxyReq, coreEntity.RouterPath{})
+
+				assert.NotNil(t, err)
+				assert.True(t, c.IsAborted())
+				assert.Equal(t, http.StatusTooManyRequests, w.Code)
+				assert.Equal(t, "2", w.Header().Get("Retry-After"))
+				assert.Contains(t, w.Body.String(), "ERR0429")
+			})
+		})
+
+		t.Run("Client spoofs X-Forwarded-For", func(t *testing.T) {
+			t.Run("Count every request by the peer address", func(t *testing.T) {
+				store := mock.NewMockStore(mockCtrl)
+				gomock.InOrder(
+					store.EXPECT().Take(gomock.Any(), "global|client_ip|10.0.0.1", policy).Return(entity.Result{Allowed: true, Reset: time.Second}, nil),
+					store.EXPECT().Take(gomock.Any(), "global|client_ip|10.0.0.1", policy).Return(entity.Result{Allowed: false, RetryAfter: time.Second, Reset: time.Second}, nil),
+				)
+
+				rateLimit := newRateLimit(store)
+
+				for i, forwardedFor := range []string{"198.51.100.1", "198.51.100.2"} {
+					w := httptest.NewRecorder()
+					c, engine := gin.CreateTestContext(w)
+					assert.Nil(t, routerUsecase.TrustProxies(engine, nil))
+
+					c.Request = httptest.NewRequest("GET", "/users/me", nil)
+					c.Request.RemoteAddr = "10.0.0.1:1234"
+					c.Request.Header.Set("X-Forwarded-For", forwardedFor)
+
+					err := rateLimit.Intervene(c, httptest.NewRequest("GET", "/users/me", nil), coreEntity.RouterPath{})
+					assert.Equal(t, i == 1, err != nil)
+				}
+			})
+		})
+
+		t.Run("Store failed", func(t *testing.T) {
+			t.Run("Let the request through", func(t *testing.T) {
+				c, _, proxyReq := newContext()
+
+				store := mock.NewMockStore(mockCtrl)
+				store.EXPECT().Take(gomock.Any(), gomock.Any(), policy).Return(entity.Result{}, errors.New("store is down"))
+
+				err := newRateLimit(store).Intervene(c, proxyReq, coreEntity.RouterPath{})
+
+				assert.Nil(t, err)
+				assert.False(t, c.IsAborted())
+			})
+		})
+
+		t.Run("Rate limit disabled on path", func(t *testing.T) {
+			t.Run("Skip the store", func(t *testing.T) {
+				c, _, proxyReq := newContext()
+
+				err := newRateLimit(mock.NewMockStore(mockCtrl)).Intervene(c, proxyReq, coreEntity.RouterPath{RateLimit: &coreEntity.RouteRateLimit{Disabled: true}})
+				assert.Nil(t, err)
+			})
+		})
+
+		t.Run("Keyed by resource owner", func(t *testing.T) {
+			rateLimit := &coreEntity.RouteRateLimit{Key: "resource_owner_id"}
+
+			t.Run("Use the id set by the oauth plugin on oauth routes", func(t *testing.T) {
+				c, _, proxyReq := newContext()
+				proxyReq.Header.Add("Resource-Owner-ID", "forged")
+				proxyReq.Header.Add("Resource-Owner-ID", "42")
+
+				store := mock.NewMockStore(mockCtrl)
+				store.EXPECT().Take(gomock.Any(), "|resource_owner_id|42", gomock.Any()).Return(entity.Result{Allowed: true}, nil)
+
+				err := newRateLimit(store).Intervene(c, proxyReq, coreEntity.RouterPath{Auth: "oauth", RateLimit: rateLimit})
+				assert.Nil(t, err)
+			})
+
+			t.Run("Fall back to client ip on other routes", func(t *testing.T) {
+				c, _, proxyReq := newContext()
+				proxyReq.Header.Add("Resource-Owner-ID", "forged")
+
+				store := mock.NewMockStore(mockCtrl)
+				store.EXPECT().Take(gomock.Any(), "|resource_owner_id|10.0.0.1", gomock.Any()).Return(entity.Result{Allowed: true}, nil)
+
+				err := newRateLimit(store).Intervene(c, proxyReq, coreEntity.RouterPath{Auth: "none", RateLimit: rateLimit})
+				assert.Nil(t, err)
+			})
+		})
+
+		t.Run("Keyed by header", func(t *testing.T) {
+			t.Run("Use the header value", func(t *testing.T) {
+				c, _, proxyReq := newContext()
+				c.Request.Header.Set("X-Api-Key", "key-1")
+
+				store := mock.NewMockStore(mockCtrl)
+				store.EXPECT().Take(gomock.Any(), "|header:X-Api-Key|key-1", gomock.Any()).Return(entity.Result{Allowed: true}, nil)
+
+				err := newRateLimit(store).Intervene(c, proxyReq, coreEntity.RouterPath{RateLimit: &coreEntity.RouteRateLimit{Key: "header:X-Api-Key"}})
+				assert.Nil(t, err)
+			})
+		})
+	})
+}
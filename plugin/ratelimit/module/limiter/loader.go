@@ -0,0 +1,11 @@
+package limiter
+
+import (
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+	"github.com/kodefluence/altair/plugin/ratelimit/module/limiter/controller/downstream"
+)
+
+func Load(appModule module.App, policy entity.Policy, store downstream.Store, apiError module.ApiError) {
+	appModule.Controller().InjectDownstream(downstream.NewRateLimit(policy, store, appModule.Controller().ListMetric(), apiError))
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+type windowCounter struct {
+	period time.Duration
+	window time.Time
+
+	// current and previous are the counts last read from the repository.
+	current  int
+	previous int
+	// inflight are the hits of the current window being added by a sync.
+	inflight int
+	// pending are the hits not added to the repository yet, per window.
+	pending map[time.Time]int
+
+	used time.Time
+}
+
+func (w *windowCounter) count() int {
+	return w.current + w.inflight + w.pending[w.window]
+}
+
+// roll moves the counter to window, the current window becomes the previous
+// one when they are adjacent.
+func (w *windowCounter) roll(window time.Time) {
+	if window.Equal(w.window) {
+		return
+	}
+
+	w.previous = 0
+	if window.Sub(w.window) == w.period {
+		w.previous = w.count()
+	}
+
+	w.window = window
+	w.current = 0
+	w.inflight = 0
+}
+
+// CachedSlidingWindow is a sliding window counted in the process memory and
+// synced with the repository every interval, the repository is only hit
+// synchronously the first time a key is seen. Requests counted by the other
+// instances are only seen after a sync, so the limit can be overrun by what
+// the instances allow within one interval.
+type CachedSlidingWindow struct {
+	repository WindowRepository
+	interval   time.Duration
+	counters   map[string]*windowCounter
+	lock       *sync.Mutex
+	now        func() time.Time
+}
+
+// NewCachedSlidingWindow starts syncing the counters in the background for the
+// lifetime of the process.
+func NewCachedSlidingWindow(repository WindowRepository, interval time.Duration) *CachedSlidingWindow {
+	c := newCachedSlidingWindow(repository, interval)
+	go c.run()
+	return c
+}
+
+func newCachedSlidingWindow(repository WindowRepository, interval time.Duration) *CachedSlidingWindow {
+	return &CachedSlidingWindow{
+		repository: repository,
+		interval:   interval,
+		counters:   map[string]*windowCounter{},
+		lock:       &sync.Mutex{},
+		now:        time.Now,
+	}
+}
+
+func (c *CachedSlidingWindow) Take(ctx context.Context, key string, policy entity.Policy) (entity.Result, error) {
+	now := c.now()
+	window := windowStart(now, policy.Period)
+
+	c.lock.Lock()
+	counter, ok := c.counters[key]
+	c.lock.Unlock()
+
+	if !ok {
+		current, previous, err := c.repository.Counts(ctx, key, window, policy.Period)
+		if err != nil {
+			return entity.Result{}, err
+		}
+
+		c.lock.Lock()
+		if counter, ok = c.counters[key]; !ok {
+			counter = &windowCounter{period: policy.Period, window: window, current: current, previous: previous, pending: map[time.Time]int{}}
+			c.counters[key] = counter
+		}
+		c.lock.Unlock()
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	counter.roll(window)
+	counter.used = now
+
+	result := slidingWindowResult(counter.count(), counter.previous, policy, now.Sub(window))
+	if result.Allowed {
+		counter.pending[window]++
+	}
+
+	return result, nil
+}
+
+func (c *CachedSlidingWindow) run() {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		c.sync(context.Background())
+	}
+}
+
+type pendingHits struct {
+	key    string
+	period time.Duration
+	window time.Time
+	hits   map[time.Time]int
+	// inflight are the hits of window, hits only keeps the ones not added yet.
+	inflight int
+}
+
+// sync adds the pending hits to the repository and reads back the counts of
+// every key, keys unused for two periods are forgotten.
+func (c *CachedSlidingWindow) sync(ctx context.Context) {
+	now := c.now()
+
+	c.lock.Lock()
+	batch := make([]pendingHits, 0, len(c.counters))
+	for key, counter := range c.counters {
+		if len(counter.pending) == 0 && now.Sub(counter.used) > counter.period*2 {
+			delete(c.counters, key)
+			continue
+		}
+
+		inflight := counter.pending[counter.window]
+		batch = append(batch, pendingHits{key: key, period: counter.period, window: counter.window, hits: counter.pending, inflight: inflight})
+		counter.inflight += inflight
+		counter.pending = map[time.Time]int{}
+	}
+	c.lock.Unlock()
+
+	for _, p := range batch {
+		if err := c.flush(ctx, p); err != nil {
+			log.Error().Err(err).Stack().Str("key", p.key).Array("tags", zerolog.Arr().Str("ratelimit").Str("usecase").Str("sync")).Msg("Error syncing rate limit counters, retrying on the next sync")
+			c.restore(p)
+			continue
+		}
+
+		current, previous, err := c.repository.Counts(ctx, p.key, p.window, p.period)
+		if err != nil {
+			log.Error().Err(err).Stack().Str("key", p.key).Array("tags", zerolog.Arr().Str("ratelimit").Str("usecase").Str("sync")).Msg("Error reading rate limit counters")
+		}
+
+		c.lock.Lock()
+		if counter, ok := c.counters[p.key]; ok && counter.window.Equal(p.window) {
+			counter.inflight -= p.inflight
+			if err == nil {
+				counter.current, counter.previous = current, previous
+			} else {
+				counter.current += p.inflight
+			}
+		}
+		c.lock.Unlock()
+	}
+}
+
+func (c *CachedSlidingWindow) flush(ctx context.Context, p pendingHits) error {
+	for window, hits := range p.hits {
+		if err := c.repository.Add(ctx, p.key, window, p.period, hits); err != nil {
+			return err
+		}
+
+		delete(p.hits, window)
+	}
+
+	return nil
+}
+
+// restore puts back the hits a failed sync did not add.
+func (c *CachedSlidingWindow) restore(p pendingHits) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	counter, ok := c.counters[p.key]
+	if !ok {
+		counter = &windowCounter{period: p.period, window: p.window, pending: map[time.Time]int{}}
+		c.counters[p.key] = counter
+	}
+
+	if counter.window.Equal(p.window) {
+		counter.inflight -= p.inflight
+		counter.current += p.inflight - p.hits[p.window]
+	}
+
+	for window, hits := range p.hits {
+		counter.pending[window] += hits
+	}
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+	"github.com/kodefluence/altair/plugin/ratelimit/module/limiter/usecase/mock"
+)
+
+func TestCachedSlidingWindow(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	policy := entity.Policy{Key: "client_ip", Limit: 3, Period: time.Minute, Burst: 3, Algorithm: "sliding_window"}
+
+	window := time.Unix(1200, 0)
+	now := window
+
+	repository := mock.NewMockWindowRepository(mockCtrl)
+
+	c := newCachedSlidingWindow(repository, time.Second)
+	c.now = func() time.Time { return now }
+
+	t.Run("Read the repository the first time a key is seen", func(t *testing.T) {
+		repository.EXPECT().Counts(gomock.Any(), "a", window, time.Minute).Return(1, 0, nil)
+
+		result, err := c.Take(context.Background(), "a", policy)
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 1, result.Remaining)
+	})
+
+	t.Run("Count locally afterwards", func(t *testing.T) {
+		result, err := c.Take(context.Background(), "a", policy)
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+
+		result, err = c.Take(context.Background(), "a", policy)
+		assert.Nil(t, err)
+		assert.False(t, result.Allowed)
+	})
+
+	t.Run("Keep the pending hits when the sync fails", func(t *testing.T) {
+		repository.EXPECT().Add(gomock.Any(), "a", window, time.Minute, 2).Return(errors.New("unexpected error"))
+
+		c.sync(context.Background())
+
+		assert.Equal(t, 2, c.counters["a"].pending[window])
+		assert.Equal(t, 0, c.counters["a"].inflight)
+	})
+
+	t.Run("Add the pending hits and read back the counts of every instance", func(t *testing.T) {
+		repository.EXPECT().Add(gomock.Any(), "a", window, time.Minute, 2).Return(nil)
+		repository.EXPECT().Counts(gomock.Any(), "a", window, time.Minute).Return(4, 1, nil)
+
+		c.sync(context.Background())
+
+		counter := c.counters["a"]
+		assert.Equal(t, 4, counter.current)
+		assert.Equal(t, 1, counter.previous)
+		assert.Equal(t, 0, len(counter.pending))
+	})
+
+	t.Run("Roll the current window into the previous one", func(t *testing.T) {
+		now = window.Add(time.Minute + time.Second*30)
+
+		result, err := c.Take(context.Background(), "a", policy)
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 4, c.counters["a"].previous)
+		assert.Equal(t, 1, c.counters["a"].pending[window.Add(time.Minute)])
+	})
+
+	t.Run("Forget keys unused for two periods", func(t *testing.T) {
+		repository.EXPECT().Add(gomock.Any(), "a", window.Add(time.Minute), time.Minute, 1).Return(nil)
+		repository.EXPECT().Counts(gomock.Any(), "a", window.Add(time.Minute), time.Minute).Return(1, 2, nil)
+		c.sync(context.Background())
+
+		now = now.Add(time.Minute * 3)
+		c.sync(context.Background())
+
+		assert.Equal(t, 0, len(c.counters))
+	})
+}
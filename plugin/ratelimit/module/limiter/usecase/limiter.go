@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+//go:generate mockgen -destination ./mock/mock.go -package mock -source ./limiter.go
+type BucketRepository interface {
+	// Take refills the bucket of key up to now and takes one token when there
+	// is one, it returns the tokens left.
+	Take(ctx context.Context, key string, policy entity.Policy, now time.Time) (tokens float64, allowed bool, err error)
+}
+
+type WindowRepository interface {
+	// Counts returns the hits of key in the window starting at window and in
+	// the window before it.
+	Counts(ctx context.Context, key string, window time.Time, period time.Duration) (current, previous int, err error)
+	// Add counts hits of key in the window starting at window.
+	Add(ctx context.Context, key string, window time.Time, period time.Duration, hits int) error
+}
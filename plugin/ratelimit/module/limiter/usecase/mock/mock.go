@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./limiter.go
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	entity "github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+// MockBucketRepository is a mock of BucketRepository interface.
+type MockBucketRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBucketRepositoryMockRecorder
+}
+
+// MockBucketRepositoryMockRecorder is the mock recorder for MockBucketRepository.
+type MockBucketRepositoryMockRecorder struct {
+	mock *MockBucketRepository
+}
+
+// NewMockBucketRepository creates a new mock instance.
+func NewMockBucketRepository(ctrl *gomock.Controller) *MockBucketRepository {
+	mock := &MockBucketRepository{ctrl: ctrl}
+	mock.recorder = &MockBucketRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBucketRepository) EXPECT() *MockBucketRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Take mocks base method.
+func (m *MockBucketRepository) Take(ctx context.Context, key string, policy entity.Policy, now time.Time) (float64, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Take", ctx, key, policy, now)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Take indicates an expected call of Take.
+func (mr *MockBucketRepositoryMockRecorder) Take(ctx, key, policy, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Take", reflect.TypeOf((*MockBucketRepository)(nil).Take), ctx, key, policy, now)
+}
+
+// MockWindowRepository is a mock of WindowRepository interface.
+type MockWindowRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWindowRepositoryMockRecorder
+}
+
+// MockWindowRepositoryMockRecorder is the mock recorder for MockWindowRepository.
+type MockWindowRepositoryMockRecorder struct {
+	mock *MockWindowRepository
+}
+
+// NewMockWindowRepository creates a new mock instance.
+func NewMockWindowRepository(ctrl *gomock.Controller) *MockWindowRepository {
+	mock := &MockWindowRepository{ctrl: ctrl}
+	mock.recorder = &MockWindowRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWindowRepository) EXPECT() *MockWindowRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockWindowRepository) Add(ctx context.Context, key string, window time.Time, period time.Duration, hits int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, key, window, period, hits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockWindowRepositoryMockRecorder) Add(ctx, key, window, period, hits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockWindowRepository)(nil).Add), ctx, key, window, period, hits)
+}
+
+// Counts mocks base method.
+func (m *MockWindowRepository) Counts(ctx context.Context, key string, window time.Time, period time.Duration) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Counts", ctx, key, window, period)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Counts indicates an expected call of Counts.
+func (mr *MockWindowRepositoryMockRecorder) Counts(ctx, key, window, period interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Counts", reflect.TypeOf((*MockWindowRepository)(nil).Counts), ctx, key, window, period)
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+// SlidingWindow counts requests in fixed windows of one period and weights the
+// previous window by how much of it still overlaps the last period. Only
+// allowed requests are counted. The repository is read and written on every
+// request, instances racing on the same key may let a few requests over the
+// limit.
+type SlidingWindow struct {
+	repository WindowRepository
+	now        func() time.Time
+}
+
+func NewSlidingWindow(repository WindowRepository) *SlidingWindow {
+	return &SlidingWindow{repository: repository, now: time.Now}
+}
+
+func (s *SlidingWindow) Take(ctx context.Context, key string, policy entity.Policy) (entity.Result, error) {
+	now := s.now()
+	window := windowStart(now, policy.Period)
+
+	current, previous, err := s.repository.Counts(ctx, key, window, policy.Period)
+	if err != nil {
+		return entity.Result{}, err
+	}
+
+	result := slidingWindowResult(current, previous, policy, now.Sub(window))
+	if !result.Allowed {
+		return result, nil
+	}
+
+	if err := s.repository.Add(ctx, key, window, policy.Period, 1); err != nil {
+		return entity.Result{}, err
+	}
+
+	return result, nil
+}
+
+// windowStart aligns windows on the unix epoch so every instance agrees on
+// them.
+func windowStart(now time.Time, period time.Duration) time.Time {
+	return time.Unix(0, now.UnixNano()-now.UnixNano()%int64(period))
+}
+
+func slidingWindowResult(current, previous int, policy entity.Policy, elapsed time.Duration) entity.Result {
+	weight := 1 - elapsed.Seconds()/policy.Period.Seconds()
+	estimated := float64(previous)*weight + float64(current)
+
+	result := entity.Result{Reset: policy.Period - elapsed}
+	if estimated+1 <= float64(policy.Limit) {
+		result.Allowed = true
+		result.Remaining = int(math.Floor(float64(policy.Limit) - estimated - 1))
+		return result
+	}
+
+	result.RetryAfter = slidingWindowRetryAfter(current, previous, policy, elapsed)
+	return result
+}
+
+// slidingWindowRetryAfter is how long until the estimate leaves room for one
+// more request when no other request comes in meanwhile.
+func slidingWindowRetryAfter(current, previous int, policy entity.Policy, elapsed time.Duration) time.Duration {
+	period := policy.Period.Seconds()
+	left := period - elapsed.Seconds()
+	room := float64(policy.Limit - 1)
+
+	// The previous window may fade out enough before the current one ends.
+	if previous > 0 && float64(current) <= room {
+		if wait := period*(1-(room-float64(current))/float64(previous)) - elapsed.Seconds(); wait < left {
+			return secondsToDuration(math.Max(0, wait))
+		}
+	}
+
+	// Otherwise the current window has to fade out as the next previous one.
+	if current > 0 && float64(current) > room {
+		return secondsToDuration(left + period*(1-room/float64(current)))
+	}
+
+	return secondsToDuration(left)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+	"github.com/kodefluence/altair/plugin/ratelimit/module/limiter/usecase/mock"
+)
+
+func TestSlidingWindow(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	policy := entity.Policy{Key: "client_ip", Limit: 10, Period: time.Minute, Burst: 10, Algorithm: "sliding_window"}
+
+	window := time.Unix(1200, 0)
+	now := window.Add(time.Second * 15)
+
+	newSlidingWindow := func(repository WindowRepository) *SlidingWindow {
+		s := NewSlidingWindow(repository)
+		s.now = func() time.Time { return now }
+		return s
+	}
+
+	t.Run("Room left in the window", func(t *testing.T) {
+		t.Run("Count the request and weight the previous window", func(t *testing.T) {
+			repository := mock.NewMockWindowRepository(mockCtrl)
+			repository.EXPECT().Counts(gomock.Any(), "a", window, time.Minute).Return(2, 4, nil)
+			repository.EXPECT().Add(gomock.Any(), "a", window, time.Minute, 1).Return(nil)
+
+			result, err := newSlidingWindow(repository).Take(context.Background(), "a", policy)
+			assert.Nil(t, err)
+			assert.Equal(t, entity.Result{Allowed: true, Remaining: 4, Reset: time.Second * 45}, result)
+		})
+	})
+
+	t.Run("Window is full", func(t *testing.T) {
+		t.Run("Wait for the previous window to fade out", func(t *testing.T) {
+			repository := mock.NewMockWindowRepository(mockCtrl)
+			repository.EXPECT().Counts(gomock.Any(), "a", window, time.Minute).Return(5, 8, nil)
+
+			result, err := newSlidingWindow(repository).Take(context.Background(), "a", policy)
+			assert.Nil(t, err)
+			assert.False(t, result.Allowed)
+			assert.Equal(t, time.Second*15, result.RetryAfter)
+		})
+
+		t.Run("Wait for the current window to fade out", func(t *testing.T) {
+			repository := mock.NewMockWindowRepository(mockCtrl)
+			repository.EXPECT().Counts(gomock.Any(), "a", window, time.Minute).Return(10, 0, nil)
+
+			result, err := newSlidingWindow(repository).Take(context.Background(), "a", policy)
+			assert.Nil(t, err)
+			assert.False(t, result.Allowed)
+			assert.Equal(t, time.Second*51, result.RetryAfter)
+		})
+	})
+
+	t.Run("Repository failed", func(t *testing.T) {
+		t.Run("Return error", func(t *testing.T) {
+			repository := mock.NewMockWindowRepository(mockCtrl)
+			repository.EXPECT().Counts(gomock.Any(), "a", window, time.Minute).Return(0, 0, errors.New("unexpected error"))
+
+			_, err := newSlidingWindow(repository).Take(context.Background(), "a", policy)
+			assert.NotNil(t, err)
+		})
+	})
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+// TokenBucket takes one token per request from the bucket of the key, the
+// repository decides where the buckets live.
+type TokenBucket struct {
+	repository BucketRepository
+	now        func() time.Time
+}
+
+func NewTokenBucket(repository BucketRepository) *TokenBucket {
+	return &TokenBucket{repository: repository, now: time.Now}
+}
+
+func (t *TokenBucket) Take(ctx context.Context, key string, policy entity.Policy) (entity.Result, error) {
+	tokens, allowed, err := t.repository.Take(ctx, key, policy, t.now())
+	if err != nil {
+		return entity.Result{}, err
+	}
+
+	rate := float64(policy.Limit) / policy.Period.Seconds()
+
+	result := entity.Result{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(policy.Burst) - tokens) / rate),
+	}
+
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+
+	return result, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,54 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+	"github.com/kodefluence/altair/plugin/ratelimit/module/limiter/usecase"
+	"github.com/kodefluence/altair/plugin/ratelimit/module/limiter/usecase/mock"
+)
+
+func TestTokenBucket(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	policy := entity.Policy{Key: "client_ip", Limit: 2, Period: time.Second, Burst: 3, Algorithm: "token_bucket"}
+
+	t.Run("Token taken", func(t *testing.T) {
+		t.Run("Return remaining tokens and when the bucket is full again", func(t *testing.T) {
+			repository := mock.NewMockBucketRepository(mockCtrl)
+			repository.EXPECT().Take(gomock.Any(), "a", policy, gomock.Any()).Return(1.5, true, nil)
+
+			result, err := usecase.NewTokenBucket(repository).Take(context.Background(), "a", policy)
+			assert.Nil(t, err)
+			assert.Equal(t, entity.Result{Allowed: true, Remaining: 1, Reset: time.Millisecond * 750}, result)
+		})
+	})
+
+	t.Run("Bucket is empty", func(t *testing.T) {
+		t.Run("Return when the next token is refilled", func(t *testing.T) {
+			repository := mock.NewMockBucketRepository(mockCtrl)
+			repository.EXPECT().Take(gomock.Any(), "a", policy, gomock.Any()).Return(0.0, false, nil)
+
+			result, err := usecase.NewTokenBucket(repository).Take(context.Background(), "a", policy)
+			assert.Nil(t, err)
+			assert.Equal(t, entity.Result{RetryAfter: time.Millisecond * 500, Reset: time.Millisecond * 1500}, result)
+		})
+	})
+
+	t.Run("Repository failed", func(t *testing.T) {
+		t.Run("Return error", func(t *testing.T) {
+			repository := mock.NewMockBucketRepository(mockCtrl)
+			repository.EXPECT().Take(gomock.Any(), "a", policy, gomock.Any()).Return(0.0, false, errors.New("unexpected error"))
+
+			_, err := usecase.NewTokenBucket(repository).Take(context.Background(), "a", policy)
+			assert.NotNil(t, err)
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"embed"
+	"fmt"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+//go:embed config.sample.yml
+var sampleConfig []byte
+
+//go:embed migrations/mysql/*.sql
+var migrationsFS embed.FS
+
+// Plugin implements module.Plugin for the ratelimit plugin. The ratelimit
+// plugin only owns a schema when its counters are stored in mysql.
+type Plugin struct{}
+
+// Name implements module.Plugin.
+func (*Plugin) Name() string { return "ratelimit" }
+
+// DependsOn implements module.Plugin. Both are soft dependencies: metric has
+// to be loaded first for rejections to be counted, and oauth has to intervene
+// first for resource_owner_id and oauth_application_id keys to resolve.
+func (*Plugin) DependsOn() []string { return []string{"metric", "oauth"} }
+
+// Migrations implements module.Plugin. The counters table is only migrated
+// when `config.store.type` is mysql, in the `config.store.database` instance.
+func (*Plugin) Migrations(ctx module.PluginContext) []module.MigrationSet {
+	if ctx.DecodeConfig == nil {
+		return nil
+	}
+	var cfg entity.RateLimitPlugin
+	if err := ctx.DecodeConfig(&cfg); err != nil {
+		return nil
+	}
+	if cfg.StoreType() != entity.StoreMySQL {
+		return nil
+	}
+	return []module.MigrationSet{{
+		DatabaseInstance: cfg.DatabaseInstance(),
+		Driver:           "mysql",
+		FS:               migrationsFS,
+		SourcePath:       "migrations/mysql",
+		VersionTable:     "ratelimit_plugin_db_versions",
+	}}
+}
+
+// SampleConfig implements module.Plugin.
+func (*Plugin) SampleConfig() []byte { return sampleConfig }
+
+// Load implements module.Plugin and dispatches on PluginContext.Version.
+func (*Plugin) Load(ctx module.PluginContext) error {
+	switch ctx.Version {
+	case "1.0":
+		return loadV1_0(ctx)
+	default:
+		return fmt.Errorf("undefined template version: %s for ratelimit plugin", ctx.Version)
+	}
+}
+
+// LoadCommand implements module.Plugin. Ratelimit exposes no CLI subcommands.
+func (*Plugin) LoadCommand(ctx module.PluginContext) error { return nil }
@@ -0,0 +1,152 @@
+package ratelimit_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kodefluence/monorepo/db"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/core"
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/module/apierror"
+	"github.com/kodefluence/altair/module/mock"
+	"github.com/kodefluence/altair/plugin/ratelimit"
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+// Assumption: ratelimit plugin's identifier is "ratelimit".
+func TestPluginName_IsRatelimit(t *testing.T) {
+	assert.Equal(t, "ratelimit", (&ratelimit.Plugin{}).Name())
+}
+
+// Assumption: ratelimit loads after metric and oauth when they are active.
+func TestPluginDependsOn_MetricAndOauth(t *testing.T) {
+	assert.Equal(t, []string{"metric", "oauth"}, (&ratelimit.Plugin{}).DependsOn())
+}
+
+// Assumption: SampleConfig returns the embedded sample with ratelimit plugin
+// markers.
+func TestPluginSampleConfig_ContainsPluginAndVersion(t *testing.T) {
+	got := string((&ratelimit.Plugin{}).SampleConfig())
+	assert.Contains(t, got, "plugin: ratelimit")
+	assert.Contains(t, got, `version: "1.0"`)
+}
+
+// Assumption: ratelimit owns no schema unless its counters live in mysql.
+func TestPluginMigrations_NilWithoutMySQLStore(t *testing.T) {
+	assert.Nil(t, (&ratelimit.Plugin{}).Migrations(module.PluginContext{}))
+
+	ctx := module.PluginContext{
+		DecodeConfig: func(target interface{}) error {
+			target.(*entity.RateLimitPlugin).Config.Store.Type = "redis"
+			return nil
+		},
+	}
+	assert.Nil(t, (&ratelimit.Plugin{}).Migrations(ctx))
+}
+
+func TestPluginMigrations_MySQLStoreReturnsCountersTable(t *testing.T) {
+	ctx := module.PluginContext{
+		DecodeConfig: func(target interface{}) error {
+			target.(*entity.RateLimitPlugin).Config.Store = entity.StoreConfig{Type: "mysql", Database: "main_database"}
+			return nil
+		},
+	}
+
+	sets := (&ratelimit.Plugin{}).Migrations(ctx)
+	assert.Equal(t, 1, len(sets))
+	assert.Equal(t, "main_database", sets[0].DatabaseInstance)
+	assert.Equal(t, "mysql", sets[0].Driver)
+	assert.Equal(t, "ratelimit_plugin_db_versions", sets[0].VersionTable)
+
+	entries, err := fs.ReadDir(sets[0].FS, sets[0].SourcePath)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(entries))
+}
+
+func TestPluginLoad_V10MySQLStoreResolvesDatabase(t *testing.T) {
+	ctx := module.PluginContext{
+		Version: "1.0",
+		DecodeConfig: func(target interface{}) error {
+			target.(*entity.RateLimitPlugin).Config = entity.PluginConfig{Limit: 60, Algorithm: "sliding_window", Store: entity.StoreConfig{Type: "mysql", Database: "main_database"}}
+			return nil
+		},
+		Database: func(instance string) (db.DB, core.DatabaseConfig, error) {
+			return nil, nil, errors.New("database " + instance + " not found")
+		},
+	}
+	err := (&ratelimit.Plugin{}).Load(ctx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "main_database")
+}
+
+// Assumption: LoadCommand is always a no-op success.
+func TestPluginLoadCommand_AlwaysNil(t *testing.T) {
+	assert.Nil(t, (&ratelimit.Plugin{}).LoadCommand(module.PluginContext{Version: "anything"}))
+}
+
+func TestPluginLoad_RejectsUnknownVersion(t *testing.T) {
+	err := (&ratelimit.Plugin{}).Load(module.PluginContext{Version: "9.9"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "9.9")
+	assert.Contains(t, err.Error(), "ratelimit")
+}
+
+func TestPluginLoad_V10WithMissingDecodeConfigErrors(t *testing.T) {
+	err := (&ratelimit.Plugin{}).Load(module.PluginContext{Version: "1.0"})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "DecodeConfig")
+}
+
+func TestPluginLoad_V10DecodeConfigErrorPropagated(t *testing.T) {
+	ctx := module.PluginContext{
+		Version: "1.0",
+		DecodeConfig: func(_ interface{}) error {
+			return errors.New("decode boom")
+		},
+	}
+	err := (&ratelimit.Plugin{}).Load(ctx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "decode boom")
+}
+
+// Assumption: an unsupported key is reported when the plugin loads instead of
+// silently limiting by client ip.
+func TestPluginLoad_V10UnsupportedKeyErrors(t *testing.T) {
+	ctx := module.PluginContext{
+		Version: "1.0",
+		DecodeConfig: func(target interface{}) error {
+			target.(*entity.RateLimitPlugin).Config.Key = "cookie"
+			return nil
+		},
+	}
+	err := (&ratelimit.Plugin{}).Load(ctx)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cookie")
+}
+
+func TestPluginLoad_V10InjectsDownstream(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	controller := mock.NewMockController(mockCtrl)
+	controller.EXPECT().ListMetric().Return(nil)
+	controller.EXPECT().InjectDownstream(gomock.Any())
+
+	appModule := mock.NewMockApp(mockCtrl)
+	appModule.EXPECT().Controller().Return(controller).AnyTimes()
+
+	ctx := module.PluginContext{
+		Version:   "1.0",
+		AppModule: appModule,
+		ApiError:  apierror.Provide(),
+		DecodeConfig: func(target interface{}) error {
+			target.(*entity.RateLimitPlugin).Config.Limit = 60
+			return nil
+		},
+	}
+	assert.Nil(t, (&ratelimit.Plugin{}).Load(ctx))
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+// Bucket keeps the token buckets in the process memory, every altair instance
+// counts its own requests.
+type Bucket struct {
+	buckets   map[string]*bucket
+	lock      *sync.Mutex
+	lastSweep time.Time
+}
+
+func NewBucket() *Bucket {
+	return &Bucket{
+		buckets:   map[string]*bucket{},
+		lock:      &sync.Mutex{},
+		lastSweep: time.Now(),
+	}
+}
+
+// Take takes one token from the bucket of key, refilling it for the time
+// elapsed since the previous request first.
+func (m *Bucket) Take(ctx context.Context, key string, policy entity.Policy, now time.Time) (float64, bool, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.sweep(now)
+
+	rate := float64(policy.Limit) / policy.Period.Seconds()
+	capacity := float64(policy.Burst)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		m.buckets[key] = b
+	}
+
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+		b.updated = now
+	}
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	b.full = now.Add(time.Duration((capacity - b.tokens) / rate * float64(time.Second)))
+
+	return b.tokens, allowed, nil
+}
+
+// sweep forgets the buckets refilled up to their capacity, they are the same
+// as a new bucket.
+func (m *Bucket) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, b := range m.buckets {
+		if !now.Before(b.full) {
+			delete(m.buckets, key)
+		}
+	}
+}
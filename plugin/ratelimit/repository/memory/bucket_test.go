@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+func TestBucket(t *testing.T) {
+	now := time.Now()
+
+	repository := NewBucket()
+
+	policy := entity.Policy{Key: "client_ip", Limit: 2, Period: time.Second, Burst: 3, Algorithm: "token_bucket"}
+
+	t.Run("Allow up to burst requests at once", func(t *testing.T) {
+		for remaining := 2.0; remaining >= 0; remaining-- {
+			tokens, allowed, err := repository.Take(context.Background(), "a", policy, now)
+			assert.Nil(t, err)
+			assert.True(t, allowed)
+			assert.Equal(t, remaining, tokens)
+		}
+	})
+
+	t.Run("Reject when the bucket is empty", func(t *testing.T) {
+		tokens, allowed, err := repository.Take(context.Background(), "a", policy, now)
+		assert.Nil(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, 0.0, tokens)
+	})
+
+	t.Run("Count every key on its own", func(t *testing.T) {
+		_, allowed, err := repository.Take(context.Background(), "b", policy, now)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("Refill the bucket over time", func(t *testing.T) {
+		now = now.Add(time.Millisecond * 500)
+
+		tokens, allowed, err := repository.Take(context.Background(), "a", policy, now)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+		assert.Equal(t, 0.0, tokens)
+	})
+
+	t.Run("Forget full buckets", func(t *testing.T) {
+		now = now.Add(sweepInterval)
+
+		_, _, err := repository.Take(context.Background(), "c", policy, now)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(repository.buckets))
+	})
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type windowKey struct {
+	key    string
+	window int64
+}
+
+type windowHits struct {
+	hits    int
+	expires time.Time
+}
+
+// Window keeps the sliding window counters in the process memory, every altair
+// instance counts its own requests.
+type Window struct {
+	windows   map[windowKey]*windowHits
+	lock      *sync.Mutex
+	lastSweep time.Time
+}
+
+func NewWindow() *Window {
+	return &Window{
+		windows:   map[windowKey]*windowHits{},
+		lock:      &sync.Mutex{},
+		lastSweep: time.Now(),
+	}
+}
+
+func (m *Window) Counts(ctx context.Context, key string, window time.Time, period time.Duration) (int, int, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.hits(key, window), m.hits(key, window.Add(-period)), nil
+}
+
+func (m *Window) Add(ctx context.Context, key string, window time.Time, period time.Duration, hits int) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.sweep(window)
+
+	k := windowKey{key: key, window: window.UnixNano()}
+	w, ok := m.windows[k]
+	if !ok {
+		// A window is still read as the previous one during the next period.
+		w = &windowHits{expires: window.Add(period * 2)}
+		m.windows[k] = w
+	}
+	w.hits += hits
+
+	return nil
+}
+
+func (m *Window) hits(key string, window time.Time) int {
+	if w, ok := m.windows[windowKey{key: key, window: window.UnixNano()}]; ok {
+		return w.hits
+	}
+
+	return 0
+}
+
+// sweep forgets the windows no longer read.
+func (m *Window) sweep(now time.Time) {
+	if now.Sub(m.lastSweep) < sweepInterval {
+		return
+	}
+	m.lastSweep = now
+
+	for key, w := range m.windows {
+		if !now.Before(w.expires) {
+			delete(m.windows, key)
+		}
+	}
+}
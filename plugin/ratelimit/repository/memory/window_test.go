@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindow(t *testing.T) {
+	window := time.Now().Truncate(time.Minute)
+
+	repository := NewWindow()
+
+	t.Run("Count hits per key and window", func(t *testing.T) {
+		assert.Nil(t, repository.Add(context.Background(), "a", window.Add(-time.Minute), time.Minute, 3))
+		assert.Nil(t, repository.Add(context.Background(), "a", window, time.Minute, 1))
+		assert.Nil(t, repository.Add(context.Background(), "a", window, time.Minute, 1))
+		assert.Nil(t, repository.Add(context.Background(), "b", window, time.Minute, 1))
+
+		current, previous, err := repository.Counts(context.Background(), "a", window, time.Minute)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, current)
+		assert.Equal(t, 3, previous)
+	})
+
+	t.Run("Forget windows no longer read", func(t *testing.T) {
+		later := window.Add(sweepInterval * 3)
+		assert.Nil(t, repository.Add(context.Background(), "a", later, time.Minute, 1))
+
+		assert.Equal(t, 1, len(repository.windows))
+	})
+}
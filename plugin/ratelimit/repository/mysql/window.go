@@ -0,0 +1,95 @@
+package mysql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/kodefluence/monorepo/db"
+	"github.com/kodefluence/monorepo/kontext"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const cleanupInterval = time.Minute
+
+// Window is a connector to ratelimit_counters table. Keys are stored hashed,
+// they may carry header values sent by the clients.
+type Window struct {
+	sqldb       db.DB
+	lock        *sync.Mutex
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+// NewWindow create new Window struct
+func NewWindow(sqldb db.DB) *Window {
+	return &Window{sqldb: sqldb, lock: &sync.Mutex{}, lastCleanup: time.Now(), now: time.Now}
+}
+
+// Counts selecting the hits of the window and of the window before it
+func (w *Window) Counts(ctx context.Context, key string, window time.Time, period time.Duration) (int, int, error) {
+	var current, previous int
+
+	ctxWithTimeout, cf := context.WithTimeout(ctx, time.Second*10)
+	defer cf()
+
+	row := w.sqldb.QueryRowContext(
+		kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout)),
+		"ratelimit-counters-counts",
+		"select coalesce(sum(case when window_start = ? then hits else 0 end), 0), coalesce(sum(case when window_start = ? then hits else 0 end), 0) from ratelimit_counters where key_hash = ? and window_start in (?, ?)",
+		window.UnixMilli(), window.Add(-period).UnixMilli(), hashKey(key), window.UnixMilli(), window.Add(-period).UnixMilli(),
+	)
+	if err := row.Scan(&current, &previous); err != nil {
+		return 0, 0, err
+	}
+
+	return current, previous, nil
+}
+
+// Add increments the hits of the window
+func (w *Window) Add(ctx context.Context, key string, window time.Time, period time.Duration, hits int) error {
+	ctxWithTimeout, cf := context.WithTimeout(ctx, time.Second*10)
+	defer cf()
+
+	ktx := kontext.Fabricate(kontext.WithDefaultContext(ctxWithTimeout))
+
+	// A window is still read as the previous one during the next period.
+	_, err := w.sqldb.ExecContext(
+		ktx,
+		"ratelimit-counters-add",
+		"insert into ratelimit_counters (key_hash, window_start, hits, expires_at) values(?, ?, ?, ?) on duplicate key update hits = hits + values(hits)",
+		hashKey(key), window.UnixMilli(), hits, window.Add(period*2).UTC(),
+	)
+	if err != nil {
+		return err
+	}
+
+	w.cleanup(ktx)
+
+	return nil
+}
+
+// cleanup deletes the expired windows once every cleanupInterval.
+func (w *Window) cleanup(ktx kontext.Context) {
+	now := w.now()
+
+	w.lock.Lock()
+	if now.Sub(w.lastCleanup) < cleanupInterval {
+		w.lock.Unlock()
+		return
+	}
+	w.lastCleanup = now
+	w.lock.Unlock()
+
+	if _, err := w.sqldb.ExecContext(ktx, "ratelimit-counters-cleanup", "delete from ratelimit_counters where expires_at < ?", now.UTC()); err != nil {
+		log.Error().Err(err).Stack().Array("tags", zerolog.Arr().Str("ratelimit").Str("repository").Str("cleanup")).Msg("Error deleting expired rate limit counters")
+	}
+}
+
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,75 @@
+package mysql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/kodefluence/monorepo/db/mock"
+	"github.com/kodefluence/monorepo/exception"
+	"github.com/stretchr/testify/assert"
+
+	repository "github.com/kodefluence/altair/plugin/ratelimit/repository/mysql"
+)
+
+func TestWindow(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	window := time.Unix(1200, 0)
+	keyHash := "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb"
+
+	t.Run("Counts", func(t *testing.T) {
+		t.Run("Given key and window", func(t *testing.T) {
+			t.Run("When database operation complete it will return the hits of both windows", func(t *testing.T) {
+				sqldb := mockdb.NewMockDB(mockCtrl)
+				row := mockdb.NewMockRow(mockCtrl)
+
+				sqldb.EXPECT().QueryRowContext(gomock.Any(), "ratelimit-counters-counts", "select coalesce(sum(case when window_start = ? then hits else 0 end), 0), coalesce(sum(case when window_start = ? then hits else 0 end), 0) from ratelimit_counters where key_hash = ? and window_start in (?, ?)", int64(1200000), int64(1140000), keyHash, int64(1200000), int64(1140000)).Return(row)
+				row.EXPECT().Scan(gomock.Any()).DoAndReturn(func(dest ...interface{}) exception.Exception {
+					*dest[0].(*int) = 3
+					*dest[1].(*int) = 5
+					return nil
+				})
+
+				current, previous, err := repository.NewWindow(sqldb).Counts(context.Background(), "a", window, time.Minute)
+				assert.Nil(t, err)
+				assert.Equal(t, 3, current)
+				assert.Equal(t, 5, previous)
+			})
+
+			t.Run("When database operation failed it will return error", func(t *testing.T) {
+				sqldb := mockdb.NewMockDB(mockCtrl)
+				row := mockdb.NewMockRow(mockCtrl)
+
+				sqldb.EXPECT().QueryRowContext(gomock.Any(), "ratelimit-counters-counts", gomock.Any(), gomock.Any()).Return(row)
+				row.EXPECT().Scan(gomock.Any()).Return(exception.Throw(errors.New("unexpected error")))
+
+				_, _, err := repository.NewWindow(sqldb).Counts(context.Background(), "a", window, time.Minute)
+				assert.NotNil(t, err)
+			})
+		})
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		t.Run("Given key, window and hits", func(t *testing.T) {
+			t.Run("When database operation complete it will return nil", func(t *testing.T) {
+				sqldb := mockdb.NewMockDB(mockCtrl)
+
+				sqldb.EXPECT().ExecContext(gomock.Any(), "ratelimit-counters-add", "insert into ratelimit_counters (key_hash, window_start, hits, expires_at) values(?, ?, ?, ?) on duplicate key update hits = hits + values(hits)", keyHash, int64(1200000), 2, window.Add(time.Minute*2).UTC()).Return(nil, nil)
+
+				assert.Nil(t, repository.NewWindow(sqldb).Add(context.Background(), "a", window, time.Minute, 2))
+			})
+
+			t.Run("When database operation failed it will return error", func(t *testing.T) {
+				sqldb := mockdb.NewMockDB(mockCtrl)
+
+				sqldb.EXPECT().ExecContext(gomock.Any(), "ratelimit-counters-add", gomock.Any(), gomock.Any()).Return(nil, exception.Throw(errors.New("unexpected error")))
+
+				assert.NotNil(t, repository.NewWindow(sqldb).Add(context.Background(), "a", window, time.Minute, 2))
+			})
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+)
+
+// takeScript refills the bucket up to now and takes one token. Tokens are
+// returned as a string, lua numbers are truncated to integers in replies.
+var takeScript = NewScript(`
+local rate = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+  tokens = capacity
+  updated = now
+end
+
+if now > updated then
+  tokens = math.min(capacity, tokens + (now - updated) * rate)
+  updated = now
+end
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'updated', updated)
+redis.call('PEXPIRE', KEYS[1], math.ceil((capacity - tokens) / rate) + 1000)
+
+return {allowed, tostring(tokens)}
+`)
+
+// Bucket keeps the token buckets in redis. The time comes from the gateway so
+// a bucket is refilled the same way whatever the clock of the server.
+type Bucket struct {
+	client    *Client
+	keyPrefix string
+}
+
+// NewBucket create new Bucket struct
+func NewBucket(client *Client, keyPrefix string) *Bucket {
+	return &Bucket{client: client, keyPrefix: keyPrefix}
+}
+
+func (b *Bucket) Take(ctx context.Context, key string, policy entity.Policy, now time.Time) (float64, bool, error) {
+	// Tokens refilled per millisecond.
+	rate := float64(policy.Limit) / float64(policy.Period.Milliseconds())
+
+	reply, err := b.client.Eval(ctx, takeScript, []string{b.keyPrefix + key},
+		strconv.FormatFloat(rate, 'g', -1, 64),
+		strconv.Itoa(policy.Burst),
+		strconv.FormatInt(now.UnixMilli(), 10),
+	)
+	if err != nil {
+		return 0, false, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return 0, false, fmt.Errorf("redis: unexpected token bucket reply %v", reply)
+	}
+
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return 0, false, fmt.Errorf("redis: unexpected token bucket reply %v", reply)
+	}
+
+	raw, ok := values[1].([]byte)
+	if !ok {
+		return 0, false, fmt.Errorf("redis: unexpected token bucket reply %v", reply)
+	}
+
+	tokens, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil || math.IsNaN(tokens) {
+		return 0, false, fmt.Errorf("redis: unexpected token count %s", raw)
+	}
+
+	return tokens, allowed == 1, nil
+}
@@ -0,0 +1,37 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+	"github.com/kodefluence/altair/plugin/ratelimit/repository/redis"
+)
+
+func TestBucket(t *testing.T) {
+	policy := entity.Policy{Key: "client_ip", Limit: 2, Period: time.Second, Burst: 3, Algorithm: "token_bucket"}
+	now := time.Unix(1200, 0)
+
+	t.Run("Take", func(t *testing.T) {
+		t.Run("Return the tokens left", func(t *testing.T) {
+			server := newFakeServer(t, func(args []string) string { return "*2\r\n:1\r\n$3\r\n1.5\r\n" })
+
+			tokens, allowed, err := redis.NewBucket(redis.NewClient(server.listener.Addr().String(), "", 0, time.Second), "altair:ratelimit:").Take(context.Background(), "a", policy, now)
+
+			assert.Nil(t, err)
+			assert.True(t, allowed)
+			assert.Equal(t, 1.5, tokens)
+			assert.Equal(t, []string{"1", "altair:ratelimit:a", "0.002", "3", "1200000"}, server.received()[0][2:])
+		})
+
+		t.Run("Return error on unexpected reply", func(t *testing.T) {
+			server := newFakeServer(t, func(args []string) string { return ":1\r\n" })
+
+			_, _, err := redis.NewBucket(redis.NewClient(server.listener.Addr().String(), "", 0, time.Second), "altair:ratelimit:").Take(context.Background(), "a", policy, now)
+			assert.NotNil(t, err)
+		})
+	})
+}
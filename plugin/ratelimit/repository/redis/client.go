@@ -0,0 +1,255 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxIdleConns = 16
+
+// Error is an error reply of the server.
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// Client speaks the RESP protocol to a redis compatible server. It only knows
+// what the ratelimit repositories need: plain commands and lua scripts.
+type Client struct {
+	address  string
+	password string
+	db       int
+	timeout  time.Duration
+	idle     chan *conn
+}
+
+// NewClient create new Client, connections are opened on demand and kept for
+// reuse.
+func NewClient(address, password string, db int, timeout time.Duration) *Client {
+	return &Client{
+		address:  address,
+		password: password,
+		db:       db,
+		timeout:  timeout,
+		idle:     make(chan *conn, maxIdleConns),
+	}
+}
+
+// Do sends one command and returns its reply: string for status replies,
+// int64 for integers, []byte or nil for bulk strings and []interface{} for
+// arrays. Error replies are returned as Error.
+//
+// The server may close a pooled connection while it is idle, a network error
+// on a reused connection is retried once on a fresh one.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	reply, reused, err := c.do(ctx, false, args...)
+	if reused && retryable(err) && ctx.Err() == nil {
+		reply, _, err = c.do(ctx, true, args...)
+	}
+
+	return reply, err
+}
+
+func (c *Client) do(ctx context.Context, fresh bool, args ...string) (interface{}, bool, error) {
+	cn, reused, err := c.conn(ctx, fresh)
+	if err != nil {
+		return nil, reused, err
+	}
+
+	if err := cn.SetDeadline(c.deadline(ctx)); err != nil {
+		_ = cn.Close()
+		return nil, reused, err
+	}
+
+	reply, err := cn.do(args...)
+
+	var replyErr Error
+	if err != nil && !errors.As(err, &replyErr) {
+		_ = cn.Close()
+		return nil, reused, err
+	}
+
+	c.release(cn)
+	return reply, reused, err
+}
+
+// retryable reports whether err is a network error other than a timeout, a
+// timeout already spent the time allowed for the command.
+func retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var replyErr Error
+	if errors.As(err, &replyErr) {
+		return false
+	}
+
+	var netErr net.Error
+	return !errors.As(err, &netErr) || !netErr.Timeout()
+}
+
+// Script is a lua script called by its sha1, it is only sent to the server
+// when the server does not know it yet.
+type Script struct {
+	source string
+	sha    string
+}
+
+func NewScript(source string) *Script {
+	sum := sha1.Sum([]byte(source))
+	return &Script{source: source, sha: hex.EncodeToString(sum[:])}
+}
+
+// Eval runs the script with keys and args.
+func (c *Client) Eval(ctx context.Context, script *Script, keys []string, args ...string) (interface{}, error) {
+	command := append([]string{"", strconv.Itoa(len(keys))}, keys...)
+	command = append(command, args...)
+
+	command[0] = script.sha
+	reply, err := c.Do(ctx, append([]string{"EVALSHA"}, command...)...)
+
+	var replyErr Error
+	if errors.As(err, &replyErr) && strings.HasPrefix(string(replyErr), "NOSCRIPT") {
+		command[0] = script.source
+		return c.Do(ctx, append([]string{"EVAL"}, command...)...)
+	}
+
+	return reply, err
+}
+
+func (c *Client) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		return d
+	}
+
+	return deadline
+}
+
+// conn returns an idle connection when there is one, fresh skips them and
+// always dials.
+func (c *Client) conn(ctx context.Context, fresh bool) (*conn, bool, error) {
+	if !fresh {
+		select {
+		case cn := <-c.idle:
+			return cn, true, nil
+		default:
+		}
+	}
+
+	dialer := net.Dialer{Deadline: c.deadline(ctx)}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return nil, false, err
+	}
+
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn), writer: bufio.NewWriter(netConn)}
+	if err := cn.SetDeadline(c.deadline(ctx)); err != nil {
+		_ = cn.Close()
+		return nil, false, err
+	}
+
+	if c.password != "" {
+		if _, err := cn.do("AUTH", c.password); err != nil {
+			_ = cn.Close()
+			return nil, false, err
+		}
+	}
+
+	if c.db != 0 {
+		if _, err := cn.do("SELECT", strconv.Itoa(c.db)); err != nil {
+			_ = cn.Close()
+			return nil, false, err
+		}
+	}
+
+	return cn, false, nil
+}
+
+func (c *Client) release(cn *conn) {
+	select {
+	case c.idle <- cn:
+	default:
+		_ = cn.Close()
+	}
+}
+
+func (cn *conn) do(args ...string) (interface{}, error) {
+	fmt.Fprintf(cn.writer, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(cn.writer, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	if err := cn.writer.Flush(); err != nil {
+		return nil, err
+	}
+
+	return cn.read()
+}
+
+func (cn *conn) read() (interface{}, error) {
+	line, err := cn.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	line = strings.TrimSuffix(line, "\r\n")
+	if len(line) == 0 {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(cn.reader, buf); err != nil {
+			return nil, err
+		}
+
+		return buf[:size], nil
+	case '*':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return nil, err
+		}
+
+		values := make([]interface{}, size)
+		for i := range values {
+			value, err := cn.read()
+			var replyErr Error
+			if err != nil && !errors.As(err, &replyErr) {
+				return nil, err
+			}
+			values[i] = value
+		}
+
+		return values, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply `%s`", line)
+	}
+}
@@ -0,0 +1,191 @@
+package redis_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/repository/redis"
+)
+
+// fakeServer answers every command with the raw RESP reply of handle and
+// records the commands it received. Connections idle for longer than
+// idleTimeout are closed when it is set.
+type fakeServer struct {
+	listener    net.Listener
+	handle      func(args []string) string
+	idleTimeout time.Duration
+
+	lock     sync.Mutex
+	commands [][]string
+	accepted int
+}
+
+func newFakeServer(t *testing.T, handle func(args []string) string) *fakeServer {
+	return newIdleClosingFakeServer(t, 0, handle)
+}
+
+func newIdleClosingFakeServer(t *testing.T, idleTimeout time.Duration, handle func(args []string) string) *fakeServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	s := &fakeServer{listener: listener, handle: handle, idleTimeout: idleTimeout}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			s.lock.Lock()
+			s.accepted++
+			s.lock.Unlock()
+
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *fakeServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		if s.idleTimeout > 0 {
+			_ = conn.SetReadDeadline(time.Now().Add(s.idleTimeout))
+		}
+
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, size)
+		for i := range args {
+			header, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+
+			length, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+			buf := make([]byte, length+2)
+			if _, err := io.ReadFull(reader, buf); err != nil {
+				return
+			}
+			args[i] = string(buf[:length])
+		}
+
+		s.lock.Lock()
+		s.commands = append(s.commands, args)
+		s.lock.Unlock()
+
+		if _, err := conn.Write([]byte(s.handle(args))); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) received() [][]string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.commands
+}
+
+func TestClient(t *testing.T) {
+	t.Run("Do", func(t *testing.T) {
+		t.Run("Authenticate and select the database on connect", func(t *testing.T) {
+			server := newFakeServer(t, func(args []string) string { return "+OK\r\n" })
+
+			client := redis.NewClient(server.listener.Addr().String(), "secret", 2, time.Second)
+			reply, err := client.Do(context.Background(), "PING")
+
+			assert.Nil(t, err)
+			assert.Equal(t, "OK", reply)
+			assert.Equal(t, [][]string{{"AUTH", "secret"}, {"SELECT", "2"}, {"PING"}}, server.received())
+		})
+
+		t.Run("Parse every reply type", func(t *testing.T) {
+			server := newFakeServer(t, func(args []string) string {
+				return "*4\r\n:42\r\n$5\r\nhello\r\n$-1\r\n*1\r\n+OK\r\n"
+			})
+
+			reply, err := redis.NewClient(server.listener.Addr().String(), "", 0, time.Second).Do(context.Background(), "ANY")
+
+			assert.Nil(t, err)
+			assert.Equal(t, []interface{}{int64(42), []byte("hello"), nil, []interface{}{"OK"}}, reply)
+		})
+
+		t.Run("Return error replies and keep the connection", func(t *testing.T) {
+			server := newFakeServer(t, func(args []string) string { return "-ERR unknown command\r\n" })
+
+			client := redis.NewClient(server.listener.Addr().String(), "", 0, time.Second)
+			_, err := client.Do(context.Background(), "NOPE")
+			assert.Equal(t, redis.Error("ERR unknown command"), err)
+
+			_, err = client.Do(context.Background(), "NOPE")
+			assert.NotNil(t, err)
+		})
+
+		t.Run("Retry on a fresh connection when the server closed the idle one", func(t *testing.T) {
+			server := newIdleClosingFakeServer(t, time.Millisecond*20, func(args []string) string { return "+PONG\r\n" })
+
+			client := redis.NewClient(server.listener.Addr().String(), "", 0, time.Second)
+			_, err := client.Do(context.Background(), "PING")
+			assert.Nil(t, err)
+
+			time.Sleep(time.Millisecond * 100)
+
+			reply, err := client.Do(context.Background(), "PING")
+			assert.Nil(t, err)
+			assert.Equal(t, "PONG", reply)
+			assert.Equal(t, [][]string{{"PING"}, {"PING"}}, server.received())
+
+			server.lock.Lock()
+			defer server.lock.Unlock()
+			assert.Equal(t, 2, server.accepted)
+		})
+
+		t.Run("Return error when the server is unreachable", func(t *testing.T) {
+			listener, _ := net.Listen("tcp", "127.0.0.1:0")
+			address := listener.Addr().String()
+			_ = listener.Close()
+
+			_, err := redis.NewClient(address, "", 0, time.Second).Do(context.Background(), "PING")
+			assert.NotNil(t, err)
+		})
+	})
+
+	t.Run("Eval", func(t *testing.T) {
+		t.Run("Send the script when the server does not know it", func(t *testing.T) {
+			server := newFakeServer(t, func(args []string) string {
+				if args[0] == "EVALSHA" {
+					return "-NOSCRIPT No matching script.\r\n"
+				}
+				return ":1\r\n"
+			})
+
+			reply, err := redis.NewClient(server.listener.Addr().String(), "", 0, time.Second).Eval(context.Background(), redis.NewScript("return 1"), []string{"k"}, "a")
+
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), reply)
+
+			commands := server.received()
+			assert.Equal(t, []string{"EVALSHA", "e0e1f9fabfc9d4800c877a703b823ac0578ff8db", "1", "k", "a"}, commands[0])
+			assert.Equal(t, []string{"EVAL", "return 1", "1", "k", "a"}, commands[1])
+		})
+	})
+}
@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// addScript increments a window and lets it expire once it is no longer read
+// as the previous window.
+var addScript = NewScript(`
+local hits = redis.call('INCRBY', KEYS[1], ARGV[1])
+if redis.call('PTTL', KEYS[1]) < 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return hits
+`)
+
+// Window keeps the sliding window counters in redis, one key per window.
+type Window struct {
+	client    *Client
+	keyPrefix string
+}
+
+// NewWindow create new Window struct
+func NewWindow(client *Client, keyPrefix string) *Window {
+	return &Window{client: client, keyPrefix: keyPrefix}
+}
+
+func (w *Window) Counts(ctx context.Context, key string, window time.Time, period time.Duration) (int, int, error) {
+	reply, err := w.client.Do(ctx, "MGET", w.key(key, window), w.key(key, window.Add(-period)))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return 0, 0, fmt.Errorf("redis: unexpected MGET reply %v", reply)
+	}
+
+	current, err := parseHits(values[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	previous, err := parseHits(values[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return current, previous, nil
+}
+
+func (w *Window) Add(ctx context.Context, key string, window time.Time, period time.Duration, hits int) error {
+	ttl := window.Add(period * 2).Sub(time.Now()).Milliseconds()
+	if ttl < 1 {
+		ttl = 1
+	}
+
+	_, err := w.client.Eval(ctx, addScript, []string{w.key(key, window)}, strconv.Itoa(hits), strconv.FormatInt(ttl, 10))
+	return err
+}
+
+func (w *Window) key(key string, window time.Time) string {
+	return w.keyPrefix + key + ":" + strconv.FormatInt(window.UnixMilli(), 10)
+}
+
+func parseHits(value interface{}) (int, error) {
+	if value == nil {
+		return 0, nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected counter value %v", value)
+	}
+
+	return strconv.Atoi(string(bytes))
+}
@@ -0,0 +1,42 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kodefluence/altair/plugin/ratelimit/repository/redis"
+)
+
+func TestWindow(t *testing.T) {
+	window := time.Unix(1200, 0)
+
+	t.Run("Counts", func(t *testing.T) {
+		t.Run("Read both windows, missing ones count zero", func(t *testing.T) {
+			server := newFakeServer(t, func(args []string) string { return "*2\r\n$1\r\n3\r\n$-1\r\n" })
+
+			current, previous, err := redis.NewWindow(redis.NewClient(server.listener.Addr().String(), "", 0, time.Second), "altair:ratelimit:").Counts(context.Background(), "a", window, time.Minute)
+
+			assert.Nil(t, err)
+			assert.Equal(t, 3, current)
+			assert.Equal(t, 0, previous)
+			assert.Equal(t, []string{"MGET", "altair:ratelimit:a:1200000", "altair:ratelimit:a:1140000"}, server.received()[0])
+		})
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		t.Run("Increment the window", func(t *testing.T) {
+			server := newFakeServer(t, func(args []string) string { return ":2\r\n" })
+
+			err := redis.NewWindow(redis.NewClient(server.listener.Addr().String(), "", 0, time.Second), "altair:ratelimit:").Add(context.Background(), "a", window, time.Minute, 2)
+
+			assert.Nil(t, err)
+
+			command := server.received()[0]
+			assert.Equal(t, "EVALSHA", command[0])
+			assert.Equal(t, []string{"1", "altair:ratelimit:a:1200000", "2"}, command[2:5])
+		})
+	})
+}
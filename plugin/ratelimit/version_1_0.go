@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/ratelimit/entity"
+	"github.com/kodefluence/altair/plugin/ratelimit/module/limiter"
+	"github.com/kodefluence/altair/plugin/ratelimit/module/limiter/controller/downstream"
+	"github.com/kodefluence/altair/plugin/ratelimit/module/limiter/usecase"
+	"github.com/kodefluence/altair/plugin/ratelimit/repository/memory"
+	"github.com/kodefluence/altair/plugin/ratelimit/repository/mysql"
+	"github.com/kodefluence/altair/plugin/ratelimit/repository/redis"
+)
+
+// errMissingDecodeConfig guards against PluginContext values constructed
+// outside of plugin.runner.buildContext, which always populates DecodeConfig.
+var errMissingDecodeConfig = errors.New("ratelimit plugin: PluginContext.DecodeConfig is nil")
+
+func loadV1_0(ctx module.PluginContext) error {
+	if ctx.DecodeConfig == nil {
+		return errMissingDecodeConfig
+	}
+	var rateLimitPlugin entity.RateLimitPlugin
+	if err := ctx.DecodeConfig(&rateLimitPlugin); err != nil {
+		return err
+	}
+
+	policy, err := rateLimitPlugin.Policy()
+	if err != nil {
+		return err
+	}
+
+	store, err := newStore(ctx, rateLimitPlugin, policy)
+	if err != nil {
+		return err
+	}
+
+	limiter.Load(ctx.AppModule, policy, store, ctx.ApiError)
+
+	return nil
+}
+
+// newStore pairs the configured algorithm with the repository holding its
+// counters.
+func newStore(ctx module.PluginContext, rateLimitPlugin entity.RateLimitPlugin, policy entity.Policy) (downstream.Store, error) {
+	var buckets usecase.BucketRepository
+	var windows usecase.WindowRepository
+
+	switch rateLimitPlugin.StoreType() {
+	case entity.StoreMySQL:
+		if ctx.Database == nil {
+			return nil, fmt.Errorf("ratelimit plugin: PluginContext.Database is nil")
+		}
+
+		sqldb, _, err := ctx.Database(rateLimitPlugin.DatabaseInstance())
+		if err != nil {
+			return nil, err
+		}
+
+		windows = mysql.NewWindow(sqldb)
+	case entity.StoreRedis:
+		timeout, err := rateLimitPlugin.RedisTimeout()
+		if err != nil {
+			return nil, err
+		}
+
+		redisConfig := rateLimitPlugin.Config.Store.Redis
+		client := redis.NewClient(redisConfig.Address, redisConfig.Password, redisConfig.DB, timeout)
+
+		buckets = redis.NewBucket(client, rateLimitPlugin.RedisKeyPrefix())
+		windows = redis.NewWindow(client, rateLimitPlugin.RedisKeyPrefix())
+	default:
+		buckets = memory.NewBucket()
+		windows = memory.NewWindow()
+	}
+
+	if policy.Algorithm == entity.AlgorithmTokenBucket {
+		return usecase.NewTokenBucket(buckets), nil
+	}
+
+	syncInterval, err := rateLimitPlugin.SyncInterval()
+	if err != nil {
+		return nil, err
+	}
+
+	if syncInterval > 0 {
+		return usecase.NewCachedSlidingWindow(windows, syncInterval), nil
+	}
+
+	return usecase.NewSlidingWindow(windows), nil
+}
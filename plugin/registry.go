@@ -2,8 +2,11 @@ package plugin
 
 import (
 	"github.com/kodefluence/altair/module"
+	"github.com/kodefluence/altair/plugin/cache"
+	"github.com/kodefluence/altair/plugin/ipfilter"
 	"github.com/kodefluence/altair/plugin/metric"
 	"github.com/kodefluence/altair/plugin/oauth"
+	"github.com/kodefluence/altair/plugin/ratelimit"
 )
 
 // Registry returns every plugin compiled into this binary. This is the single
@@ -16,7 +19,10 @@ import (
 // diffs stable.
 func Registry() []module.Plugin {
 	return []module.Plugin{
+		&cache.Plugin{},
+		&ipfilter.Plugin{},
 		&metric.Plugin{},
 		&oauth.Plugin{},
+		&ratelimit.Plugin{},
 	}
 }
@@ -15,9 +15,11 @@ import (
 // Load builds a PluginContext for every active plugin in Registry() and calls
 // Plugin.Load in topologically-sorted order. A plugin is "active" iff it is
 // listed in app.yml `plugins:` AND has a matching config/plugin/<name>.yml
-// (AND-gate).
-func Load(appBearer core.AppBearer, pluginBearer core.PluginBearer, dbBearer core.DatabaseBearer, apiError module.ApiError, appModule module.App) error {
+// (AND-gate). publicModule is handed to plugins serving unauthenticated
+// endpoints.
+func Load(appBearer core.AppBearer, pluginBearer core.PluginBearer, dbBearer core.DatabaseBearer, apiError module.ApiError, appModule module.App, publicModule module.App) error {
 	return run(Registry(), appBearer, pluginBearer, dbBearer, apiError, appModule, func(p module.Plugin, ctx module.PluginContext) error {
+		ctx.PublicModule = publicModule
 		return p.Load(ctx)
 	})
 }
@@ -4,12 +4,14 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/kodefluence/monorepo/db"
 
 	"github.com/kodefluence/altair/core"
+	"github.com/kodefluence/altair/entity"
 	"github.com/kodefluence/altair/module"
 )
 
@@ -41,14 +43,21 @@ type stubAppConfig struct {
 	enabled map[string]bool
 }
 
-func (s *stubAppConfig) Port() int                     { return 0 }
-func (s *stubAppConfig) BasicAuthUsername() string     { return "" }
-func (s *stubAppConfig) BasicAuthPassword() string     { return "" }
-func (s *stubAppConfig) ProxyHost() string             { return "" }
-func (s *stubAppConfig) PluginExists(name string) bool { return s.enabled[name] }
-func (s *stubAppConfig) Plugins() []string             { return nil }
-func (s *stubAppConfig) AutoMigrate() bool             { return false }
-func (s *stubAppConfig) Dump() string                  { return "" }
+func (s *stubAppConfig) Port() int                          { return 0 }
+func (s *stubAppConfig) BasicAuthUsername() string          { return "" }
+func (s *stubAppConfig) BasicAuthPassword() string          { return "" }
+func (s *stubAppConfig) ProxyHost() string                  { return "" }
+func (s *stubAppConfig) PluginExists(name string) bool      { return s.enabled[name] }
+func (s *stubAppConfig) Plugins() []string                  { return nil }
+func (s *stubAppConfig) AutoMigrate() bool                  { return false }
+func (s *stubAppConfig) TLS() entity.AppTLSConfig           { return entity.AppTLSConfig{} }
+func (s *stubAppConfig) HTTP2() bool                        { return false }
+func (s *stubAppConfig) H2C() bool                          { return false }
+func (s *stubAppConfig) ShutdownDelay() time.Duration       { return 0 }
+func (s *stubAppConfig) ShutdownDrainPeriod() time.Duration { return 0 }
+func (s *stubAppConfig) CORS() *entity.RouteCORS            { return nil }
+func (s *stubAppConfig) TrustedProxies() []string           { return nil }
+func (s *stubAppConfig) Dump() string                       { return "" }
 
 type stubAppBearer struct{ cfg core.AppConfig }
 
@@ -276,7 +285,7 @@ func TestRun_AndGateViolationErrorsBeforeLoad(t *testing.T) {
 // Load with empty bearers returns nil (no real plugins active in test).
 func TestPublicLoad_SmokeWithEmptyBearersReturnsNil(t *testing.T) {
 	appBearer, pluginBearer, dbBearer := runWith(nil, map[string]bool{}, map[string]bool{}, nil)
-	assert.Nil(t, Load(appBearer, pluginBearer, dbBearer, nil, nil))
+	assert.Nil(t, Load(appBearer, pluginBearer, dbBearer, nil, nil, nil))
 	assert.Nil(t, LoadCommand(appBearer, pluginBearer, dbBearer, nil, nil))
 }
 
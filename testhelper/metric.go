@@ -13,7 +13,11 @@ func NewDummyMetric() *DummyMetric {
 
 func (*DummyMetric) InjectCounter(metricName string, labels ...string)     {}
 func (*DummyMetric) InjectHistogram(metricName string, labels ...string)   {}
+func (*DummyMetric) InjectGauge(metricName string, labels ...string)       {}
 func (*DummyMetric) Inc(metricName string, labels map[string]string) error { return nil }
 func (*DummyMetric) Observe(metricName string, value float64, labels map[string]string) error {
 	return nil
 }
+func (*DummyMetric) Set(metricName string, value float64, labels map[string]string) error {
+	return nil
+}
@@ -11,7 +11,7 @@ import (
 )
 
 type Value interface {
-	int | string | time.Time
+	int | string | bool | time.Time
 }
 
 func ValueToPointer[V Value](v V) *V {
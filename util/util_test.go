@@ -35,6 +35,10 @@ func TestUtil(t *testing.T) {
 	assert.Equal(t, &stringValue, util.ValueToPointer(stringValue))
 	assert.Equal(t, stringValue, util.PointerToValue(&stringValue))
 
+	boolValue := true
+	assert.Equal(t, &boolValue, util.ValueToPointer(boolValue))
+	assert.Equal(t, boolValue, util.PointerToValue(&boolValue))
+
 	timeValue := time.Now()
 	assert.Equal(t, &timeValue, util.ValueToPointer(timeValue))
 	assert.Equal(t, timeValue, util.PointerToValue(&timeValue))